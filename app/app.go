@@ -30,7 +30,25 @@ type Application struct {
 type Theme struct {
 	tview.Theme
 
-	SidebarTitleBorderColor string
+	SidebarTitleBorderColor tcell.Color
+
+	// ChangedRowColor, InsertedRowColor and DeletedRowColor are the
+	// backgrounds of the cells with pending changes.
+	ChangedRowColor  tcell.Color
+	InsertedRowColor tcell.Color
+	DeletedRowColor  tcell.Color
+
+	// SelectedTextColor and SelectedBackgroundColor are the colours of the
+	// selected node of the tree.
+	SelectedTextColor       tcell.Color
+	SelectedBackgroundColor tcell.Color
+
+	// ErrorColor is the colour of the errors, WarningColor of what is waited
+	// for and SuccessColor of what went well. They also colour the production,
+	// staging and development connections.
+	ErrorColor   tcell.Color
+	WarningColor tcell.Color
+	SuccessColor tcell.Color
 }
 
 func init() {
@@ -47,22 +65,8 @@ func init() {
 	App.EnableMouse(true)
 	App.EnablePaste(true)
 
-	Styles = &Theme{
-		Theme: tview.Theme{
-			PrimitiveBackgroundColor:    tcell.ColorDefault,
-			ContrastBackgroundColor:     tcell.ColorBlue,
-			MoreContrastBackgroundColor: tcell.ColorGreen,
-			BorderColor:                 tcell.ColorWhite,
-			TitleColor:                  tcell.ColorWhite,
-			GraphicsColor:               tcell.ColorGray,
-			PrimaryTextColor:            tcell.ColorDefault.TrueColor(),
-			SecondaryTextColor:          tcell.ColorYellow,
-			TertiaryTextColor:           tcell.ColorGreen,
-			InverseTextColor:            tcell.ColorWhite,
-			ContrastSecondaryTextColor:  tcell.ColorBlack,
-		},
-		SidebarTitleBorderColor: "#666A7E",
-	}
+	theme := Themes[DefaultTheme]
+	Styles = &theme
 
 	tview.Styles = Styles.Theme
 }
@@ -82,6 +86,11 @@ func (a *Application) Connections() []models.Connection {
 	return a.config.Connections
 }
 
+// SavedQueries returns the saved queries.
+func (a *Application) SavedQueries() []models.SavedQuery {
+	return a.config.Queries
+}
+
 // SaveConnections saves the database connections.
 func (a *Application) SaveConnections(connections []models.Connection) error {
 	return a.config.SaveConnections(connections)
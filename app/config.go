@@ -1,19 +1,25 @@
 package app
 
 import (
-	"fmt"
-	"net/url"
 	"os"
 	"path/filepath"
 
 	"github.com/pelletier/go-toml/v2"
 
 	"github.com/jorgerojas26/lazysql/drivers"
+	"github.com/jorgerojas26/lazysql/helpers/logger"
 	"github.com/jorgerojas26/lazysql/models"
 )
 
 type Config struct {
-	AppConfig   *models.AppConfig   `toml:"application"`
+	AppConfig *models.AppConfig `toml:"application"`
+	// Keymap maps the groups of keybindings to the command names to their
+	// keys.
+	Keymap map[string]map[string]any `toml:"keymap,omitempty"`
+	// Theme names the built-in theme and the colours it overrides.
+	Theme map[string]string `toml:"theme,omitempty"`
+	// Queries are the saved queries.
+	Queries     []models.SavedQuery `toml:"query,omitempty"`
 	Connections []models.Connection `toml:"database"`
 }
 
@@ -58,6 +64,20 @@ func LoadConfig() error {
 		App.config.Connections[i].URL = parseConfigURL(&conn)
 	}
 
+	// An invalid theme should not keep the application from starting either,
+	// the default theme is used instead.
+	if err := SetTheme(App.config.Theme); err != nil {
+		logger.Error("Invalid theme configuration", map[string]any{"error": err.Error()})
+
+		_ = SetTheme(nil)
+	}
+
+	// An invalid keybinding should not keep the application from starting,
+	// the commands it was set for keep their default keys.
+	if err := Keymaps.Configure(App.config.Keymap); err != nil {
+		logger.Error("Invalid keymap configuration", map[string]any{"error": err.Error()})
+	}
+
 	return nil
 }
 
@@ -84,28 +104,16 @@ func (c *Config) SaveConnections(connections []models.Connection) error {
 
 // parseConfigURL automatically generates the URL from the connection struct
 // if the URL is empty. It is useful for handling usernames and passwords with
-// special characters. NOTE: Only MSSQL is supported for now!
+// special characters.
 func parseConfigURL(conn *models.Connection) string {
-	if conn.URL != "" {
+	if conn.URL != "" || conn.Provider == "" {
 		return conn.URL
 	}
 
-	// Only MSSQL is supported for now.
-	if conn.Provider != drivers.DriverMSSQL {
-		return conn.URL
+	urlstr, err := drivers.BuildURL(*conn)
+	if err != nil {
+		logger.Error("Could not generate the URL of the connection", map[string]any{"name": conn.Name, "error": err.Error()})
 	}
 
-	user := url.QueryEscape(conn.Username)
-	pass := url.QueryEscape(conn.Password)
-
-	return fmt.Sprintf(
-		"%s://%s:%s@%s:%s?database=%s%s",
-		conn.Provider,
-		user,
-		pass,
-		conn.Hostname,
-		conn.Port,
-		conn.DBName,
-		conn.URLParams,
-	)
+	return urlstr
 }
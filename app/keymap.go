@@ -1,6 +1,11 @@
 package app
 
 import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
 	"github.com/gdamore/tcell/v2"
 
 	cmd "github.com/jorgerojas26/lazysql/commands"
@@ -19,6 +24,8 @@ type (
 type KeymapSystem struct {
 	Groups map[string]Map
 	Global Map
+	// State is what was typed so far of a sequence, shared by the groups.
+	State *keymap.State
 }
 
 func (c KeymapSystem) Group(name string) Map {
@@ -32,9 +39,166 @@ func (c KeymapSystem) Group(name string) Map {
 }
 
 // Resolve translates a tcell.EventKey into a command based on the mappings in
-// the global group
-func (c KeymapSystem) Resolve(event *tcell.EventKey) cmd.Command {
-	return c.Global.Resolve(event)
+// the group
+func (c KeymapSystem) Resolve(group string, event *tcell.EventKey) cmd.Command {
+	return c.State.Resolve(c.Group(group), event)
+}
+
+// ResolveCount resolves the event like Resolve, and returns the count typed
+// before the command, like 5 in 5j, or 1.
+func (c KeymapSystem) ResolveCount(group string, event *tcell.EventKey) (cmd.Command, int) {
+	return c.State.ResolveCount(c.Group(group), event)
+}
+
+// Pending reports whether the keys typed so far are the start of a sequence
+// or a count.
+func (c KeymapSystem) Pending() bool {
+	return c.State.Pending()
+}
+
+// GroupCommand is a command of a group with the keys of its first binding.
+type GroupCommand struct {
+	Cmd         cmd.Command
+	Description string
+	// Keys is nil when the command is not bound.
+	Keys keymap.Keys
+}
+
+// Commands returns the commands of the group, bound or not, in the order of
+// the default bindings, then the ones only bound in the configuration.
+func (c KeymapSystem) Commands(group string) []GroupCommand {
+	var commands []GroupCommand
+
+	index := map[cmd.Command]int{}
+
+	for _, bind := range defaultGroups[group] {
+		if _, ok := index[bind.Cmd]; !ok {
+			index[bind.Cmd] = len(commands)
+			commands = append(commands, GroupCommand{Cmd: bind.Cmd, Description: bind.Description})
+		}
+	}
+
+	for _, bind := range c.Group(group) {
+		i, ok := index[bind.Cmd]
+		if !ok {
+			i = len(commands)
+			index[bind.Cmd] = i
+			commands = append(commands, GroupCommand{Cmd: bind.Cmd, Description: bind.Description})
+		}
+
+		if commands[i].Keys == nil {
+			commands[i].Keys = bind.Keys()
+		}
+	}
+
+	return commands
+}
+
+// Configure replaces the bindings of the commands set in the configuration,
+// which maps the groups to the command names to their keys. A command is given
+// one key or a list of keys, an empty list unbinds it. The commands whose keys
+// are invalid keep their bindings, the errors are returned together.
+func (c KeymapSystem) Configure(config map[string]map[string]any) error {
+	var errs []error
+
+	for _, groupName := range slices.Sorted(maps.Keys(config)) {
+		group, ok := c.Groups[groupName]
+		if !ok {
+			errs = append(errs, fmt.Errorf("keymap: unknown group %q", groupName))
+			continue
+		}
+
+		var configured Map
+
+		for _, name := range slices.Sorted(maps.Keys(config[groupName])) {
+			command, ok := cmd.Parse(name)
+			if !ok {
+				errs = append(errs, fmt.Errorf("keymap.%s: unknown command %q", groupName, name))
+				continue
+			}
+
+			keys, err := configKeys(config[groupName][name])
+			if err != nil {
+				errs = append(errs, fmt.Errorf("keymap.%s.%s: %w", groupName, name, err))
+				continue
+			}
+
+			sequences := make([]keymap.Keys, 0, len(keys))
+
+			for _, text := range keys {
+				sequence, err := keymap.ParseKeys(text)
+				if err != nil {
+					errs = append(errs, fmt.Errorf("keymap.%s.%s: %w", groupName, name, err))
+					break
+				}
+
+				sequences = append(sequences, sequence)
+			}
+
+			// A command with a key that does not parse keeps its bindings.
+			if len(sequences) < len(keys) {
+				continue
+			}
+
+			description := command.String()
+
+			for _, bind := range group {
+				if bind.Cmd == command {
+					description = bind.Description
+					break
+				}
+			}
+
+			group = slices.DeleteFunc(group, func(bind Bind) bool {
+				return bind.Cmd == command
+			})
+
+			for _, sequence := range sequences {
+				configured = append(configured, Bind{Key: sequence[0], Then: sequence[1:], Cmd: command, Description: description})
+			}
+		}
+
+		group = append(configured, group...)
+
+		// The configured bindings come first, each is compared with the ones
+		// after it.
+		for i, bind := range configured {
+			for _, other := range group[i+1:] {
+				if other.Cmd != bind.Cmd && (bind.Keys().HasPrefix(other.Keys()) || other.Keys().HasPrefix(bind.Keys())) {
+					errs = append(errs, fmt.Errorf("keymap.%s: %s of %s conflicts with %s of %s",
+						groupName, bind.Keys(), bind.Cmd, other.Keys(), other.Cmd))
+				}
+			}
+		}
+
+		c.Groups[groupName] = group
+	}
+
+	return errors.Join(errs...)
+}
+
+// configKeys returns the keys of a command in the configuration, a string or
+// a list of strings.
+func configKeys(value any) ([]string, error) {
+	switch value := value.(type) {
+	case string:
+		return []string{value}, nil
+	case []any:
+		keys := make([]string, 0, len(value))
+
+		for _, key := range value {
+			text, ok := key.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid key %v", key)
+			}
+
+			keys = append(keys, text)
+		}
+
+		return keys, nil
+	}
+
+	return nil, fmt.Errorf("invalid keys %v, expected a string or a list of strings", value)
 }
 
 const (
@@ -46,10 +210,14 @@ const (
 	ConnectionGroup   = "connection"
 	SidebarGroup      = "sidebar"
 	QueryPreviewGroup = "querypreview"
+	SchemaDiffGroup   = "schemadiff"
+	DataDiffGroup     = "datadiff"
+	ERDiagramGroup    = "erdiagram"
 )
 
 // Define a global KeymapSystem object with default keybinds
 var Keymaps = KeymapSystem{
+	State: keymap.NewState(),
 	Groups: map[string]Map{
 		HomeGroup: {
 			Bind{Key: Key{Char: 'L'}, Cmd: cmd.MoveRight, Description: "Focus table"},
@@ -60,6 +228,12 @@ var Keymaps = KeymapSystem{
 			Bind{Key: Key{Code: tcell.KeyBackspace2}, Cmd: cmd.SwitchToConnectionsView, Description: "Switch to connections list"},
 			Bind{Key: Key{Char: '?'}, Cmd: cmd.HelpPopup, Description: "Help"},
 			Bind{Key: Key{Code: tcell.KeyCtrlP}, Cmd: cmd.SearchGlobal, Description: "Global search"},
+			Bind{Key: Key{Code: tcell.KeyCtrlO}, Cmd: cmd.SwitchConnection, Description: "Switch to another open connection"},
+			Bind{Key: Key{Code: tcell.KeyCtrlN}, Cmd: cmd.NextConnection, Description: "Switch to next open connection"},
+			Bind{Key: Key{Code: tcell.KeyCtrlB}, Cmd: cmd.PreviousConnection, Description: "Switch to previous open connection"},
+			Bind{Key: Key{Code: tcell.KeyCtrlX}, Cmd: cmd.Disconnect, Description: "Disconnect"},
+			Bind{Key: Key{Code: tcell.KeyCtrlG}, Cmd: cmd.ShowCommands, Description: "Show the output of the connection commands"},
+			Bind{Key: Key{Code: tcell.KeyCtrlK}, Cmd: cmd.CommandPalette, Description: "Command palette"},
 		},
 		ConnectionGroup: {
 			Bind{Key: Key{Char: 'n'}, Cmd: cmd.NewConnection, Description: "Create a new database connection"},
@@ -67,7 +241,10 @@ var Keymaps = KeymapSystem{
 			Bind{Key: Key{Code: tcell.KeyEnter}, Cmd: cmd.Connect, Description: "Connect to database"},
 			Bind{Key: Key{Char: 'e'}, Cmd: cmd.EditConnection, Description: "Edit a database connection"},
 			Bind{Key: Key{Char: 'd'}, Cmd: cmd.DeleteConnection, Description: "Delete a database connection"},
+			Bind{Key: Key{Char: '/'}, Cmd: cmd.Search, Description: "Filter connections"},
+			Bind{Key: Key{Char: 'x'}, Cmd: cmd.Disconnect, Description: "Disconnect from a database"},
 			Bind{Key: Key{Char: 'q'}, Cmd: cmd.Quit, Description: "Quit"},
+			Bind{Key: Key{Code: tcell.KeyCtrlK}, Cmd: cmd.CommandPalette, Description: "Command palette"},
 		},
 		TreeGroup: {
 			Bind{Key: Key{Char: 'g'}, Cmd: cmd.GotoTop, Description: "Go to top"},
@@ -87,12 +264,31 @@ var Keymaps = KeymapSystem{
 			Bind{Key: Key{Char: 'c'}, Cmd: cmd.TreeCollapseAll, Description: "Collapse all"},
 			Bind{Key: Key{Char: 'e'}, Cmd: cmd.ExpandAll, Description: "Expand all"},
 			Bind{Key: Key{Char: 'R'}, Cmd: cmd.Refresh, Description: "Refresh tree"},
+			Bind{Key: Key{Char: 'D'}, Cmd: cmd.SchemaDiff, Description: "Compare schema with another database"},
+			Bind{Key: Key{Char: 'd'}, Cmd: cmd.DataDiff, Description: "Compare rows with another table"},
+			Bind{Key: Key{Char: 'Y'}, Cmd: cmd.CopyTable, Description: "Copy table to another connection"},
+			Bind{Key: Key{Char: 'x'}, Cmd: cmd.Dump, Description: "Dump database or table to a SQL file"},
+			Bind{Key: Key{Char: 'i'}, Cmd: cmd.Restore, Description: "Restore a SQL file"},
+			Bind{Key: Key{Char: 'v'}, Cmd: cmd.ERDiagram, Description: "Show ER diagram"},
+			Bind{Key: Key{Char: 'a'}, Cmd: cmd.AttachDatabase, Description: "Attach a SQLite database"},
 		},
 		TreeFilterGroup: {
 			Bind{Key: Key{Code: tcell.KeyEscape}, Cmd: cmd.UnfocusTreeFilter, Description: "Unfocus tree filter"},
 			Bind{Key: Key{Code: tcell.KeyEnter}, Cmd: cmd.CommitTreeFilter, Description: "Commit tree filter search"},
 		},
 		TableGroup: {
+			Bind{Key: Key{Char: 'j'}, Cmd: cmd.MoveDown, Description: "Go down"},
+			Bind{Key: Key{Code: tcell.KeyDown}, Cmd: cmd.MoveDown, Description: "Go down"},
+			Bind{Key: Key{Char: 'k'}, Cmd: cmd.MoveUp, Description: "Go up"},
+			Bind{Key: Key{Code: tcell.KeyUp}, Cmd: cmd.MoveUp, Description: "Go up"},
+			Bind{Key: Key{Char: 'h'}, Cmd: cmd.MoveLeft, Description: "Go left"},
+			Bind{Key: Key{Code: tcell.KeyLeft}, Cmd: cmd.MoveLeft, Description: "Go left"},
+			Bind{Key: Key{Char: 'l'}, Cmd: cmd.MoveRight, Description: "Go right"},
+			Bind{Key: Key{Code: tcell.KeyRight}, Cmd: cmd.MoveRight, Description: "Go right"},
+			Bind{Key: Key{Char: 'g'}, Cmd: cmd.GotoTop, Description: "Go to first row"},
+			Bind{Key: Key{Char: 'G'}, Cmd: cmd.GotoBottom, Description: "Go to last row"},
+			Bind{Key: Key{Code: tcell.KeyCtrlD}, Cmd: cmd.ScrollDown, Description: "Scroll down"},
+			Bind{Key: Key{Code: tcell.KeyCtrlU}, Cmd: cmd.ScrollUp, Description: "Scroll up"},
 			Bind{Key: Key{Char: '/'}, Cmd: cmd.Search, Description: "Search"},
 			Bind{Key: Key{Char: 'c'}, Cmd: cmd.Edit, Description: "Change cell"},
 			Bind{Key: Key{Char: 'd'}, Cmd: cmd.Delete, Description: "Delete row"},
@@ -120,6 +316,10 @@ var Keymaps = KeymapSystem{
 			Bind{Key: Key{Char: '3'}, Cmd: cmd.ConstraintsMenu, Description: "Switch to constraints menu"},
 			Bind{Key: Key{Char: '4'}, Cmd: cmd.ForeignKeysMenu, Description: "Switch to foreign keys menu"},
 			Bind{Key: Key{Char: '5'}, Cmd: cmd.IndexesMenu, Description: "Switch to indexes menu"},
+			Bind{Key: Key{Char: '6'}, Cmd: cmd.ExtraMenu1, Description: "Switch to the first driver menu"},
+			Bind{Key: Key{Char: '7'}, Cmd: cmd.ExtraMenu2, Description: "Switch to the second driver menu"},
+			Bind{Key: Key{Char: '8'}, Cmd: cmd.ExtraMenu3, Description: "Switch to the third driver menu"},
+			Bind{Key: Key{Char: '9'}, Cmd: cmd.ExtraMenu4, Description: "Switch to the fourth driver menu"},
 			// Sidebar
 			Bind{Key: Key{Char: 'S'}, Cmd: cmd.ToggleSidebar, Description: "Toggle sidebar"},
 			Bind{Key: Key{Char: 's'}, Cmd: cmd.FocusSidebar, Description: "Focus sidebar"},
@@ -148,5 +348,41 @@ var Keymaps = KeymapSystem{
 			Bind{Key: Key{Char: 'y'}, Cmd: cmd.Copy, Description: "Copy query to clipboard"},
 			Bind{Key: Key{Char: 'd'}, Cmd: cmd.Delete, Description: "Delete query"},
 		},
+		SchemaDiffGroup: {
+			Bind{Key: Key{Code: tcell.KeyTab}, Cmd: cmd.GotoNext, Description: "Switch between diff and migration"},
+			Bind{Key: Key{Char: 'y'}, Cmd: cmd.Copy, Description: "Copy migration to clipboard"},
+			Bind{Key: Key{Char: 'q'}, Cmd: cmd.Quit, Description: "Close"},
+		},
+		DataDiffGroup: {
+			Bind{Key: Key{Code: tcell.KeyCtrlS}, Cmd: cmd.Save, Description: "Queue changes to sync the target"},
+			Bind{Key: Key{Char: 'q'}, Cmd: cmd.Quit, Description: "Close"},
+		},
+		ERDiagramGroup: {
+			Bind{Key: Key{Char: 'h'}, Cmd: cmd.MoveLeft, Description: "Pan left"},
+			Bind{Key: Key{Char: 'l'}, Cmd: cmd.MoveRight, Description: "Pan right"},
+			Bind{Key: Key{Char: 'k'}, Cmd: cmd.MoveUp, Description: "Pan up"},
+			Bind{Key: Key{Char: 'j'}, Cmd: cmd.MoveDown, Description: "Pan down"},
+			Bind{Key: Key{Code: tcell.KeyTab}, Cmd: cmd.GotoNext, Description: "Select next table"},
+			Bind{Key: Key{Code: tcell.KeyBacktab}, Cmd: cmd.GotoPrev, Description: "Select previous table"},
+			Bind{Key: Key{Code: tcell.KeyEnter}, Cmd: cmd.Execute, Description: "Open table"},
+			Bind{Key: Key{Char: 'm'}, Cmd: cmd.ExportMermaid, Description: "Copy as Mermaid"},
+			Bind{Key: Key{Char: 'D'}, Cmd: cmd.ExportDOT, Description: "Copy as Graphviz DOT"},
+			Bind{Key: Key{Char: 'q'}, Cmd: cmd.Quit, Description: "Close"},
+		},
 	},
 }
+
+// defaultGroups are the default bindings, kept to list the commands unbound in
+// the configuration.
+var defaultGroups = cloneGroups(Keymaps.Groups)
+
+// cloneGroups returns a copy of the groups that Configure does not change.
+func cloneGroups(groups map[string]Map) map[string]Map {
+	clone := make(map[string]Map, len(groups))
+
+	for name, group := range groups {
+		clone[name] = slices.Clone(group)
+	}
+
+	return clone
+}
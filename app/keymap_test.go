@@ -0,0 +1,198 @@
+package app
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+
+	cmd "github.com/jorgerojas26/lazysql/commands"
+)
+
+func newTestKeymapSystem() KeymapSystem {
+	return KeymapSystem{
+		Groups: map[string]Map{
+			TableGroup: {
+				Bind{Key: Key{Char: 'g'}, Cmd: cmd.GotoTop, Description: "Go to first row"},
+				Bind{Key: Key{Char: 'G'}, Cmd: cmd.GotoBottom, Description: "Go to last row"},
+				Bind{Key: Key{Char: 'y'}, Cmd: cmd.Copy, Description: "Copy cell value to clipboard"},
+				Bind{Key: Key{Char: 'd'}, Cmd: cmd.Delete, Description: "Delete row"},
+			},
+		},
+	}
+}
+
+// groupKeys returns the keys of the commands of the group, in order.
+func groupKeys(group Map) map[cmd.Command][]string {
+	keys := make(map[cmd.Command][]string)
+
+	for _, bind := range group {
+		keys[bind.Cmd] = append(keys[bind.Cmd], bind.Keys().String())
+	}
+
+	return keys
+}
+
+func TestKeymapSystem_Configure(t *testing.T) {
+	testCases := []struct {
+		name     string
+		config   map[string]map[string]any
+		expected map[cmd.Command][]string
+		errors   []string
+	}{
+		{
+			name:   "sequence and list",
+			config: map[string]map[string]any{TableGroup: {"GotoTop": "gg", "Copy": []any{"yy", "<C-c>"}}},
+			expected: map[cmd.Command][]string{
+				cmd.GotoTop:    {"gg"},
+				cmd.Copy:       {"yy", "<Ctrl-C>"},
+				cmd.GotoBottom: {"G"},
+				cmd.Delete:     {"d"},
+			},
+		},
+		{
+			name:   "empty list unbinds",
+			config: map[string]map[string]any{TableGroup: {"Delete": []any{}}},
+			expected: map[cmd.Command][]string{
+				cmd.GotoTop:    {"g"},
+				cmd.GotoBottom: {"G"},
+				cmd.Copy:       {"y"},
+			},
+		},
+		{
+			name:   "unknown group and command",
+			config: map[string]map[string]any{"nowhere": {"GotoTop": "gg"}, TableGroup: {"Fly": "f", "Copy": "Y"}},
+			expected: map[cmd.Command][]string{
+				cmd.GotoTop:    {"g"},
+				cmd.GotoBottom: {"G"},
+				cmd.Copy:       {"Y"},
+				cmd.Delete:     {"d"},
+			},
+			errors: []string{`unknown group "nowhere"`, `keymap.table: unknown command "Fly"`},
+		},
+		{
+			name:   "invalid keys keep the defaults",
+			config: map[string]map[string]any{TableGroup: {"Copy": []any{"yy", "<Hyper>"}, "Delete": 4}},
+			expected: map[cmd.Command][]string{
+				cmd.GotoTop:    {"g"},
+				cmd.GotoBottom: {"G"},
+				cmd.Copy:       {"y"},
+				cmd.Delete:     {"d"},
+			},
+			errors: []string{"keymap.table.Copy: unknown key <Hyper>", "keymap.table.Delete: invalid keys 4"},
+		},
+		{
+			name:   "prefix conflict",
+			config: map[string]map[string]any{TableGroup: {"Copy": "gy"}},
+			expected: map[cmd.Command][]string{
+				cmd.Copy:       {"gy"},
+				cmd.GotoTop:    {"g"},
+				cmd.GotoBottom: {"G"},
+				cmd.Delete:     {"d"},
+			},
+			errors: []string{"keymap.table: gy of Copy conflicts with g of GotoTop"},
+		},
+		{
+			name:   "same key",
+			config: map[string]map[string]any{TableGroup: {"Delete": "G"}},
+			expected: map[cmd.Command][]string{
+				cmd.Delete:     {"G"},
+				cmd.GotoTop:    {"g"},
+				cmd.GotoBottom: {"G"},
+				cmd.Copy:       {"y"},
+			},
+			errors: []string{"keymap.table: G of Delete conflicts with G of GotoBottom"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			keymaps := newTestKeymapSystem()
+
+			err := keymaps.Configure(tc.config)
+
+			if len(tc.errors) == 0 && err != nil {
+				t.Fatalf("expected no error, but got %v", err)
+			}
+
+			for _, expected := range tc.errors {
+				if err == nil || !strings.Contains(err.Error(), expected) {
+					t.Fatalf("expected the error to contain %q, but got %v", expected, err)
+				}
+			}
+
+			if result := groupKeys(keymaps.Group(TableGroup)); !reflect.DeepEqual(result, tc.expected) {
+				t.Fatalf("expected %v, but got %v", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestKeymapSystem_Configure_KeepsDescriptions(t *testing.T) {
+	keymaps := newTestKeymapSystem()
+
+	if err := keymaps.Configure(map[string]map[string]any{TableGroup: {"GotoTop": "<Home>"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	bind := keymaps.Group(TableGroup)[0]
+	if bind.Key != (Key{Code: tcell.KeyHome}) || bind.Description != "Go to first row" {
+		t.Fatalf("expected <Home> to go to the first row, but got %v %q", bind, bind.Description)
+	}
+}
+
+func TestKeymapSystem_Commands(t *testing.T) {
+	keymaps := KeymapSystem{Groups: cloneGroups(Keymaps.Groups)}
+
+	config := map[string]map[string]any{ConnectionGroup: {"NewConnection": []any{}, "EditConnection": []any{"E", "<C-e>"}}}
+	if err := keymaps.Configure(config); err != nil {
+		t.Fatal(err)
+	}
+
+	commands := keymaps.Commands(ConnectionGroup)
+
+	// The commands keep the order of the defaults, bound or not.
+	if commands[0].Cmd != cmd.NewConnection || commands[0].Keys != nil || commands[0].Description != "Create a new database connection" {
+		t.Fatalf("expected NewConnection to be listed first without keys, but got %+v", commands[0])
+	}
+
+	for _, command := range commands {
+		if command.Cmd == cmd.EditConnection && command.Keys.String() != "E" {
+			t.Fatalf("expected EditConnection to be bound to E, but got %s", command.Keys)
+		}
+	}
+
+	if len(commands) != len(Keymaps.Commands(ConnectionGroup)) {
+		t.Fatalf("expected %d commands, but got %d", len(Keymaps.Commands(ConnectionGroup)), len(commands))
+	}
+}
+
+func TestConfigKeys(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    any
+		expected []string
+		wantErr  bool
+	}{
+		{name: "string", value: "gg", expected: []string{"gg"}},
+		{name: "list", value: []any{"j", "<Down>"}, expected: []string{"j", "<Down>"}},
+		{name: "empty list", value: []any{}, expected: []string{}},
+		{name: "number", value: int64(5), wantErr: true},
+		{name: "list with a number", value: []any{"j", int64(5)}, wantErr: true},
+		{name: "table", value: map[string]any{"key": "j"}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			keys, err := configKeys(tc.value)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected an error to be %v, but got %v", tc.wantErr, err)
+			}
+
+			if !reflect.DeepEqual(keys, tc.expected) {
+				t.Fatalf("expected %q, but got %q", tc.expected, keys)
+			}
+		})
+	}
+}
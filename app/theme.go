@@ -0,0 +1,181 @@
+package app
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// DefaultTheme is the theme used when the configuration does not name one.
+const DefaultTheme = "dark"
+
+// Themes are the built-in themes, by name.
+var Themes = map[string]Theme{
+	"dark": {
+		Theme: tview.Theme{
+			PrimitiveBackgroundColor:    tcell.ColorDefault,
+			ContrastBackgroundColor:     tcell.ColorBlue,
+			MoreContrastBackgroundColor: tcell.ColorGreen,
+			BorderColor:                 tcell.ColorWhite,
+			TitleColor:                  tcell.ColorWhite,
+			GraphicsColor:               tcell.ColorGray,
+			PrimaryTextColor:            tcell.ColorDefault.TrueColor(),
+			SecondaryTextColor:          tcell.ColorYellow,
+			TertiaryTextColor:           tcell.ColorGreen,
+			InverseTextColor:            tcell.ColorWhite,
+			ContrastSecondaryTextColor:  tcell.ColorBlack,
+		},
+		SidebarTitleBorderColor: tcell.GetColor("#666A7E"),
+		ChangedRowColor:         tcell.ColorOrange,
+		InsertedRowColor:        tcell.ColorDarkGreen,
+		DeletedRowColor:         tcell.ColorRed,
+		SelectedTextColor:       tcell.ColorBlack,
+		SelectedBackgroundColor: tcell.ColorYellow,
+		ErrorColor:              tcell.ColorRed,
+		WarningColor:            tcell.ColorYellow,
+		SuccessColor:            tcell.ColorGreen,
+	},
+	"light": {
+		Theme: tview.Theme{
+			PrimitiveBackgroundColor:    tcell.ColorDefault,
+			ContrastBackgroundColor:     tcell.ColorLightBlue,
+			MoreContrastBackgroundColor: tcell.ColorLightGreen,
+			BorderColor:                 tcell.ColorBlack,
+			TitleColor:                  tcell.ColorBlack,
+			GraphicsColor:               tcell.ColorGray,
+			PrimaryTextColor:            tcell.ColorDefault.TrueColor(),
+			SecondaryTextColor:          tcell.ColorDarkBlue,
+			TertiaryTextColor:           tcell.ColorDarkGreen,
+			InverseTextColor:            tcell.ColorGray,
+			ContrastSecondaryTextColor:  tcell.ColorWhite,
+		},
+		SidebarTitleBorderColor: tcell.ColorSilver,
+		ChangedRowColor:         tcell.ColorSandyBrown,
+		InsertedRowColor:        tcell.ColorLightGreen,
+		DeletedRowColor:         tcell.ColorLightCoral,
+		SelectedTextColor:       tcell.ColorWhite,
+		SelectedBackgroundColor: tcell.ColorDarkBlue,
+		ErrorColor:              tcell.ColorDarkRed,
+		WarningColor:            tcell.ColorDarkGoldenrod,
+		SuccessColor:            tcell.ColorDarkGreen,
+	},
+	"high-contrast": {
+		Theme: tview.Theme{
+			PrimitiveBackgroundColor:    tcell.ColorBlack,
+			ContrastBackgroundColor:     tcell.ColorBlue,
+			MoreContrastBackgroundColor: tcell.ColorGreen,
+			BorderColor:                 tcell.ColorWhite,
+			TitleColor:                  tcell.ColorWhite,
+			GraphicsColor:               tcell.ColorWhite,
+			PrimaryTextColor:            tcell.ColorWhite,
+			SecondaryTextColor:          tcell.ColorYellow,
+			TertiaryTextColor:           tcell.ColorAqua,
+			InverseTextColor:            tcell.ColorSilver,
+			ContrastSecondaryTextColor:  tcell.ColorBlack,
+		},
+		SidebarTitleBorderColor: tcell.ColorWhite,
+		ChangedRowColor:         tcell.ColorDarkOrange,
+		InsertedRowColor:        tcell.ColorGreen,
+		DeletedRowColor:         tcell.ColorRed,
+		SelectedTextColor:       tcell.ColorBlack,
+		SelectedBackgroundColor: tcell.ColorYellow,
+		ErrorColor:              tcell.ColorRed,
+		WarningColor:            tcell.ColorYellow,
+		SuccessColor:            tcell.ColorLime,
+	},
+	"solarized": {
+		Theme: tview.Theme{
+			PrimitiveBackgroundColor:    tcell.GetColor("#002b36"),
+			ContrastBackgroundColor:     tcell.GetColor("#073642"),
+			MoreContrastBackgroundColor: tcell.GetColor("#586e75"),
+			BorderColor:                 tcell.GetColor("#93a1a1"),
+			TitleColor:                  tcell.GetColor("#93a1a1"),
+			GraphicsColor:               tcell.GetColor("#586e75"),
+			PrimaryTextColor:            tcell.GetColor("#93a1a1"),
+			SecondaryTextColor:          tcell.GetColor("#b58900"),
+			TertiaryTextColor:           tcell.GetColor("#859900"),
+			InverseTextColor:            tcell.GetColor("#657b83"),
+			ContrastSecondaryTextColor:  tcell.GetColor("#002b36"),
+		},
+		SidebarTitleBorderColor: tcell.GetColor("#586e75"),
+		ChangedRowColor:         tcell.GetColor("#cb4b16"),
+		InsertedRowColor:        tcell.GetColor("#2aa198"),
+		DeletedRowColor:         tcell.GetColor("#dc322f"),
+		SelectedTextColor:       tcell.GetColor("#002b36"),
+		SelectedBackgroundColor: tcell.GetColor("#b58900"),
+		ErrorColor:              tcell.GetColor("#dc322f"),
+		WarningColor:            tcell.GetColor("#b58900"),
+		SuccessColor:            tcell.GetColor("#859900"),
+	},
+}
+
+// colors returns the colours of the theme by the names they have in the
+// configuration.
+func (theme *Theme) colors() map[string]*tcell.Color {
+	return map[string]*tcell.Color{
+		"PrimitiveBackgroundColor":    &theme.PrimitiveBackgroundColor,
+		"ContrastBackgroundColor":     &theme.ContrastBackgroundColor,
+		"MoreContrastBackgroundColor": &theme.MoreContrastBackgroundColor,
+		"BorderColor":                 &theme.BorderColor,
+		"TitleColor":                  &theme.TitleColor,
+		"GraphicsColor":               &theme.GraphicsColor,
+		"PrimaryTextColor":            &theme.PrimaryTextColor,
+		"SecondaryTextColor":          &theme.SecondaryTextColor,
+		"TertiaryTextColor":           &theme.TertiaryTextColor,
+		"InverseTextColor":            &theme.InverseTextColor,
+		"ContrastSecondaryTextColor":  &theme.ContrastSecondaryTextColor,
+		"SidebarTitleBorderColor":     &theme.SidebarTitleBorderColor,
+		"ChangedRowColor":             &theme.ChangedRowColor,
+		"InsertedRowColor":            &theme.InsertedRowColor,
+		"DeletedRowColor":             &theme.DeletedRowColor,
+		"SelectedTextColor":           &theme.SelectedTextColor,
+		"SelectedBackgroundColor":     &theme.SelectedBackgroundColor,
+		"ErrorColor":                  &theme.ErrorColor,
+		"WarningColor":                &theme.WarningColor,
+		"SuccessColor":                &theme.SuccessColor,
+	}
+}
+
+// SetTheme uses the theme of the configuration, the built-in theme it names
+// with Name and the colours it overrides, written as names like "red" or as
+// hexadecimal like "#ff0000".
+func SetTheme(config map[string]string) error {
+	name := DefaultTheme
+	if config["Name"] != "" {
+		name = config["Name"]
+	}
+
+	theme, ok := Themes[name]
+	if !ok {
+		return fmt.Errorf("theme: unknown theme %q, expected one of %s", name, strings.Join(slices.Sorted(maps.Keys(Themes)), ", "))
+	}
+
+	colors := theme.colors()
+
+	for _, key := range slices.Sorted(maps.Keys(config)) {
+		if key == "Name" {
+			continue
+		}
+
+		color, ok := colors[key]
+		if !ok {
+			return fmt.Errorf("theme: unknown colour %q", key)
+		}
+
+		value := config[key]
+
+		*color = tcell.GetColor(value)
+		if *color == tcell.ColorDefault && value != "default" {
+			return fmt.Errorf("theme.%s: invalid colour %q", key, value)
+		}
+	}
+
+	*Styles = theme
+	tview.Styles = Styles.Theme
+
+	return nil
+}
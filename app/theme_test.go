@@ -0,0 +1,171 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/pelletier/go-toml/v2"
+	"github.com/rivo/tview"
+)
+
+// keepStyles restores the styles changed by the test when it ends.
+func keepStyles(t *testing.T) {
+	t.Helper()
+
+	styles, theme := *Styles, tview.Styles
+
+	t.Cleanup(func() {
+		*Styles = styles
+		tview.Styles = theme
+	})
+}
+
+func TestSetTheme(t *testing.T) {
+	testCases := []struct {
+		name     string
+		config   string
+		expected func() Theme
+	}{
+		{
+			name:     "no theme",
+			config:   "",
+			expected: func() Theme { return Themes[DefaultTheme] },
+		},
+		{
+			name:     "built-in theme",
+			config:   "[theme]\nName = \"solarized\"\n",
+			expected: func() Theme { return Themes["solarized"] },
+		},
+		{
+			name:   "overridden colours",
+			config: "[theme]\nName = \"light\"\nErrorColor = \"#ff0000\"\nBorderColor = \"navy\"\nPrimaryTextColor = \"default\"\n",
+			expected: func() Theme {
+				theme := Themes["light"]
+				theme.ErrorColor = tcell.GetColor("#ff0000")
+				theme.BorderColor = tcell.ColorNavy
+				theme.PrimaryTextColor = tcell.ColorDefault
+
+				return theme
+			},
+		},
+		{
+			name:   "overridden colours of the default theme",
+			config: "[theme]\nSuccessColor = \"lime\"\n",
+			expected: func() Theme {
+				theme := Themes[DefaultTheme]
+				theme.SuccessColor = tcell.ColorLime
+
+				return theme
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			keepStyles(t)
+
+			var config Config
+			if err := toml.Unmarshal([]byte(tc.config), &config); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := SetTheme(config.Theme); err != nil {
+				t.Fatalf("expected no error, but got %v", err)
+			}
+
+			if expected := tc.expected(); *Styles != expected || tview.Styles != expected.Theme {
+				t.Fatalf("expected %+v, but got %+v", expected, *Styles)
+			}
+		})
+	}
+}
+
+func TestSetTheme_Errors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		config   map[string]string
+		expected string
+	}{
+		{
+			name:     "unknown theme",
+			config:   map[string]string{"Name": "neon"},
+			expected: `unknown theme "neon", expected one of dark, high-contrast, light, solarized`,
+		},
+		{
+			name:     "unknown colour",
+			config:   map[string]string{"Name": "dark", "BackgroundColour": "red"},
+			expected: `unknown colour "BackgroundColour"`,
+		},
+		{
+			name:     "invalid colour",
+			config:   map[string]string{"ErrorColor": "#zzzzzz"},
+			expected: `theme.ErrorColor: invalid colour "#zzzzzz"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			keepStyles(t)
+
+			styles := *Styles
+
+			err := SetTheme(tc.config)
+			if err == nil || !strings.Contains(err.Error(), tc.expected) {
+				t.Fatalf("expected the error to contain %q, but got %v", tc.expected, err)
+			}
+
+			// The styles are left as they were.
+			if *Styles != styles {
+				t.Fatalf("expected the styles not to change, but got %+v", *Styles)
+			}
+		})
+	}
+}
+
+func TestSetTheme_KeepsBuiltInThemes(t *testing.T) {
+	keepStyles(t)
+
+	dark := Themes["dark"]
+
+	if err := SetTheme(map[string]string{"Name": "dark", "ErrorColor": "blue"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if Themes["dark"] != dark {
+		t.Fatal("expected the colours set in the configuration not to change the built-in theme")
+	}
+}
+
+func TestLoadConfig_InvalidTheme(t *testing.T) {
+	keepStyles(t)
+
+	config := *App.config
+	t.Cleanup(func() { *App.config = config })
+
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+
+	if err := os.MkdirAll(filepath.Join(dir, "lazysql"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "lazysql", "config.toml"), []byte("[theme]\nName = \"neon\"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SetTheme(map[string]string{"Name": "light"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The error is logged and the default theme is used.
+	if err := LoadConfig(); err != nil {
+		t.Fatalf("expected no error, but got %v", err)
+	}
+
+	if expected := Themes[DefaultTheme]; *Styles != expected || tview.Styles != expected.Theme {
+		t.Fatalf("expected the default theme, but got %+v", *Styles)
+	}
+}
@@ -9,6 +9,7 @@ const (
 	SwitchToEditorView
 	SwitchToConnectionsView
 	HelpPopup
+	CommandPalette
 
 	// Movement: Basic
 	MoveUp
@@ -26,6 +27,8 @@ const (
 	// Movement: Page
 	PageNext
 	PagePrev
+	ScrollDown
+	ScrollUp
 
 	// Menu:
 	RecordsMenu
@@ -33,6 +36,10 @@ const (
 	ConstraintsMenu
 	ForeignKeysMenu
 	IndexesMenu
+	ExtraMenu1
+	ExtraMenu2
+	ExtraMenu3
+	ExtraMenu4
 
 	// Tabs
 	TabNext
@@ -68,6 +75,15 @@ const (
 	FocusSidebar
 	UnfocusSidebar
 	ToggleSidebar
+	SchemaDiff
+	DataDiff
+	CopyTable
+	Dump
+	Restore
+	ERDiagram
+	ExportMermaid
+	ExportDOT
+	AttachDatabase
 
 	// Connection
 	NewConnection
@@ -75,8 +91,24 @@ const (
 	TestConnection
 	EditConnection
 	DeleteConnection
+	SwitchConnection
+	NextConnection
+	PreviousConnection
+	Disconnect
+	ShowCommands
 )
 
+// Parse returns the command named name, as returned by String.
+func Parse(name string) (Command, bool) {
+	for c := Noop + 1; c.String() != "Unknown"; c++ {
+		if c.String() == name {
+			return c, true
+		}
+	}
+
+	return Noop, false
+}
+
 func (c Command) String() string {
 	switch c {
 	case Noop:
@@ -88,6 +120,8 @@ func (c Command) String() string {
 		return "SwitchToConnectionsView"
 	case HelpPopup:
 		return "HelpPopup"
+	case CommandPalette:
+		return "CommandPalette"
 
 	// Movement: Basic
 	case MoveUp:
@@ -117,6 +151,10 @@ func (c Command) String() string {
 		return "PageNext"
 	case PagePrev:
 		return "PagePrev"
+	case ScrollDown:
+		return "ScrollDown"
+	case ScrollUp:
+		return "ScrollUp"
 
 	// Tabs
 	case TabNext:
@@ -165,6 +203,16 @@ func (c Command) String() string {
 		return "EditConnection"
 	case DeleteConnection:
 		return "DeleteConnection"
+	case SwitchConnection:
+		return "SwitchConnection"
+	case NextConnection:
+		return "NextConnection"
+	case PreviousConnection:
+		return "PreviousConnection"
+	case Disconnect:
+		return "Disconnect"
+	case ShowCommands:
+		return "ShowCommands"
 	case Refresh:
 		return "Refresh"
 	case UnfocusEditor:
@@ -179,6 +227,14 @@ func (c Command) String() string {
 		return "ForeignKeysMenu"
 	case IndexesMenu:
 		return "IndexesMenu"
+	case ExtraMenu1:
+		return "ExtraMenu1"
+	case ExtraMenu2:
+		return "ExtraMenu2"
+	case ExtraMenu3:
+		return "ExtraMenu3"
+	case ExtraMenu4:
+		return "ExtraMenu4"
 	case UnfocusTreeFilter:
 		return "UnfocusTreeFilter"
 	case CommitTreeFilter:
@@ -203,6 +259,24 @@ func (c Command) String() string {
 		return "CommitEdit"
 	case DiscardEdit:
 		return "DiscardEdit"
+	case SchemaDiff:
+		return "SchemaDiff"
+	case DataDiff:
+		return "DataDiff"
+	case CopyTable:
+		return "CopyTable"
+	case Dump:
+		return "Dump"
+	case Restore:
+		return "Restore"
+	case ERDiagram:
+		return "ERDiagram"
+	case ExportMermaid:
+		return "ExportMermaid"
+	case ExportDOT:
+		return "ExportDOT"
+	case AttachDatabase:
+		return "AttachDatabase"
 	}
 
 	return "Unknown"
@@ -16,7 +16,8 @@ func InitFromArg(connectionString string) error {
 	}
 	DBName := strings.Split(parsed.Normalize(",", "NULL", 0), ",")[3]
 
-	if DBName == "NULL" {
+	// The files of a files URL are not databases.
+	if DBName == "NULL" || parsed.Driver == drivers.DriverFiles {
 		DBName = ""
 	}
 
@@ -37,13 +38,24 @@ func InitFromArg(connectionString string) error {
 		newDBDriver = &drivers.SQLite{}
 	case drivers.DriverClickhouse:
 		newDBDriver = &drivers.Clickhouse{}
+	case drivers.DriverMSSQL:
+		newDBDriver = &drivers.MSSQL{}
+	case drivers.DriverDuckDB:
+		newDBDriver = &drivers.DuckDB{}
+	case drivers.DriverFiles:
+		newDBDriver = &drivers.Files{}
+	case drivers.DriverOracle:
+		newDBDriver = &drivers.Oracle{}
 	}
 
 	err = newDBDriver.Connect(connection.URL)
 	if err != nil {
 		return fmt.Errorf("could not connect to database %s: %s", connectionString, err)
 	}
-	mainPages.AddAndSwitchToPage(connection.URL, NewHomePage(connection, newDBDriver).Flex, true)
+	home := NewHomePage(connection, newDBDriver)
+	newConnectionHealth(App.Context(), "", nil).watch(home)
+
+	mainPages.AddAndSwitchToPage(connection.URL, home.Flex, true)
 
 	return nil
 }
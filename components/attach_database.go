@@ -0,0 +1,126 @@
+package components
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/jorgerojas26/lazysql/app"
+	"github.com/jorgerojas26/lazysql/drivers"
+)
+
+type AttachDatabaseForm struct {
+	*tview.Flex
+	Form       *tview.Form
+	StatusText *tview.TextView
+	Home       *Home
+	attaching  bool
+}
+
+// NewAttachDatabaseForm asks for a database file attached to the current
+// connection.
+func NewAttachDatabaseForm(home *Home) *AttachDatabaseForm {
+	form := tview.NewForm().SetFieldBackgroundColor(app.Styles.InverseTextColor).SetButtonBackgroundColor(tview.Styles.InverseTextColor).SetLabelColor(tview.Styles.PrimaryTextColor).SetFieldTextColor(tview.Styles.ContrastSecondaryTextColor)
+
+	nameField := tview.NewInputField().SetLabel("Name")
+
+	form.AddInputField("File", "", 0, nil, func(text string) {
+		nameField.SetText(attachedDatabaseName(text))
+	})
+	form.AddFormItem(nameField)
+
+	statusText := tview.NewTextView()
+	statusText.SetBorderPadding(0, 0, 1, 1)
+	statusText.SetText("Enter to attach, Esc to cancel")
+	statusText.SetTextColor(app.Styles.TertiaryTextColor)
+
+	container := tview.NewFlex().SetDirection(tview.FlexRow)
+	container.SetBorder(true)
+	container.SetTitle(" Attach database ")
+	container.AddItem(form, 0, 1, true)
+	container.AddItem(statusText, 1, 0, false)
+
+	wrapper := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(container, 9, 0, true).
+			AddItem(nil, 0, 1, false), 0, 2, true).
+		AddItem(nil, 0, 1, false)
+
+	attachDatabaseForm := &AttachDatabaseForm{
+		Flex:       wrapper,
+		Form:       form,
+		StatusText: statusText,
+		Home:       home,
+	}
+
+	form.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEsc:
+			mainPages.RemovePage(pageNameAttachDatabase)
+			return nil
+		case tcell.KeyEnter:
+			if attachDatabaseForm.attaching {
+				return nil
+			}
+
+			file := form.GetFormItemByLabel("File").(*tview.InputField).GetText()
+			name := nameField.GetText()
+
+			if file == "" || name == "" {
+				attachDatabaseForm.setStatus("File and name are required", app.Styles.ErrorColor)
+				return nil
+			}
+
+			attachDatabaseForm.attaching = true
+
+			go attachDatabaseForm.attach(file, name)
+			return nil
+		}
+
+		return event
+	})
+
+	return attachDatabaseForm
+}
+
+func (form *AttachDatabaseForm) attach(file, name string) {
+	defer func() {
+		form.attaching = false
+	}()
+
+	attacher, ok := drivers.Unwrap(form.Home.DBDriver).(drivers.DatabaseAttacher)
+	if !ok {
+		form.setStatus("The current connection can not attach databases", app.Styles.ErrorColor)
+		return
+	}
+
+	if err := attacher.AttachDatabase(file, name); err != nil {
+		form.setStatus(err.Error(), app.Styles.ErrorColor)
+		return
+	}
+
+	form.Home.Tree.Refresh(form.Home.Connection.DBName)
+
+	mainPages.RemovePage(pageNameAttachDatabase)
+	App.Draw()
+}
+
+func (form *AttachDatabaseForm) setStatus(text string, color tcell.Color) {
+	form.StatusText.SetText(text).SetTextColor(color)
+	App.Draw()
+}
+
+// attachedDatabaseName suggests a schema name for a database file, its base
+// name without extension and with dots replaced.
+func attachedDatabaseName(file string) string {
+	name := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	if name == "." || name == string(filepath.Separator) {
+		return ""
+	}
+
+	return strings.ReplaceAll(name, ".", "_")
+}
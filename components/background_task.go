@@ -0,0 +1,53 @@
+package components
+
+import (
+	"context"
+	"sync"
+)
+
+// backgroundTask is a task a form runs in the background, like a copy or a
+// dump. It is started and cancelled from the UI while the task ends on its own
+// goroutine.
+type backgroundTask struct {
+	mu     sync.Mutex
+	cancel context.CancelFunc
+}
+
+// start returns the context of a new task, ok is false if one is running.
+func (task *backgroundTask) start() (ctx context.Context, ok bool) {
+	task.mu.Lock()
+	defer task.mu.Unlock()
+
+	if task.cancel != nil {
+		return nil, false
+	}
+
+	ctx, task.cancel = context.WithCancel(App.Context())
+
+	return ctx, true
+}
+
+// stop cancels the running task, it reports whether there was one.
+func (task *backgroundTask) stop() bool {
+	task.mu.Lock()
+	defer task.mu.Unlock()
+
+	if task.cancel == nil {
+		return false
+	}
+
+	task.cancel()
+
+	return true
+}
+
+// done releases the task once it has ended.
+func (task *backgroundTask) done() {
+	task.mu.Lock()
+	defer task.mu.Unlock()
+
+	if task.cancel != nil {
+		task.cancel()
+		task.cancel = nil
+	}
+}
@@ -0,0 +1,332 @@
+package components
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/lithammer/fuzzysearch/fuzzy"
+	"github.com/rivo/tview"
+
+	"github.com/jorgerojas26/lazysql/app"
+	"github.com/jorgerojas26/lazysql/commands"
+	"github.com/jorgerojas26/lazysql/drivers"
+	"github.com/jorgerojas26/lazysql/helpers/logger"
+)
+
+// paletteEntry is an action listed in the command palette.
+type paletteEntry struct {
+	label string
+	// key is the key the command is bound to, if any.
+	key string
+	// kind is the keymap group of a command, or what the entry opens.
+	kind string
+	// search is the text the filter is matched with.
+	search string
+	run    func()
+}
+
+// CommandPalette is a fuzzy finder of the commands available where the focus
+// is, the tables of the connection, the connections and the saved queries.
+type CommandPalette struct {
+	*tview.Flex
+	Input *tview.InputField
+	Table *tview.Table
+
+	entries []paletteEntry
+	// shown are the entries matching the filter, in the rows of the table.
+	shown []paletteEntry
+}
+
+// NewCommandPalette returns the palette of the home page of a connection, or
+// of the list of connections when home is nil.
+func NewCommandPalette(home *Home) *CommandPalette {
+	input := tview.NewInputField()
+	input.SetLabel("> ")
+	input.SetLabelColor(app.Styles.TertiaryTextColor)
+	input.SetFieldStyle(tcell.StyleDefault.Background(app.Styles.PrimitiveBackgroundColor).Foreground(app.Styles.PrimaryTextColor))
+	input.SetPlaceholder("Type a command, a table or a connection...")
+	input.SetPlaceholderStyle(tcell.StyleDefault.Background(app.Styles.PrimitiveBackgroundColor).Foreground(app.Styles.InverseTextColor))
+
+	table := tview.NewTable()
+	table.SetSelectable(true, false)
+	table.SetSelectedStyle(tcell.StyleDefault.Background(app.Styles.SecondaryTextColor).Foreground(app.Styles.ContrastSecondaryTextColor))
+
+	commandEntries := paletteCommands(home)
+
+	palette := &CommandPalette{
+		Input:   input,
+		Table:   table,
+		entries: append(commandEntries, paletteEntries(home)...),
+	}
+
+	// The tables are listed after the commands once the driver returns them.
+	if home != nil {
+		go func() {
+			tables := paletteTables(home)
+
+			App.QueueUpdateDraw(func() {
+				palette.entries = slices.Insert(palette.entries, len(commandEntries), tables...)
+				palette.filter(palette.Input.GetText())
+			})
+		}()
+	}
+
+	previous := App.GetFocus()
+
+	closePalette := func() {
+		mainPages.RemovePage(pageNameCommandPalette)
+		App.SetFocus(previous)
+	}
+
+	input.SetChangedFunc(palette.filter)
+
+	input.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		row, _ := table.GetSelection()
+
+		switch event.Key() {
+		case tcell.KeyEsc:
+			closePalette()
+		case tcell.KeyEnter:
+			if row >= 0 && row < len(palette.shown) {
+				closePalette()
+				palette.shown[row].run()
+			}
+		case tcell.KeyDown, tcell.KeyCtrlN, tcell.KeyTab:
+			if row < len(palette.shown)-1 {
+				table.Select(row+1, 0)
+			}
+		case tcell.KeyUp, tcell.KeyCtrlP, tcell.KeyBacktab:
+			if row > 0 {
+				table.Select(row-1, 0)
+			}
+		default:
+			return event
+		}
+
+		return nil
+	})
+
+	list := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(input, 1, 0, true).
+		AddItem(table, 0, 1, false)
+	list.SetBorder(true)
+	list.SetBorderColor(app.Styles.PrimaryTextColor)
+	list.SetTitle(" Command palette ")
+
+	palette.Flex = tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(list, 20, 0, true).
+			AddItem(nil, 0, 1, false), 80, 0, true).
+		AddItem(nil, 0, 1, false)
+
+	palette.filter("")
+
+	return palette
+}
+
+// filter shows the entries fuzzy matching the text, the closest first.
+func (palette *CommandPalette) filter(text string) {
+	type rankedEntry struct {
+		paletteEntry
+		rank int
+	}
+
+	var matches []rankedEntry
+
+	for _, entry := range palette.entries {
+		if rank := fuzzy.RankMatchFold(text, entry.search); rank >= 0 {
+			matches = append(matches, rankedEntry{entry, rank})
+		}
+	}
+
+	// Without a filter the entries keep their order, commands first.
+	if text != "" {
+		slices.SortStableFunc(matches, func(a, b rankedEntry) int {
+			return a.rank - b.rank
+		})
+	}
+
+	palette.shown = palette.shown[:0]
+	for _, match := range matches {
+		palette.shown = append(palette.shown, match.paletteEntry)
+	}
+
+	palette.Table.Clear()
+
+	for row, entry := range palette.shown {
+		palette.Table.SetCell(row, 0, tview.NewTableCell(tview.Escape(entry.label)).SetExpansion(1))
+		palette.Table.SetCell(row, 1, tview.NewTableCell(tview.Escape(entry.key)).SetAlign(tview.AlignRight).SetTextColor(app.Styles.SecondaryTextColor))
+		palette.Table.SetCell(row, 2, tview.NewTableCell(entry.kind).SetAlign(tview.AlignRight).SetTextColor(app.Styles.TertiaryTextColor))
+	}
+
+	palette.Table.Select(0, 0)
+	palette.Table.ScrollToBeginning()
+}
+
+// paletteGroups returns the keymap groups of the commands available where the
+// focus is.
+func paletteGroups(home *Home) []string {
+	if home == nil {
+		return []string{app.ConnectionGroup}
+	}
+
+	if home.FocusedWrapper != focusedWrapperRight {
+		return []string{app.TreeGroup, app.HomeGroup}
+	}
+
+	if tab := home.TabbedPane.GetCurrentTab(); tab != nil && tab.Content.Sidebar != nil && tab.Content.Sidebar.HasFocus() {
+		return []string{app.SidebarGroup, app.HomeGroup}
+	}
+
+	return []string{app.TableGroup, app.HomeGroup}
+}
+
+// paletteCommands returns the commands available where the focus is, bound or
+// not.
+func paletteCommands(home *Home) []paletteEntry {
+	var entries []paletteEntry
+
+	for _, group := range paletteGroups(home) {
+		for _, command := range app.Keymaps.Commands(group) {
+			if command.Cmd == commands.CommandPalette {
+				continue
+			}
+
+			entries = append(entries, paletteEntry{
+				label:  command.Description,
+				key:    command.Keys.String(),
+				kind:   group,
+				search: fmt.Sprintf("%s %s", command.Description, command.Cmd),
+				run: func() {
+					runPaletteCommand(home, group, command.Cmd)
+				},
+			})
+		}
+	}
+
+	return entries
+}
+
+// runPaletteCommand runs the command of the group with the handler its keys
+// are resolved by.
+func runPaletteCommand(home *Home, group string, command commands.Command) {
+	switch group {
+	case app.ConnectionGroup:
+		connectionSelection.runCommand(command)
+	case app.TreeGroup:
+		home.Tree.runCommand(command, 1)
+	case app.HomeGroup:
+		home.runHomeCommand(command)
+	case app.TableGroup:
+		if !home.runTabCommand(command) {
+			if tab := home.TabbedPane.GetCurrentTab(); tab != nil {
+				tab.Content.runCommand(command, 1)
+			}
+		}
+	case app.SidebarGroup:
+		if tab := home.TabbedPane.GetCurrentTab(); tab != nil && tab.Content.Sidebar != nil {
+			tab.Content.Sidebar.runCommand(command)
+		}
+	}
+}
+
+// paletteTables returns the entries opening the tables of the databases of the
+// connection.
+func paletteTables(home *Home) []paletteEntry {
+	databases := []string{home.Tree.dbName}
+
+	if home.Tree.dbName == "" {
+		var err error
+
+		databases, err = home.DBDriver.GetDatabases()
+		if err != nil {
+			logger.Error("Could not list the databases", map[string]any{"error": err.Error()})
+			return nil
+		}
+	}
+
+	var entries []paletteEntry
+
+	for _, database := range databases {
+		tables, err := drivers.ListTables(home.DBDriver, database)
+		if err != nil {
+			logger.Error("Could not list the tables", map[string]any{"database": database, "error": err.Error()})
+			continue
+		}
+
+		for _, table := range tables {
+			name := table
+			if database != "" {
+				name = fmt.Sprintf("%s.%s", database, table)
+			}
+
+			entries = append(entries, paletteEntry{
+				label:  "Open table " + name,
+				kind:   "table",
+				search: "table " + name,
+				run: func() {
+					home.Tree.SetSelectedDatabase(database)
+					home.Tree.SetSelectedTable(table)
+				},
+			})
+		}
+	}
+
+	return entries
+}
+
+// paletteEntries returns the entries of the palette after the commands and the
+// tables: the connections and the saved queries.
+func paletteEntries(home *Home) []paletteEntry {
+	var entries []paletteEntry
+
+	for _, connection := range App.Connections() {
+		if open := openHome(connection.Name); open != nil {
+			if open != home {
+				entries = append(entries, paletteEntry{
+					label:  "Switch to connection " + connection.Name,
+					kind:   "connection",
+					search: "connection " + connection.Name,
+					run: func() {
+						switchToHome(open)
+					},
+				})
+			}
+
+			continue
+		}
+
+		entries = append(entries, paletteEntry{
+			label:  "Connect to " + connection.Name,
+			kind:   "connection",
+			search: "connection " + connection.Name,
+			run: func() {
+				// The progress of the connection is shown in the list.
+				mainPages.SwitchToPage(pageNameConnections)
+				go connectionSelection.Connect(connection)
+			},
+		})
+	}
+
+	if home != nil {
+		for _, query := range App.SavedQueries() {
+			if query.Connection != "" && query.Connection != home.Connection.Name {
+				continue
+			}
+
+			entries = append(entries, paletteEntry{
+				label:  "Open query " + query.Name,
+				kind:   "query",
+				search: "query " + query.Name,
+				run: func() {
+					home.openEditor().Editor.SetText(query.Query, true)
+				},
+			})
+		}
+	}
+
+	return entries
+}
@@ -0,0 +1,343 @@
+package components
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/rivo/tview"
+
+	"github.com/jorgerojas26/lazysql/app"
+	"github.com/jorgerojas26/lazysql/commands"
+	"github.com/jorgerojas26/lazysql/drivers"
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// providerDriver is a driver of a provider, the other methods are not used.
+type providerDriver struct {
+	drivers.Driver
+	provider string
+}
+
+func (db *providerDriver) GetProvider() string {
+	return db.provider
+}
+
+// setTestConnections saves the connections in a configuration file of the
+// test.
+func setTestConnections(t *testing.T, connections ...models.Connection) {
+	t.Helper()
+
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	previous := App.Connections()
+	t.Cleanup(func() { _ = App.SaveConnections(previous) })
+
+	if err := App.SaveConnections(connections); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// paletteLabels returns the labels of the entries of the kind.
+func paletteLabels(entries []paletteEntry, kind string) []string {
+	var labels []string
+
+	for _, entry := range entries {
+		if entry.kind == kind {
+			labels = append(labels, entry.label)
+		}
+	}
+
+	return labels
+}
+
+// tablesDriver is a driver of the databases and the tables of a provider.
+type tablesDriver struct {
+	providerDriver
+	tables map[string]map[string][]string
+}
+
+func (db *tablesDriver) GetDatabases() ([]string, error) {
+	return slices.Sorted(maps.Keys(db.tables)), nil
+}
+
+func (db *tablesDriver) GetTables(database string) (map[string][]string, error) {
+	tables, ok := db.tables[database]
+	if !ok {
+		return nil, fmt.Errorf("unknown database %s", database)
+	}
+
+	return tables, nil
+}
+
+// keepKeymaps restores the keymap groups changed by the test when it ends.
+func keepKeymaps(t *testing.T) {
+	t.Helper()
+
+	groups := make(map[string]app.Map, len(app.Keymaps.Groups))
+	for name, group := range app.Keymaps.Groups {
+		groups[name] = slices.Clone(group)
+	}
+
+	t.Cleanup(func() {
+		maps.Copy(app.Keymaps.Groups, groups)
+	})
+}
+
+func TestPaletteEntries_ConnectionList(t *testing.T) {
+	newSwitcherTestHomes(t, "b")
+	setTestConnections(t, models.Connection{Name: "a"}, models.Connection{Name: "b"})
+
+	commandEntries := paletteCommands(nil)
+
+	// Every command of the list is listed once, with its first key, except
+	// the palette itself.
+	var expected []string
+
+	for _, command := range app.Keymaps.Commands(app.ConnectionGroup) {
+		if command.Cmd != commands.CommandPalette {
+			expected = append(expected, command.Description)
+		}
+	}
+
+	if labels := paletteLabels(commandEntries, app.ConnectionGroup); !reflect.DeepEqual(labels, expected) {
+		t.Fatalf("expected %q, but got %q", expected, labels)
+	}
+
+	if commandEntries[0].key != "n" || commandEntries[0].search != "Create a new database connection NewConnection" {
+		t.Fatalf("expected the first command to be bound to n, but got %q, %q", commandEntries[0].key, commandEntries[0].search)
+	}
+
+	// The connections are listed after the commands.
+	entries := paletteEntries(nil)
+
+	expected = []string{"Connect to a", "Switch to connection b"}
+	if labels := paletteLabels(entries, "connection"); !reflect.DeepEqual(labels, expected) || len(labels) != len(entries) {
+		t.Fatalf("expected %q, but got %q", expected, labels)
+	}
+}
+
+func TestPaletteCommands_Unbound(t *testing.T) {
+	keepKeymaps(t)
+
+	if err := app.Keymaps.Configure(map[string]map[string]any{app.ConnectionGroup: {"NewConnection": []any{}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The commands bound to no key are listed without one.
+	entries := paletteCommands(nil)
+
+	if entries[0].label != "Create a new database connection" || entries[0].key != "" {
+		t.Fatalf("expected the unbound command to be listed first without a key, but got %q, %q", entries[0].label, entries[0].key)
+	}
+}
+
+func TestPaletteCommands_RunHandler(t *testing.T) {
+	homes := newSwitcherTestHomes(t, "a")
+
+	root := tview.NewTreeNode("")
+	database := tview.NewTreeNode("app").SetReference("app")
+	database.AddChild(tview.NewTreeNode("users").SetReference("app.users"))
+	database.AddChild(tview.NewTreeNode("orders").SetReference("app.orders"))
+	root.AddChild(database)
+
+	home := homes[0]
+	home.FocusedWrapper = focusedWrapperLeft
+	home.Tree = &Tree{
+		TreeView: tview.NewTreeView().SetRoot(root).SetCurrentNode(root),
+		DBDriver: &providerDriver{provider: drivers.DriverMySQL},
+		state:    &TreeState{},
+	}
+
+	entries := paletteCommands(home)
+
+	// The commands of the tree come before the ones of the home page.
+	if entries[0].kind != app.TreeGroup || len(paletteLabels(entries, app.HomeGroup)) == 0 {
+		t.Fatalf("expected the commands of the tree and of the home page, but got %q first", entries[0].kind)
+	}
+
+	// The commands are run by their handler, not by pressing their keys.
+	for _, entry := range entries {
+		if entry.kind == app.TreeGroup && strings.HasSuffix(entry.search, " GotoBottom") {
+			entry.run()
+		}
+	}
+
+	if node := home.Tree.GetCurrentNode(); node.GetText() != "orders" {
+		t.Fatalf("expected the last table to be selected, but got %q", node.GetText())
+	}
+}
+
+func TestPaletteTables(t *testing.T) {
+	testCases := []struct {
+		name     string
+		provider string
+		dbName   string
+		tables   map[string]map[string][]string
+		expected []string
+	}{
+		{
+			name:     "databases",
+			provider: drivers.DriverMySQL,
+			tables: map[string]map[string][]string{
+				"app":  {"app": {"users", "orders"}},
+				"logs": {"logs": {"events"}},
+			},
+			expected: []string{"Open table app.orders", "Open table app.users", "Open table logs.events"},
+		},
+		{
+			name:     "schemas",
+			provider: drivers.DriverPostgres,
+			tables: map[string]map[string][]string{
+				"app": {"public": {"users"}, "audit": {"events"}, "pg_catalog": {"pg_class"}},
+			},
+			expected: []string{"Open table app.audit.events", "Open table app.public.users"},
+		},
+		{
+			name:     "database of the connection",
+			provider: drivers.DriverMySQL,
+			dbName:   "logs",
+			tables: map[string]map[string][]string{
+				"app":  {"app": {"users"}},
+				"logs": {"logs": {"events"}},
+			},
+			expected: []string{"Open table logs.events"},
+		},
+		{
+			name:     "unknown database",
+			provider: drivers.DriverMySQL,
+			dbName:   "nowhere",
+			tables:   map[string]map[string][]string{"app": {"app": {"users"}}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			driver := &tablesDriver{providerDriver: providerDriver{provider: tc.provider}, tables: tc.tables}
+
+			home := &Home{
+				DBDriver: driver,
+				Tree:     &Tree{DBDriver: driver, dbName: tc.dbName, state: &TreeState{}},
+			}
+
+			if labels := paletteLabels(paletteTables(home), "table"); !reflect.DeepEqual(labels, tc.expected) {
+				t.Fatalf("expected %q, but got %q", tc.expected, labels)
+			}
+		})
+	}
+}
+
+func TestPaletteTables_Open(t *testing.T) {
+	driver := &tablesDriver{
+		providerDriver: providerDriver{provider: drivers.DriverMySQL},
+		tables:         map[string]map[string][]string{"app": {"app": {"users", "orders"}}},
+	}
+
+	home := &Home{
+		DBDriver: driver,
+		Tree:     &Tree{DBDriver: driver, state: &TreeState{}},
+	}
+
+	for _, entry := range paletteTables(home) {
+		if entry.label == "Open table app.orders" {
+			entry.run()
+		}
+	}
+
+	if home.Tree.GetSelectedDatabase() != "app" || home.Tree.GetSelectedTable() != "orders" {
+		t.Fatalf("expected app.orders to be opened, but got %s.%s", home.Tree.GetSelectedDatabase(), home.Tree.GetSelectedTable())
+	}
+}
+
+func TestPaletteEntries_Home(t *testing.T) {
+	homes := newSwitcherTestHomes(t, "a", "b")
+	setTestConnections(t, models.Connection{Name: "a"}, models.Connection{Name: "b"}, models.Connection{Name: "c"})
+
+	// The connection of the home page is not listed.
+	expected := []string{"Switch to connection b", "Connect to c"}
+	if labels := paletteLabels(paletteEntries(homes[0]), "connection"); !reflect.DeepEqual(labels, expected) {
+		t.Fatalf("expected %q, but got %q", expected, labels)
+	}
+}
+
+func TestCommandPalette_Filter(t *testing.T) {
+	palette := &CommandPalette{
+		Table: tview.NewTable(),
+		entries: []paletteEntry{
+			{label: "Go to first row", key: "gg", kind: app.TableGroup, search: "Go to first row GotoTop"},
+			{label: "Delete row", key: "dd", kind: app.TableGroup, search: "Delete row Delete"},
+			{label: "Open table app.users", kind: "table", search: "table app.users"},
+			{label: "Open table app.user_roles", kind: "table", search: "table app.user_roles"},
+			{label: "Connect to [prod]", kind: "connection", search: "connection [prod]"},
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		text     string
+		expected []string
+	}{
+		{
+			name:     "no filter",
+			text:     "",
+			expected: []string{"Go to first row", "Delete row", "Open table app.users", "Open table app.user_roles", "Connect to [prod]"},
+		},
+		{
+			name:     "closest first",
+			text:     "users",
+			expected: []string{"Open table app.users", "Open table app.user_roles"},
+		},
+		{
+			name:     "fuzzy",
+			text:     "tblroles",
+			expected: []string{"Open table app.user_roles"},
+		},
+		{
+			name:     "ignoring case",
+			text:     "GOTOTOP",
+			expected: []string{"Go to first row"},
+		},
+		{
+			name:     "command name",
+			text:     "delete",
+			expected: []string{"Delete row"},
+		},
+		{
+			name: "no match",
+			text: "mysql",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			palette.filter(tc.text)
+
+			var labels []string
+			for _, entry := range palette.shown {
+				labels = append(labels, entry.label)
+			}
+
+			if !reflect.DeepEqual(labels, tc.expected) {
+				t.Fatalf("expected %q, but got %q", tc.expected, labels)
+			}
+
+			if rows := palette.Table.GetRowCount(); rows != len(tc.expected) {
+				t.Fatalf("expected %d rows, but got %d", len(tc.expected), rows)
+			}
+		})
+	}
+
+	// The labels are shown as they are.
+	palette.filter("prod")
+
+	if text := palette.Table.GetCell(0, 0).Text; text != tview.Escape("Connect to [prod]") {
+		t.Fatalf("expected the label to be escaped, but got %q", text)
+	}
+
+	if key := palette.Table.GetCell(0, 1).Text; key != "" {
+		t.Fatalf("expected no key, but got %q", key)
+	}
+}
@@ -0,0 +1,167 @@
+package components
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/jorgerojas26/lazysql/app"
+)
+
+// commandsRefreshInterval is how often the output of the commands is shown
+// again while the view is open.
+const commandsRefreshInterval = time.Second
+
+// CommandsView shows the commands started for a connection, whether they are
+// running, and the output of the selected one.
+type CommandsView struct {
+	*tview.Flex
+	List   *tview.List
+	Output *tview.TextView
+
+	commands []*runningCommand
+	// shownLines is the output shown for the selected command, it is only
+	// written again when it changed.
+	shownLines []string
+	stop       chan struct{}
+}
+
+func NewCommandsView(home *Home) *CommandsView {
+	list := tview.NewList()
+	list.ShowSecondaryText(false)
+	list.SetHighlightFullLine(true)
+	list.SetSelectedStyle(tcell.StyleDefault.Foreground(app.Styles.SecondaryTextColor).Background(app.Styles.PrimitiveBackgroundColor))
+	list.SetBorder(true)
+	list.SetTitle(" Commands ")
+
+	output := tview.NewTextView()
+	output.SetDynamicColors(false)
+	output.SetScrollable(true)
+	output.SetWrap(false)
+	output.SetBorder(true)
+	output.SetTitle(" Output ")
+
+	view := &CommandsView{
+		List:     list,
+		Output:   output,
+		commands: home.health.commands,
+		stop:     make(chan struct{}),
+	}
+
+	for range view.commands {
+		list.AddItem("", "", 0, nil)
+	}
+
+	list.SetChangedFunc(func(int, string, string, rune) {
+		view.shownLines = nil
+		view.refresh()
+	})
+
+	closeView := func() {
+		close(view.stop)
+		mainPages.RemovePage(pageNameConnectionCommands)
+		App.SetFocus(home)
+	}
+
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEsc, event.Key() == tcell.KeyRune && event.Rune() == 'q':
+			closeView()
+			return nil
+		case event.Key() == tcell.KeyRune && event.Rune() == 'j':
+			return tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+		case event.Key() == tcell.KeyRune && event.Rune() == 'k':
+			return tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)
+		case event.Key() == tcell.KeyTab:
+			App.SetFocus(output)
+			return nil
+		}
+
+		return event
+	})
+
+	output.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEsc, event.Key() == tcell.KeyRune && event.Rune() == 'q':
+			closeView()
+			return nil
+		case event.Key() == tcell.KeyTab, event.Key() == tcell.KeyBacktab:
+			App.SetFocus(list)
+			return nil
+		}
+
+		return event
+	})
+
+	view.Flex = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().
+			AddItem(nil, 0, 1, false).
+			AddItem(list, 40, 0, true).
+			AddItem(output, 0, 6, false).
+			AddItem(nil, 0, 1, false), 0, 6, true).
+		AddItem(nil, 0, 1, false)
+
+	view.refresh()
+
+	go view.follow()
+
+	return view
+}
+
+// follow shows the output again until the view is closed.
+func (view *CommandsView) follow() {
+	ticker := time.NewTicker(commandsRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-view.stop:
+			return
+		case <-ticker.C:
+			view.refresh()
+			App.Draw()
+		}
+	}
+}
+
+// refresh shows whether the commands are running and the output of the
+// selected one.
+func (view *CommandsView) refresh() {
+	for i, command := range view.commands {
+		status := fmt.Sprintf("[%s]stopped[-]", app.Styles.ErrorColor)
+		if command.running() {
+			status = fmt.Sprintf("[%s]running[-]", app.Styles.SuccessColor)
+		}
+
+		view.List.SetItemText(i, fmt.Sprintf("%s %s", status, tview.Escape(command.Command.Command)), "")
+	}
+
+	selected := view.List.GetCurrentItem()
+	if selected < 0 || selected >= len(view.commands) {
+		return
+	}
+
+	process := view.commands[selected].process.Load()
+	if process == nil {
+		view.Output.SetText("")
+		return
+	}
+
+	lines := process.Output()
+	if !process.Running() {
+		lines = append(lines, "", commandExitError(process).Error())
+	}
+
+	if slices.Equal(lines, view.shownLines) {
+		return
+	}
+
+	view.shownLines = lines
+	view.Output.SetText(strings.Join(lines, "\n"))
+	view.Output.ScrollToEnd()
+}
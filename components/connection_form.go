@@ -1,6 +1,9 @@
 package components
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/gdamore/tcell/v2"
@@ -12,11 +15,36 @@ import (
 	"github.com/jorgerojas26/lazysql/models"
 )
 
+// tlsModes are the options of the TLS mode drop down, the first one leaves the
+// mode to the driver unless other TLS fields are set.
+var tlsModes = []string{"default", models.TLSModeDisable, models.TLSModeRequire, models.TLSModeVerifyCA, models.TLSModeVerifyFull}
+
+// connectionProviders are the options of the provider drop down.
+var connectionProviders = []string{
+	drivers.DriverPostgres,
+	drivers.DriverMySQL,
+	drivers.DriverMSSQL,
+	drivers.DriverClickhouse,
+	drivers.DriverOracle,
+	drivers.DriverSqlite,
+	drivers.DriverDuckDB,
+	drivers.DriverFiles,
+}
+
+// clickhouseProtocols are the options of the protocol drop down of ClickHouse.
+var clickhouseProtocols = []string{drivers.ClickhouseProtocolNative, drivers.ClickhouseProtocolHTTP}
+
 type ConnectionForm struct {
 	*tview.Flex
 	*tview.Form
 	StatusText *tview.TextView
 	Action     string
+
+	// fields are the parts of the URL shown by the fields of the provider.
+	fields models.Connection
+	// syncing is set while the fields and the URL are updated from each
+	// other.
+	syncing bool
 }
 
 func NewConnectionForm(connectionPages *models.ConnectionPages) *ConnectionForm {
@@ -25,33 +53,31 @@ func NewConnectionForm(connectionPages *models.ConnectionPages) *ConnectionForm
 	wrapper.SetDirection(tview.FlexColumnCSS)
 
 	addForm := tview.NewForm().SetFieldBackgroundColor(app.Styles.InverseTextColor).SetButtonBackgroundColor(tview.Styles.InverseTextColor).SetLabelColor(tview.Styles.PrimaryTextColor).SetFieldTextColor(tview.Styles.ContrastSecondaryTextColor)
-	addForm.AddInputField("Name", "", 0, nil, nil)
-	addForm.AddInputField("URL", "", 0, nil, nil)
 
 	buttonsWrapper := tview.NewFlex().SetDirection(tview.FlexColumn)
 
-	saveButton := tview.NewButton("[yellow]F1 [dark]Save")
+	saveButton := tview.NewButton(fmt.Sprintf("[%s]F1 [dark]Save", app.Styles.SecondaryTextColor))
 	saveButton.SetStyle(tcell.StyleDefault.Background(app.Styles.PrimaryTextColor))
 	saveButton.SetBorder(true)
 
 	buttonsWrapper.AddItem(saveButton, 0, 1, false)
 	buttonsWrapper.AddItem(nil, 1, 0, false)
 
-	testButton := tview.NewButton("[yellow]F2 [dark]Test")
+	testButton := tview.NewButton(fmt.Sprintf("[%s]F2 [dark]Test", app.Styles.SecondaryTextColor))
 	testButton.SetStyle(tcell.StyleDefault.Background(app.Styles.PrimaryTextColor))
 	testButton.SetBorder(true)
 
 	buttonsWrapper.AddItem(testButton, 0, 1, false)
 	buttonsWrapper.AddItem(nil, 1, 0, false)
 
-	connectButton := tview.NewButton("[yellow]F3 [dark]Connect")
+	connectButton := tview.NewButton(fmt.Sprintf("[%s]F3 [dark]Connect", app.Styles.SecondaryTextColor))
 	connectButton.SetStyle(tcell.StyleDefault.Background(app.Styles.PrimaryTextColor))
 	connectButton.SetBorder(true)
 
 	buttonsWrapper.AddItem(connectButton, 0, 1, false)
 	buttonsWrapper.AddItem(nil, 1, 0, false)
 
-	cancelButton := tview.NewButton("[yellow]Esc [dark]Cancel")
+	cancelButton := tview.NewButton(fmt.Sprintf("[%s]Esc [dark]Cancel", app.Styles.SecondaryTextColor))
 	cancelButton.SetStyle(tcell.StyleDefault.Background(tcell.Color(app.Styles.PrimaryTextColor)))
 	cancelButton.SetBorder(true)
 
@@ -70,6 +96,8 @@ func NewConnectionForm(connectionPages *models.ConnectionPages) *ConnectionForm
 		StatusText: statusText,
 	}
 
+	form.SetConnection(models.Connection{})
+
 	wrapper.SetInputCapture(form.inputCapture(connectionPages))
 
 	return form
@@ -79,19 +107,24 @@ func (form *ConnectionForm) inputCapture(connectionPages *models.ConnectionPages
 	return func(event *tcell.EventKey) *tcell.EventKey {
 		if event.Key() == tcell.KeyEsc {
 			connectionPages.SwitchToPage(pageNameConnectionSelection)
+		} else if event.Key() == tcell.KeyEnter && form.dropDownHasFocus() {
+			// Enter opens the options of the drop downs.
+			return event
 		} else if event.Key() == tcell.KeyF1 || event.Key() == tcell.KeyEnter {
-			connectionName := form.GetFormItem(0).(*tview.InputField).GetText()
+			connectionName := form.text("Name")
 
 			if connectionName == "" {
-				form.StatusText.SetText("Connection name is required").SetTextStyle(tcell.StyleDefault.Foreground(tcell.ColorRed))
+				form.StatusText.SetText("Connection name is required").SetTextStyle(tcell.StyleDefault.Foreground(app.Styles.ErrorColor))
 				return event
 			}
 
-			connectionString := form.GetFormItem(1).(*tview.InputField).GetText()
+			// The references to environment variables are saved, never their
+			// values.
+			connectionString := drivers.EscapeEnvReferences(form.text("URL"))
 
 			parsed, err := helpers.ParseConnectionString(connectionString)
 			if err != nil {
-				form.StatusText.SetText(err.Error()).SetTextStyle(tcell.StyleDefault.Foreground(tcell.ColorRed))
+				form.StatusText.SetText(err.Error()).SetTextStyle(tcell.StyleDefault.Foreground(app.Styles.ErrorColor))
 				return event
 			}
 
@@ -100,34 +133,61 @@ func (form *ConnectionForm) inputCapture(connectionPages *models.ConnectionPages
 
 			DBName := strings.Split(parsed.Normalize(",", "NULL", 0), ",")[3]
 
-			if DBName == "NULL" {
+			// The files of a files URL are not databases.
+			if DBName == "NULL" || parsed.Driver == drivers.DriverFiles {
 				DBName = ""
 			}
 
-			parsedDatabaseData := models.Connection{
-				Name:     connectionName,
-				Provider: parsed.Driver,
-				DBName:   DBName,
-				URL:      connectionString,
-			}
+			parsedDatabaseData := form.settings(connectionString)
+			parsedDatabaseData.Provider = parsed.Driver
+			parsedDatabaseData.DBName = DBName
 
 			switch form.Action {
 			case actionNewConnection:
+				if err := drivers.ValidateTLS(parsedDatabaseData); err != nil {
+					form.StatusText.SetText(err.Error()).SetTextStyle(tcell.StyleDefault.Foreground(app.Styles.ErrorColor))
+					return event
+				}
 
 				newDatabases = append(databases, parsedDatabaseData)
 				err := app.App.SaveConnections(newDatabases)
 				if err != nil {
-					form.StatusText.SetText(err.Error()).SetTextStyle(tcell.StyleDefault.Foreground(tcell.ColorRed))
+					form.StatusText.SetText(err.Error()).SetTextStyle(tcell.StyleDefault.Foreground(app.Styles.ErrorColor))
 					return event
 				}
 
 			case actionEditConnection:
 				newDatabases = make([]models.Connection, len(databases))
-				row, _ := connectionsTable.GetSelection()
+
+				// The rows of the table are not the connections when they
+				// are grouped or filtered.
+				_, row, ok := connectionsTable.GetSelectedConnection()
+				if !ok {
+					form.StatusText.SetText("No connection selected").SetTextStyle(tcell.StyleDefault.Foreground(app.Styles.ErrorColor))
+					return event
+				}
 
 				for i, database := range databases {
 					if i == row {
-						newDatabases[i] = parsedDatabaseData
+						// Keep the settings the form does not show.
+						newDatabases[i] = database
+						newDatabases[i].Name = parsedDatabaseData.Name
+						newDatabases[i].Group = parsedDatabaseData.Group
+						newDatabases[i].Tags = parsedDatabaseData.Tags
+						newDatabases[i].Provider = parsedDatabaseData.Provider
+						newDatabases[i].DBName = parsedDatabaseData.DBName
+						newDatabases[i].URL = parsedDatabaseData.URL
+						newDatabases[i].PasswordCommand = parsedDatabaseData.PasswordCommand
+						newDatabases[i].TLS = parsedDatabaseData.TLS
+
+						// The URL replaces the fields it was generated from.
+						newDatabases[i].Username = ""
+						newDatabases[i].Password = ""
+						newDatabases[i].Hostname = ""
+						newDatabases[i].Port = ""
+						newDatabases[i].URLParams = ""
+						newDatabases[i].File = ""
+						newDatabases[i].Protocol = ""
 
 						// newDatabases[i].Name = connectionName
 						// newDatabases[i].Provider = database.Provider
@@ -138,6 +198,12 @@ func (form *ConnectionForm) inputCapture(connectionPages *models.ConnectionPages
 						// newDatabases[i].Query = parsed.Query().Encode()
 						// newDatabases[i].DBName = helpers.ParsedDBName(parsed.Path)
 						// newDatabases[i].DSN = parsed.DSN
+
+						// The SSH tunnel is kept from the configuration.
+						if err := drivers.ValidateTLS(newDatabases[i]); err != nil {
+							form.StatusText.SetText(err.Error()).SetTextStyle(tcell.StyleDefault.Foreground(app.Styles.ErrorColor))
+							return event
+						}
 					} else {
 						newDatabases[i] = database
 					}
@@ -145,7 +211,7 @@ func (form *ConnectionForm) inputCapture(connectionPages *models.ConnectionPages
 
 				err := app.App.SaveConnections(newDatabases)
 				if err != nil {
-					form.StatusText.SetText(err.Error()).SetTextStyle(tcell.StyleDefault.Foreground(tcell.ColorRed))
+					form.StatusText.SetText(err.Error()).SetTextStyle(tcell.StyleDefault.Foreground(app.Styles.ErrorColor))
 					return event
 
 				}
@@ -155,17 +221,16 @@ func (form *ConnectionForm) inputCapture(connectionPages *models.ConnectionPages
 			connectionPages.SwitchToPage(pageNameConnectionSelection)
 
 		} else if event.Key() == tcell.KeyF2 {
-			connectionString := form.GetFormItem(1).(*tview.InputField).GetText()
-			go form.testConnection(connectionString)
+			go form.testConnection(form.settings(drivers.EscapeEnvReferences(form.text("URL"))))
 		}
 		return event
 	}
 }
 
-func (form *ConnectionForm) testConnection(connectionString string) {
-	parsed, err := helpers.ParseConnectionString(connectionString)
+func (form *ConnectionForm) testConnection(connection models.Connection) {
+	parsed, err := helpers.ParseConnectionString(connection.URL)
 	if err != nil {
-		form.StatusText.SetText(err.Error()).SetTextStyle(tcell.StyleDefault.Foreground(tcell.ColorRed))
+		form.StatusText.SetText(err.Error()).SetTextStyle(tcell.StyleDefault.Foreground(app.Styles.ErrorColor))
 		return
 	}
 
@@ -184,12 +249,23 @@ func (form *ConnectionForm) testConnection(connectionString string) {
 		db = &drivers.MSSQL{}
 	case drivers.DriverClickhouse:
 		db = &drivers.Clickhouse{}
+	case drivers.DriverDuckDB:
+		db = &drivers.DuckDB{}
+	case drivers.DriverFiles:
+		db = &drivers.Files{}
+	case drivers.DriverOracle:
+		db = &drivers.Oracle{}
 	}
 
-	err = db.TestConnection(connectionString)
+	connectionString, tunnel, err := drivers.ConnectionURL(connection)
+	if err == nil {
+		err = db.TestConnection(connectionString)
+		// The tunnel was only opened for the test.
+		_ = tunnel.Release()
+	}
 
 	if err != nil {
-		form.StatusText.SetText(err.Error()).SetTextStyle(tcell.StyleDefault.Foreground(tcell.ColorRed))
+		form.StatusText.SetText(err.Error()).SetTextStyle(tcell.StyleDefault.Foreground(app.Styles.ErrorColor))
 	} else {
 		form.StatusText.SetText("Connection success").SetTextColor(app.Styles.TertiaryTextColor)
 	}
@@ -199,3 +275,285 @@ func (form *ConnectionForm) testConnection(connectionString string) {
 func (form *ConnectionForm) SetAction(action string) {
 	form.Action = action
 }
+
+// SetConnection fills the fields of the form with the connection.
+func (form *ConnectionForm) SetConnection(connection models.Connection) {
+	fields, err := drivers.ParseURL(connection.URL)
+	if err != nil {
+		// Keep the URL as it is, the fields stay empty.
+		fields = models.Connection{Provider: connectionProviders[0]}
+	}
+
+	form.fields = fields
+	form.render(connection)
+	form.StatusText.SetText("")
+}
+
+// render adds the fields of the provider to the form, the settings that are
+// not part of the URL are taken from the connection.
+func (form *ConnectionForm) render(connection models.Connection) {
+	form.syncing = true
+	defer func() { form.syncing = false }()
+
+	fields := form.fields
+
+	form.Form.Clear(true)
+	form.AddInputField("Name", connection.Name, 0, nil, nil)
+	form.AddInputField("Group", connection.Group, 0, nil, nil)
+	form.AddInputField("Tags", strings.Join(connection.Tags, ", "), 0, nil, nil)
+	form.AddDropDown("Provider", connectionProviders, optionIndex(connectionProviders, fields.Provider), form.providerSelected)
+
+	if drivers.IsFileProvider(fields.Provider) {
+		file := tview.NewInputField().SetLabel(fileLabel(fields.Provider)).SetText(fields.File)
+		file.SetAutocompleteFunc(completeFilePath(fields.Provider == drivers.DriverFiles))
+		file.SetChangedFunc(form.fieldChanged(func(text string) { form.fields.File = text }))
+		form.AddFormItem(file)
+	} else {
+		form.AddInputField("Host", fields.Hostname, 0, nil, form.fieldChanged(func(text string) { form.fields.Hostname = text }))
+		form.AddInputField("Port", fields.Port, 0, tview.InputFieldInteger, form.fieldChanged(func(text string) { form.fields.Port = text }))
+		form.AddInputField("User", fields.Username, 0, nil, form.fieldChanged(func(text string) { form.fields.Username = text }))
+		form.AddPasswordField("Password", fields.Password, 0, '*', form.fieldChanged(func(text string) { form.fields.Password = text }))
+		form.AddInputField("Password command", connection.PasswordCommand, 0, nil, nil)
+		form.AddInputField("Database", fields.DBName, 0, nil, form.fieldChanged(func(text string) { form.fields.DBName = text }))
+
+		if fields.Provider == drivers.DriverClickhouse {
+			form.AddDropDown("Protocol", clickhouseProtocols, optionIndex(clickhouseProtocols, fields.Protocol), func(option string, _ int) {
+				if option == drivers.ClickhouseProtocolNative {
+					option = ""
+				}
+
+				form.fieldChanged(func(string) { form.fields.Protocol = option })(option)
+			})
+		}
+	}
+
+	form.AddInputField("Params", fields.URLParams, 0, nil, form.fieldChanged(func(text string) { form.fields.URLParams = text }))
+	form.AddInputField("URL", connection.URL, 0, nil, form.urlChanged)
+
+	if drivers.IsFileProvider(fields.Provider) {
+		return
+	}
+
+	tlsConfig := connection.TLS
+	if tlsConfig == nil {
+		tlsConfig = &models.TLSConfig{}
+	}
+
+	form.AddDropDown("TLS mode", tlsModes, optionIndex(tlsModes, tlsConfig.Mode), nil)
+	form.AddInputField("TLS CA file", tlsConfig.CAFile, 0, nil, nil)
+	form.AddInputField("TLS client cert", tlsConfig.CertFile, 0, nil, nil)
+	form.AddInputField("TLS client key", tlsConfig.KeyFile, 0, nil, nil)
+	form.AddInputField("TLS server name", tlsConfig.ServerName, 0, nil, nil)
+}
+
+// fieldChanged returns the changed func of a field, it generates the URL from
+// the fields after set updated them.
+func (form *ConnectionForm) fieldChanged(set func(text string)) func(text string) {
+	return func(text string) {
+		if form.syncing {
+			return
+		}
+
+		set(text)
+
+		connectionString, err := drivers.BuildURL(form.fields)
+		if err != nil {
+			return
+		}
+
+		form.syncing = true
+		form.GetFormItemByLabel("URL").(*tview.InputField).SetText(connectionString)
+		form.syncing = false
+	}
+}
+
+// urlChanged fills the fields from the URL, showing the fields of its provider
+// if it changed.
+func (form *ConnectionForm) urlChanged(connectionString string) {
+	if form.syncing {
+		return
+	}
+
+	fields, err := drivers.ParseURL(connectionString)
+	if err != nil {
+		return
+	}
+
+	if fields.Provider != form.fields.Provider {
+		form.fields = fields
+		form.render(form.settings(connectionString))
+		form.focus("URL")
+
+		return
+	}
+
+	form.fields = fields
+
+	form.syncing = true
+	defer func() { form.syncing = false }()
+
+	if drivers.IsFileProvider(fields.Provider) {
+		form.GetFormItemByLabel(fileLabel(fields.Provider)).(*tview.InputField).SetText(fields.File)
+	} else {
+		form.GetFormItemByLabel("Host").(*tview.InputField).SetText(fields.Hostname)
+		form.GetFormItemByLabel("Port").(*tview.InputField).SetText(fields.Port)
+		form.GetFormItemByLabel("User").(*tview.InputField).SetText(fields.Username)
+		form.GetFormItemByLabel("Password").(*tview.InputField).SetText(fields.Password)
+		form.GetFormItemByLabel("Database").(*tview.InputField).SetText(fields.DBName)
+
+		if protocol, ok := form.GetFormItemByLabel("Protocol").(*tview.DropDown); ok {
+			protocol.SetCurrentOption(optionIndex(clickhouseProtocols, fields.Protocol))
+		}
+	}
+
+	form.GetFormItemByLabel("Params").(*tview.InputField).SetText(fields.URLParams)
+}
+
+// providerSelected shows the fields of the provider, the URL is generated
+// again for it.
+func (form *ConnectionForm) providerSelected(provider string, _ int) {
+	if form.syncing || provider == form.fields.Provider {
+		return
+	}
+
+	// The port and the parameters of a provider mean nothing to the others.
+	form.fields.Provider = provider
+	form.fields.Port = ""
+	form.fields.URLParams = ""
+	form.fields.Protocol = ""
+
+	// The URL of another provider would be misleading.
+	connectionString, err := drivers.BuildURL(form.fields)
+	if err != nil {
+		connectionString = ""
+	}
+
+	form.render(form.settings(connectionString))
+	form.focus("Provider")
+}
+
+// focus focuses the field with the label.
+func (form *ConnectionForm) focus(label string) {
+	form.SetFocus(form.GetFormItemIndex(label))
+	App.SetFocus(form.Form)
+}
+
+// text returns the text of the input field with the label.
+func (form *ConnectionForm) text(label string) string {
+	field, ok := form.GetFormItemByLabel(label).(*tview.InputField)
+	if !ok {
+		return ""
+	}
+
+	return field.GetText()
+}
+
+func (form *ConnectionForm) dropDownHasFocus() bool {
+	index, _ := form.GetFocusedItemIndex()
+	if index < 0 {
+		return false
+	}
+
+	_, ok := form.GetFormItem(index).(*tview.DropDown)
+
+	return ok
+}
+
+// settings returns the connection with the URL and the settings of the form
+// that are not part of the URL.
+func (form *ConnectionForm) settings(connectionString string) models.Connection {
+	var tags []string
+
+	for _, tag := range strings.Split(form.text("Tags"), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+
+	return models.Connection{
+		Name:            form.text("Name"),
+		Group:           strings.TrimSpace(form.text("Group")),
+		Tags:            tags,
+		URL:             connectionString,
+		PasswordCommand: form.text("Password command"),
+		TLS:             form.tlsConfig(),
+	}
+}
+
+// tlsConfig returns the TLS settings of the form, nil when they are all empty.
+func (form *ConnectionForm) tlsConfig() *models.TLSConfig {
+	modes, ok := form.GetFormItemByLabel("TLS mode").(*tview.DropDown)
+	if !ok {
+		return nil
+	}
+
+	mode, _ := modes.GetCurrentOption()
+
+	tlsConfig := models.TLSConfig{
+		CAFile:     form.text("TLS CA file"),
+		CertFile:   form.text("TLS client cert"),
+		KeyFile:    form.text("TLS client key"),
+		ServerName: form.text("TLS server name"),
+	}
+
+	if mode > 0 {
+		tlsConfig.Mode = tlsModes[mode]
+	}
+
+	if tlsConfig == (models.TLSConfig{}) {
+		return nil
+	}
+
+	return &tlsConfig
+}
+
+func fileLabel(provider string) string {
+	if provider == drivers.DriverFiles {
+		return "Files"
+	}
+
+	return "File"
+}
+
+// optionIndex returns the index of the option, or 0 when it is not one.
+func optionIndex(options []string, option string) int {
+	for i, o := range options {
+		if o == option {
+			return i
+		}
+	}
+
+	return 0
+}
+
+// completeFilePath returns the autocomplete func of the file fields, the files
+// provider takes a comma separated list of files.
+func completeFilePath(multiple bool) func(text string) []string {
+	return func(text string) []string {
+		if text == "" {
+			return nil
+		}
+
+		prefix := ""
+		if i := strings.LastIndex(text, ","); multiple && i != -1 {
+			prefix, text = text[:i+1], text[i+1:]
+		}
+
+		matches, err := filepath.Glob(text + "*")
+		if err != nil {
+			return nil
+		}
+
+		entries := make([]string, 0, len(matches))
+
+		for _, match := range matches {
+			if info, err := os.Stat(match); err == nil && info.IsDir() {
+				match += string(filepath.Separator)
+			}
+
+			entries = append(entries, prefix+match)
+		}
+
+		return entries
+	}
+}
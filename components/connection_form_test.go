@@ -0,0 +1,49 @@
+package components
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/jorgerojas26/lazysql/app"
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+func TestConnectionForm_EditGroupedAndFiltered(t *testing.T) {
+	setTestConnections(t,
+		models.Connection{Name: "alpha", Group: "production", URL: "postgres://localhost/alpha"},
+		models.Connection{Name: "beta", URL: "postgres://localhost/beta"},
+		models.Connection{Name: "gamma", Group: "production", URL: "postgres://localhost/gamma"},
+	)
+
+	table := NewConnectionsTable()
+	table.Filter.SetText("gam")
+
+	// The header of the group is the first row, gamma the second one.
+	table.Select(1, 0)
+
+	connection, _, ok := table.GetSelectedConnection()
+	if !ok || connection.Name != "gamma" {
+		t.Fatalf("expected gamma to be selected, but got %q", connection.Name)
+	}
+
+	pages := &models.ConnectionPages{Pages: tview.NewPages()}
+
+	form := NewConnectionForm(pages)
+	form.SetConnection(connection)
+	form.SetAction(actionEditConnection)
+	form.GetFormItemByLabel("Name").(*tview.InputField).SetText("delta")
+
+	form.inputCapture(pages)(tcell.NewEventKey(tcell.KeyF1, 0, tcell.ModNone))
+
+	var names []string
+	for _, connection := range app.App.Connections() {
+		names = append(names, connection.Name)
+	}
+
+	if expected := []string{"alpha", "beta", "delta"}; !reflect.DeepEqual(names, expected) {
+		t.Fatalf("expected %q, but got %q (%s)", expected, names, form.StatusText.GetText(true))
+	}
+}
@@ -0,0 +1,386 @@
+package components
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jorgerojas26/lazysql/drivers"
+	"github.com/jorgerojas26/lazysql/helpers"
+	"github.com/jorgerojas26/lazysql/helpers/logger"
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+const (
+	// healthCheckInterval is how often an open connection is pinged.
+	healthCheckInterval = 15 * time.Second
+	// healthMaxBackoff is the longest wait between two reconnection attempts.
+	healthMaxBackoff = 30 * time.Second
+	// healthPingTimeout is how long a ping may take before the connection is
+	// considered lost.
+	healthPingTimeout = 5 * time.Second
+	// reconnectTimeout is how long a failed call waits for the connection to
+	// come back before its error is shown.
+	reconnectTimeout = 10 * time.Second
+	// commandTimeout is how long a command may take to be ready when it does
+	// not set its timeout.
+	commandTimeout = 30 * time.Second
+)
+
+// Health states of an open connection.
+const (
+	healthConnected = iota
+	healthReconnecting
+)
+
+// runningCommand is a command started for a connection.
+type runningCommand struct {
+	*models.Command
+	// process is the last run of the command.
+	process atomic.Pointer[helpers.Process]
+}
+
+// running reports whether the last run of the command did not exit.
+func (command *runningCommand) running() bool {
+	process := command.process.Load()
+	return process != nil && process.Running()
+}
+
+// connectionHealth pings an open connection in the background. When it is
+// lost, the commands that stopped are run again and the connection is pinged
+// with a backoff until it is back.
+type connectionHealth struct {
+	ctx      context.Context
+	port     string
+	commands []*runningCommand
+	driver   drivers.Driver
+	// resilient is the driver of the home, wrapping driver.
+	resilient *drivers.Resilient
+	home      *Home
+
+	mu       sync.Mutex
+	state    int
+	attempts int
+	// recovered is closed when the connection is back.
+	recovered chan struct{}
+	wake      chan struct{}
+}
+
+// newConnectionHealth returns the health of a connection whose commands run
+// with the port until the context is done.
+func newConnectionHealth(ctx context.Context, port string, commands []*models.Command) *connectionHealth {
+	health := &connectionHealth{
+		ctx:       ctx,
+		port:      port,
+		recovered: make(chan struct{}),
+		wake:      make(chan struct{}, 1),
+	}
+
+	close(health.recovered)
+
+	for _, command := range commands {
+		health.commands = append(health.commands, &runningCommand{Command: command})
+	}
+
+	return health
+}
+
+// expand replaces ${port} in the text with the port of the commands.
+func (health *connectionHealth) expand(text string) string {
+	return strings.ReplaceAll(text, "${port}", health.port)
+}
+
+// startCommand starts the command, the connection is checked when it exits.
+func (health *connectionHealth) startCommand(command *runningCommand) error {
+	env := make(map[string]string, len(command.Env))
+	for name, value := range command.Env {
+		env[name] = health.expand(value)
+	}
+
+	process, err := helpers.StartCommand(health.ctx, health.expand(command.Command.Command), env, command.Dir)
+	if err != nil {
+		return err
+	}
+
+	command.process.Store(process)
+
+	done := App.Register()
+
+	go func() {
+		<-process.Done()
+		done()
+
+		// A tunnel that stopped takes the connection with it.
+		health.wakeUp()
+	}()
+
+	return nil
+}
+
+// waitForReady waits for the command to write the log line, open the port
+// and answer on the URL it waits for, in this order. It fails when the
+// command exits or is not ready in time.
+func (health *connectionHealth) waitForReady(command *runningCommand) error {
+	process := command.process.Load()
+
+	timeout := commandTimeout
+	if command.Timeout != "" {
+		var err error
+
+		timeout, err = time.ParseDuration(command.Timeout)
+		if err != nil {
+			return fmt.Errorf("invalid timeout: %w", err)
+		}
+	}
+
+	var expression *regexp.Regexp
+	if command.WaitForLog != "" {
+		var err error
+
+		expression, err = regexp.Compile(command.WaitForLog)
+		if err != nil {
+			return fmt.Errorf("invalid log expression: %w", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(health.ctx, timeout)
+	defer cancel()
+
+	// A command that exited will never be ready.
+	go func() {
+		select {
+		case <-process.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	var err error
+
+	if expression != nil {
+		err = process.WaitForLog(ctx, expression)
+	}
+
+	if err == nil && command.WaitForPort != "" {
+		err = helpers.WaitForPort(ctx, health.expand(command.WaitForPort))
+	}
+
+	if err == nil && command.WaitForHTTP != "" {
+		err = helpers.WaitForHTTP(ctx, health.expand(command.WaitForHTTP))
+	}
+
+	switch {
+	case err == nil:
+		return nil
+	case !process.Running():
+		return commandExitError(process)
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return fmt.Errorf("not ready after %s: %w", timeout, err)
+	}
+
+	return err
+}
+
+// commandExitError describes why the command exited, with the last line of its
+// output.
+func commandExitError(process *helpers.Process) error {
+	err := process.Err()
+	if err == nil {
+		err = errors.New("command exited")
+	}
+
+	if line := process.LastLine(); line != "" {
+		return fmt.Errorf("%w: %s", err, line)
+	}
+
+	return err
+}
+
+// watch wraps the driver of the home so its failed calls wait for the
+// connection to come back, and starts the health checks.
+func (health *connectionHealth) watch(home *Home) {
+	health.driver = home.DBDriver
+	health.home = home
+
+	health.resilient = &drivers.Resilient{Driver: home.DBDriver, Reconnect: health.reconnect, Wait: health.wait}
+
+	home.DBDriver = health.resilient
+	home.Tree.DBDriver = home.DBDriver
+	home.health = health
+
+	health.setState(healthConnected, nil)
+
+	go health.monitor()
+}
+
+func (health *connectionHealth) wakeUp() {
+	select {
+	case health.wake <- struct{}{}:
+	default:
+	}
+}
+
+// monitor checks the connection until the context is done.
+func (health *connectionHealth) monitor() {
+	delay := healthCheckInterval
+
+	for {
+		select {
+		case <-health.ctx.Done():
+			return
+		case <-time.After(delay):
+		case <-health.wake:
+		}
+
+		err := health.check()
+		if health.ctx.Err() != nil {
+			return
+		}
+
+		if err == nil {
+			health.setState(healthConnected, nil)
+			delay = healthCheckInterval
+
+			continue
+		}
+
+		logger.Error("Connection lost", map[string]any{"name": health.home.Connection.Name, "error": err.Error()})
+
+		attempts := health.setState(healthReconnecting, err)
+		delay = min(time.Second<<min(attempts-1, 5), healthMaxBackoff)
+	}
+}
+
+// check runs the commands that stopped again and pings the connection, then
+// restores the state of the session a new connection lost. A connection that
+// is not being reconnected is not pinged while a call is running, the ping
+// would wait for it on the drivers with a single connection like SQLite.
+func (health *connectionHealth) check() error {
+	for _, command := range health.commands {
+		if command.running() {
+			continue
+		}
+
+		err := health.startCommand(command)
+		if err == nil {
+			err = health.waitForReady(command)
+		}
+
+		if err != nil {
+			return fmt.Errorf("command %q: %w", command.Command.Command, err)
+		}
+	}
+
+	health.mu.Lock()
+	connected := health.state == healthConnected
+	health.mu.Unlock()
+
+	if connected && health.resilient != nil && health.resilient.Busy() {
+		return nil
+	}
+
+	ping := make(chan error, 1)
+
+	go func() {
+		err := health.driver.Ping()
+
+		if restorer, ok := drivers.Unwrap(health.driver).(drivers.SessionRestorer); ok && err == nil {
+			err = restorer.RestoreSession()
+		}
+
+		ping <- err
+	}()
+
+	select {
+	case err := <-ping:
+		return err
+	case <-time.After(healthPingTimeout):
+		return errors.New("ping timed out")
+	}
+}
+
+// setState records the state of the connection and shows it, it returns the
+// number of failed attempts to reconnect.
+func (health *connectionHealth) setState(state int, err error) int {
+	health.mu.Lock()
+
+	if state == healthConnected {
+		health.attempts = 0
+
+		if health.state != healthConnected {
+			close(health.recovered)
+		}
+	} else {
+		health.attempts++
+
+		if health.state == healthConnected {
+			health.recovered = make(chan struct{})
+		}
+	}
+
+	health.state = state
+	attempts := health.attempts
+
+	health.mu.Unlock()
+
+	health.home.HelpStatus.SetHealth(state, attempts, err)
+	App.Draw()
+
+	return attempts
+}
+
+// wait is called before the calls that change something, it waits for the
+// connection to come back when it is being reconnected.
+func (health *connectionHealth) wait() error {
+	health.mu.Lock()
+	recovered := health.recovered
+	health.mu.Unlock()
+
+	select {
+	case <-recovered:
+		return nil
+	default:
+	}
+
+	health.wakeUp()
+
+	return health.waitRecovered(recovered)
+}
+
+// waitRecovered waits for the connection to come back, for reconnectTimeout at
+// most.
+func (health *connectionHealth) waitRecovered(recovered chan struct{}) error {
+	select {
+	case <-recovered:
+		return nil
+	case <-health.ctx.Done():
+		return health.ctx.Err()
+	case <-time.After(reconnectTimeout):
+		return errors.New("the connection did not come back")
+	}
+}
+
+// reconnect is called when a call failed because of the connection, it waits
+// for the connection to come back.
+func (health *connectionHealth) reconnect() error {
+	health.mu.Lock()
+
+	if health.state == healthConnected {
+		// The health check did not notice yet.
+		health.state = healthReconnecting
+		health.recovered = make(chan struct{})
+	}
+
+	recovered := health.recovered
+
+	health.mu.Unlock()
+
+	health.wakeUp()
+
+	return health.waitRecovered(recovered)
+}
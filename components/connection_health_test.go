@@ -0,0 +1,112 @@
+package components
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jorgerojas26/lazysql/drivers"
+)
+
+// pingDriver counts its pings and blocks GetDatabases until release is
+// closed.
+type pingDriver struct {
+	drivers.Driver
+	pings   atomic.Int32
+	started chan struct{}
+	release chan struct{}
+}
+
+func (db *pingDriver) Ping() error {
+	db.pings.Add(1)
+	return nil
+}
+
+func (db *pingDriver) GetDatabases() ([]string, error) {
+	close(db.started)
+	<-db.release
+
+	return nil, nil
+}
+
+func newTestConnectionHealth(t *testing.T) (*connectionHealth, *pingDriver) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	db := &pingDriver{started: make(chan struct{}), release: make(chan struct{})}
+
+	health := newConnectionHealth(ctx, "", nil)
+	health.driver = db
+	health.resilient = &drivers.Resilient{Driver: db, Reconnect: health.reconnect, Wait: health.wait}
+
+	return health, db
+}
+
+func TestConnectionHealth_CheckSkipsPingWhileBusy(t *testing.T) {
+	health, db := newTestConnectionHealth(t)
+
+	if err := health.check(); err != nil || db.pings.Load() != 1 {
+		t.Fatalf("expected a ping, but got %d pings, %v", db.pings.Load(), err)
+	}
+
+	done := make(chan struct{})
+
+	go func() {
+		_, _ = health.resilient.GetDatabases()
+		close(done)
+	}()
+
+	<-db.started
+
+	if err := health.check(); err != nil || db.pings.Load() != 1 {
+		t.Fatalf("expected no ping while a call runs, but got %d pings, %v", db.pings.Load(), err)
+	}
+
+	// A connection being reconnected is pinged anyway.
+	health.mu.Lock()
+	health.state = healthReconnecting
+	health.mu.Unlock()
+
+	if err := health.check(); err != nil || db.pings.Load() != 2 {
+		t.Fatalf("expected a ping while reconnecting, but got %d pings, %v", db.pings.Load(), err)
+	}
+
+	close(db.release)
+	<-done
+}
+
+func TestConnectionHealth_Wait(t *testing.T) {
+	health, _ := newTestConnectionHealth(t)
+
+	if err := health.wait(); err != nil {
+		t.Fatalf("expected a connected connection not to wait, but got %v", err)
+	}
+
+	recovered := make(chan struct{})
+
+	health.mu.Lock()
+	health.state = healthReconnecting
+	health.recovered = recovered
+	health.mu.Unlock()
+
+	waited := make(chan error, 1)
+
+	go func() {
+		waited <- health.wait()
+	}()
+
+	select {
+	case err := <-waited:
+		t.Fatalf("expected to wait for the connection, but got %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(recovered)
+
+	if err := <-waited; err != nil {
+		t.Fatalf("expected the wait to end once the connection is back, but got %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package components
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -17,8 +18,15 @@ import (
 type ConnectionSelection struct {
 	*tview.Flex
 	StatusText *tview.TextView
+
+	connectionForm  *ConnectionForm
+	connectionPages *models.ConnectionPages
 }
 
+// connectionSelection connects to the connections picked outside of the list,
+// like in the command palette.
+var connectionSelection *ConnectionSelection
+
 func NewConnectionSelection(connectionForm *ConnectionForm, connectionPages *models.ConnectionPages) *ConnectionSelection {
 	wrapper := tview.NewFlex()
 
@@ -26,35 +34,35 @@ func NewConnectionSelection(connectionForm *ConnectionForm, connectionPages *mod
 
 	buttonsWrapper := tview.NewFlex().SetDirection(tview.FlexRowCSS)
 
-	newButton := tview.NewButton("[yellow]N[dark]ew")
+	newButton := tview.NewButton(fmt.Sprintf("[%s]N[dark]ew", app.Styles.SecondaryTextColor))
 	newButton.SetStyle(tcell.StyleDefault.Background(app.Styles.PrimitiveBackgroundColor))
 	newButton.SetBorder(true)
 
 	buttonsWrapper.AddItem(newButton, 0, 1, false)
 	buttonsWrapper.AddItem(nil, 1, 0, false)
 
-	connectButton := tview.NewButton("[yellow]C[dark]onnect")
+	connectButton := tview.NewButton(fmt.Sprintf("[%s]C[dark]onnect", app.Styles.SecondaryTextColor))
 	connectButton.SetStyle(tcell.StyleDefault.Background(app.Styles.PrimitiveBackgroundColor))
 	connectButton.SetBorder(true)
 
 	buttonsWrapper.AddItem(connectButton, 0, 1, false)
 	buttonsWrapper.AddItem(nil, 1, 0, false)
 
-	editButton := tview.NewButton("[yellow]E[dark]dit")
+	editButton := tview.NewButton(fmt.Sprintf("[%s]E[dark]dit", app.Styles.SecondaryTextColor))
 	editButton.SetStyle(tcell.StyleDefault.Background(app.Styles.PrimitiveBackgroundColor))
 	editButton.SetBorder(true)
 
 	buttonsWrapper.AddItem(editButton, 0, 1, false)
 	buttonsWrapper.AddItem(nil, 1, 0, false)
 
-	deleteButton := tview.NewButton("[yellow]D[dark]elete")
+	deleteButton := tview.NewButton(fmt.Sprintf("[%s]D[dark]elete", app.Styles.SecondaryTextColor))
 	deleteButton.SetStyle(tcell.StyleDefault.Background(app.Styles.PrimitiveBackgroundColor))
 	deleteButton.SetBorder(true)
 
 	buttonsWrapper.AddItem(deleteButton, 0, 1, false)
 	buttonsWrapper.AddItem(nil, 1, 0, false)
 
-	quitButton := tview.NewButton("[yellow]Q[dark]uit")
+	quitButton := tview.NewButton(fmt.Sprintf("[%s]Q[dark]uit", app.Styles.SecondaryTextColor))
 	quitButton.SetStyle(tcell.StyleDefault.Background(app.Styles.PrimitiveBackgroundColor))
 	quitButton.SetBorder(true)
 
@@ -64,118 +72,157 @@ func NewConnectionSelection(connectionForm *ConnectionForm, connectionPages *mod
 	statusText := tview.NewTextView()
 	statusText.SetBorderPadding(1, 1, 0, 0)
 
-	wrapper.AddItem(NewConnectionsTable(), 0, 1, true)
+	wrapper.AddItem(NewConnectionsTable().Wrapper, 0, 1, true)
 	wrapper.AddItem(statusText, 4, 0, false)
 	wrapper.AddItem(buttonsWrapper, 3, 0, false)
 
 	cs := &ConnectionSelection{
-		Flex:       wrapper,
-		StatusText: statusText,
+		Flex:            wrapper,
+		StatusText:      statusText,
+		connectionForm:  connectionForm,
+		connectionPages: connectionPages,
 	}
 
+	connectionSelection = cs
+
 	wrapper.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		connections := connectionsTable.GetConnections()
-
-		command := app.Keymaps.Group(app.ConnectionGroup).Resolve(event)
-
-		if len(connections) != 0 {
-			row, _ := connectionsTable.GetSelection()
-			selectedConnection := connections[row]
-
-			switch command {
-			case commands.Connect:
-				go cs.Connect(selectedConnection)
-			case commands.EditConnection:
-				connectionPages.SwitchToPage(pageNameConnectionForm)
-				connectionForm.GetFormItemByLabel("Name").(*tview.InputField).SetText(selectedConnection.Name)
-				connectionForm.GetFormItemByLabel("URL").(*tview.InputField).SetText(selectedConnection.URL)
-				connectionForm.StatusText.SetText("")
-
-				connectionForm.SetAction(actionEditConnection)
-				return nil
-			case commands.DeleteConnection:
-				confirmationModal := NewConfirmationModal("")
-
-				confirmationModal.SetDoneFunc(func(_ int, buttonLabel string) {
-					mainPages.RemovePage(pageNameConfirmation)
-					confirmationModal = nil
-
-					if buttonLabel == "Yes" {
-						newConnections := append(connections[:row], connections[row+1:]...)
-
-						err := app.App.SaveConnections(newConnections)
-						if err != nil {
-							connectionsTable.SetError(err)
-						} else {
-							connectionsTable.SetConnections(newConnections)
-						}
+		if connectionsTable.Filter.HasFocus() {
+			return event
+		}
 
-					}
-				})
+		if cs.runCommand(app.Keymaps.Resolve(app.ConnectionGroup, event)) {
+			return nil
+		}
 
-				mainPages.AddPage(pageNameConfirmation, confirmationModal, true, true)
+		return event
+	})
 
-				return nil
-			}
-		}
+	return cs
+}
 
+// runCommand runs a command of the list of connections, it reports whether the
+// command was handled.
+func (cs *ConnectionSelection) runCommand(command commands.Command) bool {
+	connections := connectionsTable.GetConnections()
+
+	if command == commands.Connect && connectionsTable.ToggleSelectedGroup() {
+		return true
+	}
+
+	if selectedConnection, row, ok := connectionsTable.GetSelectedConnection(); ok {
 		switch command {
-		case commands.NewConnection:
-			connectionForm.SetAction(actionNewConnection)
-			connectionForm.GetFormItemByLabel("Name").(*tview.InputField).SetText("")
-			connectionForm.GetFormItemByLabel("URL").(*tview.InputField).SetText("")
-			connectionForm.StatusText.SetText("")
-			connectionPages.SwitchToPage(pageNameConnectionForm)
-		case commands.Quit:
-			if wrapper.HasFocus() {
-				app.App.Stop()
+		case commands.Connect:
+			go cs.Connect(selectedConnection)
+		case commands.Disconnect:
+			if home := openHome(selectedConnection.Name); home != nil {
+				confirmDisconnect(home)
 			}
+
+			return true
+		case commands.EditConnection:
+			cs.connectionPages.SwitchToPage(pageNameConnectionForm)
+			cs.connectionForm.SetConnection(selectedConnection)
+
+			cs.connectionForm.SetAction(actionEditConnection)
+			return true
+		case commands.DeleteConnection:
+			confirmationModal := NewConfirmationModal("")
+
+			confirmationModal.SetDoneFunc(func(_ int, buttonLabel string) {
+				mainPages.RemovePage(pageNameConfirmation)
+				confirmationModal = nil
+
+				if buttonLabel == "Yes" {
+					newConnections := append(append([]models.Connection{}, connections[:row]...), connections[row+1:]...)
+
+					err := app.App.SaveConnections(newConnections)
+					if err != nil {
+						connectionsTable.SetError(err)
+					} else {
+						connectionsTable.SetConnections(newConnections)
+					}
+
+				}
+			})
+
+			mainPages.AddPage(pageNameConfirmation, confirmationModal, true, true)
+
+			return true
 		}
+	}
 
-		return event
-	})
+	switch command {
+	case commands.Search:
+		App.SetFocus(connectionsTable.Filter)
+		return true
+	case commands.CommandPalette:
+		mainPages.AddPage(pageNameCommandPalette, NewCommandPalette(nil), true, true)
+		return true
+	case commands.NewConnection:
+		cs.connectionForm.SetAction(actionNewConnection)
+		cs.connectionForm.SetConnection(models.Connection{})
+		cs.connectionPages.SwitchToPage(pageNameConnectionForm)
+	case commands.Quit:
+		if cs.HasFocus() {
+			app.App.Stop()
+		}
+	}
 
-	return cs
+	return false
 }
 
 func (cs *ConnectionSelection) Connect(connection models.Connection) *tview.Application {
-	if mainPages.HasPage(connection.Name) {
-		mainPages.SwitchToPage(connection.Name)
+	if home := openHome(connection.Name); home != nil {
+		switchToHome(home)
 		return App.Draw()
 	}
 
+	// The commands live as long as the connection, they are stopped when it
+	// fails or is disconnected.
+	ctx, stopCommands := context.WithCancel(App.Context())
+	connected := false
+
+	defer func() {
+		if !connected {
+			stopCommands()
+		}
+	}()
+
+	port := ""
+
 	if len(connection.Commands) > 0 {
-		port, err := helpers.GetFreePort()
+		var err error
+
+		port, err = helpers.GetFreePort()
 		if err != nil {
-			cs.StatusText.SetText(err.Error()).SetTextStyle(tcell.StyleDefault.Foreground(tcell.ColorRed))
+			cs.StatusText.SetText(err.Error()).SetTextStyle(tcell.StyleDefault.Foreground(app.Styles.ErrorColor))
 			return App.Draw()
 		}
 
 		// Replace ${port} with the actual port.
 		connection.URL = strings.ReplaceAll(connection.URL, "${port}", port)
+	}
 
-		for i, command := range connection.Commands {
-			message := fmt.Sprintf("Running command %d/%d...", i+1, len(connection.Commands))
-			cs.StatusText.SetText(message).SetTextColor(app.Styles.TertiaryTextColor)
-			App.Draw()
+	health := newConnectionHealth(ctx, port, connection.Commands)
 
-			cmd := strings.ReplaceAll(command.Command, "${port}", port)
-			if err := helpers.RunCommand(App.Context(), cmd, App.Register()); err != nil {
-				cs.StatusText.SetText(err.Error()).SetTextStyle(tcell.StyleDefault.Foreground(tcell.ColorRed))
-				return App.Draw()
-			}
+	for i, command := range health.commands {
+		message := fmt.Sprintf("Running command %d/%d...", i+1, len(health.commands))
+		cs.StatusText.SetText(message).SetTextColor(app.Styles.TertiaryTextColor)
+		App.Draw()
 
-			if command.WaitForPort != "" {
-				port := strings.ReplaceAll(command.WaitForPort, "${port}", port)
+		if err := health.startCommand(command); err != nil {
+			cs.StatusText.SetText(err.Error()).SetTextStyle(tcell.StyleDefault.Foreground(app.Styles.ErrorColor))
+			return App.Draw()
+		}
 
-				message := fmt.Sprintf("Waiting for port %s...", port)
-				cs.StatusText.SetText(message).SetTextColor(app.Styles.TertiaryTextColor)
-				App.Draw()
+		if command.WaitForLog != "" || command.WaitForPort != "" || command.WaitForHTTP != "" {
+			message := fmt.Sprintf("Waiting for command %d/%d to be ready...", i+1, len(health.commands))
+			cs.StatusText.SetText(message).SetTextColor(app.Styles.TertiaryTextColor)
+			App.Draw()
 
-				if err := helpers.WaitForPort(App.Context(), port); err != nil {
-					cs.StatusText.SetText(err.Error()).SetTextStyle(tcell.StyleDefault.Foreground(tcell.ColorRed))
-					return App.Draw()
-				}
+			if err := health.waitForReady(command); err != nil {
+				cs.StatusText.SetText(err.Error()).SetTextStyle(tcell.StyleDefault.Foreground(app.Styles.ErrorColor))
+				return App.Draw()
 			}
 		}
 	}
@@ -196,25 +243,89 @@ func (cs *ConnectionSelection) Connect(connection models.Connection) *tview.Appl
 		newDBDriver = &drivers.MSSQL{}
 	case drivers.DriverClickhouse:
 		newDBDriver = &drivers.Clickhouse{}
+	case drivers.DriverDuckDB:
+		newDBDriver = &drivers.DuckDB{}
+	case drivers.DriverFiles:
+		newDBDriver = &drivers.Files{}
+	case drivers.DriverOracle:
+		newDBDriver = &drivers.Oracle{}
 	}
 
-	err := newDBDriver.Connect(connection.URL)
+	newDBDriver, err := drivers.Connect(newDBDriver, connection)
 	if err != nil {
-		cs.StatusText.SetText(err.Error()).SetTextStyle(tcell.StyleDefault.Foreground(tcell.ColorRed))
+		cs.StatusText.SetText(err.Error()).SetTextStyle(tcell.StyleDefault.Foreground(app.Styles.ErrorColor))
 		return App.Draw()
 	}
 
-	selectedRow, selectedCol := connectionsTable.GetSelection()
-	cell := connectionsTable.GetCell(selectedRow, selectedCol)
-	cell.SetText(fmt.Sprintf("[green]* %s", cell.Text))
+	connected = true
+
+	connectionsTable.SetConnected(connection.Name)
 	cs.StatusText.SetText("")
 
 	newHome := NewHomePage(connection, newDBDriver)
+	newHome.stopCommands = stopCommands
+	health.watch(newHome)
 	newHome.Tree.SetCurrentNode(newHome.Tree.GetRoot())
-	newHome.Tree.Wrapper.SetTitle(connection.Name)
+
+	if environment, _, ok := connectionEnvironment(connection); ok {
+		newHome.Tree.Wrapper.SetTitle(fmt.Sprintf("%s (%s)", connection.Name, environment))
+	} else {
+		newHome.Tree.Wrapper.SetTitle(connection.Name)
+	}
 
 	mainPages.AddAndSwitchToPage(connection.Name, newHome, true)
+	addOpenHome(newHome)
 	App.SetFocus(newHome.Tree)
 
 	return App.Draw()
 }
+
+// connectToDatabase opens a new driver for the connection, it is used by the
+// features that work with a second database next to the current one.
+func connectToDatabase(connection models.Connection) (drivers.Driver, error) {
+	if strings.Contains(connection.URL, "${port}") {
+		return nil, fmt.Errorf("connection %s needs its commands to be running, connect to it first", connection.Name)
+	}
+
+	provider := connectionProvider(connection)
+
+	var db drivers.Driver
+
+	switch provider {
+	case drivers.DriverMySQL:
+		db = &drivers.MySQL{}
+	case drivers.DriverPostgres:
+		db = &drivers.Postgres{}
+	case drivers.DriverSqlite:
+		db = &drivers.SQLite{}
+	case drivers.DriverMSSQL:
+		db = &drivers.MSSQL{}
+	case drivers.DriverClickhouse:
+		db = &drivers.Clickhouse{}
+	case drivers.DriverDuckDB:
+		db = &drivers.DuckDB{}
+	case drivers.DriverFiles:
+		db = &drivers.Files{}
+	case drivers.DriverOracle:
+		db = &drivers.Oracle{}
+	default:
+		return nil, fmt.Errorf("unsupported provider %q", provider)
+	}
+
+	return drivers.Connect(db, connection)
+}
+
+// connectionProvider returns the provider of the connection, connections
+// saved by older versions only have the URL.
+func connectionProvider(connection models.Connection) string {
+	if connection.Provider != "" {
+		return connection.Provider
+	}
+
+	parsed, err := helpers.ParseConnectionString(connection.URL)
+	if err != nil {
+		return ""
+	}
+
+	return parsed.Driver
+}
@@ -0,0 +1,203 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/jorgerojas26/lazysql/app"
+	"github.com/jorgerojas26/lazysql/helpers/logger"
+)
+
+// openHomes are the pages of the open connections, in the order they were
+// opened.
+var openHomes []*Home
+
+// openHome returns the page of the open connection with the name.
+func openHome(name string) *Home {
+	for _, home := range openHomes {
+		if home.Connection.Name == name {
+			return home
+		}
+	}
+
+	return nil
+}
+
+// addOpenHome adds the page of a new connection to the switcher.
+func addOpenHome(home *Home) {
+	openHomes = append(openHomes, home)
+	refreshConnectionStrips()
+}
+
+// switchToHome shows the page of the open connection.
+func switchToHome(home *Home) {
+	mainPages.SwitchToPage(home.Connection.Name)
+	App.SetFocus(home)
+	refreshConnectionStrips()
+}
+
+// cycleHome switches to the open connection offset places after the one of
+// the home, wrapping around.
+func cycleHome(home *Home, offset int) {
+	if len(openHomes) < 2 {
+		return
+	}
+
+	for i, h := range openHomes {
+		if h == home {
+			next := (i + offset + len(openHomes)) % len(openHomes)
+			switchToHome(openHomes[next])
+
+			return
+		}
+	}
+}
+
+// refreshConnectionStrips lists the open connections in the status bar of
+// every page, the current one highlighted.
+func refreshConnectionStrips() {
+	for _, home := range openHomes {
+		var text strings.Builder
+
+		for i, h := range openHomes {
+			name := tview.Escape(h.Connection.Name)
+
+			if h == home {
+				fmt.Fprintf(&text, "[%s:%s] %d %s [-:-] ", app.Styles.ContrastSecondaryTextColor, app.Styles.TertiaryTextColor, i+1, name)
+			} else {
+				fmt.Fprintf(&text, " %d %s  ", i+1, name)
+			}
+		}
+
+		home.ConnectionsStrip.SetText(text.String())
+	}
+}
+
+// Disconnect closes the driver of the connection, stops the commands started
+// for it and removes its page. The next open connection is shown, or the list
+// of connections when there is none.
+func (home *Home) Disconnect() {
+	if home.stopCommands != nil {
+		home.stopCommands()
+	}
+
+	if err := home.DBDriver.Close(); err != nil {
+		logger.Error("Could not close the connection", map[string]any{"name": home.Connection.Name, "error": err.Error()})
+	}
+
+	index := -1
+
+	for i, h := range openHomes {
+		if h == home {
+			index = i
+			break
+		}
+	}
+
+	if index >= 0 {
+		openHomes = append(openHomes[:index], openHomes[index+1:]...)
+	}
+
+	mainPages.RemovePage(home.Connection.Name)
+	mainPages.RemovePage(home.Connection.URL)
+	connectionsTable.SetDisconnected(home.Connection.Name)
+
+	if len(openHomes) == 0 {
+		mainPages.SwitchToPage(pageNameConnections)
+		refreshConnectionStrips()
+
+		return
+	}
+
+	switchToHome(openHomes[min(index, len(openHomes)-1)])
+}
+
+// confirmDisconnect asks before disconnecting from the connection.
+func confirmDisconnect(home *Home) {
+	confirmationModal := NewConfirmationModal(fmt.Sprintf("Disconnect from %s?", home.Connection.Name))
+
+	confirmationModal.SetDoneFunc(func(_ int, buttonLabel string) {
+		mainPages.RemovePage(pageNameConfirmation)
+
+		if buttonLabel == "Yes" {
+			home.Disconnect()
+		}
+	})
+
+	mainPages.AddPage(pageNameConfirmation, confirmationModal, true, true)
+}
+
+// ConnectionSwitcher is a picker of the open connections.
+type ConnectionSwitcher struct {
+	*tview.Flex
+	List *tview.List
+}
+
+func NewConnectionSwitcher(current *Home) *ConnectionSwitcher {
+	list := tview.NewList()
+	list.ShowSecondaryText(false)
+	list.SetHighlightFullLine(true)
+	list.SetSelectedStyle(tcell.StyleDefault.Foreground(app.Styles.SecondaryTextColor).Background(app.Styles.PrimitiveBackgroundColor))
+	list.SetBorder(true)
+	list.SetTitle(" Connections ")
+
+	for i, home := range openHomes {
+		shortcut := rune(0)
+
+		if i < 9 {
+			shortcut = rune('1' + i)
+		}
+
+		name := tview.Escape(home.Connection.Name)
+		if _, color, ok := connectionEnvironment(home.Connection); ok {
+			name = fmt.Sprintf("[%s]%s[-]", color.String(), name)
+		}
+
+		list.AddItem(name, "", shortcut, func() {
+			mainPages.RemovePage(pageNameConnectionSwitcher)
+			switchToHome(home)
+		})
+
+		if home == current {
+			list.SetCurrentItem(i)
+		}
+	}
+
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEsc, event.Key() == tcell.KeyRune && event.Rune() == 'q':
+			mainPages.RemovePage(pageNameConnectionSwitcher)
+			App.SetFocus(current)
+
+			return nil
+		case event.Key() == tcell.KeyRune && event.Rune() == 'j':
+			return tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+		case event.Key() == tcell.KeyRune && event.Rune() == 'k':
+			return tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)
+		case event.Key() == tcell.KeyRune && event.Rune() == 'x':
+			home := openHomes[list.GetCurrentItem()]
+			mainPages.RemovePage(pageNameConnectionSwitcher)
+			confirmDisconnect(home)
+
+			return nil
+		}
+
+		return event
+	})
+
+	wrapper := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(list, min(len(openHomes), 15)+2, 0, true).
+			AddItem(nil, 0, 1, false), 40, 0, true).
+		AddItem(nil, 0, 1, false)
+
+	return &ConnectionSwitcher{
+		Flex: wrapper,
+		List: list,
+	}
+}
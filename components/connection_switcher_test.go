@@ -0,0 +1,140 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/rivo/tview"
+
+	"github.com/jorgerojas26/lazysql/drivers"
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// closingDriver records that it was closed, the other methods are not used.
+type closingDriver struct {
+	drivers.Driver
+	closed bool
+}
+
+func (db *closingDriver) Close() error {
+	db.closed = true
+	return nil
+}
+
+// newSwitcherTestHomes opens homes with the names, like connecting to them
+// does, and resets the switcher when the test ends.
+func newSwitcherTestHomes(t *testing.T, names ...string) []*Home {
+	t.Helper()
+
+	mainPages = tview.NewPages()
+	mainPages.AddPage(pageNameConnections, tview.NewBox(), true, true)
+	NewConnectionsTable()
+
+	t.Cleanup(func() { openHomes = nil })
+
+	var homes []*Home
+
+	for _, name := range names {
+		home := &Home{
+			Flex:             tview.NewFlex(),
+			Connection:       models.Connection{Name: name},
+			DBDriver:         &closingDriver{},
+			ConnectionsStrip: tview.NewTextView(),
+		}
+
+		mainPages.AddAndSwitchToPage(name, home, true)
+		addOpenHome(home)
+		homes = append(homes, home)
+	}
+
+	return homes
+}
+
+func currentPage(t *testing.T) string {
+	t.Helper()
+
+	name, _ := mainPages.GetFrontPage()
+
+	return name
+}
+
+func TestCycleHome(t *testing.T) {
+	homes := newSwitcherTestHomes(t, "a", "b", "c")
+
+	testCases := []struct {
+		from     int
+		offset   int
+		expected string
+	}{
+		{from: 0, offset: 1, expected: "b"},
+		{from: 2, offset: 1, expected: "a"},
+		{from: 0, offset: -1, expected: "c"},
+		{from: 1, offset: -1, expected: "a"},
+	}
+
+	for _, tc := range testCases {
+		cycleHome(homes[tc.from], tc.offset)
+
+		if page := currentPage(t); page != tc.expected {
+			t.Fatalf("expected %s after %s%+d, but got %s", tc.expected, homes[tc.from].Connection.Name, tc.offset, page)
+		}
+	}
+
+	if openHome("b") != homes[1] || openHome("d") != nil {
+		t.Fatal("expected openHome to find the open connections only")
+	}
+}
+
+func TestConnectionStrips(t *testing.T) {
+	homes := newSwitcherTestHomes(t, "a", "b")
+
+	for i, home := range homes {
+		text := home.ConnectionsStrip.GetText(true)
+
+		if !strings.Contains(text, "1 a") || !strings.Contains(text, "2 b") {
+			t.Fatalf("expected every open connection in the strip of %s, but got %q", home.Connection.Name, text)
+		}
+
+		// The strip highlights the connection of its page.
+		highlighted := home.ConnectionsStrip.GetText(false)
+		if !strings.Contains(highlighted, fmt.Sprintf("] %d %s [-:-]", i+1, home.Connection.Name)) {
+			t.Fatalf("expected %s to be highlighted, but got %q", home.Connection.Name, highlighted)
+		}
+	}
+}
+
+func TestHome_Disconnect(t *testing.T) {
+	homes := newSwitcherTestHomes(t, "a", "b", "c")
+
+	stopped := false
+	homes[1].stopCommands = func() { stopped = true }
+
+	switchToHome(homes[1])
+	homes[1].Disconnect()
+
+	if !homes[1].DBDriver.(*closingDriver).closed || !stopped {
+		t.Fatal("expected the driver to be closed and the commands to be stopped")
+	}
+
+	if len(openHomes) != 2 || openHome("b") != nil || mainPages.HasPage("b") {
+		t.Fatalf("expected b to be removed, but got %d open connections", len(openHomes))
+	}
+
+	// The next connection is shown.
+	if page := currentPage(t); page != "c" {
+		t.Fatalf("expected c to be shown, but got %s", page)
+	}
+
+	homes[2].Disconnect()
+
+	if page := currentPage(t); page != "a" {
+		t.Fatalf("expected a to be shown, but got %s", page)
+	}
+
+	homes[0].Disconnect()
+
+	if page := currentPage(t); page != pageNameConnections {
+		t.Fatalf("expected the list of connections to be shown, but got %s", page)
+	}
+}
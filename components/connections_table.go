@@ -1,38 +1,88 @@
 package components
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/gdamore/tcell/v2"
+	"github.com/lithammer/fuzzysearch/fuzzy"
 	"github.com/rivo/tview"
 
 	"github.com/jorgerojas26/lazysql/app"
 	"github.com/jorgerojas26/lazysql/models"
 )
 
+// environmentColors are the colours of the theme of the connections whose
+// group or tags name an environment.
+var environmentColors = map[string]*tcell.Color{
+	"prod":        &app.Styles.ErrorColor,
+	"production":  &app.Styles.ErrorColor,
+	"staging":     &app.Styles.WarningColor,
+	"stage":       &app.Styles.WarningColor,
+	"dev":         &app.Styles.SuccessColor,
+	"development": &app.Styles.SuccessColor,
+	"local":       &app.Styles.SuccessColor,
+}
+
+// connectionRow is a row of the table, either the header of a group or a
+// connection.
+type connectionRow struct {
+	group string
+	// index is the index of the connection, -1 for the header of a group.
+	index int
+}
+
 type ConnectionsTable struct {
 	*tview.Table
 	Wrapper       *tview.Flex
+	Filter        *tview.InputField
 	errorTextView *tview.TextView
 	error         string
 	connections   []models.Connection
+	rows          []connectionRow
+	collapsed     map[string]bool
+	connected     map[string]bool
 }
 
 var connectionsTable *ConnectionsTable
 
 func NewConnectionsTable() *ConnectionsTable {
-	wrapper := tview.NewFlex()
+	wrapper := tview.NewFlex().SetDirection(tview.FlexRow)
 
 	errorTextView := tview.NewTextView()
-	errorTextView.SetTextStyle(tcell.StyleDefault.Foreground(tcell.ColorRed))
+	errorTextView.SetTextStyle(tcell.StyleDefault.Foreground(app.Styles.ErrorColor))
+
+	filter := tview.NewInputField()
+	filter.SetLabel("Filter: ")
+	filter.SetLabelColor(app.Styles.TertiaryTextColor)
+	filter.SetFieldBackgroundColor(app.Styles.PrimitiveBackgroundColor)
+	filter.SetFieldTextColor(app.Styles.PrimaryTextColor)
 
 	table := &ConnectionsTable{
 		Table:         tview.NewTable().SetSelectable(true, false),
 		Wrapper:       wrapper,
+		Filter:        filter,
 		errorTextView: errorTextView,
+		collapsed:     map[string]bool{},
+		connected:     map[string]bool{},
 	}
 
 	table.SetOffset(5, 0)
 	table.SetSelectedStyle(tcell.StyleDefault.Foreground(app.Styles.SecondaryTextColor).Background(tview.Styles.PrimitiveBackgroundColor))
 
+	filter.SetChangedFunc(func(string) {
+		table.render(-1)
+	})
+
+	filter.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEscape {
+			filter.SetText("")
+		}
+
+		App.SetFocus(table)
+	})
+
+	wrapper.AddItem(filter, 1, 0, false)
 	wrapper.AddItem(table, 0, 1, true)
 	table.SetConnections(app.App.Connections())
 
@@ -41,34 +91,203 @@ func NewConnectionsTable() *ConnectionsTable {
 	return connectionsTable
 }
 
-func (ct *ConnectionsTable) AddConnection(connection models.Connection) {
-	rowCount := ct.GetRowCount()
-	ct.SetCellSimple(rowCount, 0, connection.Name)
-	ct.connections = append(ct.connections, connection)
-}
-
 func (ct *ConnectionsTable) GetConnections() []models.Connection {
 	return ct.connections
 }
 
+// GetSelectedConnection returns the selected connection and its index in
+// the connections, ok is false when the header of a group is selected.
+func (ct *ConnectionsTable) GetSelectedConnection() (connection models.Connection, index int, ok bool) {
+	row, _ := ct.GetSelection()
+	if row < 0 || row >= len(ct.rows) || ct.rows[row].index < 0 {
+		return models.Connection{}, -1, false
+	}
+
+	index = ct.rows[row].index
+
+	return ct.connections[index], index, true
+}
+
+// ToggleSelectedGroup collapses or expands the group of the selected header,
+// it returns false when a connection is selected.
+func (ct *ConnectionsTable) ToggleSelectedGroup() bool {
+	row, _ := ct.GetSelection()
+	if row < 0 || row >= len(ct.rows) || ct.rows[row].index >= 0 {
+		return false
+	}
+
+	group := ct.rows[row].group
+	ct.collapsed[group] = !ct.collapsed[group]
+	ct.render(-1)
+
+	// Keep the header selected.
+	for i, r := range ct.rows {
+		if r.index < 0 && r.group == group {
+			ct.Select(i, 0)
+		}
+	}
+
+	return true
+}
+
+// SetConnected marks the connection as connected in the list.
+func (ct *ConnectionsTable) SetConnected(name string) {
+	ct.connected[name] = true
+
+	_, index, _ := ct.GetSelectedConnection()
+	ct.render(index)
+}
+
+// SetDisconnected removes the connected mark of the connection.
+func (ct *ConnectionsTable) SetDisconnected(name string) {
+	delete(ct.connected, name)
+
+	_, index, _ := ct.GetSelectedConnection()
+	ct.render(index)
+}
+
 func (ct *ConnectionsTable) GetError() string {
 	return ct.error
 }
 
 func (ct *ConnectionsTable) SetConnections(connections []models.Connection) {
-	ct.connections = make([]models.Connection, 0)
+	ct.connections = connections
+
+	ct.render(-1)
+	App.ForceDraw()
+}
+
+func (ct *ConnectionsTable) SetError(err error) {
+	ct.error = err.Error()
+	ct.errorTextView.SetText(ct.error)
+}
+
+// render lists the connections that match the filter under their groups and
+// selects the connection with the index, or the first row.
+func (ct *ConnectionsTable) render(selected int) {
+	if selected < 0 {
+		_, selected, _ = ct.GetSelectedConnection()
+	}
+
+	filter := strings.TrimSpace(ct.Filter.GetText())
+
+	var groups []string
+
+	members := map[string][]int{}
+
+	for i, connection := range ct.connections {
+		if !connectionMatches(connection, filter) {
+			continue
+		}
+
+		if _, ok := members[connection.Group]; !ok {
+			groups = append(groups, connection.Group)
+		}
+
+		members[connection.Group] = append(members[connection.Group], i)
+	}
 
 	ct.Clear()
+	ct.rows = nil
+
+	// The connections without a group come first, without a header.
+	for _, index := range members[""] {
+		ct.addConnectionRow(index, "")
+	}
+
+	for _, group := range groups {
+		if group == "" {
+			continue
+		}
+
+		// The groups with matches are expanded while filtering.
+		collapsed := ct.collapsed[group] && filter == ""
 
-	for _, connection := range connections {
-		ct.AddConnection(connection)
+		arrow := "▾"
+		if collapsed {
+			arrow = "▸"
+		}
+
+		header := fmt.Sprintf("%s %s (%d)", arrow, tview.Escape(group), len(members[group]))
+		color := app.Styles.TertiaryTextColor
+		if environmentColor, ok := environmentColors[strings.ToLower(group)]; ok {
+			color = *environmentColor
+		}
+
+		ct.SetCell(len(ct.rows), 0, tview.NewTableCell(header).SetTextColor(color))
+		ct.rows = append(ct.rows, connectionRow{group: group, index: -1})
+
+		if collapsed {
+			continue
+		}
+
+		for _, index := range members[group] {
+			ct.addConnectionRow(index, "  ")
+		}
 	}
 
 	ct.Select(0, 0)
-	App.ForceDraw()
+
+	for i, r := range ct.rows {
+		if r.index >= 0 && r.index == selected {
+			ct.Select(i, 0)
+		}
+	}
 }
 
-func (ct *ConnectionsTable) SetError(err error) {
-	ct.error = err.Error()
-	ct.errorTextView.SetText(ct.error)
+func (ct *ConnectionsTable) addConnectionRow(index int, indent string) {
+	connection := ct.connections[index]
+	row := len(ct.rows)
+
+	name := indent + tview.Escape(connection.Name)
+	if ct.connected[connection.Name] {
+		name = fmt.Sprintf("%s[%s]* %s", indent, app.Styles.SuccessColor, tview.Escape(connection.Name))
+	}
+
+	nameCell := tview.NewTableCell(name).SetExpansion(1)
+	if _, color, ok := connectionEnvironment(connection); ok {
+		nameCell.SetTextColor(color)
+	}
+
+	ct.SetCell(row, 0, nameCell)
+	ct.SetCell(row, 1, tview.NewTableCell(connectionProvider(connection)).SetTextColor(app.Styles.InverseTextColor))
+
+	tags := make([]string, len(connection.Tags))
+	for i, tag := range connection.Tags {
+		tags[i] = "#" + tview.Escape(tag)
+	}
+
+	ct.SetCell(row, 2, tview.NewTableCell(strings.Join(tags, " ")).SetTextColor(app.Styles.TertiaryTextColor))
+
+	ct.rows = append(ct.rows, connectionRow{group: connection.Group, index: index})
+}
+
+// connectionMatches reports whether the filter fuzzy matches the name, the
+// group, a tag or the provider of the connection.
+func connectionMatches(connection models.Connection, filter string) bool {
+	if filter == "" {
+		return true
+	}
+
+	candidates := append([]string{connection.Name, connection.Group, connectionProvider(connection)}, connection.Tags...)
+
+	for _, candidate := range candidates {
+		if candidate != "" && fuzzy.MatchFold(filter, candidate) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// connectionEnvironment returns the environment named by a tag or the group of
+// the connection, and its colour.
+func connectionEnvironment(connection models.Connection) (string, tcell.Color, bool) {
+	for _, name := range append(append([]string{}, connection.Tags...), connection.Group) {
+		if color, ok := environmentColors[strings.ToLower(name)]; ok {
+			return name, *color, true
+		}
+	}
+
+	return "", tcell.ColorDefault, false
 }
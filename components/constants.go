@@ -1,8 +1,6 @@
 package components
 
 import (
-	"github.com/gdamore/tcell/v2"
-
 	"github.com/jorgerojas26/lazysql/app"
 )
 
@@ -16,6 +14,9 @@ const (
 	pageNameConnections  string = "Connections"
 	pageNameDMLPreview   string = "DMLPreview"
 
+	// Command palette
+	pageNameCommandPalette string = "CommandPalette"
+
 	// Results table
 	pageNameTable                  string = "Table"
 	pageNameTableError             string = "TableError"
@@ -31,9 +32,32 @@ const (
 	// Connections
 	pageNameConnectionSelection string = "ConnectionSelection"
 	pageNameConnectionForm      string = "ConnectionForm"
+	pageNameConnectionSwitcher  string = "ConnectionSwitcher"
+	pageNameConnectionCommands  string = "ConnectionCommands"
 
 	// SetValueList
 	pageNameSetValue string = "SetValue"
+
+	// Schema diff
+	pageNameSchemaDiffForm string = "SchemaDiffForm"
+	pageNameSchemaDiff     string = "SchemaDiff"
+
+	// Data diff
+	pageNameDataDiffForm string = "DataDiffForm"
+	pageNameDataDiff     string = "DataDiff"
+
+	// Copy table
+	pageNameCopyTable string = "CopyTable"
+
+	// Dump and restore
+	pageNameDump    string = "Dump"
+	pageNameRestore string = "Restore"
+
+	// ER diagram
+	pageNameERDiagram string = "ERDiagram"
+
+	// Attach database
+	pageNameAttachDatabase string = "AttachDatabase"
 )
 
 // Tabs
@@ -57,6 +81,13 @@ const (
 	eventTreeSelectedDatabase string = "SelectedDatabase"
 	eventTreeSelectedTable    string = "SelectedTable"
 	eventTreeIsFiltering      string = "IsFiltering"
+	eventTreeSchemaDiff       string = "SchemaDiff"
+	eventTreeDataDiff         string = "DataDiff"
+	eventTreeCopyTable        string = "CopyTable"
+	eventTreeDump             string = "Dump"
+	eventTreeRestore          string = "Restore"
+	eventTreeERDiagram        string = "ERDiagram"
+	eventTreeAttachDatabase   string = "AttachDatabase"
 )
 
 // Results table menu items
@@ -78,8 +109,4 @@ const (
 const (
 	focusedWrapperLeft  string = "left"
 	focusedWrapperRight string = "right"
-
-	colorTableChange = tcell.ColorOrange
-	colorTableInsert = tcell.ColorDarkGreen
-	colorTableDelete = tcell.ColorRed
 )
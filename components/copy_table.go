@@ -0,0 +1,161 @@
+package components
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/jorgerojas26/lazysql/app"
+	"github.com/jorgerojas26/lazysql/drivers"
+	"github.com/jorgerojas26/lazysql/helpers/logger"
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+type CopyTableForm struct {
+	*tview.Flex
+	Form       *tview.Form
+	StatusText *tview.TextView
+	Home       *Home
+	task       backgroundTask
+}
+
+// NewCopyTableForm asks for the connection, database and table the selected
+// table is copied to.
+func NewCopyTableForm(home *Home, sourceDatabase, sourceTable string) *CopyTableForm {
+	form := tview.NewForm().SetFieldBackgroundColor(app.Styles.InverseTextColor).SetButtonBackgroundColor(tview.Styles.InverseTextColor).SetLabelColor(tview.Styles.PrimaryTextColor).SetFieldTextColor(tview.Styles.ContrastSecondaryTextColor)
+
+	connections := []models.Connection{}
+	connectionNames := []string{}
+
+	for _, conn := range app.App.Connections() {
+		if conn.Name != home.Connection.Name {
+			connections = append(connections, conn)
+			connectionNames = append(connectionNames, conn.Name)
+		}
+	}
+
+	connections = append(connections, home.Connection)
+	connectionNames = append(connectionNames, schemaDiffCurrentConnection)
+
+	targetTableField := tview.NewInputField().SetLabel("Target table")
+	targetDatabaseField := tview.NewInputField().SetLabel("Target database")
+
+	connectionDropDown := tview.NewDropDown().SetLabel("Target connection")
+	connectionDropDown.SetOptions(connectionNames, func(_ string, index int) {
+		if index < 0 {
+			return
+		}
+
+		connection := connections[index]
+
+		targetDatabaseField.SetText(connection.DBName)
+		targetTableField.SetText(drivers.CopyTableName(sourceTable, connectionProvider(connection)))
+	})
+
+	form.AddFormItem(connectionDropDown)
+	form.AddFormItem(targetDatabaseField)
+	form.AddFormItem(targetTableField)
+
+	connectionDropDown.SetCurrentOption(0)
+
+	statusText := tview.NewTextView()
+	statusText.SetBorderPadding(0, 0, 1, 1)
+	statusText.SetText("Enter to copy, Esc to cancel")
+	statusText.SetTextColor(app.Styles.TertiaryTextColor)
+
+	container := tview.NewFlex().SetDirection(tview.FlexRow)
+	container.SetBorder(true)
+	container.SetTitle(fmt.Sprintf(" Copy %s to connection ", sourceTable))
+	container.AddItem(form, 0, 1, true)
+	container.AddItem(statusText, 1, 0, false)
+
+	wrapper := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(container, 11, 0, true).
+			AddItem(nil, 0, 1, false), 0, 2, true).
+		AddItem(nil, 0, 1, false)
+
+	copyTableForm := &CopyTableForm{
+		Flex:       wrapper,
+		Form:       form,
+		StatusText: statusText,
+		Home:       home,
+	}
+
+	form.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEsc:
+			// Esc cancels a running copy before closing the form.
+			if copyTableForm.task.stop() {
+				return nil
+			}
+
+			mainPages.RemovePage(pageNameCopyTable)
+			return nil
+		case tcell.KeyEnter:
+			if _, ok := App.GetFocus().(*tview.DropDown); ok {
+				return event
+			}
+
+			index, _ := connectionDropDown.GetCurrentOption()
+			if index < 0 {
+				return nil
+			}
+
+			ctx, ok := copyTableForm.task.start()
+			if !ok {
+				return nil
+			}
+
+			source := drivers.DataDiffTable{Driver: home.DBDriver, Database: sourceDatabase, Table: sourceTable}
+
+			go copyTableForm.copy(ctx, source, connections[index], targetDatabaseField.GetText(), targetTableField.GetText())
+			return nil
+		}
+
+		return event
+	})
+
+	return copyTableForm
+}
+
+func (form *CopyTableForm) copy(ctx context.Context, source drivers.DataDiffTable, targetConnection models.Connection, targetDatabase, targetTable string) {
+	defer form.task.done()
+
+	form.setStatus("Connecting...", app.Styles.TertiaryTextColor)
+
+	// The current connection gets its own driver too, so the copy does not
+	// block the tree and the tables while it runs.
+	target, err := connectToDatabase(targetConnection)
+	if err != nil {
+		form.setStatus(err.Error(), app.Styles.ErrorColor)
+		return
+	}
+	defer target.Close()
+
+	copied, err := drivers.CopyTable(ctx, source, drivers.DataDiffTable{Driver: target, Database: targetDatabase, Table: targetTable}, func(copied, total int) {
+		form.setStatus(fmt.Sprintf("Copied %d/%d rows, Esc to cancel...", copied, total), app.Styles.TertiaryTextColor)
+	})
+	if err != nil {
+		logger.Info("Error copying table", map[string]any{"table": source.Table, "copied": copied, "error": err.Error()})
+
+		if ctx.Err() != nil {
+			form.setStatus(fmt.Sprintf("Cancelled after %d rows", copied), app.Styles.TertiaryTextColor)
+		} else {
+			form.setStatus(fmt.Sprintf("Copied %d rows: %s", copied, err.Error()), app.Styles.ErrorColor)
+		}
+
+		return
+	}
+
+	form.setStatus(fmt.Sprintf("Copied %d rows to %s/%s, Esc to close", copied, targetConnection.Name, targetTable), app.Styles.TertiaryTextColor)
+}
+
+func (form *CopyTableForm) setStatus(text string, color tcell.Color) {
+	form.StatusText.SetText(text).SetTextColor(color)
+	App.Draw()
+}
@@ -0,0 +1,293 @@
+package components
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/jorgerojas26/lazysql/app"
+	"github.com/jorgerojas26/lazysql/commands"
+	"github.com/jorgerojas26/lazysql/drivers"
+	"github.com/jorgerojas26/lazysql/helpers/logger"
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+type DataDiffForm struct {
+	*tview.Flex
+	Form       *tview.Form
+	StatusText *tview.TextView
+	Home       *Home
+	task       backgroundTask
+}
+
+// NewDataDiffForm asks for the table to compare the selected table against.
+func NewDataDiffForm(home *Home, sourceDatabase, sourceTable string) *DataDiffForm {
+	form := tview.NewForm().SetFieldBackgroundColor(app.Styles.InverseTextColor).SetButtonBackgroundColor(tview.Styles.InverseTextColor).SetLabelColor(tview.Styles.PrimaryTextColor).SetFieldTextColor(tview.Styles.ContrastSecondaryTextColor)
+
+	connectionNames := []string{schemaDiffCurrentConnection}
+	connections := app.App.Connections()
+
+	for _, conn := range connections {
+		if conn.Name != home.Connection.Name {
+			connectionNames = append(connectionNames, conn.Name)
+		}
+	}
+
+	form.AddDropDown("Target connection", connectionNames, 0, nil)
+	form.AddInputField("Target database", sourceDatabase, 0, nil, nil)
+	form.AddInputField("Target table", sourceTable, 0, nil, nil)
+
+	statusText := tview.NewTextView()
+	statusText.SetBorderPadding(0, 0, 1, 1)
+	statusText.SetText("Enter to compare, Esc to cancel")
+	statusText.SetTextColor(app.Styles.TertiaryTextColor)
+
+	container := tview.NewFlex().SetDirection(tview.FlexRow)
+	container.SetBorder(true)
+	container.SetTitle(fmt.Sprintf(" Data diff: %s ", sourceTable))
+	container.AddItem(form, 0, 1, true)
+	container.AddItem(statusText, 1, 0, false)
+
+	wrapper := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(container, 11, 0, true).
+			AddItem(nil, 0, 1, false), 0, 2, true).
+		AddItem(nil, 0, 1, false)
+
+	dataDiffForm := &DataDiffForm{
+		Flex:       wrapper,
+		Form:       form,
+		StatusText: statusText,
+		Home:       home,
+	}
+
+	form.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEsc:
+			// Esc cancels a running diff before closing the form.
+			if dataDiffForm.task.stop() {
+				return nil
+			}
+
+			mainPages.RemovePage(pageNameDataDiffForm)
+			return nil
+		case tcell.KeyEnter:
+			if _, ok := App.GetFocus().(*tview.DropDown); ok {
+				return event
+			}
+
+			_, targetConnectionName := form.GetFormItemByLabel("Target connection").(*tview.DropDown).GetCurrentOption()
+			targetDatabase := form.GetFormItemByLabel("Target database").(*tview.InputField).GetText()
+			targetTable := form.GetFormItemByLabel("Target table").(*tview.InputField).GetText()
+
+			var targetConnection *models.Connection
+			for i, conn := range connections {
+				if conn.Name == targetConnectionName {
+					targetConnection = &connections[i]
+				}
+			}
+
+			ctx, ok := dataDiffForm.task.start()
+			if !ok {
+				return nil
+			}
+
+			source := drivers.DataDiffTable{Driver: home.DBDriver, Database: sourceDatabase, Table: sourceTable}
+
+			go dataDiffForm.compare(ctx, source, targetConnection, targetDatabase, targetTable)
+			return nil
+		}
+
+		return event
+	})
+
+	return dataDiffForm
+}
+
+// compare runs the diff, targetConnection is nil when the target table is on
+// the current connection.
+func (form *DataDiffForm) compare(ctx context.Context, source drivers.DataDiffTable, targetConnection *models.Connection, targetDatabase, targetTable string) {
+	defer form.task.done()
+
+	form.setStatus("Connecting...", app.Styles.TertiaryTextColor)
+
+	target := drivers.DataDiffTable{Driver: form.Home.DBDriver, Database: targetDatabase, Table: targetTable}
+
+	if targetConnection != nil {
+		targetDriver, err := connectToDatabase(*targetConnection)
+		if err != nil {
+			form.setStatus(err.Error(), app.Styles.ErrorColor)
+			return
+		}
+
+		target.Driver = targetDriver
+	}
+
+	closeTarget := func() {
+		if targetConnection != nil {
+			target.Driver.Close()
+		}
+	}
+
+	diff, err := drivers.DiffTableData(ctx, source, target, func(compared int) {
+		form.setStatus(fmt.Sprintf("Compared %d rows, Esc to cancel...", compared), app.Styles.TertiaryTextColor)
+	})
+	if err != nil {
+		closeTarget()
+
+		if ctx.Err() != nil {
+			form.setStatus("Cancelled", app.Styles.TertiaryTextColor)
+		} else {
+			form.setStatus(err.Error(), app.Styles.ErrorColor)
+		}
+
+		return
+	}
+
+	logger.Info("Data diff", map[string]any{"source": source.Table, "target": targetTable, "rows": len(diff.Rows)})
+
+	view := NewDataDiffView(diff, source, target, func(changes []models.DBDMLChange) {
+		if targetConnection == nil {
+			// The changes are added to the pending changes of the current
+			// connection, they are reviewed and saved as any other edit.
+			form.Home.ListOfDBChanges = append(form.Home.ListOfDBChanges, changes...)
+			mainPages.RemovePage(pageNameDataDiff)
+			return
+		}
+
+		queryPreviewModal := NewQueryPreviewModal(&changes, target.Driver, func() {
+			mainPages.RemovePage(pageNameDataDiff)
+			closeTarget()
+		})
+
+		mainPages.AddPage(pageNameDMLPreview, queryPreviewModal, true, true)
+	}, closeTarget)
+
+	mainPages.RemovePage(pageNameDataDiffForm)
+	mainPages.AddPage(pageNameDataDiff, view, true, true)
+	App.Draw()
+}
+
+func (form *DataDiffForm) setStatus(text string, color tcell.Color) {
+	form.StatusText.SetText(text).SetTextColor(color)
+	App.Draw()
+}
+
+type DataDiffView struct {
+	*tview.Flex
+	Table *tview.Table
+}
+
+// NewDataDiffView lists the rows that differ. Changed rows are shown as a
+// pair of source and target rows with the changed cells highlighted.
+func NewDataDiffView(diff models.DataDiff, source, target drivers.DataDiffTable, onQueue func([]models.DBDMLChange), onClose func()) *DataDiffView {
+	table := tview.NewTable()
+	table.SetBorder(true)
+	table.SetTitle(fmt.Sprintf(" %s → %s (%d source rows, %d target rows, %d differences) ", source.Table, target.Table, diff.SourceRowCount, diff.TargetRowCount, len(diff.Rows)))
+	table.SetSelectable(true, false)
+	table.SetFixed(1, 1)
+	table.SetSelectedStyle(tcell.StyleDefault.Background(app.Styles.SecondaryTextColor).Foreground(tview.Styles.ContrastSecondaryTextColor))
+
+	keybindings := tview.NewTextView()
+	keybindings.SetDynamicColors(true)
+	keybindings.SetWrap(false)
+	keybindings.SetBorder(true)
+	keybindings.SetTitle(" Keybindings ")
+
+	for _, command := range app.Keymaps.Group(app.DataDiffGroup) {
+		keybindings.SetText(fmt.Sprintf("%s [%s](%s) [default]%s", keybindings.GetText(false), app.Styles.SecondaryTextColor, command.Keys().String(), command.Description))
+	}
+
+	container := tview.NewFlex().SetDirection(tview.FlexRow)
+	container.AddItem(table, 0, 1, true)
+	container.AddItem(keybindings, 3, 0, false)
+
+	view := &DataDiffView{
+		Flex:  container,
+		Table: table,
+	}
+
+	view.populateTable(diff)
+
+	container.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		command := app.Keymaps.Resolve(app.DataDiffGroup, event)
+
+		switch {
+		case command == commands.Quit || event.Key() == tcell.KeyEsc:
+			mainPages.RemovePage(pageNameDataDiff)
+			onClose()
+			return nil
+		case command == commands.Save:
+			changes := drivers.DataDiffChanges(diff, target.Database, target.Table)
+			if len(changes) > 0 {
+				onQueue(changes)
+			}
+			return nil
+		}
+
+		return event
+	})
+
+	return view
+}
+
+func (view *DataDiffView) populateTable(diff models.DataDiff) {
+	view.Table.SetCell(0, 0, tview.NewTableCell("").SetSelectable(false))
+
+	for i, column := range diff.Columns {
+		view.Table.SetCell(0, i+1, tview.NewTableCell(column).SetSelectable(false).SetTextColor(app.Styles.TertiaryTextColor))
+	}
+
+	row := 1
+
+	for _, rowDiff := range diff.Rows {
+		switch rowDiff.Status {
+		case models.SchemaDiffAdded:
+			view.setRow(row, "+ source", rowDiff.Source, app.Styles.InsertedRowColor, nil)
+			row++
+		case models.SchemaDiffRemoved:
+			view.setRow(row, "- target", rowDiff.Target, app.Styles.DeletedRowColor, nil)
+			row++
+		case models.SchemaDiffChanged:
+			view.setRow(row, "~ source", rowDiff.Source, app.Styles.PrimaryTextColor, rowDiff.ChangedColumns)
+			view.setRow(row+1, "~ target", rowDiff.Target, app.Styles.PrimaryTextColor, rowDiff.ChangedColumns)
+			row += 2
+		}
+	}
+
+	view.Table.Select(1, 0)
+}
+
+func (view *DataDiffView) setRow(row int, label string, values []string, color tcell.Color, changedColumns []int) {
+	view.Table.SetCell(row, 0, tview.NewTableCell(label).SetTextColor(color))
+
+	for i, value := range values {
+		cell := tview.NewTableCell(tview.Escape(displayRecordValue(value))).SetTextColor(color).SetMaxWidth(40)
+
+		for _, changed := range changedColumns {
+			if changed == i {
+				cell.SetTextColor(tview.Styles.ContrastSecondaryTextColor)
+				cell.SetBackgroundColor(app.Styles.ChangedRowColor)
+			}
+		}
+
+		view.Table.SetCell(row, i+1, cell)
+	}
+}
+
+// displayRecordValue turns the NULL and empty markers returned by the drivers
+// into their display values.
+func displayRecordValue(value string) string {
+	switch value {
+	case "NULL&":
+		return "NULL"
+	case "EMPTY&":
+		return "EMPTY"
+	}
+
+	return value
+}
@@ -0,0 +1,261 @@
+package components
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/jorgerojas26/lazysql/app"
+	"github.com/jorgerojas26/lazysql/drivers"
+	"github.com/jorgerojas26/lazysql/helpers/logger"
+)
+
+type DumpForm struct {
+	*tview.Flex
+	Form       *tview.Form
+	StatusText *tview.TextView
+	Home       *Home
+	task       backgroundTask
+}
+
+// NewDumpForm asks for the file the selected table, or every table of the
+// database when no table is selected, is dumped to.
+func NewDumpForm(home *Home, database, table string) *DumpForm {
+	form := tview.NewForm().SetFieldBackgroundColor(app.Styles.InverseTextColor).SetButtonBackgroundColor(tview.Styles.InverseTextColor).SetLabelColor(tview.Styles.PrimaryTextColor).SetFieldTextColor(tview.Styles.ContrastSecondaryTextColor)
+
+	name := table
+	if name == "" {
+		name = database
+	}
+
+	form.AddInputField("File", name+".sql", 0, nil, nil)
+	form.AddInputField("Tables", table, 0, nil, nil)
+
+	statusText := tview.NewTextView()
+	statusText.SetBorderPadding(0, 0, 1, 1)
+	statusText.SetText("Leave tables empty to dump the whole database. Enter to dump, Esc to cancel")
+	statusText.SetTextColor(app.Styles.TertiaryTextColor)
+
+	container := tview.NewFlex().SetDirection(tview.FlexRow)
+	container.SetBorder(true)
+	container.SetTitle(fmt.Sprintf(" Dump %s ", name))
+	container.AddItem(form, 0, 1, true)
+	container.AddItem(statusText, 1, 0, false)
+
+	wrapper := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(container, 9, 0, true).
+			AddItem(nil, 0, 1, false), 0, 2, true).
+		AddItem(nil, 0, 1, false)
+
+	dumpForm := &DumpForm{
+		Flex:       wrapper,
+		Form:       form,
+		StatusText: statusText,
+		Home:       home,
+	}
+
+	form.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEsc:
+			// Esc cancels a running dump before closing the form.
+			if dumpForm.task.stop() {
+				return nil
+			}
+
+			mainPages.RemovePage(pageNameDump)
+			return nil
+		case tcell.KeyEnter:
+			file := form.GetFormItemByLabel("File").(*tview.InputField).GetText()
+			tables := splitTableList(form.GetFormItemByLabel("Tables").(*tview.InputField).GetText())
+
+			if file == "" {
+				dumpForm.setStatus("File is required", app.Styles.ErrorColor)
+				return nil
+			}
+
+			ctx, ok := dumpForm.task.start()
+			if !ok {
+				return nil
+			}
+
+			go dumpForm.dump(ctx, database, tables, file)
+			return nil
+		}
+
+		return event
+	})
+
+	return dumpForm
+}
+
+func (form *DumpForm) dump(ctx context.Context, database string, tables []string, file string) {
+	defer form.task.done()
+
+	output, err := os.Create(file)
+	if err != nil {
+		form.setStatus(err.Error(), app.Styles.ErrorColor)
+		return
+	}
+
+	err = drivers.DumpTables(ctx, form.Home.DBDriver, database, tables, output, func(table string, rows int) {
+		form.setStatus(fmt.Sprintf("Dumped %d rows of %s, Esc to cancel...", rows, table), app.Styles.TertiaryTextColor)
+	})
+
+	if closeErr := output.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		logger.Info("Error dumping database", map[string]any{"database": database, "file": file, "error": err.Error()})
+
+		// A partial dump would restore silently incomplete data.
+		os.Remove(file)
+
+		if ctx.Err() != nil {
+			form.setStatus("Cancelled", app.Styles.TertiaryTextColor)
+		} else {
+			form.setStatus(err.Error(), app.Styles.ErrorColor)
+		}
+
+		return
+	}
+
+	form.setStatus(fmt.Sprintf("Dumped to %s, Esc to close", file), app.Styles.TertiaryTextColor)
+}
+
+func (form *DumpForm) setStatus(text string, color tcell.Color) {
+	form.StatusText.SetText(text).SetTextColor(color)
+	App.Draw()
+}
+
+type RestoreForm struct {
+	*tview.Flex
+	Form       *tview.Form
+	StatusText *tview.TextView
+	Home       *Home
+	task       backgroundTask
+}
+
+// NewRestoreForm asks for the SQL file executed on the current connection.
+func NewRestoreForm(home *Home, database string) *RestoreForm {
+	form := tview.NewForm().SetFieldBackgroundColor(app.Styles.InverseTextColor).SetButtonBackgroundColor(tview.Styles.InverseTextColor).SetLabelColor(tview.Styles.PrimaryTextColor).SetFieldTextColor(tview.Styles.ContrastSecondaryTextColor)
+
+	form.AddInputField("File", "", 0, nil, nil)
+
+	statusText := tview.NewTextView()
+	statusText.SetBorderPadding(0, 0, 1, 1)
+	statusText.SetText("Enter to restore, Esc to cancel")
+	statusText.SetTextColor(app.Styles.TertiaryTextColor)
+
+	container := tview.NewFlex().SetDirection(tview.FlexRow)
+	container.SetBorder(true)
+	container.SetTitle(fmt.Sprintf(" Restore into %s ", database))
+	container.AddItem(form, 0, 1, true)
+	container.AddItem(statusText, 1, 0, false)
+
+	wrapper := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(container, 7, 0, true).
+			AddItem(nil, 0, 1, false), 0, 2, true).
+		AddItem(nil, 0, 1, false)
+
+	restoreForm := &RestoreForm{
+		Flex:       wrapper,
+		Form:       form,
+		StatusText: statusText,
+		Home:       home,
+	}
+
+	form.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEsc:
+			// Esc stops a running restore before closing the form, the
+			// statements that already ran are kept.
+			if restoreForm.task.stop() {
+				return nil
+			}
+
+			mainPages.RemovePage(pageNameRestore)
+			return nil
+		case tcell.KeyEnter:
+			file := form.GetFormItemByLabel("File").(*tview.InputField).GetText()
+
+			if file == "" {
+				restoreForm.setStatus("File is required", app.Styles.ErrorColor)
+				return nil
+			}
+
+			ctx, ok := restoreForm.task.start()
+			if !ok {
+				return nil
+			}
+
+			go restoreForm.restore(ctx, database, file)
+			return nil
+		}
+
+		return event
+	})
+
+	return restoreForm
+}
+
+func (form *RestoreForm) restore(ctx context.Context, database, file string) {
+	defer form.task.done()
+
+	script, err := os.ReadFile(file)
+	if err != nil {
+		form.setStatus(err.Error(), app.Styles.ErrorColor)
+		return
+	}
+
+	executed, err := drivers.RestoreScript(ctx, form.Home.DBDriver, string(script), func(executed, total int) {
+		form.setStatus(fmt.Sprintf("Executed %d/%d statements, Esc to stop...", executed, total), app.Styles.TertiaryTextColor)
+	})
+
+	// The restore may have created tables even if it did not finish.
+	if executed > 0 {
+		form.Home.Tree.Refresh(database)
+	}
+
+	if err != nil {
+		logger.Info("Error restoring file", map[string]any{"file": file, "executed": executed, "error": err.Error()})
+
+		if ctx.Err() != nil {
+			form.setStatus(fmt.Sprintf("Stopped after %d statements", executed), app.Styles.TertiaryTextColor)
+		} else {
+			form.setStatus(err.Error(), app.Styles.ErrorColor)
+		}
+
+		return
+	}
+
+	form.setStatus(fmt.Sprintf("Executed %d statements, Esc to close", executed), app.Styles.TertiaryTextColor)
+}
+
+func (form *RestoreForm) setStatus(text string, color tcell.Color) {
+	form.StatusText.SetText(text).SetTextColor(color)
+	App.Draw()
+}
+
+// splitTableList returns the comma separated tables of a form field.
+func splitTableList(text string) []string {
+	var tables []string
+
+	for _, table := range strings.Split(text, ",") {
+		if table = strings.TrimSpace(table); table != "" {
+			tables = append(tables, table)
+		}
+	}
+
+	return tables
+}
@@ -0,0 +1,258 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/jorgerojas26/lazysql/app"
+	"github.com/jorgerojas26/lazysql/commands"
+	"github.com/jorgerojas26/lazysql/drivers"
+	"github.com/jorgerojas26/lazysql/helpers/logger"
+	"github.com/jorgerojas26/lazysql/lib"
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+type ERDiagramView struct {
+	*tview.Flex
+	Canvas *ERDiagramCanvas
+	Home   *Home
+}
+
+// NewERDiagramView shows the tables of a database, or of a single Postgres
+// schema when schema is not empty, with the relations between them. The
+// schema is loaded in the background.
+func NewERDiagramView(home *Home, database, schema string) *ERDiagramView {
+	name := database
+	if schema != "" {
+		name = fmt.Sprintf("%s.%s", database, schema)
+	}
+
+	canvas := NewERDiagramCanvas()
+	canvas.SetBorder(true)
+	canvas.SetTitle(fmt.Sprintf(" ER diagram: %s (loading...) ", name))
+
+	keybindings := tview.NewTextView()
+	keybindings.SetDynamicColors(true)
+	keybindings.SetWrap(false)
+	keybindings.SetBorder(true)
+	keybindings.SetTitle(" Keybindings ")
+
+	for _, command := range app.Keymaps.Group(app.ERDiagramGroup) {
+		keybindings.SetText(fmt.Sprintf("%s [%s](%s) [default]%s", keybindings.GetText(false), app.Styles.SecondaryTextColor, command.Keys().String(), command.Description))
+	}
+
+	container := tview.NewFlex().SetDirection(tview.FlexRow)
+	container.AddItem(canvas, 0, 1, true)
+	container.AddItem(keybindings, 3, 0, false)
+
+	view := &ERDiagramView{
+		Flex:   container,
+		Canvas: canvas,
+		Home:   home,
+	}
+
+	container.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		command := app.Keymaps.Resolve(app.ERDiagramGroup, event)
+
+		switch {
+		case command == commands.Quit || event.Key() == tcell.KeyEsc:
+			mainPages.RemovePage(pageNameERDiagram)
+		case command == commands.MoveLeft:
+			canvas.Pan(-4, 0)
+		case command == commands.MoveRight:
+			canvas.Pan(4, 0)
+		case command == commands.MoveUp:
+			canvas.Pan(0, -2)
+		case command == commands.MoveDown:
+			canvas.Pan(0, 2)
+		case command == commands.GotoNext:
+			canvas.SelectTable(1)
+		case command == commands.GotoPrev:
+			canvas.SelectTable(-1)
+		case command == commands.Execute:
+			if table := canvas.GetSelectedTable(); table != "" {
+				mainPages.RemovePage(pageNameERDiagram)
+				home.Tree.SetSelectedDatabase(database)
+				home.Tree.SetSelectedTable(table)
+			}
+		case command == commands.ExportMermaid:
+			view.export(drivers.ERDiagramMermaid(canvas.diagram), "Mermaid")
+		case command == commands.ExportDOT:
+			view.export(drivers.ERDiagramDOT(canvas.diagram), "DOT")
+		default:
+			return event
+		}
+
+		return nil
+	})
+
+	go view.load(database, schema, name)
+
+	return view
+}
+
+func (view *ERDiagramView) load(database, schema, name string) {
+	schemas, err := drivers.GetDatabaseSchema(view.Home.DBDriver, database)
+	if err != nil {
+		view.Canvas.SetTitle(fmt.Sprintf(" ER diagram: %s ", name))
+		view.Canvas.SetTitleColor(app.Styles.ErrorColor)
+		view.Canvas.SetText(err.Error())
+		App.Draw()
+		return
+	}
+
+	if schema != "" {
+		for table := range schemas {
+			if !strings.HasPrefix(table, schema+".") {
+				delete(schemas, table)
+			}
+		}
+	}
+
+	diagram := drivers.BuildERDiagram(schemas)
+
+	view.Canvas.SetDiagram(diagram)
+	view.Canvas.SetTitle(fmt.Sprintf(" ER diagram: %s (%d tables, %d relations) ", name, len(diagram.Tables), len(diagram.Relations)))
+	App.Draw()
+}
+
+func (view *ERDiagramView) export(text, format string) {
+	if err := lib.NewClipboard().Write(text); err != nil {
+		logger.Info("Error copying ER diagram", map[string]any{"error": err.Error()})
+		return
+	}
+
+	view.Canvas.SetText(fmt.Sprintf("Copied as %s", format))
+}
+
+// ERDiagramCanvas draws a rendered diagram and scrolls over it.
+type ERDiagramCanvas struct {
+	*tview.Box
+	diagram  models.ERDiagram
+	canvas   [][]rune
+	owners   [][]int
+	offsetX  int
+	offsetY  int
+	selected int
+	text     string
+}
+
+func NewERDiagramCanvas() *ERDiagramCanvas {
+	return &ERDiagramCanvas{
+		Box:      tview.NewBox(),
+		selected: -1,
+	}
+}
+
+func (canvas *ERDiagramCanvas) SetDiagram(diagram models.ERDiagram) {
+	canvas.diagram = diagram
+	canvas.canvas = drivers.RenderERDiagram(diagram)
+	canvas.owners = make([][]int, len(canvas.canvas))
+	canvas.text = ""
+
+	for y, row := range canvas.canvas {
+		canvas.owners[y] = make([]int, len(row))
+		for x := range row {
+			canvas.owners[y][x] = -1
+		}
+	}
+
+	for i, table := range diagram.Tables {
+		for y := table.Y; y < table.Y+table.Height; y++ {
+			for x := table.X; x < table.X+table.Width; x++ {
+				canvas.owners[y][x] = i
+			}
+		}
+	}
+
+	if len(diagram.Tables) > 0 {
+		canvas.selected = 0
+	} else {
+		canvas.text = "No tables"
+	}
+}
+
+// SetText shows a message on the bottom border, it is cleared when the view
+// is scrolled.
+func (canvas *ERDiagramCanvas) SetText(text string) {
+	canvas.text = text
+}
+
+func (canvas *ERDiagramCanvas) GetSelectedTable() string {
+	if canvas.selected < 0 || canvas.selected >= len(canvas.diagram.Tables) {
+		return ""
+	}
+
+	return canvas.diagram.Tables[canvas.selected].Name
+}
+
+// Pan scrolls the diagram, keeping it inside the view.
+func (canvas *ERDiagramCanvas) Pan(x, y int) {
+	_, _, width, height := canvas.GetInnerRect()
+
+	canvas.offsetX = max(0, min(canvas.offsetX+x, canvas.diagram.Width-width))
+	canvas.offsetY = max(0, min(canvas.offsetY+y, canvas.diagram.Height-height))
+	canvas.text = ""
+}
+
+// SelectTable moves the selection by the given number of tables, in the
+// order they are laid out, and scrolls the selected table into view.
+func (canvas *ERDiagramCanvas) SelectTable(step int) {
+	count := len(canvas.diagram.Tables)
+	if count == 0 {
+		return
+	}
+
+	canvas.selected = ((canvas.selected+step)%count + count) % count
+	canvas.text = ""
+
+	table := canvas.diagram.Tables[canvas.selected]
+	_, _, width, height := canvas.GetInnerRect()
+
+	if table.X < canvas.offsetX || table.Width > width {
+		canvas.offsetX = table.X
+	} else if table.X+table.Width > canvas.offsetX+width {
+		canvas.offsetX = table.X + table.Width - width
+	}
+
+	if table.Y < canvas.offsetY || table.Height > height {
+		canvas.offsetY = table.Y
+	} else if table.Y+table.Height > canvas.offsetY+height {
+		canvas.offsetY = table.Y + table.Height - height
+	}
+}
+
+func (canvas *ERDiagramCanvas) Draw(screen tcell.Screen) {
+	canvas.DrawForSubclass(screen, canvas)
+
+	x, y, width, height := canvas.GetInnerRect()
+
+	lineStyle := tcell.StyleDefault.Foreground(app.Styles.TertiaryTextColor).Background(app.Styles.PrimitiveBackgroundColor)
+	tableStyle := tcell.StyleDefault.Foreground(app.Styles.PrimaryTextColor).Background(app.Styles.PrimitiveBackgroundColor)
+	selectedStyle := tcell.StyleDefault.Foreground(app.Styles.SecondaryTextColor).Background(app.Styles.PrimitiveBackgroundColor)
+
+	for row := 0; row < height && canvas.offsetY+row < len(canvas.canvas); row++ {
+		line := canvas.canvas[canvas.offsetY+row]
+
+		for column := 0; column < width && canvas.offsetX+column < len(line); column++ {
+			style := lineStyle
+
+			switch canvas.owners[canvas.offsetY+row][canvas.offsetX+column] {
+			case -1:
+			case canvas.selected:
+				style = selectedStyle
+			default:
+				style = tableStyle
+			}
+
+			screen.SetContent(x+column, y+row, line[canvas.offsetX+column], nil, style)
+		}
+	}
+
+	if canvas.text != "" {
+		tview.Print(screen, " "+canvas.text+" ", x, y+height, width, tview.AlignRight, app.Styles.TertiaryTextColor)
+	}
+}
@@ -1,6 +1,8 @@
 package components
 
 import (
+	"maps"
+	"slices"
 	"strings"
 
 	"github.com/gdamore/tcell/v2"
@@ -42,13 +44,15 @@ func NewHelpModal() *HelpModal {
 
 	for groupName := range keymapGroups {
 		for _, key := range keymapGroups[groupName] {
-			if len(key.Key.String()) > len(mostLengthyKey) {
-				mostLengthyKey = key.Key.String()
+			if len(key.Keys().String()) > len(mostLengthyKey) {
+				mostLengthyKey = key.Keys().String()
 			}
 		}
 	}
 
-	for groupName, keys := range keymapGroups {
+	// The bindings are the default ones with the ones of the configuration.
+	for _, groupName := range slices.Sorted(maps.Keys(keymapGroups)) {
+		keys := keymapGroups[groupName]
 		rowCount := table.GetRowCount()
 		groupNameCell := tview.NewTableCell(strings.ToUpper(groupName))
 		groupNameCell.SetTextColor(app.Styles.TertiaryTextColor)
@@ -59,13 +63,15 @@ func NewHelpModal() *HelpModal {
 		table.SetCell(rowCount+2, 0, tview.NewTableCell("").SetSelectable(false))
 
 		for i, key := range keys {
-			keyText := key.Key.String()
+			keyText := key.Keys().String()
 
 			if len(keyText) < len(mostLengthyKey) {
 				keyText = strings.Repeat(" ", len(mostLengthyKey)-len(keyText)) + keyText
 			}
 			table.SetCell(rowCount+3+i, 0, tview.NewTableCell(keyText).SetAlign(tview.AlignRight).SetTextColor(app.Styles.SecondaryTextColor))
 			table.SetCell(rowCount+3+i, 1, tview.NewTableCell(key.Description).SetAlign(tview.AlignLeft).SetExpansion(1))
+			// The command is named like in the keymap sections of the configuration.
+			table.SetCell(rowCount+3+i, 2, tview.NewTableCell(key.Cmd.String()).SetAlign(tview.AlignRight).SetTextColor(app.Styles.TertiaryTextColor))
 		}
 
 	}
@@ -73,7 +79,7 @@ func NewHelpModal() *HelpModal {
 	table.Select(3, 0)
 
 	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		command := app.Keymaps.Group(app.HomeGroup).Resolve(event)
+		command := app.Keymaps.Resolve(app.HomeGroup, event)
 		if command == commands.Quit || command == commands.HelpPopup || event.Key() == tcell.KeyEsc {
 			mainPages.RemovePage(pageNameHelp)
 		}
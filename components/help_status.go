@@ -1,6 +1,8 @@
 package components
 
 import (
+	"fmt"
+
 	"github.com/rivo/tview"
 
 	"github.com/jorgerojas26/lazysql/app"
@@ -9,10 +11,16 @@ import (
 
 type HelpStatus struct {
 	*tview.TextView
+
+	// Health shows whether the connection is alive.
+	Health *tview.TextView
 }
 
 func NewHelpStatus() HelpStatus {
-	status := HelpStatus{tview.NewTextView().SetTextColor(app.Styles.TertiaryTextColor)}
+	status := HelpStatus{
+		TextView: tview.NewTextView().SetTextColor(app.Styles.TertiaryTextColor),
+		Health:   tview.NewTextView().SetDynamicColors(true).SetTextAlign(tview.AlignRight),
+	}
 
 	status.SetStatusOnTree()
 
@@ -28,7 +36,7 @@ func (status *HelpStatus) UpdateText(binds []keymap.Bind) {
 
 		newtext += ": "
 
-		newtext += key.Key.String()
+		newtext += key.Keys().String()
 
 		islast := i == len(binds)-1
 
@@ -52,3 +60,19 @@ func (status *HelpStatus) SetStatusOnEditorView() {
 func (status *HelpStatus) SetStatusOnTableView() {
 	status.UpdateText(app.Keymaps.Group(app.TableGroup))
 }
+
+// SetHealth shows the state of the connection, with the error that made it
+// reconnect.
+func (status *HelpStatus) SetHealth(state, attempts int, err error) {
+	switch state {
+	case healthConnected:
+		status.Health.SetText(fmt.Sprintf("[%s]● connected", app.Styles.SuccessColor))
+	case healthReconnecting:
+		text := fmt.Sprintf("[%s]● reconnecting (%d)", app.Styles.WarningColor, attempts)
+		if err != nil {
+			text = fmt.Sprintf("[%s]● %s, reconnecting (%d)", app.Styles.ErrorColor, tview.Escape(err.Error()), attempts)
+		}
+
+		status.Health.SetText(text)
+	}
+}
@@ -1,6 +1,7 @@
 package components
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/gdamore/tcell/v2"
@@ -22,8 +23,22 @@ type Home struct {
 	HelpStatus      HelpStatus
 	HelpModal       *HelpModal
 	DBDriver        drivers.Driver
+	Connection      models.Connection
 	FocusedWrapper  string
 	ListOfDBChanges []models.DBDMLChange
+
+	// ConnectionsStrip lists the open connections.
+	ConnectionsStrip *tview.TextView
+
+	// stopCommands stops the commands started for the connection.
+	stopCommands context.CancelFunc
+
+	// health checks the connection in the background.
+	health *connectionHealth
+
+	// focusColor is the colour of the border of the focused wrapper, the
+	// colour of the environment of the connection when it has one.
+	focusColor tcell.Color
 }
 
 func NewHomePage(connection models.Connection, dbdriver drivers.Driver) *Home {
@@ -43,7 +58,17 @@ func NewHomePage(connection models.Connection, dbdriver drivers.Driver) *Home {
 		HelpStatus:      NewHelpStatus(),
 		HelpModal:       NewHelpModal(),
 		DBDriver:        dbdriver,
+		Connection:      connection,
 		ListOfDBChanges: []models.DBDMLChange{},
+		focusColor:      app.Styles.PrimaryTextColor,
+	}
+
+	home.ConnectionsStrip = tview.NewTextView().SetDynamicColors(true).SetTextColor(app.Styles.TertiaryTextColor)
+
+	if _, color, ok := connectionEnvironment(connection); ok {
+		home.focusColor = color
+		tree.Wrapper.SetBorderColor(color)
+		tree.Wrapper.SetTitleColor(color)
 	}
 
 	go home.subscribeToTreeChanges()
@@ -62,6 +87,12 @@ func NewHomePage(connection models.Connection, dbdriver drivers.Driver) *Home {
 	maincontent.AddItem(rightWrapper, 0, 5, false)
 
 	home.AddItem(maincontent, 0, 1, false)
+
+	statusBar := tview.NewFlex()
+	statusBar.AddItem(home.ConnectionsStrip, 0, 1, false)
+	statusBar.AddItem(home.HelpStatus.Health, 0, 1, false)
+
+	home.AddItem(statusBar, 1, 0, false)
 	// home.AddItem(home.HelpStatus, 1, 1, false)
 
 	home.SetInputCapture(home.homeInputCapture)
@@ -127,6 +158,47 @@ func (home *Home) subscribeToTreeChanges() {
 			} else {
 				home.SetInputCapture(home.homeInputCapture)
 			}
+		case eventTreeSchemaDiff:
+			database := stateChange.Value.(string)
+			schemaDiffForm := NewSchemaDiffForm(home.Connection, home.DBDriver, database)
+
+			mainPages.AddPage(pageNameSchemaDiffForm, schemaDiffForm, true, true)
+			App.Draw()
+		case eventTreeDataDiff:
+			reference := stateChange.Value.([]string)
+			dataDiffForm := NewDataDiffForm(home, reference[0], reference[1])
+
+			mainPages.AddPage(pageNameDataDiffForm, dataDiffForm, true, true)
+			App.Draw()
+		case eventTreeCopyTable:
+			reference := stateChange.Value.([]string)
+			copyTableForm := NewCopyTableForm(home, reference[0], reference[1])
+
+			mainPages.AddPage(pageNameCopyTable, copyTableForm, true, true)
+			App.Draw()
+		case eventTreeDump:
+			reference := stateChange.Value.([]string)
+			dumpForm := NewDumpForm(home, reference[0], reference[1])
+
+			mainPages.AddPage(pageNameDump, dumpForm, true, true)
+			App.Draw()
+		case eventTreeRestore:
+			database := stateChange.Value.(string)
+			restoreForm := NewRestoreForm(home, database)
+
+			mainPages.AddPage(pageNameRestore, restoreForm, true, true)
+			App.Draw()
+		case eventTreeERDiagram:
+			reference := stateChange.Value.([]string)
+			erDiagramView := NewERDiagramView(home, reference[0], reference[1])
+
+			mainPages.AddPage(pageNameERDiagram, erDiagramView, true, true)
+			App.Draw()
+		case eventTreeAttachDatabase:
+			attachDatabaseForm := NewAttachDatabaseForm(home)
+
+			mainPages.AddPage(pageNameAttachDatabase, attachDatabaseForm, true, true)
+			App.Draw()
 		}
 	}
 }
@@ -134,7 +206,7 @@ func (home *Home) subscribeToTreeChanges() {
 func (home *Home) focusRightWrapper() {
 	home.Tree.RemoveHighlight()
 
-	home.RightWrapper.SetBorderColor(app.Styles.PrimaryTextColor)
+	home.RightWrapper.SetBorderColor(home.focusColor)
 	home.LeftWrapper.SetBorderColor(app.Styles.InverseTextColor)
 	home.TabbedPane.Highlight()
 	tab := home.TabbedPane.GetCurrentTab()
@@ -181,7 +253,7 @@ func (home *Home) focusLeftWrapper() {
 	home.Tree.Highlight()
 
 	home.RightWrapper.SetBorderColor(app.Styles.InverseTextColor)
-	home.LeftWrapper.SetBorderColor(app.Styles.PrimaryTextColor)
+	home.LeftWrapper.SetBorderColor(home.focusColor)
 
 	tab := home.TabbedPane.GetCurrentTab()
 
@@ -199,10 +271,38 @@ func (home *Home) focusLeftWrapper() {
 	home.FocusedWrapper = focusedWrapperLeft
 }
 
+// openEditor switches to the tab of the SQL editor, opening it if needed, and
+// focuses the editor.
+func (home *Home) openEditor() *ResultsTable {
+	var tableWithEditor *ResultsTable
+
+	if tab := home.TabbedPane.GetTabByName(tabNameEditor); tab != nil {
+		home.TabbedPane.SwitchToTabByName(tabNameEditor)
+		tableWithEditor = tab.Content
+	} else {
+		tableWithEditor = NewResultsTable(&home.ListOfDBChanges, home.Tree, home.DBDriver).WithEditor()
+		home.TabbedPane.AppendTab(tabNameEditor, tableWithEditor, tabNameEditor)
+	}
+
+	tableWithEditor.SetIsFiltering(true)
+	home.HelpStatus.SetStatusOnEditorView()
+	home.focusRightWrapper()
+
+	return tableWithEditor
+}
+
 func (home *Home) rightWrapperInputCapture(event *tcell.EventKey) *tcell.EventKey {
-	var tab *Tab
+	if home.runTabCommand(app.Keymaps.Resolve(app.TableGroup, event)) {
+		return nil
+	}
 
-	command := app.Keymaps.Group(app.TableGroup).Resolve(event)
+	return event
+}
+
+// runTabCommand runs a command of the tabs of the right wrapper, it reports
+// whether the command was handled.
+func (home *Home) runTabCommand(command commands.Command) bool {
+	var tab *Tab
 
 	switch command {
 	case commands.TabPrev:
@@ -213,12 +313,12 @@ func (home *Home) rightWrapperInputCapture(event *tcell.EventKey) *tcell.EventKe
 			table := tab.Content
 			if !table.GetIsEditing() && !table.GetIsFiltering() {
 				home.focusTab(home.TabbedPane.SwitchToPreviousTab())
-				return nil
+				return true
 			}
 
 		}
 
-		return event
+		return false
 	case commands.TabNext:
 		tab := home.TabbedPane.GetCurrentTab()
 
@@ -226,17 +326,17 @@ func (home *Home) rightWrapperInputCapture(event *tcell.EventKey) *tcell.EventKe
 			table := tab.Content
 			if !table.GetIsEditing() && !table.GetIsFiltering() {
 				home.focusTab(home.TabbedPane.SwitchToNextTab())
-				return nil
+				return true
 			}
 		}
 
-		return event
+		return false
 	case commands.TabFirst:
 		home.focusTab(home.TabbedPane.SwitchToFirstTab())
-		return nil
+		return true
 	case commands.TabLast:
 		home.focusTab(home.TabbedPane.SwitchToLastTab())
-		return nil
+		return true
 	case commands.TabClose:
 		tab = home.TabbedPane.GetCurrentTab()
 
@@ -248,7 +348,7 @@ func (home *Home) rightWrapperInputCapture(event *tcell.EventKey) *tcell.EventKe
 
 				if home.TabbedPane.GetLength() == 0 {
 					home.focusLeftWrapper()
-					return nil
+					return true
 				}
 			}
 		}
@@ -279,10 +379,20 @@ func (home *Home) rightWrapperInputCapture(event *tcell.EventKey) *tcell.EventKe
 		}
 	}
 
-	return event
+	return false
 }
 
 func (home *Home) homeInputCapture(event *tcell.EventKey) *tcell.EventKey {
+	if home.runHomeCommand(app.Keymaps.Resolve(app.HomeGroup, event)) {
+		return nil
+	}
+
+	return event
+}
+
+// runHomeCommand runs a command of the home page, it reports whether the
+// command was handled.
+func (home *Home) runHomeCommand(command commands.Command) bool {
 	tab := home.TabbedPane.GetCurrentTab()
 
 	var table *ResultsTable
@@ -291,8 +401,6 @@ func (home *Home) homeInputCapture(event *tcell.EventKey) *tcell.EventKey {
 		table = tab.Content
 	}
 
-	command := app.Keymaps.Group(app.HomeGroup).Resolve(event)
-
 	switch command {
 	case commands.MoveLeft:
 		if table != nil && !table.GetIsEditing() && !table.GetIsFiltering() && home.FocusedWrapper == focusedWrapperRight {
@@ -303,18 +411,7 @@ func (home *Home) homeInputCapture(event *tcell.EventKey) *tcell.EventKey {
 			home.focusRightWrapper()
 		}
 	case commands.SwitchToEditorView:
-		tab := home.TabbedPane.GetTabByName(tabNameEditor)
-
-		if tab != nil {
-			home.TabbedPane.SwitchToTabByName(tabNameEditor)
-			tab.Content.SetIsFiltering(true)
-		} else {
-			tableWithEditor := NewResultsTable(&home.ListOfDBChanges, home.Tree, home.DBDriver).WithEditor()
-			home.TabbedPane.AppendTab(tabNameEditor, tableWithEditor, tabNameEditor)
-			tableWithEditor.SetIsFiltering(true)
-		}
-		home.HelpStatus.SetStatusOnEditorView()
-		home.focusRightWrapper()
+		home.openEditor()
 		App.ForceDraw()
 	case commands.SwitchToConnectionsView:
 		if (table != nil && !table.GetIsEditing() && !table.GetIsFiltering() && !table.GetIsLoading()) || table == nil {
@@ -325,10 +422,16 @@ func (home *Home) homeInputCapture(event *tcell.EventKey) *tcell.EventKey {
 			App.Stop()
 		}
 	case commands.Save:
-		if (len(home.ListOfDBChanges) > 0) && !table.GetIsEditing() {
+		if (len(home.ListOfDBChanges) > 0) && (table == nil || !table.GetIsEditing()) {
 			queryPreviewModal := NewQueryPreviewModal(&home.ListOfDBChanges, home.DBDriver, func() {
 				home.ListOfDBChanges = []models.DBDMLChange{}
-				table.FetchRecords(nil)
+				if table != nil {
+					table.FetchRecords(nil)
+
+					if tracker, ok := drivers.Unwrap(home.DBDriver).(drivers.MutationTracker); ok && table.Menu != nil {
+						go table.TrackMutations(tracker)
+					}
+				}
 				home.Tree.ForceRemoveHighlight()
 			})
 
@@ -347,6 +450,36 @@ func (home *Home) homeInputCapture(event *tcell.EventKey) *tcell.EventKey {
 			// })
 			mainPages.AddPage(pageNameHelp, home.HelpModal, true, true)
 		}
+	case commands.SwitchConnection:
+		if table == nil || (!table.GetIsEditing() && !table.GetIsFiltering()) {
+			mainPages.AddPage(pageNameConnectionSwitcher, NewConnectionSwitcher(home), true, true)
+			return true
+		}
+	case commands.NextConnection, commands.PreviousConnection:
+		if table == nil || (!table.GetIsEditing() && !table.GetIsFiltering()) {
+			offset := 1
+			if command == commands.PreviousConnection {
+				offset = -1
+			}
+
+			cycleHome(home, offset)
+			return true
+		}
+	case commands.Disconnect:
+		if table == nil || (!table.GetIsEditing() && !table.GetIsFiltering()) {
+			confirmDisconnect(home)
+			return true
+		}
+	case commands.ShowCommands:
+		if home.health != nil && len(home.health.commands) > 0 && (table == nil || (!table.GetIsEditing() && !table.GetIsFiltering())) {
+			mainPages.AddPage(pageNameConnectionCommands, NewCommandsView(home), true, true)
+			return true
+		}
+	case commands.CommandPalette:
+		if table == nil || (!table.GetIsEditing() && !table.GetIsFiltering()) {
+			mainPages.AddPage(pageNameCommandPalette, NewCommandPalette(home), true, true)
+			return true
+		}
 	case commands.SearchGlobal:
 		if table != nil && !table.GetIsEditing() && !table.GetIsFiltering() && home.FocusedWrapper == focusedWrapperRight {
 			home.focusLeftWrapper()
@@ -358,5 +491,5 @@ func (home *Home) homeInputCapture(event *tcell.EventKey) *tcell.EventKey {
 		home.Tree.SetIsFiltering(true)
 	}
 
-	return event
+	return false
 }
@@ -3,6 +3,7 @@ package components
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/google/uuid"
@@ -73,7 +74,7 @@ func NewResultsTable(listOfDBChanges *[]models.DBDMLChange, tree *Tree, dbdriver
 	errorModal := tview.NewModal()
 	errorModal.AddButtons([]string{"Ok"})
 	errorModal.SetText("An error occurred")
-	errorModal.SetBackgroundColor(tcell.ColorRed)
+	errorModal.SetBackgroundColor(app.Styles.ErrorColor)
 	errorModal.SetTextColor(app.Styles.PrimaryTextColor)
 	errorModal.SetButtonStyle(tcell.StyleDefault.Foreground(app.Styles.PrimaryTextColor))
 	errorModal.SetFocus(0)
@@ -132,7 +133,12 @@ func NewResultsTable(listOfDBChanges *[]models.DBDMLChange, tree *Tree, dbdriver
 }
 
 func (table *ResultsTable) WithFilter() *ResultsTable {
-	menu := NewResultsTableMenu()
+	var extraMenus []string
+	if provider, ok := drivers.Unwrap(table.DBDriver).(drivers.TableMenuProvider); ok {
+		extraMenus = provider.TableMenus()
+	}
+
+	menu := NewResultsTableMenu(extraMenus...)
 	filter := NewResultsFilter()
 
 	table.Menu = menu
@@ -308,7 +314,7 @@ func (table *ResultsTable) AddInsertedRows() {
 			tableCell.SetReference(inserts[i].PrimaryKeyInfo[0].Value)
 
 			tableCell.SetTextColor(app.Styles.PrimaryTextColor)
-			tableCell.SetBackgroundColor(colorTableInsert)
+			tableCell.SetBackgroundColor(app.Styles.InsertedRowColor)
 
 			table.SetCell(rowIndex, j, tableCell)
 		}
@@ -325,7 +331,7 @@ func (table *ResultsTable) AppendNewRow(cells []models.CellValue, index int, UUI
 		// there might be a better way to do this, but it works for now
 		tableCell.SetReference(UUID)
 		tableCell.SetTextColor(app.Styles.PrimaryTextColor)
-		tableCell.SetBackgroundColor(tcell.ColorDarkGreen)
+		tableCell.SetBackgroundColor(app.Styles.InsertedRowColor)
 
 		switch cell.Type {
 		case models.Null, models.Empty, models.Default:
@@ -336,7 +342,7 @@ func (table *ResultsTable) AppendNewRow(cells []models.CellValue, index int, UUI
 			tableCell.SetTextColor(app.Styles.InverseTextColor)
 		}
 
-		tableCell.SetBackgroundColor(colorTableInsert)
+		tableCell.SetBackgroundColor(app.Styles.InsertedRowColor)
 		table.SetCell(index, i, tableCell)
 	}
 
@@ -345,15 +351,23 @@ func (table *ResultsTable) AppendNewRow(cells []models.CellValue, index int, UUI
 }
 
 func (table *ResultsTable) tableInputCapture(event *tcell.EventKey) *tcell.EventKey {
+	// The keys only do what they are bound to, the keys bound to nothing
+	// like Enter are left to the table.
+	if table.runCommand(app.Keymaps.ResolveCount(app.TableGroup, event)) || event.Key() == tcell.KeyRune || app.Keymaps.Pending() {
+		return nil
+	}
+
+	return event
+}
+
+// runCommand runs a command of the table, count times for the movements. It
+// reports whether the command was handled.
+func (table *ResultsTable) runCommand(command commands.Command, count int) bool {
 	selectedRowIndex, selectedColumnIndex := table.GetSelection()
 	colCount := table.GetColumnCount()
 	rowCount := table.GetRowCount()
 
-	eventKey := event.Rune()
-
-	command := app.Keymaps.Group(app.TableGroup).Resolve(event)
-
-	menuCommands := []commands.Command{commands.RecordsMenu, commands.ColumnsMenu, commands.ConstraintsMenu, commands.ForeignKeysMenu, commands.IndexesMenu, commands.Refresh}
+	menuCommands := []commands.Command{commands.RecordsMenu, commands.ColumnsMenu, commands.ConstraintsMenu, commands.ForeignKeysMenu, commands.IndexesMenu, commands.ExtraMenu1, commands.ExtraMenu2, commands.ExtraMenu3, commands.ExtraMenu4, commands.Refresh}
 
 	if helpers.ContainsCommand(menuCommands, command) {
 		table.Select(1, 0)
@@ -378,13 +392,20 @@ func (table *ResultsTable) tableInputCapture(event *tcell.EventKey) *tcell.Event
 		case commands.IndexesMenu:
 			table.Menu.SetSelectedOption(5)
 			table.UpdateRows(table.GetIndexes())
+		case commands.ExtraMenu1, commands.ExtraMenu2, commands.ExtraMenu3, commands.ExtraMenu4:
+			index := int(command - commands.ExtraMenu1)
+
+			if index < len(table.Menu.ExtraItems) {
+				table.Menu.SetSelectedOption(len(menuItems) + index + 1)
+				go table.FetchTableMenu(table.Menu.ExtraItems[index])
+			}
 		case commands.Refresh:
 			if table.Loading != nil {
 				app.App.SetFocus(table.Loading)
 			}
 			table.Menu.SetSelectedOption(1)
 			if err := table.FetchRecords(nil); err != nil {
-				return event
+				return false
 			}
 		}
 	}
@@ -399,7 +420,7 @@ func (table *ResultsTable) tableInputCapture(event *tcell.EventKey) *tcell.Event
 	}
 
 	if rowCount == 1 || colCount == 0 {
-		return nil
+		return true
 	}
 
 	if command == commands.Edit {
@@ -408,34 +429,34 @@ func (table *ResultsTable) tableInputCapture(event *tcell.EventKey) *tcell.Event
 				table.UpdateSidebar()
 			}
 		})
+	} else if command == commands.MoveDown {
+		table.Select(min(selectedRowIndex+count, rowCount-1), selectedColumnIndex)
+	} else if command == commands.MoveUp {
+		table.Select(max(selectedRowIndex-count, 1), selectedColumnIndex)
+	} else if command == commands.MoveRight {
+		table.Select(selectedRowIndex, min(selectedColumnIndex+count, colCount-1))
+	} else if command == commands.MoveLeft {
+		table.Select(selectedRowIndex, max(selectedColumnIndex-count, 0))
 	} else if command == commands.GotoNext {
 		if selectedColumnIndex+1 < colCount {
-			table.Select(selectedRowIndex, selectedColumnIndex+1)
+			table.Select(selectedRowIndex, min(selectedColumnIndex+count, colCount-1))
 		}
 	} else if command == commands.GotoPrev {
 		if selectedColumnIndex > 0 {
-			table.Select(selectedRowIndex, selectedColumnIndex-1)
+			table.Select(selectedRowIndex, max(selectedColumnIndex-count, 0))
 		}
 	} else if command == commands.GotoEnd {
 		table.Select(selectedRowIndex, colCount-1)
 	} else if command == commands.GotoStart {
 		table.Select(selectedRowIndex, 0)
-	} else if command == commands.GotoBottom {
-		go table.Select(1, selectedColumnIndex)
 	} else if command == commands.GotoTop {
+		go table.Select(1, selectedColumnIndex)
+	} else if command == commands.GotoBottom {
 		go table.Select(rowCount-1, selectedColumnIndex)
-	} else if eventKey == 4 { // Ctrl + D
-		if selectedRowIndex+7 > rowCount-1 {
-			go table.Select(rowCount-1, selectedColumnIndex)
-		} else {
-			go table.Select(selectedRowIndex+7, selectedColumnIndex)
-		}
-	} else if eventKey == 21 { // Ctrl + U
-		if selectedRowIndex-7 < 1 {
-			go table.Select(1, selectedColumnIndex)
-		} else {
-			go table.Select(selectedRowIndex-7, selectedColumnIndex)
-		}
+	} else if command == commands.ScrollDown {
+		go table.Select(min(selectedRowIndex+7*count, rowCount-1), selectedColumnIndex)
+	} else if command == commands.ScrollUp {
+		go table.Select(max(selectedRowIndex-7*count, 1), selectedColumnIndex)
 	} else if command == commands.Delete {
 		if table.Menu.GetSelectedOption() == 1 {
 
@@ -507,7 +528,7 @@ func (table *ResultsTable) tableInputCapture(event *tcell.EventKey) *tcell.Event
 		}
 	}
 
-	return event
+	return command != commands.Noop
 }
 
 func (table *ResultsTable) UpdateRows(rows [][]string) {
@@ -526,7 +547,7 @@ func (table *ResultsTable) UpdateRowsColor(headerColor tcell.Color, rowColor tce
 			} else {
 				cellReference := cell.GetReference()
 
-				if cellReference != nil && (cellReference == "EMPTY&" || cellReference == "NULL&" || cellReference == "DEFAULT&") && (cell.BackgroundColor != colorTableDelete && cell.BackgroundColor != colorTableChange && cell.BackgroundColor != colorTableInsert) {
+				if cellReference != nil && (cellReference == "EMPTY&" || cellReference == "NULL&" || cellReference == "DEFAULT&") && (cell.BackgroundColor != app.Styles.DeletedRowColor && cell.BackgroundColor != app.Styles.ChangedRowColor && cell.BackgroundColor != app.Styles.InsertedRowColor) {
 					cell.SetStyle(table.GetItalicStyle())
 				} else {
 					cell.SetTextColor(rowColor)
@@ -945,6 +966,17 @@ func (table *ResultsTable) FetchRecords(onError func()) [][]string {
 			table.SetError(err.Error(), nil)
 		}
 
+		// The rows of the tables without a primary key are edited by the
+		// pseudocolumn identifying them, when the driver selects one.
+		if identifier, ok := drivers.Unwrap(table.DBDriver).(drivers.RowIdentifier); ok && err == nil && len(primaryKeyColumnNames) == 0 {
+			rowID, err := identifier.RowIDColumn(databaseName, tableName)
+			if err != nil {
+				table.SetError(err.Error(), nil)
+			} else if rowID != "" {
+				primaryKeyColumnNames = []string{rowID}
+			}
+		}
+
 		if len(records) > 0 {
 			table.SetRecords(records)
 		}
@@ -966,6 +998,67 @@ func (table *ResultsTable) FetchRecords(onError func()) [][]string {
 	return [][]string{}
 }
 
+// FetchTableMenu shows the rows of a menu of the driver, they are queried
+// every time the menu is selected.
+func (table *ResultsTable) FetchTableMenu(menu string) {
+	provider, ok := drivers.Unwrap(table.DBDriver).(drivers.TableMenuProvider)
+	if !ok {
+		return
+	}
+
+	table.SetLoading(true)
+
+	rows, err := provider.GetTableMenu(table.GetDatabaseName(), table.GetTableName(), menu)
+	if err != nil {
+		table.SetError(err.Error(), nil)
+	} else {
+		table.UpdateRows(rows)
+	}
+
+	table.SetLoading(false)
+	App.Draw()
+}
+
+// mutationPollInterval is how often the progress of mutations is queried.
+const mutationPollInterval = time.Second
+
+// TrackMutations shows the progress of the mutations of the table in the menu
+// title until they are done, then fetches the records again.
+func (table *ResultsTable) TrackMutations(tracker drivers.MutationTracker) {
+	defer func() {
+		table.Menu.SetTitle("")
+		App.Draw()
+	}()
+
+	for {
+		progress, err := tracker.GetMutationProgress(table.GetDatabaseName(), table.GetTableName())
+		if err != nil {
+			table.SetError(err.Error(), nil)
+			return
+		}
+
+		// Failing mutations are retried by the server, they are reported
+		// once and no longer tracked.
+		if progress.FailReason != "" {
+			table.SetError(fmt.Sprintf("Mutation failed: %s", progress.FailReason), nil)
+			return
+		}
+
+		if progress.Pending == 0 {
+			if table.Menu.GetSelectedOption() == 1 {
+				table.FetchRecords(nil)
+			}
+
+			return
+		}
+
+		table.Menu.SetTitle(fmt.Sprintf(" %d mutations running, %d parts to rewrite ", progress.Pending, progress.PartsToDo))
+		App.Draw()
+
+		time.Sleep(mutationPollInterval)
+	}
+}
+
 func (table *ResultsTable) StartEditingCell(row int, col int, callback func(newValue string, row, col int)) {
 	table.SetIsEditing(true)
 	table.SetInputCapture(nil)
@@ -1128,7 +1221,7 @@ func (table *ResultsTable) AppendNewChange(changeType models.DMLType, rowIndex i
 					}
 				} else {
 					(*table.state.listOfDBChanges)[i].Values = append((*table.state.listOfDBChanges)[i].Values, value)
-					table.SetCellColor(rowIndex, colIndex, colorTableChange)
+					table.SetCellColor(rowIndex, colIndex, app.Styles.ChangedRowColor)
 				}
 
 			case models.DMLDeleteType:
@@ -1141,10 +1234,10 @@ func (table *ResultsTable) AppendNewChange(changeType models.DMLType, rowIndex i
 	if !dmlChangeAlreadyExists {
 		switch changeType {
 		case models.DMLDeleteType:
-			table.SetRowColor(rowIndex, colorTableDelete)
+			table.SetRowColor(rowIndex, app.Styles.DeletedRowColor)
 		case models.DMLUpdateType:
-			tableCell.SetStyle(tcell.StyleDefault.Background(colorTableChange))
-			table.SetCellColor(rowIndex, colIndex, colorTableChange)
+			tableCell.SetStyle(tcell.StyleDefault.Background(app.Styles.ChangedRowColor))
+			table.SetCellColor(rowIndex, colIndex, app.Styles.ChangedRowColor)
 		}
 
 		newDMLChange := models.DBDMLChange{
@@ -1441,10 +1534,10 @@ func (table *ResultsTable) colorChangedCells() {
 	for _, dmlChange := range *table.state.listOfDBChanges {
 		switch dmlChange.Type {
 		case models.DMLDeleteType:
-			table.SetRowColor(dmlChange.Values[0].TableRowIndex, colorTableDelete)
+			table.SetRowColor(dmlChange.Values[0].TableRowIndex, app.Styles.DeletedRowColor)
 		case models.DMLUpdateType:
 			for _, value := range dmlChange.Values {
-				table.SetCellColor(value.TableRowIndex, value.TableColumnIndex, colorTableChange)
+				table.SetCellColor(value.TableRowIndex, value.TableColumnIndex, app.Styles.ChangedRowColor)
 			}
 		}
 	}
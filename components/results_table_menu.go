@@ -2,10 +2,12 @@ package components
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/rivo/tview"
 
 	"github.com/jorgerojas26/lazysql/app"
+	"github.com/jorgerojas26/lazysql/commands"
 )
 
 type ResultsTableMenuState struct {
@@ -14,8 +16,9 @@ type ResultsTableMenuState struct {
 
 type ResultsTableMenu struct {
 	*tview.Flex
-	state     *ResultsTableMenuState
-	MenuItems []*tview.TextView
+	state      *ResultsTableMenuState
+	MenuItems  []*tview.TextView
+	ExtraItems []string
 }
 
 var menuItems = []string{
@@ -26,49 +29,77 @@ var menuItems = []string{
 	menuIndexes,
 }
 
-func NewResultsTableMenu() *ResultsTableMenu {
+// NewResultsTableMenu creates the menu of a table, the extra items are the
+// menus of the driver shown after the common ones.
+func NewResultsTableMenu(extraItems ...string) *ResultsTableMenu {
 	state := &ResultsTableMenuState{
 		SelectedOption: 1,
 	}
 
 	menu := &ResultsTableMenu{
-		Flex:  tview.NewFlex(),
-		state: state,
+		Flex:       tview.NewFlex(),
+		state:      state,
+		ExtraItems: extraItems,
 	}
 
 	menu.SetBorder(true)
 
-	for i, item := range menuItems {
+	items := append(slices.Clone(menuItems), extraItems...)
+
+	for i, item := range items {
 		separator := " | "
-		if i == len(menuItems)-1 {
+		if i == len(items)-1 {
 			separator = ""
 		}
 
-		text := fmt.Sprintf("%s [%d] %s", item, i+1, separator)
+		text := fmt.Sprintf("%s %s", item, separator)
+		if key := menuKey(i); key != "" {
+			text = fmt.Sprintf("%s [%s] %s", item, key, separator)
+		}
+
 		textview := tview.NewTextView().SetText(text)
 
 		if i == 0 {
 			textview.SetTextColor(app.Styles.PrimaryTextColor)
 		}
 
-		size := 15
-
-		switch item {
-		case menuConstraints:
-			size = 19
-		case menuForeignKeys:
-			size = 20
-		case menuIndexes:
-			size = 16
-		}
-
 		menu.MenuItems = append(menu.MenuItems, textview)
-		menu.AddItem(textview, size, 0, false)
+		menu.AddItem(textview, len(text)+1, 0, false)
 	}
 
 	return menu
 }
 
+// menuCommands are the commands switching to the menu items, in the order of
+// the items.
+var menuCommands = []commands.Command{
+	commands.RecordsMenu,
+	commands.ColumnsMenu,
+	commands.ConstraintsMenu,
+	commands.ForeignKeysMenu,
+	commands.IndexesMenu,
+	commands.ExtraMenu1,
+	commands.ExtraMenu2,
+	commands.ExtraMenu3,
+	commands.ExtraMenu4,
+}
+
+// menuKey returns the first keys bound to the command of the i-th menu item,
+// or nothing when it is not bound.
+func menuKey(i int) string {
+	if i >= len(menuCommands) {
+		return ""
+	}
+
+	for _, bind := range app.Keymaps.Group(app.TableGroup) {
+		if bind.Cmd == menuCommands[i] {
+			return bind.Keys().String()
+		}
+	}
+
+	return ""
+}
+
 // Getters and Setters
 func (menu *ResultsTableMenu) GetSelectedOption() int {
 	return menu.state.SelectedOption
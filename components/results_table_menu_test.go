@@ -0,0 +1,45 @@
+package components
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/jorgerojas26/lazysql/app"
+)
+
+func TestMenuKey(t *testing.T) {
+	group := slices.Clone(app.Keymaps.Groups[app.TableGroup])
+	t.Cleanup(func() { app.Keymaps.Groups[app.TableGroup] = group })
+
+	if err := app.Keymaps.Configure(map[string]map[string]any{app.TableGroup: {"ColumnsMenu": "<A-2>", "ExtraMenu4": []any{}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		item     int
+		expected string
+	}{
+		{item: 0, expected: "1"},
+		{item: 1, expected: "<Alt-2>"},
+		{item: 2, expected: "3"},
+		{item: 5, expected: "6"},
+		{item: 8, expected: ""},
+		{item: 9, expected: ""},
+	}
+
+	for _, tc := range testCases {
+		if key := menuKey(tc.item); key != tc.expected {
+			t.Fatalf("expected %q for the item %d, but got %q", tc.expected, tc.item, key)
+		}
+	}
+
+	menu := NewResultsTableMenu("Engine", "Parts", "Compression", "Mutations")
+
+	if text := menu.MenuItems[1].GetText(false); text != "Columns [<Alt-2>]  | " {
+		t.Fatalf("expected the configured key in the menu, but got %q", text)
+	}
+
+	if text := menu.MenuItems[8].GetText(false); text != "Mutations " {
+		t.Fatalf("expected no key for an unbound menu, but got %q", text)
+	}
+}
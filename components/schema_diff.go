@@ -0,0 +1,288 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/jorgerojas26/lazysql/app"
+	"github.com/jorgerojas26/lazysql/commands"
+	"github.com/jorgerojas26/lazysql/drivers"
+	"github.com/jorgerojas26/lazysql/helpers/logger"
+	"github.com/jorgerojas26/lazysql/lib"
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+const schemaDiffCurrentConnection = "Current connection"
+
+type SchemaDiffForm struct {
+	*tview.Flex
+	Form       *tview.Form
+	StatusText *tview.TextView
+	DBDriver   drivers.Driver
+}
+
+// NewSchemaDiffForm asks for the database to compare the source database
+// against, it can be another database of the current connection or a
+// database of any saved connection.
+func NewSchemaDiffForm(connection models.Connection, dbdriver drivers.Driver, sourceDatabase string) *SchemaDiffForm {
+	form := tview.NewForm().SetFieldBackgroundColor(app.Styles.InverseTextColor).SetButtonBackgroundColor(tview.Styles.InverseTextColor).SetLabelColor(tview.Styles.PrimaryTextColor).SetFieldTextColor(tview.Styles.ContrastSecondaryTextColor)
+
+	connectionNames := []string{schemaDiffCurrentConnection}
+	connections := app.App.Connections()
+
+	for _, conn := range connections {
+		if conn.Name != connection.Name {
+			connectionNames = append(connectionNames, conn.Name)
+		}
+	}
+
+	form.AddInputField("Source database", sourceDatabase, 0, nil, nil)
+	form.AddDropDown("Target connection", connectionNames, 0, nil)
+	form.AddInputField("Target database", sourceDatabase, 0, nil, nil)
+
+	statusText := tview.NewTextView()
+	statusText.SetBorderPadding(0, 0, 1, 1)
+	statusText.SetText("Enter to compare, Esc to cancel")
+	statusText.SetTextColor(app.Styles.TertiaryTextColor)
+
+	container := tview.NewFlex().SetDirection(tview.FlexRow)
+	container.SetBorder(true)
+	container.SetTitle(" Schema diff ")
+	container.AddItem(form, 0, 1, true)
+	container.AddItem(statusText, 1, 0, false)
+
+	wrapper := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(container, 11, 0, true).
+			AddItem(nil, 0, 1, false), 0, 2, true).
+		AddItem(nil, 0, 1, false)
+
+	schemaDiffForm := &SchemaDiffForm{
+		Flex:       wrapper,
+		Form:       form,
+		StatusText: statusText,
+		DBDriver:   dbdriver,
+	}
+
+	form.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEsc:
+			mainPages.RemovePage(pageNameSchemaDiffForm)
+			return nil
+		case tcell.KeyEnter:
+			// Let the dropdown open its list.
+			if _, ok := App.GetFocus().(*tview.DropDown); ok {
+				return event
+			}
+
+			_, targetConnectionName := form.GetFormItemByLabel("Target connection").(*tview.DropDown).GetCurrentOption()
+			source := form.GetFormItemByLabel("Source database").(*tview.InputField).GetText()
+			target := form.GetFormItemByLabel("Target database").(*tview.InputField).GetText()
+
+			targetConnection := connection
+			for _, conn := range connections {
+				if conn.Name == targetConnectionName {
+					targetConnection = conn
+				}
+			}
+
+			go schemaDiffForm.compare(source, targetConnection, target)
+			return nil
+		}
+
+		return event
+	})
+
+	return schemaDiffForm
+}
+
+func (form *SchemaDiffForm) compare(sourceDatabase string, targetConnection models.Connection, targetDatabase string) {
+	form.setStatus("Connecting...", app.Styles.TertiaryTextColor)
+
+	// A dedicated connection is opened for the target even if it is the
+	// current one, Postgres switches its connection when reading another
+	// database and the tree would end up on the wrong one.
+	target, err := connectToDatabase(targetConnection)
+	if err != nil {
+		form.setStatus(err.Error(), app.Styles.ErrorColor)
+		return
+	}
+	defer target.Close()
+
+	form.setStatus(fmt.Sprintf("Reading schema of %s...", sourceDatabase), app.Styles.TertiaryTextColor)
+
+	sourceSchema, err := drivers.GetDatabaseSchema(form.DBDriver, sourceDatabase)
+	if err != nil {
+		form.setStatus(err.Error(), app.Styles.ErrorColor)
+		return
+	}
+
+	form.setStatus(fmt.Sprintf("Reading schema of %s...", targetDatabase), app.Styles.TertiaryTextColor)
+
+	targetSchema, err := drivers.GetDatabaseSchema(target, targetDatabase)
+	if err != nil {
+		form.setStatus(err.Error(), app.Styles.ErrorColor)
+		return
+	}
+
+	// The tables of another provider are compared with the names and the
+	// types they get on the target.
+	sourceSchema = drivers.ConvertSchemas(sourceSchema, form.DBDriver.GetProvider(), targetSchema, target.GetProvider())
+
+	diff := drivers.DiffSchemas(sourceSchema, targetSchema)
+	migration := drivers.GenerateMigration(diff, target)
+
+	logger.Info("Schema diff", map[string]any{"source": sourceDatabase, "target": targetDatabase, "statements": len(migration)})
+
+	title := fmt.Sprintf(" %s → %s/%s ", sourceDatabase, targetConnection.Name, targetDatabase)
+
+	mainPages.RemovePage(pageNameSchemaDiffForm)
+	mainPages.AddPage(pageNameSchemaDiff, NewSchemaDiffView(title, diff, migration), true, true)
+	App.Draw()
+}
+
+func (form *SchemaDiffForm) setStatus(text string, color tcell.Color) {
+	form.StatusText.SetText(text).SetTextColor(color)
+	App.Draw()
+}
+
+type SchemaDiffView struct {
+	*tview.Flex
+	Table     *tview.Table
+	Migration *tview.TextView
+}
+
+// NewSchemaDiffView shows the differences side by side together with the
+// migration script that brings the target in line with the source.
+func NewSchemaDiffView(title string, diff models.SchemaDiff, migration []string) *SchemaDiffView {
+	table := tview.NewTable()
+	table.SetBorder(true)
+	table.SetTitle(title)
+	table.SetSelectable(true, false)
+	table.SetFixed(1, 0)
+	table.SetSelectedStyle(tcell.StyleDefault.Background(app.Styles.SecondaryTextColor).Foreground(tview.Styles.ContrastSecondaryTextColor))
+
+	migrationView := tview.NewTextView()
+	migrationView.SetBorder(true)
+	migrationView.SetTitle(" Migration ")
+	migrationView.SetWrap(false)
+
+	script := strings.Join(migration, "\n\n")
+	if script == "" {
+		script = "-- Schemas are identical"
+	}
+	migrationView.SetText(script)
+
+	keybindings := tview.NewTextView()
+	keybindings.SetDynamicColors(true)
+	keybindings.SetWrap(false)
+	keybindings.SetBorder(true)
+	keybindings.SetTitle(" Keybindings ")
+
+	for _, command := range app.Keymaps.Group(app.SchemaDiffGroup) {
+		keybindings.SetText(fmt.Sprintf("%s [%s](%s) [default]%s", keybindings.GetText(false), app.Styles.SecondaryTextColor, command.Keys().String(), command.Description))
+	}
+
+	content := tview.NewFlex()
+	content.AddItem(table, 0, 3, true)
+	content.AddItem(migrationView, 0, 2, false)
+
+	container := tview.NewFlex().SetDirection(tview.FlexRow)
+	container.AddItem(content, 0, 1, true)
+	container.AddItem(keybindings, 3, 0, false)
+
+	view := &SchemaDiffView{
+		Flex:      container,
+		Table:     table,
+		Migration: migrationView,
+	}
+
+	view.populateTable(diff)
+
+	container.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		command := app.Keymaps.Resolve(app.SchemaDiffGroup, event)
+
+		switch {
+		case command == commands.Quit || event.Key() == tcell.KeyEsc:
+			mainPages.RemovePage(pageNameSchemaDiff)
+			return nil
+		case command == commands.GotoNext:
+			if table.HasFocus() {
+				App.SetFocus(migrationView)
+			} else {
+				App.SetFocus(table)
+			}
+			return nil
+		case command == commands.Copy:
+			err := lib.NewClipboard().Write(script)
+			if err != nil {
+				logger.Info("Error copying migration", map[string]any{"error": err.Error()})
+			}
+			return nil
+		}
+
+		return event
+	})
+
+	return view
+}
+
+func (view *SchemaDiffView) populateTable(diff models.SchemaDiff) {
+	for col, header := range []string{"Object", "Status", "Source", "Target"} {
+		view.Table.SetCell(0, col, tview.NewTableCell(header).SetSelectable(false).SetTextColor(app.Styles.TertiaryTextColor))
+	}
+
+	row := 1
+
+	for _, table := range diff.Tables {
+		view.setRow(row, table.Name, table.Status, "", "")
+		row++
+
+		for _, object := range table.Objects {
+			if object.Status == models.SchemaDiffUnchanged {
+				continue
+			}
+
+			view.setRow(row, fmt.Sprintf("  %s %s", object.Kind, object.Name), object.Status, object.Source, object.Target)
+			row++
+		}
+	}
+
+	view.Table.Select(1, 0)
+}
+
+func (view *SchemaDiffView) setRow(row int, name string, status models.SchemaDiffStatus, source, target string) {
+	color := app.Styles.PrimaryTextColor
+
+	switch status {
+	case models.SchemaDiffAdded:
+		color = app.Styles.InsertedRowColor
+	case models.SchemaDiffRemoved:
+		color = app.Styles.DeletedRowColor
+	case models.SchemaDiffChanged:
+		color = app.Styles.ChangedRowColor
+	}
+
+	view.Table.SetCell(row, 0, tview.NewTableCell(tview.Escape(name)).SetTextColor(color))
+	view.Table.SetCell(row, 1, tview.NewTableCell(schemaDiffStatusText(status)).SetTextColor(color))
+	view.Table.SetCell(row, 2, tview.NewTableCell(tview.Escape(source)).SetExpansion(1))
+	view.Table.SetCell(row, 3, tview.NewTableCell(tview.Escape(target)).SetExpansion(1))
+}
+
+func schemaDiffStatusText(status models.SchemaDiffStatus) string {
+	switch status {
+	case models.SchemaDiffAdded:
+		return "added"
+	case models.SchemaDiffRemoved:
+		return "removed"
+	case models.SchemaDiffChanged:
+		return "changed"
+	}
+
+	return "unchanged"
+}
@@ -64,7 +64,7 @@ func (list *SetValueList) OnFinish(callback func(selection models.CellValueType,
 	})
 
 	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		command := app.Keymaps.Group(app.TableGroup).Resolve(event)
+		command := app.Keymaps.Resolve(app.TableGroup, event)
 
 		if command == commands.SetValue {
 			list.Hide()
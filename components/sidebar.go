@@ -206,8 +206,16 @@ func (sidebar *Sidebar) EditTextCurrentField() {
 }
 
 func (sidebar *Sidebar) inputCapture(event *tcell.EventKey) *tcell.EventKey {
-	command := app.Keymaps.Group(app.SidebarGroup).Resolve(event)
+	if sidebar.runCommand(app.Keymaps.Resolve(app.SidebarGroup, event)) {
+		return nil
+	}
+
+	return event
+}
 
+// runCommand runs a command of the sidebar, it reports whether the command was
+// handled.
+func (sidebar *Sidebar) runCommand(command commands.Command) bool {
 	switch command {
 	case commands.UnfocusSidebar:
 		sidebar.Publish(models.StateChange{Key: eventSidebarUnfocusing, Value: nil})
@@ -233,7 +241,7 @@ func (sidebar *Sidebar) inputCapture(event *tcell.EventKey) *tcell.EventKey {
 		columnName = columnNameSplit[0]
 
 		sidebar.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-			command := app.Keymaps.Group(app.SidebarGroup).Resolve(event)
+			command := app.Keymaps.Resolve(app.SidebarGroup, event)
 
 			switch command {
 			case commands.CommitEdit:
@@ -262,7 +270,7 @@ func (sidebar *Sidebar) inputCapture(event *tcell.EventKey) *tcell.EventKey {
 
 		sidebar.EditTextCurrentField()
 
-		return nil
+		return true
 	case commands.SetValue:
 		currentItemIndex := sidebar.GetCurrentFieldIndex()
 		item := sidebar.Flex.GetItem(currentItemIndex).(*tview.TextArea)
@@ -289,7 +297,7 @@ func (sidebar *Sidebar) inputCapture(event *tcell.EventKey) *tcell.EventKey {
 
 		list.Show(x, y, 30)
 
-		return nil
+		return true
 	case commands.Copy:
 		currentItemIndex := sidebar.GetCurrentFieldIndex()
 		item := sidebar.Flex.GetItem(currentItemIndex).(*tview.TextArea)
@@ -302,7 +310,7 @@ func (sidebar *Sidebar) inputCapture(event *tcell.EventKey) *tcell.EventKey {
 			sidebar.Publish(models.StateChange{Key: eventSidebarError, Value: err.Error()})
 		}
 	}
-	return event
+	return false
 }
 
 func (sidebar *Sidebar) SetEditingStyles(item *tview.TextArea) {
@@ -326,8 +334,8 @@ func (sidebar *Sidebar) SetDisabledStyles(item *tview.TextArea) {
 }
 
 func (sidebar *Sidebar) SetEditedStyles(item *tview.TextArea) {
-	item.SetBackgroundColor(colorTableChange)
-	item.SetTextStyle(tcell.StyleDefault.Background(colorTableChange).Foreground(tview.Styles.ContrastSecondaryTextColor))
+	item.SetBackgroundColor(app.Styles.ChangedRowColor)
+	item.SetTextStyle(tcell.StyleDefault.Background(app.Styles.ChangedRowColor).Foreground(tview.Styles.ContrastSecondaryTextColor))
 	item.SetTitleColor(app.Styles.ContrastSecondaryTextColor)
 	item.SetBorderColor(app.Styles.ContrastSecondaryTextColor)
 
@@ -35,7 +35,7 @@ func NewSQLEditor() *SQLEditor {
 		},
 	}
 	sqlEditor.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		command := app.Keymaps.Group(app.EditorGroup).Resolve(event)
+		command := app.Keymaps.Resolve(app.EditorGroup, event)
 
 		switch command {
 		case commands.Execute:
@@ -34,6 +34,9 @@ type Tree struct {
 	Wrapper             *tview.Flex
 	FoundNodeCountInput *tview.InputField
 	subscribers         []chan models.StateChange
+	// dbName is the database of the connection, the tree lists all the
+	// databases when it is empty.
+	dbName string
 }
 
 func NewTree(dbName string, dbdriver drivers.Driver) *Tree {
@@ -50,6 +53,7 @@ func NewTree(dbName string, dbdriver drivers.Driver) *Tree {
 		DBDriver:            dbdriver,
 		Filter:              tview.NewInputField(),
 		FoundNodeCountInput: tview.NewInputField(),
+		dbName:              dbName,
 	}
 
 	tree.SetTopLevel(1)
@@ -68,7 +72,7 @@ func NewTree(dbName string, dbdriver drivers.Driver) *Tree {
 		tree.SetFocusFunc(nil)
 	})
 
-	selectedNodeTextColor := fmt.Sprintf("[black:%s]", app.Styles.SecondaryTextColor.Name())
+	selectedNodeTextColor := fmt.Sprintf("[%s:%s]", app.Styles.SelectedTextColor, app.Styles.SelectedBackgroundColor)
 	previouslyFocusedNode := tree.GetCurrentNode()
 	previouslyFocusedNode.SetText(selectedNodeTextColor + previouslyFocusedNode.GetText())
 
@@ -88,100 +92,11 @@ func NewTree(dbName string, dbdriver drivers.Driver) *Tree {
 		previouslyFocusedNode = node
 	})
 
-	tree.SetSelectedFunc(func(node *tview.TreeNode) {
-		if node.GetLevel() == 1 {
-			if node.IsExpanded() {
-				node.SetExpanded(false)
-			} else {
-				tree.SetSelectedDatabase(node.GetReference().(string))
-
-				// if node.GetChildren() == nil {
-				// 	tables, err := tree.DBDriver.GetTables(tree.GetSelectedDatabase())
-				// 	if err != nil {
-				// 		// TODO: Handle error
-				// 		return
-				// 	}
-				//
-				// 	tree.databasesToNodes(tables, node, true)
-				// }
-				node.SetExpanded(true)
-
-			}
-		} else if node.GetLevel() == 2 {
-			if node.GetChildren() == nil {
-				nodeReference := node.GetReference().(string)
-				split := strings.Split(nodeReference, ".")
-				databaseName := ""
-				tableName := ""
-
-				if len(split) == 1 {
-					tableName = split[0]
-				} else if len(split) > 1 {
-					databaseName = split[0]
-					tableName = split[1]
-				}
-
-				tree.SetSelectedDatabase(databaseName)
-				tree.SetSelectedTable(tableName)
-			} else {
-				node.SetExpanded(!node.IsExpanded())
-			}
-		} else if node.GetLevel() == 3 {
-			nodeReference := node.GetReference().(string)
-			split := strings.Split(nodeReference, ".")
-			databaseName := split[0]
-			schemaName := split[1]
-			tableName := split[2]
-
-			tree.SetSelectedDatabase(databaseName)
-			tree.SetSelectedTable(fmt.Sprintf("%s.%s", schemaName, tableName))
-		}
-	})
+	tree.SetSelectedFunc(tree.openNode)
 
 	tree.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		command := app.Keymaps.Group(app.TreeGroup).Resolve(event)
+		tree.runCommand(app.Keymaps.ResolveCount(app.TreeGroup, event))
 
-		switch command {
-		case commands.GotoBottom:
-			childrens := tree.GetRoot().GetChildren()
-			lastNode := childrens[len(childrens)-1]
-
-			if lastNode.IsExpanded() {
-				childNodes := lastNode.GetChildren()
-				lastChildren := childNodes[len(childNodes)-1]
-				tree.SetCurrentNode(lastChildren)
-			} else {
-				tree.SetCurrentNode(lastNode)
-			}
-		case commands.GotoTop:
-			tree.SetCurrentNode(rootNode)
-		case commands.PageNext:
-			tree.Move(5)
-		case commands.PagePrev:
-			tree.Move(-5)
-		case commands.MoveDown:
-			tree.Move(1)
-		case commands.MoveUp:
-			tree.Move(-1)
-		case commands.Execute:
-			// Can't "select" the current node via TreeView api.
-			// So fake it by sending it a Enter key event
-			return tcell.NewEventKey(tcell.KeyEnter, 0, 0)
-		case commands.Search:
-			tree.RemoveHighlight()
-			App.SetFocus(tree.Filter)
-			tree.SetIsFiltering(true)
-		case commands.NextFoundNode:
-			tree.goToNextFoundNode()
-		case commands.PreviousFoundNode:
-			tree.goToPreviousFoundNode()
-		case commands.TreeCollapseAll:
-			tree.CollapseAll()
-		case commands.ExpandAll:
-			tree.ExpandAll()
-		case commands.Refresh:
-			tree.Refresh(dbName)
-		}
 		return nil
 	})
 
@@ -246,6 +161,98 @@ func NewTree(dbName string, dbdriver drivers.Driver) *Tree {
 	return tree
 }
 
+// openNode opens the node selected in the tree: it expands or collapses a
+// database and selects a table.
+func (tree *Tree) openNode(node *tview.TreeNode) {
+	if node.GetLevel() == 1 {
+		if node.IsExpanded() {
+			node.SetExpanded(false)
+		} else {
+			tree.SetSelectedDatabase(node.GetReference().(string))
+
+			// if node.GetChildren() == nil {
+			// 	tables, err := tree.DBDriver.GetTables(tree.GetSelectedDatabase())
+			// 	if err != nil {
+			// 		// TODO: Handle error
+			// 		return
+			// 	}
+			//
+			// 	tree.databasesToNodes(tables, node, true)
+			// }
+			node.SetExpanded(true)
+
+		}
+	} else if database, table, ok := tree.nodeTable(node, node.GetLevel()); ok {
+		tree.SetSelectedDatabase(database)
+		tree.SetSelectedTable(table)
+	} else if node.GetLevel() == 2 {
+		node.SetExpanded(!node.IsExpanded())
+	}
+}
+
+// runCommand runs a command of the tree, count times for the movements.
+func (tree *Tree) runCommand(command commands.Command, count int) {
+	switch command {
+	case commands.GotoBottom:
+		childrens := tree.GetRoot().GetChildren()
+		lastNode := childrens[len(childrens)-1]
+
+		if lastNode.IsExpanded() {
+			childNodes := lastNode.GetChildren()
+			lastChildren := childNodes[len(childNodes)-1]
+			tree.SetCurrentNode(lastChildren)
+		} else {
+			tree.SetCurrentNode(lastNode)
+		}
+	case commands.GotoTop:
+		tree.SetCurrentNode(tree.GetRoot())
+	case commands.PageNext:
+		tree.Move(5 * count)
+	case commands.PagePrev:
+		tree.Move(-5 * count)
+	case commands.MoveDown:
+		tree.Move(count)
+	case commands.MoveUp:
+		tree.Move(-count)
+	case commands.Execute:
+		tree.openNode(tree.GetCurrentNode())
+	case commands.Search:
+		tree.RemoveHighlight()
+		App.SetFocus(tree.Filter)
+		tree.SetIsFiltering(true)
+	case commands.NextFoundNode:
+		tree.goToNextFoundNode()
+	case commands.PreviousFoundNode:
+		tree.goToPreviousFoundNode()
+	case commands.TreeCollapseAll:
+		tree.CollapseAll()
+	case commands.ExpandAll:
+		tree.ExpandAll()
+	case commands.Refresh:
+		tree.Refresh(tree.dbName)
+	case commands.SchemaDiff:
+		go tree.Publish(models.StateChange{Key: eventTreeSchemaDiff, Value: tree.GetCurrentDatabase()})
+	case commands.DataDiff:
+		if table := tree.GetCurrentTable(); table != "" {
+			go tree.Publish(models.StateChange{Key: eventTreeDataDiff, Value: []string{tree.GetCurrentDatabase(), table}})
+		}
+	case commands.CopyTable:
+		if table := tree.GetCurrentTable(); table != "" {
+			go tree.Publish(models.StateChange{Key: eventTreeCopyTable, Value: []string{tree.GetCurrentDatabase(), table}})
+		}
+	case commands.Dump:
+		go tree.Publish(models.StateChange{Key: eventTreeDump, Value: []string{tree.GetCurrentDatabase(), tree.GetCurrentTable()}})
+	case commands.Restore:
+		go tree.Publish(models.StateChange{Key: eventTreeRestore, Value: tree.GetCurrentDatabase()})
+	case commands.ERDiagram:
+		go tree.Publish(models.StateChange{Key: eventTreeERDiagram, Value: []string{tree.GetCurrentDatabase(), tree.GetCurrentSchema()}})
+	case commands.AttachDatabase:
+		if _, ok := drivers.Unwrap(tree.DBDriver).(drivers.DatabaseAttacher); ok {
+			go tree.Publish(models.StateChange{Key: eventTreeAttachDatabase, Value: nil})
+		}
+	}
+}
+
 func (tree *Tree) databasesToNodes(children map[string][]string, node *tview.TreeNode, defaultExpanded bool) {
 	node.ClearChildren()
 
@@ -277,7 +284,7 @@ func (tree *Tree) databasesToNodes(children map[string][]string, node *tview.Tre
 			childNode.SetColor(app.Styles.PrimaryTextColor)
 			if tree.DBDriver.GetProvider() == "sqlite3" {
 				childNode.SetReference(child)
-			} else if tree.DBDriver.GetProvider() == "postgres" {
+			} else if drivers.HasSchemas(tree.DBDriver.GetProvider()) {
 				childNode.SetReference(fmt.Sprintf("%s.%s.%s", nodeReference, key, child))
 			} else {
 				childNode.SetReference(fmt.Sprintf("%s.%s", key, child))
@@ -364,6 +371,133 @@ func (tree *Tree) GetSelectedDatabase() string {
 	return tree.state.selectedDatabase
 }
 
+// GetCurrentDatabase returns the database of the node under the cursor, or
+// the selected database if the cursor is on the root node.
+func (tree *Tree) GetCurrentDatabase() string {
+	node := tree.GetCurrentNode()
+	if node == nil || node.GetLevel() == 0 {
+		return tree.GetSelectedDatabase()
+	}
+
+	return tree.nodeDatabase(node)
+}
+
+// nodeTable returns the database and the name of the table of a node at the
+// level of the tree, ok is false if the node is not a table.
+func (tree *Tree) nodeTable(node *tview.TreeNode, level int) (database, table string, ok bool) {
+	reference, isString := node.GetReference().(string)
+	if !isString {
+		return "", "", false
+	}
+
+	switch {
+	case level == 2 && node.GetChildren() == nil:
+		// SQLite tables are referenced by name, the database is the one of
+		// their parent node.
+		if tree.DBDriver.GetProvider() == drivers.DriverSqlite {
+			return tree.nodeDatabase(node), reference, true
+		}
+
+		split := strings.SplitN(reference, ".", 2)
+		if len(split) == 1 {
+			return "", split[0], true
+		}
+
+		return split[0], split[1], true
+	case level == 3:
+		split := strings.Split(reference, ".")
+		if len(split) < 3 {
+			return "", "", false
+		}
+
+		return split[0], fmt.Sprintf("%s.%s", split[1], split[2]), true
+	}
+
+	return "", "", false
+}
+
+// nodeDatabase returns the database a node belongs to, or the selected
+// database if the node is not in the tree.
+func (tree *Tree) nodeDatabase(node *tview.TreeNode) string {
+	// Postgres and MSSQL schema nodes only reference the schema, the database is
+	// always the reference of the first level node.
+	path := tree.GetPath(node)
+	if len(path) < 2 {
+		return tree.GetSelectedDatabase()
+	}
+
+	reference, ok := path[1].GetReference().(string)
+	if !ok {
+		return tree.GetSelectedDatabase()
+	}
+
+	// The main SQLite database is listed by its file name.
+	if tree.DBDriver.GetProvider() == drivers.DriverSqlite {
+		return reference
+	}
+
+	return strings.Split(reference, ".")[0]
+}
+
+// GetCurrentSchema returns the Postgres or MSSQL schema of the node under the
+// cursor, or an empty string if the cursor is not on a schema or one of its
+// tables.
+func (tree *Tree) GetCurrentSchema() string {
+	node := tree.GetCurrentNode()
+	if node == nil || !drivers.HasSchemas(tree.DBDriver.GetProvider()) {
+		return ""
+	}
+
+	reference, ok := node.GetReference().(string)
+	if !ok {
+		return ""
+	}
+
+	switch node.GetLevel() {
+	case 2:
+		return reference
+	case 3:
+		if split := strings.Split(reference, "."); len(split) == 3 {
+			return split[1]
+		}
+	}
+
+	return ""
+}
+
+// GetCurrentTable returns the table under the cursor in the format used by the
+// driver, or an empty string if the cursor is not on a table.
+func (tree *Tree) GetCurrentTable() string {
+	node := tree.GetCurrentNode()
+	if node == nil {
+		return ""
+	}
+
+	reference, ok := node.GetReference().(string)
+	if !ok {
+		return ""
+	}
+
+	switch drivers.Dialect(tree.DBDriver.GetProvider()) {
+	case drivers.DriverSqlite:
+		if node.GetLevel() == 2 {
+			return reference
+		}
+	case drivers.DriverPostgres, drivers.DriverMSSQL:
+		if split := strings.Split(reference, "."); node.GetLevel() == 3 && len(split) == 3 {
+			return fmt.Sprintf("%s.%s", split[1], split[2])
+		}
+	default:
+		// DuckDB tables outside the main schema are referenced as
+		// database.schema.table.
+		if split := strings.SplitN(reference, ".", 2); node.GetLevel() == 2 && len(split) > 1 {
+			return split[1]
+		}
+	}
+
+	return ""
+}
+
 func (tree *Tree) GetSelectedTable() string {
 	return tree.state.selectedTable
 }
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"slices"
 	"strings"
 
 	"github.com/xo/dburl"
@@ -13,6 +14,13 @@ import (
 	"github.com/jorgerojas26/lazysql/models"
 )
 
+const (
+	clickhouseMenuEngine      = "Engine"
+	clickhouseMenuParts       = "Parts"
+	clickhouseMenuCompression = "Compression"
+	clickhouseMenuMutations   = "Mutations"
+)
+
 type Clickhouse struct {
 	Connection *sql.DB
 	Provider   string
@@ -48,6 +56,11 @@ func (db *Clickhouse) Connect(urlstr string) (err error) {
 		opts.Protocol = clickhouse.HTTP
 	}
 
+	opts.TLS, err = tlsConfigFromParam(url.Query().Get("tls"))
+	if err != nil {
+		return err
+	}
+
 	db.Connection = clickhouse.OpenDB(opts)
 
 	err = db.Connection.Ping()
@@ -424,20 +437,23 @@ func (db *Clickhouse) ExecuteQuery(query string) ([][]string, int, error) {
 	return results, len(records), nil
 }
 
+// UpdateRecord submits an ALTER TABLE UPDATE mutation, ClickHouse applies it
+// in the background. See GetMutationProgress.
 func (db *Clickhouse) UpdateRecord(database, table, column, value, primaryKeyColumnName, primaryKeyValue string) error {
-	query := "UPDATE "
+	query := "ALTER TABLE "
 	query += db.formatTableName(database, table)
-	query += fmt.Sprintf(" SET %s = ? WHERE %s = ?", column, primaryKeyColumnName)
+	query += fmt.Sprintf(" UPDATE %s = ? WHERE %s = ?", db.FormatReference(column), db.FormatReference(primaryKeyColumnName))
 
 	_, err := db.Connection.Exec(query, value, primaryKeyValue)
 
 	return err
 }
 
+// DeleteRecord submits an ALTER TABLE DELETE mutation.
 func (db *Clickhouse) DeleteRecord(database, table, primaryKeyColumnName, primaryKeyValue string) error {
-	query := "DELETE FROM "
+	query := "ALTER TABLE "
 	query += db.formatTableName(database, table)
-	query += fmt.Sprintf(" WHERE %s = ?", primaryKeyColumnName)
+	query += fmt.Sprintf(" DELETE WHERE %s = ?", db.FormatReference(primaryKeyColumnName))
 	_, err := db.Connection.Exec(query, primaryKeyValue)
 
 	return err
@@ -468,14 +484,84 @@ func (db *Clickhouse) ExecutePendingChanges(changes []models.DBDMLChange) error
 
 		case models.DMLInsertType:
 			queries = append(queries, buildInsertQuery(formattedTableName, change.Values, db))
-		case models.DMLUpdateType:
-			queries = append(queries, buildUpdateQuery(formattedTableName, change.Values, change.PrimaryKeyInfo, db))
-		case models.DMLDeleteType:
-			queries = append(queries, buildDeleteQuery(formattedTableName, change.PrimaryKeyInfo, db))
+		case models.DMLUpdateType, models.DMLDeleteType:
+			query, err := db.mutationQuery(formattedTableName, change)
+			if err != nil {
+				return err
+			}
+
+			queries = append(queries, query)
+		}
+	}
+
+	// ClickHouse has no transactions, the statements are sent one by one and
+	// the mutations run after they are accepted.
+	for _, query := range queries {
+		if _, err := db.Connection.Exec(query.Query, query.Args...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// mutationQuery returns the ALTER TABLE UPDATE or DELETE mutation ClickHouse
+// runs an update or a delete with. The mutations cannot set a column to its
+// DEFAULT nor change the key columns, those changes return an error.
+func (db *Clickhouse) mutationQuery(formattedTableName string, change models.DBDMLChange) (models.Query, error) {
+	if len(change.PrimaryKeyInfo) == 0 {
+		return models.Query{}, fmt.Errorf("the rows of %s cannot be changed without a primary key", change.Table)
+	}
+
+	queryStr := "ALTER TABLE " + formattedTableName
+	args := []any{}
+
+	if change.Type == models.DMLUpdateType {
+		if len(change.Values) == 0 {
+			return models.Query{}, fmt.Errorf("no values to update in %s", change.Table)
+		}
+
+		assignments := make([]string, len(change.Values))
+
+		for i, value := range change.Values {
+			isKey := slices.ContainsFunc(change.PrimaryKeyInfo, func(primaryKey models.PrimaryKeyInfo) bool {
+				return primaryKey.Name == value.Column
+			})
+			if isKey {
+				return models.Query{}, fmt.Errorf("%s is a key column, ClickHouse mutations cannot update it", value.Column)
+			}
+
+			placeholder := ""
+
+			switch value.Type {
+			case models.Default:
+				return models.Query{}, fmt.Errorf("ClickHouse mutations cannot set %s to its DEFAULT", value.Column)
+			case models.Null:
+				placeholder = "NULL"
+			case models.Empty:
+				placeholder = "''"
+			default:
+				args = append(args, value.Value)
+				placeholder = db.FormatPlaceholder(len(args))
+			}
+
+			assignments[i] = fmt.Sprintf("%s = %s", db.FormatReference(value.Column), placeholder)
 		}
+
+		queryStr += " UPDATE " + strings.Join(assignments, ", ")
+	} else {
+		queryStr += " DELETE"
 	}
 
-	return queriesInTransaction(db.Connection, queries)
+	conditions := make([]string, len(change.PrimaryKeyInfo))
+	for i, primaryKey := range change.PrimaryKeyInfo {
+		args = append(args, primaryKey.Value)
+		conditions[i] = fmt.Sprintf("%s = %s", db.FormatReference(primaryKey.Name), db.FormatPlaceholder(len(args)))
+	}
+
+	queryStr += " WHERE " + strings.Join(conditions, " AND ")
+
+	return models.Query{Query: queryStr, Args: args}, nil
 }
 
 func (db *Clickhouse) GetPrimaryKeyColumnNames(database, table string) (primaryKeyColumnName []string, err error) {
@@ -515,6 +601,168 @@ func (db *Clickhouse) GetPrimaryKeyColumnNames(database, table string) (primaryK
 	return primaryKeyColumnName, nil
 }
 
+// TableMenus lists the ClickHouse menus shown after the common ones.
+func (db *Clickhouse) TableMenus() []string {
+	return []string{clickhouseMenuEngine, clickhouseMenuParts, clickhouseMenuCompression, clickhouseMenuMutations}
+}
+
+// GetTableMenu returns the rows of one of the menus listed by TableMenus.
+func (db *Clickhouse) GetTableMenu(database, table, menu string) ([][]string, error) {
+	if database == "" {
+		return nil, errors.New("database name is required")
+	}
+
+	if table == "" {
+		return nil, errors.New("table name is required")
+	}
+
+	switch menu {
+	case clickhouseMenuEngine:
+		return db.getEngine(database, table)
+	case clickhouseMenuParts:
+		return db.queryRows(`SELECT
+				partition AS PARTITION,
+				count() AS PARTS,
+				sum(rows) AS ROWS,
+				formatReadableSize(sum(bytes_on_disk)) AS ON_DISK,
+				formatReadableSize(sum(data_compressed_bytes)) AS COMPRESSED,
+				formatReadableSize(sum(data_uncompressed_bytes)) AS UNCOMPRESSED,
+				round(sum(data_uncompressed_bytes) / greatest(sum(data_compressed_bytes), 1), 2) AS RATIO
+			FROM system.parts
+			WHERE database = ? AND table = ? AND active
+			GROUP BY partition
+			ORDER BY partition`, database, table)
+	case clickhouseMenuCompression:
+		return db.queryRows(`SELECT
+				name AS COLUMN_NAME,
+				type AS TYPE,
+				compression_codec AS CODEC,
+				formatReadableSize(data_compressed_bytes) AS COMPRESSED,
+				formatReadableSize(data_uncompressed_bytes) AS UNCOMPRESSED,
+				round(data_uncompressed_bytes / greatest(data_compressed_bytes, 1), 2) AS RATIO
+			FROM system.columns
+			WHERE database = ? AND table = ?
+			ORDER BY position`, database, table)
+	case clickhouseMenuMutations:
+		return db.queryRows(`SELECT
+				mutation_id AS MUTATION_ID,
+				command AS COMMAND,
+				create_time AS CREATED,
+				parts_to_do AS PARTS_TO_DO,
+				is_done AS DONE,
+				latest_fail_reason AS FAIL_REASON
+			FROM system.mutations
+			WHERE database = ? AND table = ?
+			ORDER BY create_time DESC`, database, table)
+	}
+
+	return nil, fmt.Errorf("unknown menu %s", menu)
+}
+
+// getEngine lists the engine and keys of a table with its totals, one
+// property per row.
+func (db *Clickhouse) getEngine(database, table string) ([][]string, error) {
+	rows, err := db.queryRows(`SELECT
+			t.engine,
+			t.engine_full,
+			t.sorting_key,
+			t.partition_key,
+			t.primary_key,
+			t.sampling_key,
+			t.total_rows,
+			formatReadableSize(t.total_bytes),
+			formatReadableSize(c.compressed),
+			formatReadableSize(c.uncompressed),
+			round(c.uncompressed / greatest(c.compressed, 1), 2)
+		FROM system.tables t
+		LEFT JOIN (
+			SELECT database, table, sum(data_compressed_bytes) AS compressed, sum(data_uncompressed_bytes) AS uncompressed
+			FROM system.columns
+			WHERE database = ? AND table = ?
+			GROUP BY database, table
+		) c ON c.database = t.database AND c.table = t.name
+		WHERE t.database = ? AND t.name = ?`, database, table, database, table)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) < 2 {
+		return nil, fmt.Errorf("table %s.%s not found", database, table)
+	}
+
+	properties := []string{"ENGINE", "ENGINE_FULL", "ORDER BY", "PARTITION BY", "PRIMARY KEY", "SAMPLE BY", "TOTAL_ROWS", "TOTAL_BYTES", "COMPRESSED", "UNCOMPRESSED", "COMPRESSION_RATIO"}
+
+	results := [][]string{{"PROPERTY", "VALUE"}}
+	for i, property := range properties {
+		results = append(results, []string{property, rows[1][i]})
+	}
+
+	return results, nil
+}
+
+// GetMutationProgress returns the mutations of a table that are not done yet.
+func (db *Clickhouse) GetMutationProgress(database, table string) (progress MutationProgress, err error) {
+	row := db.Connection.QueryRow(`SELECT
+			count(),
+			sum(parts_to_do),
+			anyIf(latest_fail_reason, latest_fail_reason != '')
+		FROM system.mutations
+		WHERE database = ? AND table = ? AND NOT is_done`, database, table)
+
+	var failReason sql.NullString
+	if err := row.Scan(&progress.Pending, &progress.PartsToDo, &failReason); err != nil {
+		return progress, err
+	}
+
+	progress.FailReason = failReason.String
+
+	return progress, nil
+}
+
+// queryRows runs a query with arguments and returns its rows after the column
+// names, NULL values are returned as NULL&.
+func (db *Clickhouse) queryRows(query string, args ...any) ([][]string, error) {
+	rows, err := db.Connection.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	results := [][]string{columns}
+
+	for rows.Next() {
+		rowValues := make([]any, len(columns))
+		for i := range columns {
+			rowValues[i] = new(sql.NullString)
+		}
+
+		if err := rows.Scan(rowValues...); err != nil {
+			return nil, err
+		}
+
+		row := make([]string, len(columns))
+		for i, value := range rowValues {
+			if value := value.(*sql.NullString); value.Valid {
+				row[i] = value.String
+			} else {
+				row[i] = "NULL&"
+			}
+		}
+
+		results = append(results, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
 func (db *Clickhouse) SetProvider(provider string) {
 	db.Provider = provider
 }
@@ -523,6 +771,23 @@ func (db *Clickhouse) GetProvider() string {
 	return db.Provider
 }
 
+// Ping checks that the connection to the server is alive.
+func (db *Clickhouse) Ping() error {
+	if db.Connection == nil {
+		return errors.New("not connected")
+	}
+
+	return db.Connection.Ping()
+}
+
+func (db *Clickhouse) Close() error {
+	if db.Connection == nil {
+		return nil
+	}
+
+	return db.Connection.Close()
+}
+
 func (db *Clickhouse) formatTableName(database, table string) string {
 	return fmt.Sprintf("`%s`.`%s`", database, table)
 }
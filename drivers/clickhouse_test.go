@@ -0,0 +1,316 @@
+package drivers
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+const (
+	testDBNameClickhouse      = "analytics"
+	testDBTableNameClickhouse = "events"
+)
+
+func TestClickhouse_UpdateRecord(t *testing.T) {
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock: %v", err)
+	}
+	defer conn.Close()
+
+	db := &Clickhouse{Connection: conn}
+
+	mock.ExpectExec(regexp.QuoteMeta("ALTER TABLE `analytics`.`events` UPDATE `name` = ? WHERE `id` = ?")).
+		WithArgs("new", "1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if err := db.UpdateRecord(testDBNameClickhouse, testDBTableNameClickhouse, "name", "new", "id", "1"); err != nil {
+		t.Fatalf("UpdateRecord failed: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %s", err)
+	}
+}
+
+func TestClickhouse_ExecutePendingChanges(t *testing.T) {
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock: %v", err)
+	}
+	defer conn.Close()
+
+	db := &Clickhouse{Connection: conn}
+
+	changes := []models.DBDMLChange{
+		{
+			Database: testDBNameClickhouse,
+			Table:    testDBTableNameClickhouse,
+			Type:     models.DMLInsertType,
+			Values: []models.CellValue{
+				{Column: "id", Value: "2", Type: models.String},
+			},
+		},
+		{
+			Database: testDBNameClickhouse,
+			Table:    testDBTableNameClickhouse,
+			Type:     models.DMLUpdateType,
+			Values: []models.CellValue{
+				{Column: "name", Value: "renamed", Type: models.String},
+			},
+			PrimaryKeyInfo: []models.PrimaryKeyInfo{{Name: "id", Value: "1"}},
+		},
+		{
+			Database:       testDBNameClickhouse,
+			Table:          testDBTableNameClickhouse,
+			Type:           models.DMLDeleteType,
+			PrimaryKeyInfo: []models.PrimaryKeyInfo{{Name: "id", Value: "3"}},
+		},
+	}
+
+	// The statements are not wrapped in a transaction.
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `analytics`.`events` (`id`) VALUES (?)")).
+		WithArgs("2").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("ALTER TABLE `analytics`.`events` UPDATE `name` = ? WHERE `id` = ?")).
+		WithArgs("renamed", "1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("ALTER TABLE `analytics`.`events` DELETE WHERE `id` = ?")).
+		WithArgs("3").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if err := db.ExecutePendingChanges(changes); err != nil {
+		t.Fatalf("ExecutePendingChanges failed: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %s", err)
+	}
+}
+
+func TestClickhouse_MutationQuery(t *testing.T) {
+	db := &Clickhouse{}
+	table := "`analytics`.`events`"
+	primaryKey := []models.PrimaryKeyInfo{{Name: "id", Value: "1"}, {Name: "day", Value: "2024-01-02"}}
+
+	testCases := []struct {
+		name     string
+		change   models.DBDMLChange
+		expected models.Query
+		wantErr  bool
+	}{
+		{
+			name: "update",
+			change: models.DBDMLChange{
+				Type: models.DMLUpdateType,
+				Values: []models.CellValue{
+					{Column: "name", Value: "renamed", Type: models.String},
+					{Column: "email", Type: models.Null},
+					{Column: "note", Type: models.Empty},
+				},
+				PrimaryKeyInfo: primaryKey,
+			},
+			expected: models.Query{
+				Query: "ALTER TABLE `analytics`.`events` UPDATE `name` = ?, `email` = NULL, `note` = '' WHERE `id` = ? AND `day` = ?",
+				Args:  []any{"renamed", "1", "2024-01-02"},
+			},
+		},
+		{
+			name:   "delete",
+			change: models.DBDMLChange{Type: models.DMLDeleteType, PrimaryKeyInfo: primaryKey},
+			expected: models.Query{
+				Query: "ALTER TABLE `analytics`.`events` DELETE WHERE `id` = ? AND `day` = ?",
+				Args:  []any{"1", "2024-01-02"},
+			},
+		},
+		{
+			name: "default value",
+			change: models.DBDMLChange{
+				Type:           models.DMLUpdateType,
+				Values:         []models.CellValue{{Column: "name", Type: models.Default}},
+				PrimaryKeyInfo: primaryKey,
+			},
+			wantErr: true,
+		},
+		{
+			name: "key column",
+			change: models.DBDMLChange{
+				Type:           models.DMLUpdateType,
+				Values:         []models.CellValue{{Column: "day", Value: "2024-01-03", Type: models.String}},
+				PrimaryKeyInfo: primaryKey,
+			},
+			wantErr: true,
+		},
+		{
+			name:    "no values",
+			change:  models.DBDMLChange{Type: models.DMLUpdateType, PrimaryKeyInfo: primaryKey},
+			wantErr: true,
+		},
+		{
+			name:    "no primary key",
+			change:  models.DBDMLChange{Type: models.DMLDeleteType},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			query, err := db.mutationQuery(table, tc.change)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected an error to be %v, but got %v", tc.wantErr, err)
+			}
+
+			if !tc.wantErr && !reflect.DeepEqual(query, tc.expected) {
+				t.Fatalf("expected %v, but got %v", tc.expected, query)
+			}
+		})
+	}
+}
+
+func TestClickhouse_ExecutePendingChanges_Invalid(t *testing.T) {
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock: %v", err)
+	}
+	defer conn.Close()
+
+	db := &Clickhouse{Connection: conn}
+
+	changes := []models.DBDMLChange{
+		{
+			Database: testDBNameClickhouse,
+			Table:    testDBTableNameClickhouse,
+			Type:     models.DMLInsertType,
+			Values:   []models.CellValue{{Column: "id", Value: "2", Type: models.String}},
+		},
+		{
+			Database:       testDBNameClickhouse,
+			Table:          testDBTableNameClickhouse,
+			Type:           models.DMLUpdateType,
+			Values:         []models.CellValue{{Column: "name", Type: models.Default}},
+			PrimaryKeyInfo: []models.PrimaryKeyInfo{{Name: "id", Value: "1"}},
+		},
+	}
+
+	// Nothing is sent when a change cannot be expressed.
+	err = db.ExecutePendingChanges(changes)
+	if err == nil || !strings.Contains(err.Error(), "DEFAULT") {
+		t.Fatalf("expected an error about the DEFAULT value, but got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %s", err)
+	}
+}
+
+func TestClickhouse_GetTableMenu_Engine(t *testing.T) {
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock: %v", err)
+	}
+	defer conn.Close()
+
+	db := &Clickhouse{Connection: conn}
+
+	mock.ExpectQuery("FROM system.tables t").
+		WithArgs(testDBNameClickhouse, testDBTableNameClickhouse, testDBNameClickhouse, testDBTableNameClickhouse).
+		WillReturnRows(sqlmock.NewRows([]string{"engine", "engine_full", "sorting_key", "partition_key", "primary_key", "sampling_key", "total_rows", "total_bytes", "compressed", "uncompressed", "ratio"}).
+			AddRow("MergeTree", "MergeTree PARTITION BY toYYYYMM(ts) ORDER BY (id, ts)", "id, ts", "toYYYYMM(ts)", "id, ts", "", "100", "1.00 KiB", "800.00 B", "2.34 KiB", "3"))
+
+	rows, err := db.GetTableMenu(testDBNameClickhouse, testDBTableNameClickhouse, clickhouseMenuEngine)
+	if err != nil {
+		t.Fatalf("GetTableMenu failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(rows[0], []string{"PROPERTY", "VALUE"}) {
+		t.Fatalf("unexpected header %v", rows[0])
+	}
+
+	expected := map[string]string{
+		"ENGINE":            "MergeTree",
+		"ORDER BY":          "id, ts",
+		"PARTITION BY":      "toYYYYMM(ts)",
+		"COMPRESSION_RATIO": "3",
+	}
+
+	for _, row := range rows[1:] {
+		if value, ok := expected[row[0]]; ok && value != row[1] {
+			t.Errorf("expected %s to be %q, but got %q", row[0], value, row[1])
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %s", err)
+	}
+}
+
+func TestClickhouse_GetTableMenu_Parts(t *testing.T) {
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock: %v", err)
+	}
+	defer conn.Close()
+
+	db := &Clickhouse{Connection: conn}
+
+	mock.ExpectQuery("FROM system.parts").
+		WithArgs(testDBNameClickhouse, testDBTableNameClickhouse).
+		WillReturnRows(sqlmock.NewRows([]string{"PARTITION", "PARTS", "ROWS", "ON_DISK", "COMPRESSED", "UNCOMPRESSED", "RATIO"}).
+			AddRow("202401", 3, 1000, "10.00 KiB", "9.00 KiB", "27.00 KiB", 3).
+			AddRow("202402", 1, nil, "1.00 KiB", "1.00 KiB", "1.00 KiB", 1))
+
+	rows, err := db.GetTableMenu(testDBNameClickhouse, testDBTableNameClickhouse, clickhouseMenuParts)
+	if err != nil {
+		t.Fatalf("GetTableMenu failed: %v", err)
+	}
+
+	expected := [][]string{
+		{"PARTITION", "PARTS", "ROWS", "ON_DISK", "COMPRESSED", "UNCOMPRESSED", "RATIO"},
+		{"202401", "3", "1000", "10.00 KiB", "9.00 KiB", "27.00 KiB", "3"},
+		{"202402", "1", "NULL&", "1.00 KiB", "1.00 KiB", "1.00 KiB", "1"},
+	}
+
+	if !reflect.DeepEqual(rows, expected) {
+		t.Fatalf("expected %v, but got %v", expected, rows)
+	}
+
+	if _, err := db.GetTableMenu(testDBNameClickhouse, testDBTableNameClickhouse, "Unknown"); err == nil {
+		t.Fatal("expected an error for an unknown menu")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %s", err)
+	}
+}
+
+func TestClickhouse_GetMutationProgress(t *testing.T) {
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error creating mock: %v", err)
+	}
+	defer conn.Close()
+
+	db := &Clickhouse{Connection: conn}
+
+	mock.ExpectQuery("FROM system.mutations").
+		WithArgs(testDBNameClickhouse, testDBTableNameClickhouse).
+		WillReturnRows(sqlmock.NewRows([]string{"count", "parts", "reason"}).AddRow(2, 7, ""))
+
+	progress, err := db.GetMutationProgress(testDBNameClickhouse, testDBTableNameClickhouse)
+	if err != nil {
+		t.Fatalf("GetMutationProgress failed: %v", err)
+	}
+
+	if expected := (MutationProgress{Pending: 2, PartsToDo: 7}); progress != expected {
+		t.Fatalf("expected %+v, but got %+v", expected, progress)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %s", err)
+	}
+}
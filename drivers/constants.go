@@ -11,4 +11,40 @@ const (
 	DriverSqlite     string = "sqlite3"
 	DriverMSSQL      string = "sqlserver"
 	DriverClickhouse string = "clickhouse"
+	DriverDuckDB     string = "duckdb"
+	DriverFiles      string = "files"
+	DriverOracle     string = "oracle"
 )
+
+// Flavours of MySQL and Postgres. They are detected from the server version on
+// Connect and reported by GetProvider, the connections keep the provider of
+// their URL.
+const (
+	DriverMariaDB     string = "mariadb"
+	DriverTiDB        string = "tidb"
+	DriverCockroachDB string = "cockroachdb"
+)
+
+// Dialect returns the provider whose SQL the given provider speaks, that is
+// MySQL or Postgres for their flavours and the provider itself otherwise.
+func Dialect(provider string) string {
+	switch provider {
+	case DriverMariaDB, DriverTiDB:
+		return DriverMySQL
+	case DriverCockroachDB:
+		return DriverPostgres
+	}
+
+	return provider
+}
+
+// HasSchemas reports whether the databases of a provider group their tables in
+// schemas, the tables are then referenced as "schema.table".
+func HasSchemas(provider string) bool {
+	switch Dialect(provider) {
+	case DriverPostgres, DriverMSSQL:
+		return true
+	}
+
+	return false
+}
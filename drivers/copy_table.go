@@ -0,0 +1,544 @@
+package drivers
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// Generic column types used to translate a column type from one provider to
+// another.
+const (
+	columnTypeSmallInt  = "smallint"
+	columnTypeInteger   = "integer"
+	columnTypeBigInt    = "bigint"
+	columnTypeBoolean   = "boolean"
+	columnTypeDecimal   = "decimal"
+	columnTypeFloat     = "float"
+	columnTypeDouble    = "double"
+	columnTypeChar      = "char"
+	columnTypeVarchar   = "varchar"
+	columnTypeText      = "text"
+	columnTypeDate      = "date"
+	columnTypeTime      = "time"
+	columnTypeTimestamp = "timestamp"
+	columnTypeBinary    = "binary"
+	columnTypeJSON      = "json"
+	columnTypeUUID      = "uuid"
+)
+
+var columnTypeArgumentsRegexp = regexp.MustCompile(`\(([^)]*)\)`)
+
+// genericColumnType classifies a provider specific column type, it returns
+// the generic type and its arguments (length, precision and scale).
+func genericColumnType(columnType string) (string, string) {
+	lower := strings.ToLower(strings.TrimSpace(columnType))
+
+	// ClickHouse Int8 is a single byte while Postgres int8 is a bigint.
+	if strings.Contains(columnType, "Int8") {
+		return columnTypeSmallInt, ""
+	}
+
+	// ClickHouse wrappers.
+	for _, wrapper := range []string{"nullable(", "lowcardinality("} {
+		for strings.HasPrefix(lower, wrapper) && strings.HasSuffix(lower, ")") {
+			lower = strings.TrimSuffix(strings.TrimPrefix(lower, wrapper), ")")
+		}
+	}
+
+	arguments := ""
+	if match := columnTypeArgumentsRegexp.FindStringSubmatch(lower); match != nil {
+		arguments = strings.ReplaceAll(match[1], " ", "")
+		// Oracle lengths may be in bytes or characters, like VARCHAR2(20 BYTE).
+		arguments = strings.TrimSuffix(strings.TrimSuffix(arguments, "byte"), "char")
+		lower = columnTypeArgumentsRegexp.ReplaceAllString(lower, "")
+	}
+
+	lower = strings.TrimSpace(strings.ReplaceAll(lower, "unsigned", ""))
+
+	switch {
+	case lower == "tinyint" && arguments == "1", lower == "bool", lower == "boolean", lower == "bit":
+		return columnTypeBoolean, ""
+	case lower == "smallint", lower == "tinyint", lower == "int2", lower == "int16", lower == "uint16", lower == "smallserial":
+		return columnTypeSmallInt, ""
+	case lower == "bigint", lower == "int8", lower == "int64", lower == "uint64", lower == "bigserial", lower == "serial8":
+		return columnTypeBigInt, ""
+	case lower == "interval", lower == "point":
+		return columnTypeText, ""
+	case strings.Contains(lower, "int"), lower == "serial", lower == "mediumint":
+		return columnTypeInteger, ""
+	case lower == "decimal", lower == "numeric", lower == "money", strings.HasPrefix(lower, "decimal"):
+		return columnTypeDecimal, arguments
+	case lower == "number":
+		return numberColumnType(arguments)
+	case lower == "real", lower == "float4", lower == "float32", lower == "binary_float":
+		return columnTypeFloat, ""
+	case strings.HasPrefix(lower, "double"), lower == "float", lower == "float8", lower == "float64", lower == "binary_double":
+		return columnTypeDouble, ""
+	case lower == "uuid", lower == "uniqueidentifier":
+		return columnTypeUUID, ""
+	case lower == "json", lower == "jsonb":
+		return columnTypeJSON, ""
+	case lower == "char", lower == "nchar", lower == "character", lower == "fixedstring":
+		return columnTypeChar, arguments
+	case strings.Contains(lower, "varchar"), lower == "character varying":
+		if arguments == "max" {
+			return columnTypeText, ""
+		}
+		return columnTypeVarchar, arguments
+	case strings.Contains(lower, "text"), lower == "string", strings.HasSuffix(lower, "clob"), lower == "enum", lower == "set":
+		return columnTypeText, ""
+	case lower == "date", lower == "date32":
+		return columnTypeDate, ""
+	case strings.HasPrefix(lower, "time") && !strings.HasPrefix(lower, "timestamp"):
+		return columnTypeTime, ""
+	case strings.HasPrefix(lower, "timestamp"), strings.HasPrefix(lower, "datetime"), lower == "smalldatetime", lower == "datetimeoffset":
+		return columnTypeTimestamp, ""
+	case strings.Contains(lower, "blob"), strings.Contains(lower, "binary"), lower == "bytea", lower == "image", strings.HasSuffix(lower, "raw"):
+		return columnTypeBinary, ""
+	}
+
+	return columnTypeText, ""
+}
+
+// numberColumnType classifies an Oracle NUMBER, the numbers without decimals
+// are integers of the size of their precision.
+func numberColumnType(arguments string) (string, string) {
+	precision, scale, _ := strings.Cut(arguments, ",")
+
+	digits, err := strconv.Atoi(precision)
+	if err != nil || (scale != "" && scale != "0") {
+		return columnTypeDecimal, arguments
+	}
+
+	switch {
+	case digits <= 4:
+		return columnTypeSmallInt, ""
+	case digits <= 9:
+		return columnTypeInteger, ""
+	case digits <= 18:
+		return columnTypeBigInt, ""
+	}
+
+	return columnTypeDecimal, arguments
+}
+
+// MapColumnType translates a column type of the source provider to the
+// closest type of the target provider.
+func MapColumnType(sourceProvider, targetProvider, columnType string) string {
+	if sourceProvider == targetProvider {
+		return columnType
+	}
+
+	genericType, arguments := genericColumnType(columnType)
+
+	// Oracle dates have a time of the day.
+	if sourceProvider == DriverOracle && genericType == columnTypeDate {
+		genericType = columnTypeTimestamp
+	}
+
+	withArguments := func(name, defaultArguments string) string {
+		if arguments == "" {
+			arguments = defaultArguments
+		}
+		if arguments == "" {
+			return name
+		}
+		return fmt.Sprintf("%s(%s)", name, arguments)
+	}
+
+	switch targetProvider {
+	case DriverPostgres:
+		switch genericType {
+		case columnTypeSmallInt:
+			return "smallint"
+		case columnTypeInteger:
+			return "integer"
+		case columnTypeBigInt:
+			return "bigint"
+		case columnTypeBoolean:
+			return "boolean"
+		case columnTypeDecimal:
+			return withArguments("numeric", "")
+		case columnTypeFloat:
+			return "real"
+		case columnTypeDouble:
+			return "double precision"
+		case columnTypeChar:
+			return withArguments("char", "1")
+		case columnTypeVarchar:
+			return withArguments("varchar", "")
+		case columnTypeDate:
+			return "date"
+		case columnTypeTime:
+			return "time"
+		case columnTypeTimestamp:
+			return "timestamp"
+		case columnTypeBinary:
+			return "bytea"
+		case columnTypeJSON:
+			return "jsonb"
+		case columnTypeUUID:
+			return "uuid"
+		}
+		return "text"
+	case DriverMySQL:
+		switch genericType {
+		case columnTypeSmallInt:
+			return "smallint"
+		case columnTypeInteger:
+			return "int"
+		case columnTypeBigInt:
+			return "bigint"
+		case columnTypeBoolean:
+			return "tinyint(1)"
+		case columnTypeDecimal:
+			return withArguments("decimal", "65,30")
+		case columnTypeFloat:
+			return "float"
+		case columnTypeDouble:
+			return "double"
+		case columnTypeChar:
+			return withArguments("char", "1")
+		case columnTypeVarchar:
+			return withArguments("varchar", "255")
+		case columnTypeDate:
+			return "date"
+		case columnTypeTime:
+			return "time"
+		case columnTypeTimestamp:
+			return "datetime"
+		case columnTypeBinary:
+			return "longblob"
+		case columnTypeJSON:
+			return "json"
+		case columnTypeUUID:
+			return "char(36)"
+		}
+		return "longtext"
+	case DriverSqlite:
+		switch genericType {
+		case columnTypeSmallInt, columnTypeInteger, columnTypeBigInt, columnTypeBoolean:
+			return "INTEGER"
+		case columnTypeDecimal:
+			return "NUMERIC"
+		case columnTypeFloat, columnTypeDouble:
+			return "REAL"
+		case columnTypeBinary:
+			return "BLOB"
+		}
+		return "TEXT"
+	case DriverMSSQL:
+		switch genericType {
+		case columnTypeSmallInt:
+			return "smallint"
+		case columnTypeInteger:
+			return "int"
+		case columnTypeBigInt:
+			return "bigint"
+		case columnTypeBoolean:
+			return "bit"
+		case columnTypeDecimal:
+			return withArguments("decimal", "38,10")
+		case columnTypeFloat:
+			return "real"
+		case columnTypeDouble:
+			return "float"
+		case columnTypeChar:
+			return withArguments("nchar", "1")
+		case columnTypeVarchar:
+			return withArguments("nvarchar", "255")
+		case columnTypeDate:
+			return "date"
+		case columnTypeTime:
+			return "time"
+		case columnTypeTimestamp:
+			return "datetime2"
+		case columnTypeBinary:
+			return "varbinary(max)"
+		case columnTypeUUID:
+			return "uniqueidentifier"
+		}
+		return "nvarchar(max)"
+	case DriverClickhouse:
+		switch genericType {
+		case columnTypeSmallInt:
+			return "Int16"
+		case columnTypeInteger:
+			return "Int32"
+		case columnTypeBigInt:
+			return "Int64"
+		case columnTypeBoolean:
+			return "Bool"
+		case columnTypeDecimal:
+			return withArguments("Decimal", "38,10")
+		case columnTypeFloat:
+			return "Float32"
+		case columnTypeDouble:
+			return "Float64"
+		case columnTypeDate:
+			return "Date"
+		case columnTypeTimestamp:
+			return "DateTime"
+		case columnTypeUUID:
+			return "UUID"
+		}
+		return "String"
+	case DriverDuckDB:
+		switch genericType {
+		case columnTypeSmallInt:
+			return "SMALLINT"
+		case columnTypeInteger:
+			return "INTEGER"
+		case columnTypeBigInt:
+			return "BIGINT"
+		case columnTypeBoolean:
+			return "BOOLEAN"
+		case columnTypeDecimal:
+			return withArguments("DECIMAL", "38,10")
+		case columnTypeFloat:
+			return "FLOAT"
+		case columnTypeDouble:
+			return "DOUBLE"
+		case columnTypeChar, columnTypeVarchar:
+			return "VARCHAR"
+		case columnTypeDate:
+			return "DATE"
+		case columnTypeTime:
+			return "TIME"
+		case columnTypeTimestamp:
+			return "TIMESTAMP"
+		case columnTypeBinary:
+			return "BLOB"
+		case columnTypeJSON:
+			return "JSON"
+		case columnTypeUUID:
+			return "UUID"
+		}
+		return "VARCHAR"
+	case DriverOracle:
+		switch genericType {
+		case columnTypeSmallInt:
+			return "NUMBER(5)"
+		case columnTypeInteger:
+			return "NUMBER(10)"
+		case columnTypeBigInt:
+			return "NUMBER(19)"
+		case columnTypeBoolean:
+			return "NUMBER(1)"
+		case columnTypeDecimal:
+			return withArguments("NUMBER", "")
+		case columnTypeFloat:
+			return "BINARY_FLOAT"
+		case columnTypeDouble:
+			return "BINARY_DOUBLE"
+		case columnTypeChar:
+			return withArguments("CHAR", "1")
+		case columnTypeVarchar:
+			return withArguments("VARCHAR2", "255")
+		case columnTypeDate:
+			return "DATE"
+		case columnTypeTimestamp:
+			return "TIMESTAMP"
+		case columnTypeBinary:
+			return "BLOB"
+		case columnTypeUUID:
+			return "VARCHAR2(36)"
+		case columnTypeTime:
+			return "VARCHAR2(32)"
+		}
+		return "CLOB"
+	}
+
+	return columnType
+}
+
+// convertValue adapts a value read from the source so that the target
+// accepts it for a column of the given generic type.
+func convertValue(genericType, targetProvider, value string) string {
+	switch genericType {
+	case columnTypeBoolean:
+		if targetProvider == DriverPostgres || targetProvider == DriverClickhouse || targetProvider == DriverDuckDB {
+			return value
+		}
+
+		switch strings.ToLower(value) {
+		case "true", "t":
+			return "1"
+		case "false", "f":
+			return "0"
+		}
+	case columnTypeTimestamp:
+		if parsed, err := time.Parse(time.RFC3339Nano, value); err == nil {
+			return parsed.Format("2006-01-02 15:04:05.999999")
+		}
+	case columnTypeDate:
+		if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+			return parsed.Format(time.DateOnly)
+		}
+	}
+
+	return value
+}
+
+// CopyTableName returns the default name of a copied table on the target
+// provider, Postgres tables go to the public schema.
+func CopyTableName(table, targetProvider string) string {
+	name := tableNameWithoutSchema(table)
+
+	if targetProvider == DriverPostgres {
+		return "public." + name
+	}
+
+	return name
+}
+
+// BuildCopyTableStatement returns the CREATE TABLE statement of the target
+// table. Only the columns and the primary key are copied, defaults, indexes
+// and foreign keys are provider specific or depend on other tables.
+func BuildCopyTableStatement(schema models.TableSchema, primaryKey []string, sourceProvider string, target DataDiffTable) string {
+	targetProvider := Dialect(target.Driver.GetProvider())
+
+	table := models.TableSchema{Name: target.Table}
+
+	if (targetProvider == DriverMySQL || targetProvider == DriverClickhouse) && target.Database != "" {
+		table.Name = fmt.Sprintf("%s.%s", target.Database, target.Table)
+	}
+
+	for _, column := range schema.Columns {
+		columnType := MapColumnType(sourceProvider, targetProvider, column.Type)
+
+		isPrimaryKey := slices.Contains(primaryKey, column.Name)
+
+		if targetProvider == DriverClickhouse && sourceProvider != DriverClickhouse && column.Nullable && !isPrimaryKey {
+			columnType = fmt.Sprintf("Nullable(%s)", columnType)
+		}
+
+		table.Columns = append(table.Columns, models.ColumnSchema{
+			Name:     column.Name,
+			Type:     columnType,
+			Nullable: column.Nullable && !isPrimaryKey,
+		})
+	}
+
+	if len(primaryKey) > 0 {
+		table.Constraints = []models.ConstraintSchema{{
+			Name:    tableNameWithoutSchema(target.Table) + "_pkey",
+			Type:    "PRIMARY KEY",
+			Columns: primaryKey,
+		}}
+	}
+
+	return buildCreateTableStatement(table, target.Driver)
+}
+
+// CopyTable copies the rows of the source table into the target table,
+// creating it first if it does not exist. Rows are inserted in batches, each
+// one in its own transaction, so a cancelled copy keeps the batches that were
+// already inserted.
+func CopyTable(ctx context.Context, source, target DataDiffTable, onProgress func(copied, total int)) (int, error) {
+	sourceProvider := Dialect(source.Driver.GetProvider())
+	targetProvider := Dialect(target.Driver.GetProvider())
+
+	schema, err := GetTableSchema(source.Driver, source.Database, source.Table)
+	if err != nil {
+		return 0, err
+	}
+
+	primaryKey, err := source.Driver.GetPrimaryKeyColumnNames(source.Database, source.Table)
+	if err != nil {
+		return 0, err
+	}
+
+	tables, err := ListTables(target.Driver, target.Database)
+	if err != nil {
+		return 0, err
+	}
+
+	if !slices.Contains(tables, target.Table) {
+		statement := BuildCopyTableStatement(schema, primaryKey, sourceProvider, target)
+
+		if _, err := target.Driver.ExecuteDMLStatement(statement); err != nil {
+			return 0, fmt.Errorf("creating %s: %w", target.Table, err)
+		}
+	}
+
+	genericTypes := map[string]string{}
+	for _, column := range schema.Columns {
+		genericTypes[column.Name], _ = genericColumnType(column.Type)
+	}
+
+	stream := newRecordStream(ctx, source, primaryKey)
+	copied := 0
+
+	var batch []models.DBDMLChange
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := target.Driver.ExecutePendingChanges(batch); err != nil {
+			return err
+		}
+
+		copied += len(batch)
+		batch = batch[:0]
+
+		if onProgress != nil {
+			onProgress(copied, stream.total)
+		}
+
+		return nil
+	}
+
+	for {
+		row, err := stream.next()
+		if err != nil {
+			return copied, err
+		}
+
+		if row == nil {
+			break
+		}
+
+		change := models.DBDMLChange{Database: target.Database, Table: target.Table, Type: models.DMLInsertType}
+
+		for i, column := range stream.header {
+			value := row[i]
+			if sourceProvider != targetProvider {
+				value = convertValue(genericTypes[column], targetProvider, value)
+			}
+
+			change.Values = append(change.Values, recordToCellValue(column, value))
+		}
+
+		batch = append(batch, change)
+
+		if len(batch) == RecordBatchSize {
+			if err := flush(); err != nil {
+				return copied, err
+			}
+		}
+	}
+
+	if err := flush(); err != nil {
+		return copied, err
+	}
+
+	if onProgress != nil {
+		onProgress(copied, stream.total)
+	}
+
+	return copied, nil
+}
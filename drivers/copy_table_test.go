@@ -0,0 +1,189 @@
+package drivers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+func TestMapColumnType(t *testing.T) {
+	testCases := []struct {
+		source     string
+		target     string
+		columnType string
+		expected   string
+	}{
+		{DriverSqlite, DriverPostgres, "INTEGER", "integer"},
+		{DriverSqlite, DriverPostgres, "TEXT", "text"},
+		{DriverSqlite, DriverPostgres, "VARCHAR(20)", "varchar(20)"},
+		{DriverMySQL, DriverPostgres, "tinyint(1)", "boolean"},
+		{DriverMySQL, DriverPostgres, "int unsigned", "integer"},
+		{DriverMySQL, DriverPostgres, "decimal(10,2)", "numeric(10,2)"},
+		{DriverMySQL, DriverPostgres, "datetime", "timestamp"},
+		{DriverPostgres, DriverMySQL, "character varying", "varchar(255)"},
+		{DriverPostgres, DriverMySQL, "timestamp without time zone", "datetime"},
+		{DriverPostgres, DriverMySQL, "jsonb", "json"},
+		{DriverPostgres, DriverMySQL, "interval", "longtext"},
+		{DriverPostgres, DriverSqlite, "double precision", "REAL"},
+		{DriverPostgres, DriverSqlite, "bytea", "BLOB"},
+		{DriverPostgres, DriverMSSQL, "uuid", "uniqueidentifier"},
+		{DriverPostgres, DriverMSSQL, "text", "nvarchar(max)"},
+		{DriverClickhouse, DriverPostgres, "Nullable(String)", "text"},
+		{DriverClickhouse, DriverPostgres, "UInt8", "smallint"},
+		{DriverClickhouse, DriverPostgres, "LowCardinality(String)", "text"},
+		{DriverPostgres, DriverClickhouse, "bigint", "Int64"},
+		{DriverPostgres, DriverPostgres, "citext", "citext"},
+		{DriverOracle, DriverPostgres, "NUMBER", "numeric"},
+		{DriverOracle, DriverPostgres, "NUMBER(4)", "smallint"},
+		{DriverOracle, DriverPostgres, "NUMBER(10,0)", "bigint"},
+		{DriverOracle, DriverPostgres, "NUMBER(38)", "numeric(38)"},
+		{DriverOracle, DriverPostgres, "NUMBER(10,2)", "numeric(10,2)"},
+		{DriverOracle, DriverMySQL, "VARCHAR2(40 BYTE)", "varchar(40)"},
+		{DriverOracle, DriverMySQL, "NVARCHAR2", "varchar(255)"},
+		{DriverOracle, DriverPostgres, "CLOB", "text"},
+		{DriverOracle, DriverPostgres, "NCLOB", "text"},
+		{DriverOracle, DriverPostgres, "DATE", "timestamp"},
+		{DriverOracle, DriverMySQL, "TIMESTAMP(6) WITH TIME ZONE", "datetime"},
+		{DriverOracle, DriverPostgres, "RAW", "bytea"},
+		{DriverOracle, DriverSqlite, "BINARY_DOUBLE", "REAL"},
+		{DriverPostgres, DriverOracle, "smallint", "NUMBER(5)"},
+		{DriverPostgres, DriverOracle, "bigint", "NUMBER(19)"},
+		{DriverPostgres, DriverOracle, "boolean", "NUMBER(1)"},
+		{DriverMySQL, DriverOracle, "decimal(10,2)", "NUMBER(10,2)"},
+		{DriverPostgres, DriverOracle, "double precision", "BINARY_DOUBLE"},
+		{DriverPostgres, DriverOracle, "character varying(20)", "VARCHAR2(20)"},
+		{DriverPostgres, DriverOracle, "character varying", "VARCHAR2(255)"},
+		{DriverPostgres, DriverOracle, "text", "CLOB"},
+		{DriverPostgres, DriverOracle, "date", "DATE"},
+		{DriverMySQL, DriverOracle, "datetime", "TIMESTAMP"},
+		{DriverPostgres, DriverOracle, "bytea", "BLOB"},
+		{DriverPostgres, DriverOracle, "uuid", "VARCHAR2(36)"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s %s to %s", tc.columnType, tc.source, tc.target), func(t *testing.T) {
+			result := MapColumnType(tc.source, tc.target, tc.columnType)
+			if result != tc.expected {
+				t.Fatalf("expected %q, but got %q", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestBuildCopyTableStatement(t *testing.T) {
+	schema := models.TableSchema{
+		Name: "users",
+		Columns: []models.ColumnSchema{
+			{Name: "id", Type: "INTEGER", Nullable: true},
+			{Name: "name", Type: "TEXT", Nullable: true, Default: "'unknown'"},
+			{Name: "active", Type: "BOOLEAN"},
+		},
+	}
+
+	target := DataDiffTable{Driver: &Postgres{Provider: DriverPostgres}, Table: "public.users"}
+
+	expected := "CREATE TABLE \"public\".\"users\" (\n  \"id\" integer NOT NULL,\n  \"name\" text,\n  \"active\" boolean NOT NULL,\n  CONSTRAINT \"users_pkey\" PRIMARY KEY (\"id\")\n)"
+
+	result := BuildCopyTableStatement(schema, []string{"id"}, DriverSqlite, target)
+	if result != expected {
+		t.Fatalf("expected %q, but got %q", expected, result)
+	}
+}
+
+func TestCopyTable(t *testing.T) {
+	source := newDataDiffTestDB(t, "CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)")
+	target := newDataDiffTestDB(t)
+
+	rowCount := RecordBatchSize + 10
+
+	for i := 1; i <= rowCount; i++ {
+		name := any(fmt.Sprintf("user %d", i))
+		if i%100 == 0 {
+			name = nil
+		}
+
+		if _, err := source.Connection.Exec("INSERT INTO users VALUES (?, ?)", i, name); err != nil {
+			t.Fatalf("Error inserting row: %v", err)
+		}
+	}
+
+	var progress []int
+
+	copied, err := CopyTable(context.Background(), DataDiffTable{Driver: source, Table: "users"}, DataDiffTable{Driver: target, Table: "users"}, func(copied, _ int) {
+		progress = append(progress, copied)
+	})
+	if err != nil {
+		t.Fatalf("CopyTable failed: %v", err)
+	}
+
+	if copied != rowCount {
+		t.Fatalf("expected %d copied rows, but got %d", rowCount, copied)
+	}
+
+	if len(progress) < 2 || progress[0] != RecordBatchSize {
+		t.Fatalf("expected progress after every batch, but got %v", progress)
+	}
+
+	diff, err := DiffTableData(context.Background(), DataDiffTable{Driver: source, Table: "users"}, DataDiffTable{Driver: target, Table: "users"}, nil)
+	if err != nil {
+		t.Fatalf("DiffTableData failed: %v", err)
+	}
+
+	if len(diff.Rows) != 0 {
+		t.Fatalf("expected the tables to be equal, but got %d differences", len(diff.Rows))
+	}
+}
+
+func TestCopyTable_Cancelled(t *testing.T) {
+	source := newDataDiffTestDB(t,
+		"CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)",
+		"INSERT INTO users VALUES (1, 'Alice')",
+	)
+	target := newDataDiffTestDB(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := CopyTable(ctx, DataDiffTable{Driver: source, Table: "users"}, DataDiffTable{Driver: target, Table: "users"}, nil)
+	if err == nil {
+		t.Fatal("expected an error but got nil")
+	}
+}
+
+func TestCopyTable_UUIDAndDateKeys(t *testing.T) {
+	source := newDataDiffTestDB(t, "CREATE TABLE visits (id UUID, day DATE, pages INTEGER, PRIMARY KEY (id, day))")
+	target := newDataDiffTestDB(t)
+
+	rowCount := RecordBatchSize + 10
+
+	// The batches start after the last key read, which is a uuid and a date.
+	for i := 1; i <= rowCount; i++ {
+		id := fmt.Sprintf("%08x-0000-4000-8000-000000000000", i%3)
+		day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, i).Format(time.DateOnly)
+
+		if _, err := source.Connection.Exec("INSERT INTO visits VALUES (?, ?, ?)", id, day, i); err != nil {
+			t.Fatalf("Error inserting row: %v", err)
+		}
+	}
+
+	copied, err := CopyTable(context.Background(), DataDiffTable{Driver: source, Table: "visits"}, DataDiffTable{Driver: target, Table: "visits"}, nil)
+	if err != nil {
+		t.Fatalf("CopyTable failed: %v", err)
+	}
+
+	if copied != rowCount {
+		t.Fatalf("expected %d copied rows, but got %d", rowCount, copied)
+	}
+
+	diff, err := DiffTableData(context.Background(), DataDiffTable{Driver: source, Table: "visits"}, DataDiffTable{Driver: target, Table: "visits"}, nil)
+	if err != nil {
+		t.Fatalf("DiffTableData failed: %v", err)
+	}
+
+	if len(diff.Rows) != 0 {
+		t.Fatalf("expected the tables to be equal, but got %d differences", len(diff.Rows))
+	}
+}
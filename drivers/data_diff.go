@@ -0,0 +1,606 @@
+package drivers
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// RecordBatchSize is the number of rows read or written at once when a
+// whole table is processed.
+const RecordBatchSize = 500
+
+// DataDiffTable is one side of a data diff.
+type DataDiffTable struct {
+	Driver   Driver
+	Database string
+	Table    string
+}
+
+// DiffTableData compares the rows of two tables that share the same primary
+// key columns. Both tables are read in batches ordered by the primary key and
+// merged, so only the differing rows are kept in memory.
+//
+// Numeric keys are ordered by value, dates and times by time, text keys byte
+// by byte and the other keys like the database orders their type on both
+// sides, the diff stops if a database returns its keys in another order.
+func DiffTableData(ctx context.Context, source, target DataDiffTable, onProgress func(compared int)) (models.DataDiff, error) {
+	diff := models.DataDiff{}
+
+	sourceKeys, err := source.Driver.GetPrimaryKeyColumnNames(source.Database, source.Table)
+	if err != nil {
+		return diff, err
+	}
+
+	targetKeys, err := target.Driver.GetPrimaryKeyColumnNames(target.Database, target.Table)
+	if err != nil {
+		return diff, err
+	}
+
+	if len(sourceKeys) == 0 {
+		return diff, fmt.Errorf("table %s has no primary key", source.Table)
+	}
+
+	if !sameColumns(sourceKeys, targetKeys) {
+		return diff, fmt.Errorf("primary keys do not match: (%s) and (%s)", strings.Join(sourceKeys, ", "), strings.Join(targetKeys, ", "))
+	}
+
+	diff.PrimaryKeyColumns = sourceKeys
+
+	sourceStream := newRecordStream(ctx, source, sourceKeys)
+	targetStream := newRecordStream(ctx, target, sourceKeys)
+
+	sourceRow, err := sourceStream.next()
+	if err != nil {
+		return diff, err
+	}
+
+	targetRow, err := targetStream.next()
+	if err != nil {
+		return diff, err
+	}
+
+	if !slices.Equal(sourceStream.orders, targetStream.orders) {
+		return diff, fmt.Errorf("primary keys (%s) have different types", strings.Join(sourceKeys, ", "))
+	}
+
+	// Only the columns present on both sides can be compared.
+	var sourceIndexes, targetIndexes []int
+	for i, column := range sourceStream.header {
+		if j := slices.Index(targetStream.header, column); j != -1 {
+			diff.Columns = append(diff.Columns, column)
+			sourceIndexes = append(sourceIndexes, i)
+			targetIndexes = append(targetIndexes, j)
+		}
+	}
+
+	keyIndexes := make([]int, len(sourceKeys))
+	for i, key := range sourceKeys {
+		keyIndexes[i] = slices.Index(diff.Columns, key)
+		if keyIndexes[i] == -1 {
+			return diff, fmt.Errorf("primary key column %s is missing", key)
+		}
+	}
+
+	project := func(row []string, indexes []int) []string {
+		projected := make([]string, len(indexes))
+		for i, index := range indexes {
+			projected[i] = row[index]
+		}
+		return projected
+	}
+
+	primaryKeyInfo := func(row []string) []models.PrimaryKeyInfo {
+		info := make([]models.PrimaryKeyInfo, len(keyIndexes))
+		for i, index := range keyIndexes {
+			info[i] = models.PrimaryKeyInfo{Name: diff.Columns[index], Value: row[index]}
+		}
+		return info
+	}
+
+	compared := 0
+
+	for sourceRow != nil || targetRow != nil {
+		var sourceValues, targetValues []string
+
+		if sourceRow != nil {
+			sourceValues = project(sourceRow, sourceIndexes)
+		}
+
+		if targetRow != nil {
+			targetValues = project(targetRow, targetIndexes)
+		}
+
+		order := 0
+		switch {
+		case targetRow == nil:
+			order = -1
+		case sourceRow == nil:
+			order = 1
+		default:
+			order = compareKeys(sourceValues, targetValues, keyIndexes, sourceStream.orders)
+		}
+
+		switch {
+		case order < 0:
+			diff.Rows = append(diff.Rows, models.DataRowDiff{PrimaryKeyInfo: primaryKeyInfo(sourceValues), Source: sourceValues, Status: models.SchemaDiffAdded})
+			diff.SourceRowCount++
+		case order > 0:
+			diff.Rows = append(diff.Rows, models.DataRowDiff{PrimaryKeyInfo: primaryKeyInfo(targetValues), Target: targetValues, Status: models.SchemaDiffRemoved})
+			diff.TargetRowCount++
+		default:
+			var changed []int
+			for i := range sourceValues {
+				if sourceValues[i] != targetValues[i] {
+					changed = append(changed, i)
+				}
+			}
+
+			if len(changed) > 0 {
+				diff.Rows = append(diff.Rows, models.DataRowDiff{PrimaryKeyInfo: primaryKeyInfo(targetValues), Source: sourceValues, Target: targetValues, ChangedColumns: changed, Status: models.SchemaDiffChanged})
+			}
+
+			diff.SourceRowCount++
+			diff.TargetRowCount++
+		}
+
+		if order <= 0 {
+			if sourceRow, err = sourceStream.next(); err != nil {
+				return diff, err
+			}
+		}
+
+		if order >= 0 {
+			if targetRow, err = targetStream.next(); err != nil {
+				return diff, err
+			}
+		}
+
+		compared++
+		if onProgress != nil && compared%RecordBatchSize == 0 {
+			onProgress(compared)
+		}
+	}
+
+	if onProgress != nil {
+		onProgress(compared)
+	}
+
+	return diff, nil
+}
+
+// DataDiffChanges returns the changes that make the target table match the
+// source table.
+func DataDiffChanges(diff models.DataDiff, database, table string) []models.DBDMLChange {
+	changes := make([]models.DBDMLChange, 0, len(diff.Rows))
+
+	for _, row := range diff.Rows {
+		change := models.DBDMLChange{
+			Database:       database,
+			Table:          table,
+			PrimaryKeyInfo: row.PrimaryKeyInfo,
+		}
+
+		switch row.Status {
+		case models.SchemaDiffAdded:
+			change.Type = models.DMLInsertType
+			for i, column := range diff.Columns {
+				change.Values = append(change.Values, recordToCellValue(column, row.Source[i]))
+			}
+		case models.SchemaDiffRemoved:
+			change.Type = models.DMLDeleteType
+		case models.SchemaDiffChanged:
+			change.Type = models.DMLUpdateType
+			for _, i := range row.ChangedColumns {
+				change.Values = append(change.Values, recordToCellValue(diff.Columns[i], row.Source[i]))
+			}
+		default:
+			continue
+		}
+
+		changes = append(changes, change)
+	}
+
+	return changes
+}
+
+// recordToCellValue converts a value as returned by GetRecords, where NULL
+// and empty strings are encoded, to a cell value.
+func recordToCellValue(column, value string) models.CellValue {
+	switch value {
+	case "NULL&":
+		return models.CellValue{Column: column, Type: models.Null}
+	case "EMPTY&":
+		return models.CellValue{Column: column, Value: "", Type: models.Empty}
+	}
+
+	return models.CellValue{Column: column, Value: value, Type: models.String}
+}
+
+// recordStream reads the rows of a table in batches. When the table has a
+// primary key the rows are ordered by it and every batch starts after the last
+// key read, so rows inserted or deleted meanwhile do not shift the batches.
+type recordStream struct {
+	ctx   context.Context
+	table DataDiffTable
+	keys  []string
+	// orders tells for each key how it is ordered and compared.
+	orders     []keyOrder
+	keyIndexes []int
+	sort       string
+	// rowID is the index of the pseudocolumn GetRecords adds to identify the
+	// rows of a table without primary key, it is left out of the rows.
+	rowID  int
+	header []string
+	rows   [][]string
+	// last is the last row returned, the next batch starts after its key.
+	last   []string
+	offset int
+	total  int
+	read   bool
+	done   bool
+}
+
+func newRecordStream(ctx context.Context, table DataDiffTable, keys []string) *recordStream {
+	return &recordStream{ctx: ctx, table: table, keys: keys}
+}
+
+// next returns the next row, or nil once the table has been read.
+func (stream *recordStream) next() ([]string, error) {
+	if len(stream.rows) == 0 && !stream.done {
+		if err := stream.ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if !stream.read && len(stream.keys) > 0 {
+			if err := stream.readKeyTypes(); err != nil {
+				return nil, err
+			}
+		}
+
+		where := ""
+		offset := stream.offset
+
+		if len(stream.keys) > 0 {
+			offset = 0
+
+			if stream.last != nil {
+				where = "WHERE " + stream.after(stream.last)
+			}
+		}
+
+		records, total, err := stream.table.Driver.GetRecords(stream.table.Database, stream.table.Table, where, stream.sort, offset, RecordBatchSize)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(records) == 0 {
+			return nil, errors.New("no columns returned for " + stream.table.Table)
+		}
+
+		if !stream.read {
+			stream.rowID, err = rowIDIndex(stream.table, records[0])
+			if err != nil {
+				return nil, err
+			}
+
+			stream.header = withoutColumn(records[0], stream.rowID)
+			stream.total = total
+
+			stream.keyIndexes = make([]int, len(stream.keys))
+			for i, key := range stream.keys {
+				stream.keyIndexes[i] = slices.Index(stream.header, key)
+				if stream.keyIndexes[i] == -1 {
+					return nil, fmt.Errorf("primary key column %s is missing", key)
+				}
+			}
+		}
+
+		stream.read = true
+		stream.rows = records[1:]
+
+		for i, row := range stream.rows {
+			stream.rows[i] = withoutColumn(row, stream.rowID)
+		}
+		stream.offset += len(stream.rows)
+		stream.done = len(stream.rows) < RecordBatchSize
+	}
+
+	if len(stream.rows) == 0 {
+		return nil, nil
+	}
+
+	row := stream.rows[0]
+	stream.rows = stream.rows[1:]
+
+	// The rows are merged and paged assuming the database orders the keys
+	// like compareKeys does.
+	if stream.last != nil && len(stream.keys) > 0 && compareKeys(stream.last, row, stream.keyIndexes, stream.orders) >= 0 {
+		return nil, fmt.Errorf("rows of %s are not ordered by their primary key (%s) as expected", stream.table.Table, strings.Join(stream.keys, ", "))
+	}
+
+	stream.last = row
+
+	return row, nil
+}
+
+// rowIDIndex returns the index of the pseudocolumn identifying the rows of the
+// table in the header returned by GetRecords, or -1 when there is none.
+func rowIDIndex(table DataDiffTable, header []string) (int, error) {
+	identifier, ok := Unwrap(table.Driver).(RowIdentifier)
+	if !ok {
+		return -1, nil
+	}
+
+	rowID, err := identifier.RowIDColumn(table.Database, table.Table)
+	if err != nil || rowID == "" {
+		return -1, err
+	}
+
+	// GetRecords selects the pseudocolumn after the columns of the table.
+	return slices.Index(header, rowID), nil
+}
+
+// withoutColumn returns the row without the value at the index, or the row
+// itself when the index is -1.
+func withoutColumn(row []string, i int) []string {
+	if i == -1 {
+		return row
+	}
+
+	return slices.Delete(row, i, i+1)
+}
+
+// readKeyTypes reads how the keys are ordered and orders the rows by the keys.
+func (stream *recordStream) readKeyTypes() error {
+	columns, err := stream.table.Driver.GetTableColumns(stream.table.Database, stream.table.Table)
+	if err != nil {
+		return err
+	}
+
+	types := map[string]string{}
+	for _, column := range normalizeColumns(columns) {
+		types[column.Name] = column.Type
+	}
+
+	sort := make([]string, len(stream.keys))
+	stream.orders = make([]keyOrder, len(stream.keys))
+
+	for i, key := range stream.keys {
+		stream.orders[i] = keyOrderOf(stream.table.Driver.GetProvider(), types[key])
+		sort[i] = stream.keyExpression(key, i) + " ASC"
+	}
+
+	stream.sort = strings.Join(sort, ", ")
+
+	return nil
+}
+
+// keyExpression returns the expression of the key ordered like compareKeys
+// does, text is ordered byte by byte whatever the collation of the column.
+func (stream *recordStream) keyExpression(key string, i int) string {
+	expression := stream.table.Driver.FormatReference(key)
+
+	if stream.orders[i] == keyOrderText {
+		return stream.binary(expression)
+	}
+
+	return expression
+}
+
+// keyLiteral returns the value of the key as a literal compared with the key
+// expression.
+func (stream *recordStream) keyLiteral(value string, i int) string {
+	switch stream.orders[i] {
+	case keyOrderNumeric:
+		if _, ok := new(big.Rat).SetString(value); ok {
+			return value
+		}
+	case keyOrderGUID:
+		// The GUIDs are read in the order of their bytes in SQL Server.
+		if guid, err := uuid.Parse(value); err == nil {
+			return fmt.Sprintf("CONVERT(uniqueidentifier, 0x%X)", guid[:])
+		}
+	}
+
+	if value == "EMPTY&" {
+		value = ""
+	}
+
+	literal := stream.table.Driver.FormatArg(value)
+
+	if stream.orders[i] == keyOrderText {
+		return stream.binary(literal)
+	}
+
+	return literal
+}
+
+// binary applies the binary collation of the dialect to a text expression.
+func (stream *recordStream) binary(expression string) string {
+	switch Dialect(stream.table.Driver.GetProvider()) {
+	case DriverPostgres:
+		return expression + ` COLLATE "C"`
+	case DriverMySQL:
+		return fmt.Sprintf("CAST(%s AS BINARY)", expression)
+	case DriverMSSQL:
+		return expression + " COLLATE Latin1_General_BIN2"
+	case DriverOracle:
+		return fmt.Sprintf("NLSSORT(%s, 'NLS_SORT=BINARY')", expression)
+	}
+
+	// SQLite, DuckDB and ClickHouse order text byte by byte.
+	return expression
+}
+
+// after returns the condition of the rows whose key comes after the key of
+// the row, like (a > 1) OR (a = 1 AND b > 2).
+func (stream *recordStream) after(row []string) string {
+	conditions := make([]string, len(stream.keys))
+
+	for i := range stream.keys {
+		var parts []string
+
+		for j := 0; j <= i; j++ {
+			operator := "="
+			if j == i {
+				operator = ">"
+			}
+
+			parts = append(parts, fmt.Sprintf("%s %s %s", stream.keyExpression(stream.keys[j], j), operator, stream.keyLiteral(row[stream.keyIndexes[j]], j)))
+		}
+
+		conditions[i] = "(" + strings.Join(parts, " AND ") + ")"
+	}
+
+	return strings.Join(conditions, " OR ")
+}
+
+// keyOrder is how the values of a key are ordered and compared.
+type keyOrder int
+
+const (
+	// keyOrderNative keys are ordered by the database like their text,
+	// like UUIDs and binary strings written in hexadecimal.
+	keyOrderNative keyOrder = iota
+	// keyOrderText keys are ordered byte by byte with a binary collation.
+	keyOrderText
+	keyOrderNumeric
+	// keyOrderTime keys are dates, times and timestamps, ordered by time.
+	keyOrderTime
+	// keyOrderGUID keys are SQL Server uniqueidentifiers, which are not
+	// ordered like their text.
+	keyOrderGUID
+)
+
+// guidByteOrder is the order SQL Server compares the bytes of a
+// uniqueidentifier in.
+var guidByteOrder = [16]int{10, 11, 12, 13, 14, 15, 8, 9, 6, 7, 4, 5, 0, 1, 2, 3}
+
+// timeLayouts are the layouts the drivers return dates and times in.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999 -0700 MST",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	time.DateOnly,
+	"15:04:05.999999999Z07:00",
+	"15:04:05.999999999",
+}
+
+// keyOrderOf returns how the values of a key of the column type are ordered.
+// Only the text types get a binary collation, the databases reject it on
+// types like uuid or timestamp.
+func keyOrderOf(provider, columnType string) keyOrder {
+	genericType, _ := genericColumnType(columnType)
+	lower := strings.ToLower(columnType)
+
+	switch genericType {
+	case columnTypeSmallInt, columnTypeInteger, columnTypeBigInt, columnTypeDecimal, columnTypeFloat, columnTypeDouble:
+		return keyOrderNumeric
+	case columnTypeDate, columnTypeTime, columnTypeTimestamp:
+		return keyOrderTime
+	case columnTypeUUID:
+		if Dialect(provider) == DriverMSSQL {
+			return keyOrderGUID
+		}
+	case columnTypeChar, columnTypeVarchar, columnTypeText:
+		// The types genericColumnType does not know are text too.
+		for _, text := range []string{"char", "text", "string", "clob", "enum", "set"} {
+			if strings.Contains(lower, text) {
+				return keyOrderText
+			}
+		}
+	}
+
+	return keyOrderNative
+}
+
+func compareKeys(source, target []string, keyIndexes []int, orders []keyOrder) int {
+	for i, index := range keyIndexes {
+		if result := compareValues(source[index], target[index], orders[i]); result != 0 {
+			return result
+		}
+	}
+
+	return 0
+}
+
+// compareValues compares two keys like the database orders them: numbers by
+// value, times by time, GUIDs like SQL Server and the others byte by byte.
+func compareValues(a, b string, order keyOrder) int {
+	switch order {
+	case keyOrderNumeric:
+		aNumber, aOK := new(big.Rat).SetString(a)
+		bNumber, bOK := new(big.Rat).SetString(b)
+
+		if aOK && bOK {
+			return aNumber.Cmp(bNumber)
+		}
+	case keyOrderTime:
+		aTime, aOK := parseTime(a)
+		bTime, bOK := parseTime(b)
+
+		if aOK && bOK {
+			return aTime.Compare(bTime)
+		}
+	case keyOrderGUID:
+		aGUID, aErr := uuid.Parse(a)
+		bGUID, bErr := uuid.Parse(b)
+
+		if aErr == nil && bErr == nil {
+			for _, i := range guidByteOrder {
+				if result := cmp.Compare(aGUID[i], bGUID[i]); result != 0 {
+					return result
+				}
+			}
+
+			return 0
+		}
+	}
+
+	if a == "EMPTY&" {
+		a = ""
+	}
+
+	if b == "EMPTY&" {
+		b = ""
+	}
+
+	return strings.Compare(a, b)
+}
+
+// parseTime parses a date or a time returned by a driver.
+func parseTime(value string) (time.Time, bool) {
+	for _, layout := range timeLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+func sameColumns(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for _, column := range a {
+		if !slices.Contains(b, column) {
+			return false
+		}
+	}
+
+	return true
+}
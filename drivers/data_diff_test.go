@@ -0,0 +1,322 @@
+package drivers
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+func newDataDiffTestDB(t *testing.T, statements ...string) *SQLite {
+	t.Helper()
+
+	connection, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("Error opening database: %v", err)
+	}
+	// Every connection to :memory: gets its own database.
+	connection.SetMaxOpenConns(1)
+
+	t.Cleanup(func() { connection.Close() })
+
+	for _, statement := range statements {
+		if _, err := connection.Exec(statement); err != nil {
+			t.Fatalf("Error executing %q: %v", statement, err)
+		}
+	}
+
+	return &SQLite{Connection: connection, Provider: DriverSqlite}
+}
+
+func TestDiffTableData(t *testing.T) {
+	source := newDataDiffTestDB(t,
+		"CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT, email TEXT)",
+		"INSERT INTO users VALUES (1, 'Alice', 'alice@example.com'), (2, 'Bob', NULL), (10, 'Carol', '')",
+	)
+
+	target := newDataDiffTestDB(t,
+		"CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT, email TEXT)",
+		"INSERT INTO users VALUES (1, 'Alice', 'alice@example.com'), (2, 'Robert', 'bob@example.com'), (3, 'Dave', NULL)",
+	)
+
+	sourceTable := DataDiffTable{Driver: source, Table: "users"}
+	targetTable := DataDiffTable{Driver: target, Table: "users"}
+
+	diff, err := DiffTableData(context.Background(), sourceTable, targetTable, nil)
+	if err != nil {
+		t.Fatalf("DiffTableData failed: %v", err)
+	}
+
+	expected := []models.DataRowDiff{
+		{
+			PrimaryKeyInfo: []models.PrimaryKeyInfo{{Name: "id", Value: "2"}},
+			Source:         []string{"2", "Bob", "NULL&"},
+			Target:         []string{"2", "Robert", "bob@example.com"},
+			ChangedColumns: []int{1, 2},
+			Status:         models.SchemaDiffChanged,
+		},
+		{
+			PrimaryKeyInfo: []models.PrimaryKeyInfo{{Name: "id", Value: "3"}},
+			Target:         []string{"3", "Dave", "NULL&"},
+			Status:         models.SchemaDiffRemoved,
+		},
+		{
+			PrimaryKeyInfo: []models.PrimaryKeyInfo{{Name: "id", Value: "10"}},
+			Source:         []string{"10", "Carol", "EMPTY&"},
+			Status:         models.SchemaDiffAdded,
+		},
+	}
+
+	if !reflect.DeepEqual(diff.Rows, expected) {
+		t.Fatalf("expected %v, but got %v", expected, diff.Rows)
+	}
+
+	if diff.SourceRowCount != 3 || diff.TargetRowCount != 3 {
+		t.Fatalf("expected 3 rows on each side, but got %d and %d", diff.SourceRowCount, diff.TargetRowCount)
+	}
+
+	err = target.ExecutePendingChanges(DataDiffChanges(diff, "", "users"))
+	if err != nil {
+		t.Fatalf("ExecutePendingChanges failed: %v", err)
+	}
+
+	diff, err = DiffTableData(context.Background(), sourceTable, targetTable, nil)
+	if err != nil {
+		t.Fatalf("DiffTableData failed: %v", err)
+	}
+
+	if len(diff.Rows) != 0 {
+		t.Fatalf("expected the tables to be in sync, but got %v", diff.Rows)
+	}
+}
+
+func TestDiffTableData_PrimaryKeyMismatch(t *testing.T) {
+	source := newDataDiffTestDB(t, "CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)")
+	target := newDataDiffTestDB(t, "CREATE TABLE users (id INTEGER, name TEXT PRIMARY KEY)")
+
+	_, err := DiffTableData(context.Background(), DataDiffTable{Driver: source, Table: "users"}, DataDiffTable{Driver: target, Table: "users"}, nil)
+	if err == nil {
+		t.Fatal("expected an error but got nil")
+	}
+}
+
+func TestDiffTableData_Cancelled(t *testing.T) {
+	db := newDataDiffTestDB(t, "CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := DiffTableData(ctx, DataDiffTable{Driver: db, Table: "users"}, DataDiffTable{Driver: db, Table: "users"}, nil)
+	if err == nil {
+		t.Fatal("expected an error but got nil")
+	}
+}
+
+func TestDiffTableData_TextKeys(t *testing.T) {
+	statements := []string{
+		"CREATE TABLE codes (code TEXT PRIMARY KEY, name TEXT)",
+		"INSERT INTO codes VALUES ('10', 'ten'), ('9', 'nine'), ('B', 'upper'), ('a', 'lower')",
+	}
+
+	source := newDataDiffTestDB(t, statements...)
+	target := newDataDiffTestDB(t, statements...)
+
+	diff, err := DiffTableData(context.Background(), DataDiffTable{Driver: source, Table: "codes"}, DataDiffTable{Driver: target, Table: "codes"}, nil)
+	if err != nil {
+		t.Fatalf("DiffTableData failed: %v", err)
+	}
+
+	if len(diff.Rows) != 0 {
+		t.Fatalf("expected the tables to be in sync, but got %v", diff.Rows)
+	}
+}
+
+func TestDiffTableData_Batches(t *testing.T) {
+	statements := []string{
+		"CREATE TABLE items (a INTEGER, b TEXT, value TEXT, PRIMARY KEY (a, b))",
+		`WITH RECURSIVE n(i) AS (SELECT 1 UNION ALL SELECT i + 1 FROM n WHERE i < 1200)
+		 INSERT INTO items SELECT i % 7, 'key' || i, 'value' FROM n`,
+	}
+
+	source := newDataDiffTestDB(t, statements...)
+	target := newDataDiffTestDB(t, append(statements, "UPDATE items SET value = 'changed' WHERE b = 'key1000'")...)
+
+	diff, err := DiffTableData(context.Background(), DataDiffTable{Driver: source, Table: "items"}, DataDiffTable{Driver: target, Table: "items"}, nil)
+	if err != nil {
+		t.Fatalf("DiffTableData failed: %v", err)
+	}
+
+	if diff.SourceRowCount != 1200 || diff.TargetRowCount != 1200 {
+		t.Fatalf("expected 1200 rows on each side, but got %d and %d", diff.SourceRowCount, diff.TargetRowCount)
+	}
+
+	if len(diff.Rows) != 1 || diff.Rows[0].Status != models.SchemaDiffChanged || diff.Rows[0].Source[1] != "key1000" {
+		t.Fatalf("expected key1000 to be changed, but got %v", diff.Rows)
+	}
+}
+
+func TestDiffTableData_OutOfOrder(t *testing.T) {
+	// SQLite orders the numbers before the text of a NUMERIC column, which
+	// does not match the order of the keys compared as numbers.
+	statements := []string{
+		"CREATE TABLE items (id NUMERIC PRIMARY KEY)",
+		"INSERT INTO items VALUES (2), (10), ('0a')",
+	}
+
+	source := newDataDiffTestDB(t, statements...)
+	target := newDataDiffTestDB(t, statements...)
+
+	_, err := DiffTableData(context.Background(), DataDiffTable{Driver: source, Table: "items"}, DataDiffTable{Driver: target, Table: "items"}, nil)
+	if err == nil {
+		t.Fatal("expected an error but got nil")
+	}
+}
+
+func TestDiffTableData_UUIDAndTimeKeys(t *testing.T) {
+	// The keys are ordered natively, the fractional seconds of the times sort
+	// after the whole seconds like the database orders them.
+	statements := []string{
+		"CREATE TABLE events (id UUID, at TIMESTAMP, name TEXT, PRIMARY KEY (id, at))",
+		`INSERT INTO events VALUES
+			('0d8f7a6c-1b2c-4d3e-8f90-a1b2c3d4e5f6', '2024-01-02 10:00:00', 'first'),
+			('0d8f7a6c-1b2c-4d3e-8f90-a1b2c3d4e5f6', '2024-01-02 10:00:00.5', 'second'),
+			('f1e2d3c4-b5a6-4978-8695-a4b3c2d1e0f9', '2023-12-31', 'third')`,
+	}
+
+	source := newDataDiffTestDB(t, statements...)
+	target := newDataDiffTestDB(t, append(statements, "UPDATE events SET name = 'changed' WHERE at = '2024-01-02 10:00:00.5'")...)
+
+	diff, err := DiffTableData(context.Background(), DataDiffTable{Driver: source, Table: "events"}, DataDiffTable{Driver: target, Table: "events"}, nil)
+	if err != nil {
+		t.Fatalf("DiffTableData failed: %v", err)
+	}
+
+	if len(diff.Rows) != 1 || diff.Rows[0].Status != models.SchemaDiffChanged || diff.Rows[0].Source[2] != "second" {
+		t.Fatalf("expected the second event to be changed, but got %v", diff.Rows)
+	}
+}
+
+// keyTypesDriver is a driver of a dialect whose table has the columns, the
+// other methods are not used.
+type keyTypesDriver struct {
+	Driver
+	columns [][]string
+}
+
+func (db *keyTypesDriver) GetTableColumns(_, _ string) ([][]string, error) {
+	return append([][]string{{"column_name", "data_type"}}, db.columns...), nil
+}
+
+func TestRecordStream_KeyExpressions(t *testing.T) {
+	columns := [][]string{{"id", "uuid"}, {"at", "timestamp with time zone"}, {"code", "character varying"}, {"n", "integer"}}
+	row := []string{"0d8f7a6c-1b2c-4d3e-8f90-a1b2c3d4e5f6", "2024-01-02T10:00:00.5Z", "a'b", "7"}
+
+	testCases := []struct {
+		name          string
+		driver        Driver
+		expectedSort  string
+		expectedAfter string
+	}{
+		{
+			name:         "postgres",
+			driver:       &Postgres{Provider: DriverPostgres},
+			expectedSort: `"id" ASC, "at" ASC, "code" COLLATE "C" ASC, "n" ASC`,
+			expectedAfter: `("id" > '0d8f7a6c-1b2c-4d3e-8f90-a1b2c3d4e5f6') OR ` +
+				`("id" = '0d8f7a6c-1b2c-4d3e-8f90-a1b2c3d4e5f6' AND "at" > '2024-01-02T10:00:00.5Z') OR ` +
+				`("id" = '0d8f7a6c-1b2c-4d3e-8f90-a1b2c3d4e5f6' AND "at" = '2024-01-02T10:00:00.5Z' AND "code" COLLATE "C" > 'a''b' COLLATE "C") OR ` +
+				`("id" = '0d8f7a6c-1b2c-4d3e-8f90-a1b2c3d4e5f6' AND "at" = '2024-01-02T10:00:00.5Z' AND "code" COLLATE "C" = 'a''b' COLLATE "C" AND "n" > 7)`,
+		},
+		{
+			name:         "mssql",
+			driver:       &MSSQL{Provider: DriverMSSQL},
+			expectedSort: "[id] ASC, [at] ASC, [code] COLLATE Latin1_General_BIN2 ASC, [n] ASC",
+			expectedAfter: "([id] > CONVERT(uniqueidentifier, 0x0D8F7A6C1B2C4D3E8F90A1B2C3D4E5F6)) OR " +
+				"([id] = CONVERT(uniqueidentifier, 0x0D8F7A6C1B2C4D3E8F90A1B2C3D4E5F6) AND [at] > '2024-01-02T10:00:00.5Z') OR " +
+				"([id] = CONVERT(uniqueidentifier, 0x0D8F7A6C1B2C4D3E8F90A1B2C3D4E5F6) AND [at] = '2024-01-02T10:00:00.5Z' AND [code] COLLATE Latin1_General_BIN2 > 'a''b' COLLATE Latin1_General_BIN2) OR " +
+				"([id] = CONVERT(uniqueidentifier, 0x0D8F7A6C1B2C4D3E8F90A1B2C3D4E5F6) AND [at] = '2024-01-02T10:00:00.5Z' AND [code] COLLATE Latin1_General_BIN2 = 'a''b' COLLATE Latin1_General_BIN2 AND [n] > 7)",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			table := DataDiffTable{Driver: &keyTypesDriver{Driver: tc.driver, columns: columns}, Table: "events"}
+
+			stream := newRecordStream(context.Background(), table, []string{"id", "at", "code", "n"})
+			stream.keyIndexes = []int{0, 1, 2, 3}
+
+			if err := stream.readKeyTypes(); err != nil {
+				t.Fatal(err)
+			}
+
+			if stream.sort != tc.expectedSort {
+				t.Fatalf("expected %q, but got %q", tc.expectedSort, stream.sort)
+			}
+
+			if after := stream.after(row); after != tc.expectedAfter {
+				t.Fatalf("expected %q, but got %q", tc.expectedAfter, after)
+			}
+		})
+	}
+}
+
+func TestKeyOrderOf(t *testing.T) {
+	testCases := []struct {
+		provider   string
+		columnType string
+		expected   keyOrder
+	}{
+		{provider: DriverPostgres, columnType: "integer", expected: keyOrderNumeric},
+		{provider: DriverMySQL, columnType: "decimal(10,2)", expected: keyOrderNumeric},
+		{provider: DriverPostgres, columnType: "character varying", expected: keyOrderText},
+		{provider: DriverMySQL, columnType: "varchar(20)", expected: keyOrderText},
+		{provider: DriverMySQL, columnType: "enum('a','b')", expected: keyOrderText},
+		{provider: DriverPostgres, columnType: "uuid", expected: keyOrderNative},
+		{provider: DriverMSSQL, columnType: "uniqueidentifier", expected: keyOrderGUID},
+		{provider: DriverPostgres, columnType: "date", expected: keyOrderTime},
+		{provider: DriverPostgres, columnType: "timestamp without time zone", expected: keyOrderTime},
+		{provider: DriverMSSQL, columnType: "datetime2", expected: keyOrderTime},
+		{provider: DriverPostgres, columnType: "bytea", expected: keyOrderNative},
+		{provider: DriverOracle, columnType: "NUMBER", expected: keyOrderNumeric},
+		{provider: DriverOracle, columnType: "VARCHAR2", expected: keyOrderText},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.provider+" "+tc.columnType, func(t *testing.T) {
+			if result := keyOrderOf(tc.provider, tc.columnType); result != tc.expected {
+				t.Fatalf("expected %v, but got %v", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestCompareValues(t *testing.T) {
+	testCases := []struct {
+		name     string
+		a, b     string
+		order    keyOrder
+		expected int
+	}{
+		{name: "numbers", a: "9", b: "10", order: keyOrderNumeric, expected: -1},
+		{name: "text", a: "9", b: "10", order: keyOrderText, expected: 1},
+		{name: "fractional seconds", a: "2024-01-02T10:00:00Z", b: "2024-01-02T10:00:00.5Z", order: keyOrderTime, expected: -1},
+		{name: "time zones", a: "2024-01-02T10:00:00+02:00", b: "2024-01-02T09:00:00Z", order: keyOrderTime, expected: -1},
+		{name: "dates", a: "2023-12-31", b: "2024-01-01", order: keyOrderTime, expected: -1},
+		{name: "same time", a: "2024-01-02 10:00:00", b: "2024-01-02T10:00:00", order: keyOrderTime, expected: 0},
+		// SQL Server compares the last bytes of the GUIDs first.
+		{name: "guids", a: "ff000000-0000-0000-0000-000000000001", b: "00000000-0000-0000-0000-000000000002", order: keyOrderGUID, expected: -1},
+		{name: "guid groups", a: "00000000-0000-0000-0100-000000000000", b: "00000000-0000-0000-0001-000000000000", order: keyOrderGUID, expected: 1},
+		{name: "native uuids", a: "ff000000-0000-0000-0000-000000000001", b: "00000000-0000-0000-0000-000000000002", order: keyOrderNative, expected: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if result := compareValues(tc.a, tc.b, tc.order); result != tc.expected {
+				t.Fatalf("expected %d, but got %d", tc.expected, result)
+			}
+		})
+	}
+}
@@ -20,6 +20,8 @@ type Driver interface {
 	ExecuteQuery(query string) ([][]string, int, error)
 	ExecutePendingChanges(changes []models.DBDMLChange) error
 	GetProvider() string
+	Ping() error
+	Close() error
 	GetPrimaryKeyColumnNames(database, table string) ([]string, error)
 
 	FormatArg(arg any) string
@@ -33,3 +35,47 @@ type Driver interface {
 	// find a better way to do it. See *ResultsTable.GetPrimaryKeyValue()
 	SetProvider(provider string)
 }
+
+// DatabaseAttacher is implemented by the drivers that can attach database
+// files to the open connection.
+type DatabaseAttacher interface {
+	AttachDatabase(path, name string) error
+}
+
+// SessionRestorer is implemented by the drivers whose connection holds state
+// that a new connection does not have, like the databases attached to SQLite.
+// RestoreSession is called once a lost connection is back.
+type SessionRestorer interface {
+	RestoreSession() error
+}
+
+// RowIdentifier is implemented by the drivers that identify the rows of the
+// tables without a primary key by a pseudocolumn, so their rows can be edited.
+// GetPrimaryKeyColumnNames only returns the declared primary key.
+type RowIdentifier interface {
+	// RowIDColumn returns the pseudocolumn GetRecords selects after the
+	// columns of the table, or an empty string when the table has a primary
+	// key.
+	RowIDColumn(database, table string) (string, error)
+}
+
+// TableMenuProvider is implemented by the drivers that show their own menus
+// for a table, after the records, columns, constraints, foreign keys and
+// indexes ones.
+type TableMenuProvider interface {
+	TableMenus() []string
+	GetTableMenu(database, table, menu string) ([][]string, error)
+}
+
+// MutationTracker is implemented by the drivers that apply the edits as
+// mutations running after the statements return.
+type MutationTracker interface {
+	GetMutationProgress(database, table string) (MutationProgress, error)
+}
+
+// MutationProgress describes the mutations of a table that are not done yet.
+type MutationProgress struct {
+	Pending    int
+	PartsToDo  int
+	FailReason string
+}
@@ -0,0 +1,637 @@
+package drivers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xo/dburl"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// DuckDB tables live in schemas of a database, "main" being the default one.
+// Tables of the main schema are listed by name and the others as
+// "schema.table", so the Tree shows them under the database like MySQL.
+type DuckDB struct {
+	Connection *sql.DB
+	Provider   string
+}
+
+const duckDBDefaultSchema = "main"
+
+func (db *DuckDB) TestConnection(urlstr string) (err error) {
+	return db.Connect(urlstr)
+}
+
+func (db *DuckDB) Connect(urlstr string) (err error) {
+	db.SetProvider(DriverDuckDB)
+
+	db.Connection, err = dburl.Open(urlstr)
+	if err != nil {
+		return err
+	}
+
+	err = db.Connection.Ping()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db *DuckDB) GetDatabases() ([]string, error) {
+	var databases []string
+
+	rows, err := db.Connection.Query("SELECT database_name FROM duckdb_databases() WHERE NOT internal ORDER BY database_name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var database string
+		err := rows.Scan(&database)
+		if err != nil {
+			return nil, err
+		}
+
+		databases = append(databases, database)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return databases, nil
+}
+
+func (db *DuckDB) GetTables(database string) (map[string][]string, error) {
+	if database == "" {
+		return nil, errors.New("database name is required")
+	}
+
+	query := "SELECT table_schema, table_name FROM information_schema.tables "
+	query += "WHERE table_catalog = ? AND table_schema NOT IN ('information_schema', 'pg_catalog') "
+	query += "ORDER BY table_schema, table_name"
+
+	rows, err := db.Connection.Query(query, database)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tables := make(map[string][]string)
+
+	for rows.Next() {
+		var schema, table string
+		err = rows.Scan(&schema, &table)
+		if err != nil {
+			return nil, err
+		}
+
+		if schema != duckDBDefaultSchema {
+			table = fmt.Sprintf("%s.%s", schema, table)
+		}
+
+		tables[database] = append(tables[database], table)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tables, nil
+}
+
+func (db *DuckDB) GetTableColumns(database, table string) (results [][]string, err error) {
+	if database == "" {
+		return nil, errors.New("database name is required")
+	}
+
+	if table == "" {
+		return nil, errors.New("table name is required")
+	}
+
+	schema, tableName := db.splitTableName(table)
+
+	query := "SELECT column_name, data_type, is_nullable, column_default FROM information_schema.columns "
+	query += "WHERE table_catalog = ? AND table_schema = ? AND table_name = ? "
+	query += "ORDER BY ordinal_position"
+
+	return db.queryMetadata(query, database, schema, tableName)
+}
+
+func (db *DuckDB) GetConstraints(database, table string) (results [][]string, err error) {
+	if database == "" {
+		return nil, errors.New("database name is required")
+	}
+
+	if table == "" {
+		return nil, errors.New("table name is required")
+	}
+
+	schema, tableName := db.splitTableName(table)
+
+	query := "SELECT tc.constraint_name, kcu.column_name, tc.constraint_type "
+	query += "FROM information_schema.table_constraints AS tc "
+	query += "LEFT JOIN information_schema.key_column_usage AS kcu "
+	query += "ON tc.constraint_catalog = kcu.constraint_catalog AND tc.constraint_schema = kcu.constraint_schema AND tc.constraint_name = kcu.constraint_name "
+	query += "WHERE tc.constraint_type <> 'FOREIGN KEY' AND tc.table_catalog = ? AND tc.table_schema = ? AND tc.table_name = ? "
+	// NOT NULL columns are listed as CHECK constraints too.
+	query += "AND NOT (tc.constraint_type = 'CHECK' AND tc.constraint_name LIKE '%\\_not\\_null' ESCAPE '\\') "
+	query += "ORDER BY tc.constraint_name, kcu.ordinal_position"
+
+	return db.queryMetadata(query, database, schema, tableName)
+}
+
+func (db *DuckDB) GetForeignKeys(database, table string) (results [][]string, err error) {
+	if database == "" {
+		return nil, errors.New("database name is required")
+	}
+
+	if table == "" {
+		return nil, errors.New("table name is required")
+	}
+
+	schema, tableName := db.splitTableName(table)
+
+	query := "SELECT constraint_name, unnest(constraint_column_names) AS column_name, "
+	query += "referenced_table AS foreign_table_name, unnest(referenced_column_names) AS foreign_column_name "
+	query += "FROM duckdb_constraints() "
+	query += "WHERE constraint_type = 'FOREIGN KEY' AND database_name = ? AND schema_name = ? AND table_name = ?"
+
+	return db.queryMetadata(query, database, schema, tableName)
+}
+
+func (db *DuckDB) GetIndexes(database, table string) (results [][]string, err error) {
+	if database == "" {
+		return nil, errors.New("database name is required")
+	}
+
+	if table == "" {
+		return nil, errors.New("table name is required")
+	}
+
+	schema, tableName := db.splitTableName(table)
+
+	// expressions is the list of indexed columns formatted as "[a, b]".
+	query := "SELECT index_name, is_unique, is_primary, unnest(string_split(trim(expressions, '[]'), ', ')) AS column_name, sql "
+	query += "FROM duckdb_indexes() "
+	query += "WHERE database_name = ? AND schema_name = ? AND table_name = ? "
+	query += "ORDER BY index_name"
+
+	return db.queryMetadata(query, database, schema, tableName)
+}
+
+func (db *DuckDB) GetRecords(database, table, where, sort string, offset, limit int) (paginatedResults [][]string, totalRecords int, err error) {
+	if table == "" {
+		return nil, 0, errors.New("table name is required")
+	}
+
+	if limit == 0 {
+		limit = DefaultRowLimit
+	}
+
+	formattedTableName := db.formatTableName(database, table)
+
+	query := "SELECT * FROM "
+	query += formattedTableName
+
+	if where != "" {
+		query += fmt.Sprintf(" %s", where)
+	}
+
+	if sort != "" {
+		query += fmt.Sprintf(" ORDER BY %s", sort)
+	}
+
+	query += " LIMIT ? OFFSET ?"
+
+	paginatedRows, err := db.Connection.Query(query, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer paginatedRows.Close()
+
+	columns, err := paginatedRows.Columns()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	paginatedResults = append(paginatedResults, columns)
+
+	columnTypes, err := paginatedRows.ColumnTypes()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for paginatedRows.Next() {
+		values, err := scanDuckDBRow(paginatedRows, columnTypes)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		var row []string
+		for _, value := range values {
+			switch {
+			case value == nil:
+				row = append(row, "NULL&")
+			case *value == "":
+				row = append(row, "EMPTY&")
+			default:
+				row = append(row, *value)
+			}
+		}
+
+		paginatedResults = append(paginatedResults, row)
+	}
+	if err := paginatedRows.Err(); err != nil {
+		return nil, 0, err
+	}
+	// close to release the connection
+	if err := paginatedRows.Close(); err != nil {
+		return nil, 0, err
+	}
+
+	countQuery := "SELECT COUNT(*) FROM "
+	countQuery += formattedTableName
+
+	if where != "" {
+		countQuery += fmt.Sprintf(" %s", where)
+	}
+
+	row := db.Connection.QueryRow(countQuery)
+	if err := row.Scan(&totalRecords); err != nil {
+		return nil, 0, err
+	}
+
+	return paginatedResults, totalRecords, nil
+}
+
+func (db *DuckDB) ExecuteQuery(query string) ([][]string, int, error) {
+	rows, err := db.Connection.Query(query)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	records := make([][]string, 0)
+	for rows.Next() {
+		values, err := scanDuckDBRow(rows, columnTypes)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		var row []string
+		for _, value := range values {
+			if value == nil {
+				row = append(row, "NULL")
+			} else {
+				row = append(row, *value)
+			}
+		}
+
+		records = append(records, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	// Prepend the columns to the records.
+	results := append([][]string{columns}, records...)
+
+	return results, len(records), nil
+}
+
+func (db *DuckDB) UpdateRecord(database, table, column, value, primaryKeyColumnName, primaryKeyValue string) error {
+	if table == "" {
+		return errors.New("table name is required")
+	}
+
+	if column == "" {
+		return errors.New("column name is required")
+	}
+
+	if value == "" {
+		return errors.New("value is required")
+	}
+
+	if primaryKeyColumnName == "" {
+		return errors.New("primary key column name is required")
+	}
+
+	if primaryKeyValue == "" {
+		return errors.New("primary key value is required")
+	}
+
+	query := "UPDATE "
+	query += db.formatTableName(database, table)
+	query += fmt.Sprintf(" SET %s = ? WHERE %s = ?", db.FormatReference(column), db.FormatReference(primaryKeyColumnName))
+
+	_, err := db.Connection.Exec(query, value, primaryKeyValue)
+
+	return err
+}
+
+func (db *DuckDB) DeleteRecord(database, table, primaryKeyColumnName, primaryKeyValue string) error {
+	if table == "" {
+		return errors.New("table name is required")
+	}
+
+	if primaryKeyColumnName == "" {
+		return errors.New("primary key column name is required")
+	}
+
+	if primaryKeyValue == "" {
+		return errors.New("primary key value is required")
+	}
+
+	query := "DELETE FROM "
+	query += db.formatTableName(database, table)
+	query += fmt.Sprintf(" WHERE %s = ?", db.FormatReference(primaryKeyColumnName))
+
+	_, err := db.Connection.Exec(query, primaryKeyValue)
+
+	return err
+}
+
+func (db *DuckDB) ExecuteDMLStatement(query string) (result string, err error) {
+	res, err := db.Connection.Exec(query)
+	if err != nil {
+		return "", err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%d rows affected", rowsAffected), nil
+}
+
+func (db *DuckDB) ExecutePendingChanges(changes []models.DBDMLChange) error {
+	var queries []models.Query
+
+	for _, change := range changes {
+
+		formattedTableName := db.formatTableName(change.Database, change.Table)
+
+		switch change.Type {
+
+		case models.DMLInsertType:
+			queries = append(queries, buildInsertQuery(formattedTableName, change.Values, db))
+		case models.DMLUpdateType:
+			queries = append(queries, buildUpdateQuery(formattedTableName, change.Values, change.PrimaryKeyInfo, db))
+		case models.DMLDeleteType:
+			queries = append(queries, buildDeleteQuery(formattedTableName, change.PrimaryKeyInfo, db))
+		}
+	}
+
+	return queriesInTransaction(db.Connection, queries)
+}
+
+func (db *DuckDB) GetPrimaryKeyColumnNames(database, table string) (primaryKeyColumnName []string, err error) {
+	schema, tableName := db.splitTableName(table)
+
+	query := "SELECT unnest(constraint_column_names) FROM duckdb_constraints() "
+	query += "WHERE constraint_type = 'PRIMARY KEY' AND database_name = ? AND schema_name = ? AND table_name = ?"
+
+	rows, err := db.Connection.Query(query, database, schema, tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return nil, err
+		}
+
+		primaryKeyColumnName = append(primaryKeyColumnName, column)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return primaryKeyColumnName, nil
+}
+
+func (db *DuckDB) SetProvider(provider string) {
+	db.Provider = provider
+}
+
+func (db *DuckDB) GetProvider() string {
+	return db.Provider
+}
+
+// Ping checks that the connection to the server is alive.
+func (db *DuckDB) Ping() error {
+	if db.Connection == nil {
+		return errors.New("not connected")
+	}
+
+	return db.Connection.Ping()
+}
+
+func (db *DuckDB) Close() error {
+	if db.Connection == nil {
+		return nil
+	}
+
+	return db.Connection.Close()
+}
+
+// splitTableName returns the schema and the name of a table as listed by
+// GetTables.
+func (db *DuckDB) splitTableName(table string) (string, string) {
+	if split := strings.SplitN(table, ".", 2); len(split) == 2 {
+		return split[0], split[1]
+	}
+
+	return duckDBDefaultSchema, table
+}
+
+func (db *DuckDB) formatTableName(database, table string) string {
+	schema, tableName := db.splitTableName(table)
+
+	if database == "" {
+		return fmt.Sprintf("%s.%s", db.FormatReference(schema), db.FormatReference(tableName))
+	}
+
+	return fmt.Sprintf("%s.%s.%s", db.FormatReference(database), db.FormatReference(schema), db.FormatReference(tableName))
+}
+
+// queryMetadata runs a catalog query and returns its rows as strings, with
+// the column names first.
+func (db *DuckDB) queryMetadata(query string, args ...any) (results [][]string, err error) {
+	rows, err := db.Connection.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	results = append(results, columns)
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	for rows.Next() {
+		values, err := scanDuckDBRow(rows, columnTypes)
+		if err != nil {
+			return nil, err
+		}
+
+		var row []string
+		for _, value := range values {
+			if value == nil {
+				row = append(row, "NULL")
+			} else {
+				row = append(row, *value)
+			}
+		}
+
+		results = append(results, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// scanDuckDBRow scans the current row as strings, NULL values are nil. The
+// DuckDB driver returns typed values instead of raw bytes, so they are
+// formatted according to the column type.
+func scanDuckDBRow(rows *sql.Rows, columnTypes []*sql.ColumnType) ([]*string, error) {
+	rowValues := make([]any, len(columnTypes))
+	for i := range columnTypes {
+		rowValues[i] = new(any)
+	}
+
+	if err := rows.Scan(rowValues...); err != nil {
+		return nil, err
+	}
+
+	values := make([]*string, len(columnTypes))
+
+	for i, col := range rowValues {
+		value := *col.(*any)
+		if value == nil {
+			continue
+		}
+
+		formatted := duckDBValueToString(value, columnTypes[i].DatabaseTypeName())
+		values[i] = &formatted
+	}
+
+	return values, nil
+}
+
+func duckDBValueToString(value any, databaseType string) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		if databaseType == "UUID" {
+			if parsed, err := uuid.FromBytes(v); err == nil {
+				return parsed.String()
+			}
+		}
+		return string(v)
+	case time.Time:
+		switch databaseType {
+		case "DATE":
+			return v.Format(time.DateOnly)
+		case "TIME":
+			return v.Format("15:04:05.999999")
+		case "TIMESTAMPTZ":
+			return v.Format("2006-01-02 15:04:05.999999-07:00")
+		}
+		return v.Format("2006-01-02 15:04:05.999999")
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+func (db *DuckDB) FormatArg(arg any) string {
+	if arg == "NULL" || arg == "DEFAULT" {
+		return fmt.Sprintf("%v", arg)
+	}
+
+	switch v := arg.(type) {
+	case int, int64:
+		return fmt.Sprintf("%d", v)
+	case float64, float32:
+		s := fmt.Sprintf("%f", v)
+		trimmed := strings.TrimRight(s, "0")
+		if strings.HasSuffix(trimmed, ".") {
+			trimmed += "0"
+		}
+		return trimmed
+	case string:
+		escaped := strings.ReplaceAll(v, "'", "''")
+		return fmt.Sprintf("'%s'", escaped)
+	case []byte:
+		escaped := strings.ReplaceAll(string(v), "'", "''")
+		return fmt.Sprintf("'%s'", escaped)
+	case bool:
+		if v {
+			return "true"
+		}
+
+		return "false"
+	case nil:
+		return "NULL"
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+func (db *DuckDB) FormatReference(reference string) string {
+	return fmt.Sprintf("\"%s\"", strings.ReplaceAll(reference, "\"", "\"\""))
+}
+
+func (db *DuckDB) FormatPlaceholder(_ int) string {
+	return "?"
+}
+
+func (db *DuckDB) DMLChangeToQueryString(change models.DBDMLChange) (string, error) {
+	var queryStr string
+
+	formattedTableName := db.formatTableName(change.Database, change.Table)
+
+	columnNames, values := getColNamesAndArgsAsString(change.Values)
+
+	switch change.Type {
+	case models.DMLInsertType:
+		queryStr = buildInsertQueryString(formattedTableName, columnNames, values, db)
+	case models.DMLUpdateType:
+		queryStr = buildUpdateQueryString(formattedTableName, columnNames, values, change.PrimaryKeyInfo, db)
+	case models.DMLDeleteType:
+		queryStr = buildDeleteQueryString(formattedTableName, change.PrimaryKeyInfo, db)
+	}
+
+	return queryStr, nil
+}
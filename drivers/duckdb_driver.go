@@ -0,0 +1,9 @@
+//go:build cgo && !disable_duckdb_import
+
+package drivers
+
+// import duckdb driver, it needs cgo so builds without it report an unknown
+// driver when connecting to DuckDB.
+import (
+	_ "github.com/marcboeker/go-duckdb"
+)
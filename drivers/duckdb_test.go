@@ -0,0 +1,329 @@
+package drivers
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+const (
+	testDBNameDuckDB      = "test_db"
+	testDBTableNameDuckDB = "test_table"
+)
+
+func TestDuckDB_FormatArg(t *testing.T) {
+	db := &DuckDB{}
+
+	testCases := []struct {
+		name     string
+		arg      any
+		expected string
+	}{
+		{
+			name:     "Integer argument",
+			arg:      123,
+			expected: "123",
+		},
+		{
+			name:     "String with single quote",
+			arg:      "O'Reilly",
+			expected: "'O''Reilly'",
+		},
+		{
+			name:     "Float argument",
+			arg:      123.45,
+			expected: "123.45",
+		},
+		{
+			name:     "Boolean argument",
+			arg:      true,
+			expected: "true",
+		},
+		{
+			name:     "Nil argument",
+			arg:      nil,
+			expected: "NULL",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			formattedArg := db.FormatArg(tc.arg)
+			if formattedArg != tc.expected {
+				t.Fatalf("expected %q, but got %q", tc.expected, formattedArg)
+			}
+		})
+	}
+}
+
+func TestDuckDB_DMLChangeToQueryString(t *testing.T) {
+	db := &DuckDB{}
+
+	testCases := []struct {
+		name     string
+		change   models.DBDMLChange
+		expected string
+	}{
+		{
+			name: "Insert with int value",
+			change: models.DBDMLChange{
+				Database: testDBNameDuckDB, Table: testDBTableNameDuckDB,
+				Type: models.DMLInsertType,
+				Values: []models.CellValue{
+					{Column: "name", Value: "test_name", Type: models.String},
+					{Column: "value", Value: 123, Type: models.String},
+				},
+			},
+			expected: `INSERT INTO "test_db"."main"."test_table" (name, value) VALUES ('test_name', 123)`,
+		},
+		{
+			name: "Update in another schema",
+			change: models.DBDMLChange{
+				Database: testDBNameDuckDB, Table: "sales." + testDBTableNameDuckDB,
+				Type: models.DMLUpdateType,
+				Values: []models.CellValue{
+					{Column: "name", Value: "test_name", Type: models.String},
+				},
+				PrimaryKeyInfo: []models.PrimaryKeyInfo{
+					{Name: "id", Value: "1"},
+				},
+			},
+			expected: `UPDATE "test_db"."sales"."test_table" SET "name" = 'test_name' WHERE "id" = '1'`,
+		},
+		{
+			name: "Delete with int value",
+			change: models.DBDMLChange{
+				Database: testDBNameDuckDB, Table: testDBTableNameDuckDB,
+				Type: models.DMLDeleteType,
+				PrimaryKeyInfo: []models.PrimaryKeyInfo{
+					{Name: "id", Value: 1},
+				},
+			},
+			expected: `DELETE FROM "test_db"."main"."test_table" WHERE "id" = 1`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			queryString, err := db.DMLChangeToQueryString(tc.change)
+			if err != nil {
+				t.Fatalf("DMLChangeToQueryString failed: %v", err)
+			}
+			if queryString != tc.expected {
+				t.Fatalf("Expected: %q\nGot: %q", tc.expected, queryString)
+			}
+		})
+	}
+}
+
+func TestDuckDB_GetTables(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock: %v", err)
+	}
+	defer db.Close()
+
+	duckdb := &DuckDB{Connection: db}
+
+	rows := sqlmock.NewRows([]string{"table_schema", "table_name"}).
+		AddRow("main", "users").
+		AddRow("sales", "orders")
+
+	mock.ExpectQuery("SELECT table_schema, table_name FROM information_schema.tables").
+		WithArgs(testDBNameDuckDB).
+		WillReturnRows(rows)
+
+	tables, err := duckdb.GetTables(testDBNameDuckDB)
+	if err != nil {
+		t.Fatalf("GetTables failed: %v", err)
+	}
+
+	expected := map[string][]string{testDBNameDuckDB: {"users", "sales.orders"}}
+	if !reflect.DeepEqual(tables, expected) {
+		t.Fatalf("Expected %v, got %v", expected, tables)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestDuckDB_GetRecords(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock: %v", err)
+	}
+	defer db.Close()
+
+	duckdb := &DuckDB{Connection: db}
+
+	rows := sqlmock.NewRows([]string{"id", "name"}).
+		AddRow(1, "Alice").
+		AddRow(2, nil)
+
+	mock.ExpectQuery(`SELECT \* FROM "test_db"."main"."test_table" WHERE id > 0 LIMIT \? OFFSET \?`).
+		WithArgs(DefaultRowLimit, 0).
+		WillReturnRows(rows)
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM "test_db"."main"."test_table" WHERE id > 0`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+	records, total, err := duckdb.GetRecords(testDBNameDuckDB, testDBTableNameDuckDB, "WHERE id > 0", "", 0, DefaultRowLimit)
+	if err != nil {
+		t.Fatalf("GetRecords failed: %v", err)
+	}
+
+	expected := [][]string{
+		{"id", "name"},
+		{"1", "Alice"},
+		{"2", "NULL&"},
+	}
+
+	if !reflect.DeepEqual(records, expected) {
+		t.Fatalf("Expected %v, got %v", expected, records)
+	}
+
+	if total != 2 {
+		t.Fatalf("Expected total 2, got %d", total)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestDuckDB_GetForeignKeys(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock: %v", err)
+	}
+	defer db.Close()
+
+	duckdb := &DuckDB{Connection: db}
+
+	rows := sqlmock.NewRows([]string{"constraint_name", "column_name", "foreign_table_name", "foreign_column_name"}).
+		AddRow("posts_user_id_id_fkey", "user_id", "users", "id")
+
+	mock.ExpectQuery("FROM duckdb_constraints\\(\\)").
+		WithArgs(testDBNameDuckDB, "sales", "posts").
+		WillReturnRows(rows)
+
+	results, err := duckdb.GetForeignKeys(testDBNameDuckDB, "sales.posts")
+	if err != nil {
+		t.Fatalf("GetForeignKeys failed: %v", err)
+	}
+
+	expected := [][]string{
+		{"constraint_name", "column_name", "foreign_table_name", "foreign_column_name"},
+		{"posts_user_id_id_fkey", "user_id", "users", "id"},
+	}
+
+	if !reflect.DeepEqual(results, expected) {
+		t.Fatalf("Expected %v, got %v", expected, results)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestDuckDB_ExecutePendingChanges(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock: %v", err)
+	}
+	defer db.Close()
+
+	duckdb := &DuckDB{Connection: db}
+
+	changes := []models.DBDMLChange{
+		{
+			Database: testDBNameDuckDB,
+			Table:    testDBTableNameDuckDB,
+			Type:     models.DMLUpdateType,
+			Values: []models.CellValue{
+				{Column: "name", Value: "New Name", Type: models.String},
+			},
+			PrimaryKeyInfo: []models.PrimaryKeyInfo{
+				{Name: "id", Value: 1},
+			},
+		},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "test_db"."main"."test_table" SET "name" = \? WHERE "id" = \?`).
+		WithArgs("New Name", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := duckdb.ExecutePendingChanges(changes); err != nil {
+		t.Fatalf("ExecutePendingChanges failed: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestDuckDB_GetPrimaryKeyColumnNames(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock: %v", err)
+	}
+	defer db.Close()
+
+	duckdb := &DuckDB{Connection: db}
+
+	mock.ExpectQuery("SELECT unnest\\(constraint_column_names\\) FROM duckdb_constraints\\(\\)").
+		WithArgs(testDBNameDuckDB, duckDBDefaultSchema, testDBTableNameDuckDB).
+		WillReturnRows(sqlmock.NewRows([]string{"column_name"}).AddRow("id"))
+
+	keys, err := duckdb.GetPrimaryKeyColumnNames(testDBNameDuckDB, testDBTableNameDuckDB)
+	if err != nil {
+		t.Fatalf("GetPrimaryKeyColumnNames failed: %v", err)
+	}
+
+	expected := []string{"id"}
+	if !reflect.DeepEqual(keys, expected) {
+		t.Fatalf("Expected %v, got %v", expected, keys)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestDuckDB_SetGetProvider(t *testing.T) {
+	db := &DuckDB{}
+	db.SetProvider(DriverDuckDB)
+
+	if db.GetProvider() != DriverDuckDB {
+		t.Fatalf("Provider mismatch: got %s, expected %s", db.GetProvider(), DriverDuckDB)
+	}
+}
+
+func TestDuckDB_formatTableName(t *testing.T) {
+	db := &DuckDB{}
+
+	testCases := []struct {
+		database string
+		table    string
+		expected string
+	}{
+		{database: testDBNameDuckDB, table: testDBTableNameDuckDB, expected: `"test_db"."main"."test_table"`},
+		{database: testDBNameDuckDB, table: "sales.orders", expected: `"test_db"."sales"."orders"`},
+		{database: "", table: `we"ird`, expected: `"main"."we""ird"`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s.%s", tc.database, tc.table), func(t *testing.T) {
+			if tableName := db.formatTableName(tc.database, tc.table); tableName != tc.expected {
+				t.Fatalf("formatTableName failed: got %s, expected %s", tableName, tc.expected)
+			}
+		})
+	}
+}
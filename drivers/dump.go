@@ -0,0 +1,452 @@
+package drivers
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// dumpInsertBatchSize is the number of rows written per INSERT statement.
+const dumpInsertBatchSize = 100
+
+// DumpTables writes a SQL script that recreates the given tables with their
+// rows, in the dialect of the driver. Every table of the database is dumped
+// when tables is empty. Tables are ordered so that the tables referenced by
+// a foreign key are created first.
+//
+// Table names are written without the database, so the script is restored
+// into whatever database the restoring connection uses.
+func DumpTables(ctx context.Context, db Driver, database string, tables []string, w io.Writer, onProgress func(table string, rows int)) error {
+	if len(tables) == 0 {
+		var err error
+
+		tables, err = ListTables(db, database)
+		if err != nil {
+			return err
+		}
+	}
+
+	schemas := make([]models.TableSchema, 0, len(tables))
+
+	for _, table := range tables {
+		schema, err := GetTableSchema(db, database, table)
+		if err != nil {
+			return fmt.Errorf("%s: %w", table, err)
+		}
+
+		schemas = append(schemas, dumpTableSchema(schema, Dialect(db.GetProvider())))
+	}
+
+	schemas = sortTablesByDependencies(schemas)
+
+	if _, err := fmt.Fprintf(w, "-- lazysql dump of %s (%s)\n", database, db.GetProvider()); err != nil {
+		return err
+	}
+
+	for _, schema := range schemas {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if _, err := fmt.Fprintf(w, "\n-- Table: %s\n", schema.Name); err != nil {
+			return err
+		}
+
+		diff := models.SchemaDiff{Tables: []models.TableSchemaDiff{{Name: schema.Name, Source: &schema, Status: models.SchemaDiffAdded}}}
+
+		for _, statement := range GenerateMigration(diff, db) {
+			if _, err := fmt.Fprintf(w, "%s\n", statement); err != nil {
+				return err
+			}
+		}
+
+		table := DataDiffTable{Driver: db, Database: database, Table: schema.Name}
+
+		if err := dumpTableRows(ctx, table, primaryKeyColumns(schema), binaryColumns(schema), w, onProgress); err != nil {
+			return fmt.Errorf("%s: %w", schema.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// dumpTableSchema prepares a table schema to be recreated on an empty
+// database. Postgres sequence defaults become serial columns since the
+// sequences themselves are not dumped.
+func dumpTableSchema(schema models.TableSchema, provider string) models.TableSchema {
+	if provider == DriverPostgres {
+		columns := make([]models.ColumnSchema, len(schema.Columns))
+		copy(columns, schema.Columns)
+
+		for i, column := range columns {
+			if !strings.HasPrefix(column.Default, "nextval(") {
+				continue
+			}
+
+			switch column.Type {
+			case "integer":
+				columns[i].Type = "serial"
+			case "bigint":
+				columns[i].Type = "bigserial"
+			case "smallint":
+				columns[i].Type = "smallserial"
+			default:
+				continue
+			}
+
+			columns[i].Default = ""
+		}
+
+		schema.Columns = columns
+	}
+
+	return schema
+}
+
+func primaryKeyColumns(schema models.TableSchema) []string {
+	for _, constraint := range schema.Constraints {
+		if isPrimaryKeyConstraint(constraint) {
+			return constraint.Columns
+		}
+	}
+
+	return nil
+}
+
+// binaryColumns returns the columns of the table that hold bytes, their values
+// are dumped as hex literals since they are not necessarily valid text.
+func binaryColumns(schema models.TableSchema) map[string]bool {
+	columns := map[string]bool{}
+
+	for _, column := range schema.Columns {
+		if columnType, _ := genericColumnType(column.Type); columnType == columnTypeBinary {
+			columns[column.Name] = true
+		}
+	}
+
+	return columns
+}
+
+// sortTablesByDependencies orders the tables so that every table comes after
+// the tables it references. Tables in a reference cycle keep their order.
+func sortTablesByDependencies(schemas []models.TableSchema) []models.TableSchema {
+	sorted := make([]models.TableSchema, 0, len(schemas))
+	visited := map[string]bool{}
+	visiting := map[string]bool{}
+
+	byName := map[string]models.TableSchema{}
+	for _, schema := range schemas {
+		byName[schema.Name] = schema
+	}
+
+	var visit func(schema models.TableSchema)
+	visit = func(schema models.TableSchema) {
+		if visited[schema.Name] || visiting[schema.Name] {
+			return
+		}
+
+		visiting[schema.Name] = true
+
+		for _, foreignKey := range schema.ForeignKeys {
+			if referenced, ok := byName[foreignKey.ReferencedTable]; ok {
+				visit(referenced)
+			}
+		}
+
+		visiting[schema.Name] = false
+		visited[schema.Name] = true
+		sorted = append(sorted, schema)
+	}
+
+	for _, schema := range schemas {
+		visit(schema)
+	}
+
+	return sorted
+}
+
+func dumpTableRows(ctx context.Context, table DataDiffTable, primaryKey []string, binary map[string]bool, w io.Writer, onProgress func(table string, rows int)) error {
+	stream := newRecordStream(ctx, table, primaryKey)
+	dumped := 0
+
+	var values []string
+
+	flush := func() error {
+		if len(values) == 0 {
+			return nil
+		}
+
+		columns := make([]string, len(stream.header))
+		for i, column := range stream.header {
+			columns[i] = table.Driver.FormatReference(column)
+		}
+
+		if _, err := fmt.Fprintf(w, "INSERT INTO %s (%s) VALUES\n  %s;\n", formatQualifiedReference(table.Table, table.Driver), strings.Join(columns, ", "), strings.Join(values, ",\n  ")); err != nil {
+			return err
+		}
+
+		values = values[:0]
+
+		return nil
+	}
+
+	for {
+		row, err := stream.next()
+		if err != nil {
+			return err
+		}
+
+		if row == nil {
+			break
+		}
+
+		formatted := make([]string, len(row))
+		for i, value := range row {
+			if binary[stream.header[i]] {
+				formatted[i] = dumpBinaryValue(table.Driver, value)
+			} else {
+				formatted[i] = dumpValue(table.Driver, value)
+			}
+		}
+
+		values = append(values, fmt.Sprintf("(%s)", strings.Join(formatted, ", ")))
+		dumped++
+
+		if len(values) == dumpInsertBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+
+		if onProgress != nil && dumped%RecordBatchSize == 0 {
+			onProgress(table.Table, dumped)
+		}
+	}
+
+	if err := flush(); err != nil {
+		return err
+	}
+
+	if onProgress != nil {
+		onProgress(table.Table, dumped)
+	}
+
+	return nil
+}
+
+// dumpValue formats a value as returned by GetRecords as a SQL literal.
+func dumpValue(db Driver, value string) string {
+	switch value {
+	case "NULL&":
+		return "NULL"
+	case "EMPTY&":
+		return db.FormatArg("")
+	case "NULL", "DEFAULT":
+		// FormatArg writes these as keywords.
+		return "'" + value + "'"
+	}
+
+	if provider := Dialect(db.GetProvider()); provider == DriverMySQL || provider == DriverClickhouse {
+		value = strings.ReplaceAll(value, `\`, `\\`)
+	}
+
+	return db.FormatArg(value)
+}
+
+// dumpBinaryValue formats the bytes of a binary column as returned by
+// GetRecords as a hex literal of the dialect.
+func dumpBinaryValue(db Driver, value string) string {
+	switch value {
+	case "NULL&":
+		return "NULL"
+	case "EMPTY&":
+		value = ""
+	}
+
+	hexValue := hex.EncodeToString([]byte(value))
+
+	switch Dialect(db.GetProvider()) {
+	case DriverPostgres:
+		return `'\x` + hexValue + "'::bytea"
+	case DriverMSSQL:
+		return "0x" + hexValue
+	case DriverOracle:
+		return "HEXTORAW('" + hexValue + "')"
+	case DriverClickhouse:
+		return "unhex('" + hexValue + "')"
+	case DriverDuckDB:
+		return "from_hex('" + hexValue + "')"
+	default:
+		return "X'" + hexValue + "'"
+	}
+}
+
+// SplitStatements splits a SQL script into its statements. Semicolons inside
+// quoted strings, quoted identifiers and comments are ignored, and comments
+// are left out of the statements.
+//
+// The provider enables the syntax that only some dialects have: backslash
+// escapes for MySQL and ClickHouse, dollar quoted strings for Postgres and
+// GO batch separators and bracketed identifiers for MSSQL.
+func SplitStatements(script, provider string) []string {
+	var statements []string
+	var current strings.Builder
+
+	flush := func() {
+		if statement := strings.TrimSpace(current.String()); statement != "" {
+			statements = append(statements, statement)
+		}
+		current.Reset()
+	}
+
+	runes := []rune(script)
+	backslashEscapes := provider == DriverMySQL || provider == DriverClickhouse
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		switch {
+		case r == '\'' || r == '"' || r == '`' || (r == '[' && provider == DriverMSSQL):
+			closing := r
+			if r == '[' {
+				closing = ']'
+			}
+
+			end := i + 1
+			for ; end < len(runes); end++ {
+				if backslashEscapes && r != '`' && runes[end] == '\\' {
+					end++
+					continue
+				}
+
+				if runes[end] == closing {
+					// A doubled quote is an escaped quote.
+					if end+1 < len(runes) && runes[end+1] == closing {
+						end++
+						continue
+					}
+					break
+				}
+			}
+
+			end = min(end, len(runes)-1)
+			current.WriteString(string(runes[i : end+1]))
+			i = end
+		case r == '$' && provider == DriverPostgres && dollarQuoteTag(runes[i:]) != "":
+			tag := dollarQuoteTag(runes[i:])
+
+			end := indexRunes(runes, i+len(tag), tag)
+			if end == -1 {
+				end = len(runes)
+			} else {
+				end += len(tag)
+			}
+
+			current.WriteString(string(runes[i:end]))
+			i = end - 1
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+			current.WriteRune('\n')
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			end := indexRunes(runes, i+2, "*/")
+			if end == -1 {
+				end = len(runes)
+			}
+
+			i = end + 1
+			current.WriteRune(' ')
+		case r == ';':
+			flush()
+		case provider == DriverMSSQL && isBatchSeparator(runes, i):
+			flush()
+			i++
+		default:
+			current.WriteRune(r)
+		}
+	}
+
+	flush()
+
+	return statements
+}
+
+// dollarQuoteTag returns the opening tag of a Postgres dollar quoted string,
+// like $$ or $body$, or an empty string if the text does not start with one.
+func dollarQuoteTag(runes []rune) string {
+	for i := 1; i < len(runes); i++ {
+		if runes[i] == '$' {
+			return string(runes[:i+1])
+		}
+
+		if !unicode.IsLetter(runes[i]) && runes[i] != '_' && (i == 1 || !unicode.IsDigit(runes[i])) {
+			return ""
+		}
+	}
+
+	return ""
+}
+
+// indexRunes returns the position of substr in runes starting at from, or -1.
+func indexRunes(runes []rune, from int, substr string) int {
+	needle := []rune(substr)
+
+	for i := from; i+len(needle) <= len(runes); i++ {
+		if slices.Equal(runes[i:i+len(needle)], needle) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// isBatchSeparator reports whether position i starts a line that only holds
+// the MSSQL GO keyword.
+func isBatchSeparator(runes []rune, i int) bool {
+	if i+1 >= len(runes) || !strings.EqualFold(string(runes[i:i+2]), "go") {
+		return false
+	}
+
+	for j := i - 1; j >= 0 && runes[j] != '\n'; j-- {
+		if !unicode.IsSpace(runes[j]) {
+			return false
+		}
+	}
+
+	for j := i + 2; j < len(runes) && runes[j] != '\n'; j++ {
+		if !unicode.IsSpace(runes[j]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// RestoreScript executes the statements of a script one by one, stopping at
+// the first one that fails. It returns the number of executed statements.
+func RestoreScript(ctx context.Context, db Driver, script string, onProgress func(executed, total int)) (int, error) {
+	statements := SplitStatements(script, Dialect(db.GetProvider()))
+
+	for i, statement := range statements {
+		if err := ctx.Err(); err != nil {
+			return i, err
+		}
+
+		if _, err := db.ExecuteDMLStatement(statement); err != nil {
+			return i, fmt.Errorf("statement %d: %w", i+1, err)
+		}
+
+		if onProgress != nil {
+			onProgress(i+1, len(statements))
+		}
+	}
+
+	return len(statements), nil
+}
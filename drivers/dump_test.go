@@ -0,0 +1,248 @@
+package drivers
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitStatements(t *testing.T) {
+	testCases := []struct {
+		name     string
+		provider string
+		script   string
+		expected []string
+	}{
+		{
+			name:     "semicolons",
+			provider: DriverSqlite,
+			script:   "SELECT 1; SELECT 2;\n\nSELECT 3",
+			expected: []string{"SELECT 1", "SELECT 2", "SELECT 3"},
+		},
+		{
+			name:     "quoted semicolons",
+			provider: DriverSqlite,
+			script:   `INSERT INTO "a;b" VALUES ('x;y', 'it''s;'); SELECT 1`,
+			expected: []string{`INSERT INTO "a;b" VALUES ('x;y', 'it''s;')`, "SELECT 1"},
+		},
+		{
+			name:     "comments",
+			provider: DriverPostgres,
+			script:   "-- header; comment\nSELECT 1; /* block; comment */ SELECT '--not a comment';\n-- trailing",
+			expected: []string{"SELECT 1", "SELECT '--not a comment'"},
+		},
+		{
+			name:     "backslash escapes",
+			provider: DriverMySQL,
+			script:   `INSERT INTO t VALUES ('a\';b'); SELECT 1`,
+			expected: []string{`INSERT INTO t VALUES ('a\';b')`, "SELECT 1"},
+		},
+		{
+			name:     "dollar quotes",
+			provider: DriverPostgres,
+			script:   "CREATE FUNCTION f() RETURNS int AS $body$ SELECT 1; $body$ LANGUAGE sql; SELECT $1",
+			expected: []string{"CREATE FUNCTION f() RETURNS int AS $body$ SELECT 1; $body$ LANGUAGE sql", "SELECT $1"},
+		},
+		{
+			name:     "batch separators",
+			provider: DriverMSSQL,
+			script:   "SELECT [a;b] FROM t\nGO\nSELECT 'go'\n  go  \nSELECT 2",
+			expected: []string{"SELECT [a;b] FROM t", "SELECT 'go'", "SELECT 2"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := SplitStatements(tc.script, tc.provider)
+			if !reflect.DeepEqual(result, tc.expected) {
+				t.Fatalf("expected %q, but got %q", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestDumpTables_Restore(t *testing.T) {
+	source := newDataDiffTestDB(t,
+		"CREATE TABLE posts (id INTEGER PRIMARY KEY, user_id INTEGER NOT NULL REFERENCES users (id), title TEXT)",
+		"CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT, email TEXT)",
+		"CREATE INDEX users_email ON users (email)",
+		"INSERT INTO users VALUES (1, 'Alice', 'alice@example.com'), (2, 'O''Brien; Bob', NULL), (3, 'NULL', '')",
+		"INSERT INTO posts VALUES (1, 1, 'Hello -- world'), (2, 2, NULL)",
+	)
+	target := newDataDiffTestDB(t)
+
+	var dump bytes.Buffer
+
+	progress := map[string]int{}
+
+	err := DumpTables(context.Background(), source, "main", nil, &dump, func(table string, rows int) {
+		progress[table] = rows
+	})
+	if err != nil {
+		t.Fatalf("DumpTables failed: %v", err)
+	}
+
+	script := dump.String()
+
+	if strings.Index(script, "CREATE TABLE `users`") > strings.Index(script, "CREATE TABLE `posts`") {
+		t.Fatalf("expected users to be created before posts, but got:\n%s", script)
+	}
+
+	if progress["users"] != 3 || progress["posts"] != 2 {
+		t.Fatalf("unexpected progress %v", progress)
+	}
+
+	executed, err := RestoreScript(context.Background(), target, script, nil)
+	if err != nil {
+		t.Fatalf("RestoreScript failed after %d statements: %v\n%s", executed, err, script)
+	}
+
+	for _, table := range []string{"users", "posts"} {
+		diff, err := DiffTableData(context.Background(), DataDiffTable{Driver: source, Table: table}, DataDiffTable{Driver: target, Table: table}, nil)
+		if err != nil {
+			t.Fatalf("DiffTableData failed: %v", err)
+		}
+
+		if len(diff.Rows) != 0 {
+			t.Fatalf("expected %s to be equal, but got %d differences", table, len(diff.Rows))
+		}
+	}
+
+	indexes, err := target.GetIndexes("main", "users")
+	if err != nil {
+		t.Fatalf("GetIndexes failed: %v", err)
+	}
+
+	if !strings.Contains(strings.Join(indexes[len(indexes)-1], " "), "users_email") {
+		t.Fatalf("expected the users_email index to be restored, but got %v", indexes)
+	}
+}
+
+func TestDumpTables_RestoreWithoutPrimaryKey(t *testing.T) {
+	source := newDataDiffTestDB(t,
+		"CREATE TABLE logs (message TEXT, level INTEGER)",
+		"INSERT INTO logs VALUES ('started', 1), ('stopped', 2), ('started', 1)",
+	)
+	target := newDataDiffTestDB(t)
+
+	var dump bytes.Buffer
+
+	if err := DumpTables(context.Background(), source, "main", nil, &dump, nil); err != nil {
+		t.Fatalf("DumpTables failed: %v", err)
+	}
+
+	script := dump.String()
+
+	if strings.Contains(strings.ToLower(script), "rowid") {
+		t.Fatalf("expected the rowid to be left out of the dump, but got:\n%s", script)
+	}
+
+	if _, err := RestoreScript(context.Background(), target, script, nil); err != nil {
+		t.Fatalf("RestoreScript failed: %v\n%s", err, script)
+	}
+
+	records, total, err := target.GetRecords("main", "logs", "", "", 0, DefaultRowLimit)
+	if err != nil {
+		t.Fatalf("GetRecords failed: %v", err)
+	}
+
+	expected := [][]string{
+		{"message", "level", "rowid"},
+		{"started", "1", "1"},
+		{"stopped", "2", "2"},
+		{"started", "1", "3"},
+	}
+
+	if !reflect.DeepEqual(records, expected) || total != 3 {
+		t.Fatalf("expected %v, but got %v (%d)", expected, records, total)
+	}
+}
+
+func TestDumpBinaryValue(t *testing.T) {
+	testCases := []struct {
+		name     string
+		db       Driver
+		value    string
+		expected string
+	}{
+		{name: "sqlite", db: &SQLite{Provider: DriverSqlite}, value: "\x00\xff'", expected: "X'00ff27'"},
+		{name: "mysql", db: &MySQL{Provider: DriverMySQL}, value: "ab", expected: "X'6162'"},
+		{name: "postgres", db: &Postgres{Provider: DriverPostgres}, value: "\x00\xff'", expected: `'\x00ff27'::bytea`},
+		{name: "mssql", db: &MSSQL{Provider: DriverMSSQL}, value: "\x00\xff'", expected: "0x00ff27"},
+		{name: "oracle", db: &Oracle{Provider: DriverOracle}, value: "ab", expected: "HEXTORAW('6162')"},
+		{name: "empty", db: &Postgres{Provider: DriverPostgres}, value: "EMPTY&", expected: `'\x'::bytea`},
+		{name: "null", db: &MSSQL{Provider: DriverMSSQL}, value: "NULL&", expected: "NULL"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := dumpBinaryValue(tc.db, tc.value)
+			if result != tc.expected {
+				t.Fatalf("expected %q, but got %q", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestDumpTables_RestoreBinary(t *testing.T) {
+	source := newDataDiffTestDB(t,
+		"CREATE TABLE files (id INTEGER PRIMARY KEY, content BLOB)",
+		"INSERT INTO files VALUES (1, X'00FF27C35C'), (2, X''), (3, NULL)",
+	)
+	target := newDataDiffTestDB(t)
+
+	var dump bytes.Buffer
+
+	if err := DumpTables(context.Background(), source, "main", nil, &dump, nil); err != nil {
+		t.Fatalf("DumpTables failed: %v", err)
+	}
+
+	if !strings.Contains(dump.String(), "X'00ff27c35c'") {
+		t.Fatalf("expected a hex literal in the dump, but got:\n%s", dump.String())
+	}
+
+	if _, err := RestoreScript(context.Background(), target, dump.String(), nil); err != nil {
+		t.Fatalf("RestoreScript failed: %v\n%s", err, dump.String())
+	}
+
+	rows, err := target.Connection.Query("SELECT id, hex(content), typeof(content) FROM files ORDER BY id")
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	defer rows.Close()
+
+	var result []string
+
+	for rows.Next() {
+		var id, content, contentType string
+		if err := rows.Scan(&id, &content, &contentType); err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+
+		result = append(result, id+":"+content+":"+contentType)
+	}
+
+	expected := []string{"1:00FF27C35C:blob", "2::blob", "3::null"}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("expected %v, but got %v", expected, result)
+	}
+}
+
+func TestRestoreScript_Error(t *testing.T) {
+	db := newDataDiffTestDB(t)
+
+	executed, err := RestoreScript(context.Background(), db, "CREATE TABLE a (id INTEGER); INSERT INTO missing VALUES (1); CREATE TABLE b (id INTEGER)", nil)
+	if err == nil {
+		t.Fatal("expected an error but got nil")
+	}
+
+	if executed != 1 {
+		t.Fatalf("expected 1 executed statement, but got %d", executed)
+	}
+
+	if !strings.HasPrefix(err.Error(), "statement 2:") {
+		t.Fatalf("expected the failing statement in the error, but got %v", err)
+	}
+}
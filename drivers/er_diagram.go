@@ -0,0 +1,536 @@
+package drivers
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+const (
+	// erLayerGap is the number of cells between two columns of tables, the
+	// relation lines are drawn in it.
+	erLayerGap = 10
+	// erTableGap is the number of empty rows between two tables of the same
+	// column.
+	erTableGap = 1
+)
+
+// BuildERDiagram builds the diagram of the given tables and lays it out.
+// Tables are placed in columns from left to right so that every table is on
+// the right of the tables it references, and each column is ordered to keep
+// the tables close to the ones they reference.
+func BuildERDiagram(schemas map[string]models.TableSchema) models.ERDiagram {
+	diagram := models.ERDiagram{}
+
+	names := make([]string, 0, len(schemas))
+	for name := range schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		schema := schemas[name]
+		primaryKey := primaryKeyColumns(schema)
+
+		var foreignKeyColumns []string
+		for _, foreignKey := range schema.ForeignKeys {
+			referencedTable := resolveReferencedTable(names, name, foreignKey.ReferencedTable)
+			if referencedTable == "" {
+				continue
+			}
+
+			foreignKeyColumns = append(foreignKeyColumns, foreignKey.Columns...)
+
+			diagram.Relations = append(diagram.Relations, models.ERRelation{
+				Name:              foreignKey.Name,
+				Table:             name,
+				Columns:           foreignKey.Columns,
+				ReferencedTable:   referencedTable,
+				ReferencedColumns: foreignKey.ReferencedColumns,
+			})
+		}
+
+		table := models.ERTable{Name: name}
+
+		// Primary key columns go first, then the foreign key columns in table
+		// order.
+		for _, column := range primaryKey {
+			table.Columns = append(table.Columns, models.ERColumn{
+				Name:       column,
+				Type:       findColumn(schema.Columns, column).Type,
+				PrimaryKey: true,
+				ForeignKey: slices.Contains(foreignKeyColumns, column),
+			})
+		}
+
+		for _, column := range schema.Columns {
+			if slices.Contains(foreignKeyColumns, column.Name) && !slices.Contains(primaryKey, column.Name) {
+				table.Columns = append(table.Columns, models.ERColumn{Name: column.Name, Type: column.Type, ForeignKey: true})
+			}
+		}
+
+		diagram.Tables = append(diagram.Tables, table)
+	}
+
+	layoutERDiagram(&diagram)
+
+	return diagram
+}
+
+// resolveReferencedTable returns the name of the referenced table as used in
+// the diagram. Some drivers return the referenced table without its schema,
+// the schema of the referencing table is preferred in that case.
+func resolveReferencedTable(names []string, table, referencedTable string) string {
+	if slices.Contains(names, referencedTable) {
+		return referencedTable
+	}
+
+	if split := strings.Split(table, "."); len(split) == 2 {
+		if qualified := split[0] + "." + referencedTable; slices.Contains(names, qualified) {
+			return qualified
+		}
+	}
+
+	for _, name := range names {
+		if tableNameWithoutSchema(name) == referencedTable {
+			return name
+		}
+	}
+
+	return ""
+}
+
+// ERColumnLabel returns the text of a column line in a table box.
+func ERColumnLabel(column models.ERColumn) string {
+	marker := ""
+
+	switch {
+	case column.PrimaryKey && column.ForeignKey:
+		marker = "PK,FK"
+	case column.PrimaryKey:
+		marker = "PK"
+	case column.ForeignKey:
+		marker = "FK"
+	}
+
+	return fmt.Sprintf("%-5s %s", marker, column.Name)
+}
+
+// ERColumnRow returns the row of the diagram a column of the table is drawn
+// on, or the row of the table name if the column is not in the box.
+func ERColumnRow(table models.ERTable, column string) int {
+	for i, tableColumn := range table.Columns {
+		if tableColumn.Name == column {
+			// Border, name and separator come first.
+			return table.Y + 3 + i
+		}
+	}
+
+	return table.Y + 1
+}
+
+func layoutERDiagram(diagram *models.ERDiagram) {
+	positions := map[string]int{}
+	for i, table := range diagram.Tables {
+		positions[table.Name] = i
+
+		width := len(table.Name)
+		for _, column := range table.Columns {
+			width = max(width, len(ERColumnLabel(column)))
+		}
+
+		diagram.Tables[i].Width = width + 4
+		diagram.Tables[i].Height = 3
+		if len(table.Columns) > 0 {
+			diagram.Tables[i].Height += len(table.Columns) + 1
+		}
+	}
+
+	references := map[string][]string{}
+	for _, relation := range diagram.Relations {
+		if relation.Table != relation.ReferencedTable {
+			references[relation.Table] = append(references[relation.Table], relation.ReferencedTable)
+		}
+	}
+
+	// The layer of a table is the length of the longest chain of references
+	// starting from it, tables in a reference cycle are cut at the first
+	// table of the cycle that is visited again.
+	layers := map[string]int{}
+	visiting := map[string]bool{}
+
+	var layer func(table string) int
+	layer = func(table string) int {
+		if value, ok := layers[table]; ok {
+			return value
+		}
+
+		if visiting[table] {
+			return 0
+		}
+
+		visiting[table] = true
+
+		value := 0
+		for _, referenced := range references[table] {
+			value = max(value, layer(referenced)+1)
+		}
+
+		visiting[table] = false
+		layers[table] = value
+
+		return value
+	}
+
+	var columns [][]int
+	for i, table := range diagram.Tables {
+		value := layer(table.Name)
+		for len(columns) <= value {
+			columns = append(columns, nil)
+		}
+		columns[value] = append(columns[value], i)
+	}
+
+	x := 0
+
+	for _, column := range columns {
+		// Tables are ordered by the average row of the tables they reference,
+		// which are all in the previous columns and already placed.
+		center := func(index int) float64 {
+			table := diagram.Tables[index]
+			if len(references[table.Name]) == 0 {
+				return -1
+			}
+
+			total := 0
+			for _, referenced := range references[table.Name] {
+				total += diagram.Tables[positions[referenced]].Y
+			}
+
+			return float64(total) / float64(len(references[table.Name]))
+		}
+
+		sort.SliceStable(column, func(a, b int) bool {
+			return center(column[a]) < center(column[b])
+		})
+
+		y := 0
+		width := 0
+
+		for _, index := range column {
+			diagram.Tables[index].X = x
+			diagram.Tables[index].Y = y
+
+			y += diagram.Tables[index].Height + erTableGap
+			width = max(width, diagram.Tables[index].Width)
+		}
+
+		diagram.Height = max(diagram.Height, y-erTableGap)
+		diagram.Width = x + width
+		x += width + erLayerGap
+	}
+}
+
+// ERDiagramMermaid exports the diagram as a Mermaid erDiagram.
+func ERDiagramMermaid(diagram models.ERDiagram) string {
+	var builder strings.Builder
+
+	builder.WriteString("erDiagram\n")
+
+	for _, table := range diagram.Tables {
+		if len(table.Columns) == 0 {
+			fmt.Fprintf(&builder, "    %s {\n    }\n", mermaidIdentifier(table.Name))
+			continue
+		}
+
+		fmt.Fprintf(&builder, "    %s {\n", mermaidIdentifier(table.Name))
+
+		for _, column := range table.Columns {
+			var keys []string
+			if column.PrimaryKey {
+				keys = append(keys, "PK")
+			}
+			if column.ForeignKey {
+				keys = append(keys, "FK")
+			}
+
+			columnType := column.Type
+			if columnType == "" {
+				columnType = "unknown"
+			}
+
+			fmt.Fprintf(&builder, "        %s %s %s\n", mermaidIdentifier(columnType), mermaidIdentifier(column.Name), strings.Join(keys, ","))
+		}
+
+		builder.WriteString("    }\n")
+	}
+
+	for _, relation := range diagram.Relations {
+		fmt.Fprintf(&builder, "    %s ||--o{ %s : %q\n", mermaidIdentifier(relation.ReferencedTable), mermaidIdentifier(relation.Table), relation.Name)
+	}
+
+	return builder.String()
+}
+
+// mermaidIdentifier replaces the characters Mermaid does not accept in
+// entity, attribute and type names.
+func mermaidIdentifier(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r == '-' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// ERDiagramDOT exports the diagram as a Graphviz digraph, with every table
+// as a record node and an edge from each table to the tables it references.
+func ERDiagramDOT(diagram models.ERDiagram) string {
+	var builder strings.Builder
+
+	builder.WriteString("digraph er {\n")
+	builder.WriteString("    rankdir=RL;\n")
+	builder.WriteString("    node [shape=record];\n")
+
+	for _, table := range diagram.Tables {
+		fields := []string{dotRecordLabel(table.Name)}
+
+		if len(table.Columns) > 0 {
+			var columns []string
+			for _, column := range table.Columns {
+				columns = append(columns, dotRecordLabel(ERColumnLabel(column))+"\\l")
+			}
+			fields = append(fields, strings.Join(columns, ""))
+		}
+
+		fmt.Fprintf(&builder, "    %q [label=\"{%s}\"];\n", table.Name, strings.Join(fields, "|"))
+	}
+
+	for _, relation := range diagram.Relations {
+		fmt.Fprintf(&builder, "    %q -> %q [label=%q];\n", relation.Table, relation.ReferencedTable, relation.Name)
+	}
+
+	builder.WriteString("}\n")
+
+	return builder.String()
+}
+
+// dotRecordLabel escapes the characters that have a meaning in record labels.
+func dotRecordLabel(text string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "{", `\{`, "}", `\}`, "|", `\|`, "<", `\<`, ">", `\>`, " ", `\ `)
+	return replacer.Replace(text)
+}
+
+// Directions of the line segments that meet in a cell of the rendered
+// diagram, combined to pick the box drawing character.
+const (
+	erLineUp = 1 << iota
+	erLineDown
+	erLineLeft
+	erLineRight
+)
+
+var erLineRunes = map[int]rune{
+	erLineUp:                                         '│',
+	erLineDown:                                       '│',
+	erLineUp | erLineDown:                            '│',
+	erLineLeft:                                       '─',
+	erLineRight:                                      '─',
+	erLineLeft | erLineRight:                         '─',
+	erLineDown | erLineRight:                         '┌',
+	erLineDown | erLineLeft:                          '┐',
+	erLineUp | erLineRight:                           '└',
+	erLineUp | erLineLeft:                            '┘',
+	erLineLeft | erLineRight | erLineDown:            '┬',
+	erLineLeft | erLineRight | erLineUp:              '┴',
+	erLineUp | erLineDown | erLineRight:              '├',
+	erLineUp | erLineDown | erLineLeft:               '┤',
+	erLineUp | erLineDown | erLineLeft | erLineRight: '┼',
+}
+
+// RenderERDiagram draws the diagram as lines of text. Relations are drawn
+// from the foreign key column to the referenced column, which is marked with
+// an arrow, and tables are drawn over the lines that cross them.
+func RenderERDiagram(diagram models.ERDiagram) [][]rune {
+	width := diagram.Width + erLayerGap
+	height := diagram.Height
+
+	lines := make([][]int, height)
+	for y := range lines {
+		lines[y] = make([]int, width)
+	}
+
+	horizontal := func(x1, x2, y int) {
+		if x1 > x2 {
+			x1, x2 = x2, x1
+		}
+		for x := x1; x <= x2; x++ {
+			if x > x1 {
+				lines[y][x] |= erLineLeft
+			}
+			if x < x2 {
+				lines[y][x] |= erLineRight
+			}
+		}
+	}
+
+	vertical := func(x, y1, y2 int) {
+		if y1 > y2 {
+			y1, y2 = y2, y1
+		}
+		for y := y1; y <= y2; y++ {
+			if y > y1 {
+				lines[y][x] |= erLineUp
+			}
+			if y < y2 {
+				lines[y][x] |= erLineDown
+			}
+		}
+	}
+
+	tables := map[string]models.ERTable{}
+	for _, table := range diagram.Tables {
+		tables[table.Name] = table
+	}
+
+	type arrow struct {
+		x, y int
+		r    rune
+	}
+
+	var arrows []arrow
+
+	for i, relation := range diagram.Relations {
+		table, referenced := tables[relation.Table], tables[relation.ReferencedTable]
+		if relation.Table == relation.ReferencedTable {
+			continue
+		}
+
+		y := ERColumnRow(table, firstOrEmpty(relation.Columns))
+		referencedY := ERColumnRow(referenced, firstOrEmpty(relation.ReferencedColumns))
+
+		// Each relation gets its own vertical lane in the gap, so that lines
+		// entering the same column are told apart. The lane is in the gap on
+		// the left of the referencing table, so that the long horizontal line
+		// is on the row of the referenced column.
+		lane := 2 + i%((erLayerGap-3)/2)*2
+
+		switch {
+		case table.X > referenced.X:
+			x := referenced.X + referenced.Width
+			middle := table.X - erLayerGap + lane
+
+			horizontal(x, middle, referencedY)
+			vertical(middle, referencedY, y)
+			horizontal(middle, table.X-1, y)
+			arrows = append(arrows, arrow{x, referencedY, '◄'})
+		case table.X < referenced.X:
+			x := table.X + table.Width
+			middle := nextColumnX(diagram, table.X) - erLayerGap + lane
+
+			horizontal(x, middle, y)
+			vertical(middle, y, referencedY)
+			horizontal(middle, referenced.X-1, referencedY)
+			arrows = append(arrows, arrow{referenced.X - 1, referencedY, '►'})
+		default:
+			middle := max(table.X+table.Width, referenced.X+referenced.Width) + lane - 1
+
+			horizontal(table.X+table.Width, middle, y)
+			vertical(middle, y, referencedY)
+			horizontal(referenced.X+referenced.Width, middle, referencedY)
+			arrows = append(arrows, arrow{referenced.X + referenced.Width, referencedY, '◄'})
+		}
+	}
+
+	canvas := make([][]rune, height)
+	for y := range canvas {
+		canvas[y] = make([]rune, width)
+		for x := range canvas[y] {
+			canvas[y][x] = ' '
+			if r, ok := erLineRunes[lines[y][x]]; ok {
+				canvas[y][x] = r
+			}
+		}
+	}
+
+	for _, arrow := range arrows {
+		canvas[arrow.y][arrow.x] = arrow.r
+	}
+
+	for _, table := range diagram.Tables {
+		drawERTable(canvas, table)
+	}
+
+	return canvas
+}
+
+func drawERTable(canvas [][]rune, table models.ERTable) {
+	right := table.X + table.Width - 1
+	bottom := table.Y + table.Height - 1
+
+	write := func(x, y int, text string) {
+		for _, r := range text {
+			canvas[y][x] = r
+			x++
+		}
+	}
+
+	for y := table.Y; y <= bottom; y++ {
+		for x := table.X; x <= right; x++ {
+			canvas[y][x] = ' '
+		}
+		canvas[y][table.X] = '│'
+		canvas[y][right] = '│'
+	}
+
+	for x := table.X; x <= right; x++ {
+		canvas[table.Y][x] = '─'
+		canvas[bottom][x] = '─'
+	}
+
+	canvas[table.Y][table.X] = '┌'
+	canvas[table.Y][right] = '┐'
+	canvas[bottom][table.X] = '└'
+	canvas[bottom][right] = '┘'
+
+	write(table.X+2, table.Y+1, table.Name)
+
+	if len(table.Columns) == 0 {
+		return
+	}
+
+	for x := table.X; x <= right; x++ {
+		canvas[table.Y+2][x] = '─'
+	}
+	canvas[table.Y+2][table.X] = '├'
+	canvas[table.Y+2][right] = '┤'
+
+	for i, column := range table.Columns {
+		write(table.X+2, table.Y+3+i, ERColumnLabel(column))
+	}
+}
+
+// nextColumnX returns the position of the column of tables after the one at
+// x, or the position it would have if it is the last one.
+func nextColumnX(diagram models.ERDiagram, x int) int {
+	next := diagram.Width + erLayerGap
+
+	for _, table := range diagram.Tables {
+		if table.X > x {
+			next = min(next, table.X)
+		}
+	}
+
+	return next
+}
+
+func firstOrEmpty(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
@@ -0,0 +1,140 @@
+package drivers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+func newERDiagramTestSchemas() map[string]models.TableSchema {
+	return map[string]models.TableSchema{
+		"public.users": {
+			Name:        "public.users",
+			Columns:     []models.ColumnSchema{{Name: "id", Type: "integer"}, {Name: "name", Type: "text"}},
+			Constraints: []models.ConstraintSchema{{Name: "users_pkey", Type: "PRIMARY KEY", Columns: []string{"id"}}},
+		},
+		"public.posts": {
+			Name:        "public.posts",
+			Columns:     []models.ColumnSchema{{Name: "id", Type: "integer"}, {Name: "user_id", Type: "integer"}, {Name: "title", Type: "text"}},
+			Constraints: []models.ConstraintSchema{{Name: "posts_pkey", Type: "PRIMARY KEY", Columns: []string{"id"}}},
+			ForeignKeys: []models.ForeignKeySchema{{Name: "posts_user_id_fkey", Columns: []string{"user_id"}, ReferencedTable: "users", ReferencedColumns: []string{"id"}}},
+		},
+		"public.comments": {
+			Name:    "public.comments",
+			Columns: []models.ColumnSchema{{Name: "post_id", Type: "integer"}, {Name: "user_id", Type: "integer"}},
+			ForeignKeys: []models.ForeignKeySchema{
+				{Name: "comments_post_id_fkey", Columns: []string{"post_id"}, ReferencedTable: "public.posts", ReferencedColumns: []string{"id"}},
+				{Name: "comments_user_id_fkey", Columns: []string{"user_id"}, ReferencedTable: "users", ReferencedColumns: []string{"id"}},
+			},
+		},
+		"public.tags": {
+			Name:    "public.tags",
+			Columns: []models.ColumnSchema{{Name: "name", Type: "text"}},
+		},
+	}
+}
+
+func TestBuildERDiagram(t *testing.T) {
+	diagram := BuildERDiagram(newERDiagramTestSchemas())
+
+	tables := map[string]models.ERTable{}
+	for _, table := range diagram.Tables {
+		tables[table.Name] = table
+	}
+
+	if len(diagram.Relations) != 3 {
+		t.Fatalf("expected 3 relations, but got %v", diagram.Relations)
+	}
+
+	for _, relation := range diagram.Relations {
+		if !strings.HasPrefix(relation.ReferencedTable, "public.") {
+			t.Fatalf("expected the referenced table to be resolved, but got %q", relation.ReferencedTable)
+		}
+	}
+
+	users, posts, comments := tables["public.users"], tables["public.posts"], tables["public.comments"]
+
+	if !(users.X < posts.X && posts.X < comments.X) {
+		t.Fatalf("expected every table on the right of the tables it references, but got users %d, posts %d and comments %d", users.X, posts.X, comments.X)
+	}
+
+	if tables["public.tags"].X != 0 || tables["public.tags"].Y == users.Y {
+		t.Fatalf("expected tags below users in the first column, but got %+v", tables["public.tags"])
+	}
+
+	if len(posts.Columns) != 2 || !posts.Columns[0].PrimaryKey || !posts.Columns[1].ForeignKey {
+		t.Fatalf("expected the primary key then the foreign key columns, but got %+v", posts.Columns)
+	}
+
+	if row := ERColumnRow(posts, "user_id"); row != posts.Y+4 {
+		t.Fatalf("expected user_id on row %d, but got %d", posts.Y+4, row)
+	}
+
+	if diagram.Width != comments.X+comments.Width {
+		t.Fatalf("expected the diagram to end with the last column, but got width %d", diagram.Width)
+	}
+}
+
+func TestERDiagramMermaid(t *testing.T) {
+	schemas := newERDiagramTestSchemas()
+	delete(schemas, "public.comments")
+	delete(schemas, "public.tags")
+
+	expected := `erDiagram
+    public_posts {
+        integer id PK
+        integer user_id FK
+    }
+    public_users {
+        integer id PK
+    }
+    public_users ||--o{ public_posts : "posts_user_id_fkey"
+`
+
+	result := ERDiagramMermaid(BuildERDiagram(schemas))
+	if result != expected {
+		t.Fatalf("expected %q, but got %q", expected, result)
+	}
+}
+
+func TestERDiagramDOT(t *testing.T) {
+	result := ERDiagramDOT(BuildERDiagram(newERDiagramTestSchemas()))
+
+	for _, expected := range []string{
+		`"public.users" [label="{public.users|PK\ \ \ \ id\l}"];`,
+		`"public.tags" [label="{public.tags}"];`,
+		`"public.comments" -> "public.posts" [label="comments_post_id_fkey"];`,
+	} {
+		if !strings.Contains(result, expected) {
+			t.Fatalf("expected %q in:\n%s", expected, result)
+		}
+	}
+}
+
+func TestRenderERDiagram(t *testing.T) {
+	schemas := newERDiagramTestSchemas()
+	delete(schemas, "public.comments")
+	delete(schemas, "public.tags")
+
+	expected := []string{
+		"┌──────────────┐          ┌───────────────┐",
+		"│ public.users │          │ public.posts  │",
+		"├──────────────┤          ├───────────────┤",
+		"│ PK    id     │◄─┐       │ PK    id      │",
+		"└──────────────┘  └───────│ FK    user_id │",
+		"                          └───────────────┘",
+	}
+
+	canvas := RenderERDiagram(BuildERDiagram(schemas))
+
+	if len(canvas) != len(expected) {
+		t.Fatalf("expected %d rows, but got %d", len(expected), len(canvas))
+	}
+
+	for i, row := range canvas {
+		if result := strings.TrimRight(string(row), " "); result != expected[i] {
+			t.Fatalf("row %d: expected %q, but got %q", i, expected[i], result)
+		}
+	}
+}
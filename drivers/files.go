@@ -0,0 +1,258 @@
+package drivers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/xo/dburl"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// Files loads CSV, JSON and Parquet files into the tables of an in-memory
+// SQLite database. It reports itself as SQLite so the rest of the app browses
+// it like any other SQLite database. When WriteBack is set, the tables changed
+// through the driver are written back to their files.
+type Files struct {
+	SQLite
+	WriteBack bool
+	tables    map[string]*fileTable
+}
+
+const filesDatabaseName = "files"
+
+func init() {
+	// Registering the scheme lets the connection forms parse files URLs.
+	dburl.Register(dburl.Scheme{
+		Driver: DriverFiles,
+		Generator: func(u *dburl.URL) (string, string, error) {
+			if u.Opaque != "" {
+				return u.Opaque, "", nil
+			}
+			if u.Path == "" {
+				return "", "", dburl.ErrMissingPath
+			}
+			return u.Path, "", nil
+		},
+		Opaque:  true,
+		Aliases: []string{"fs"},
+	})
+}
+
+// FilesURL returns the URL that loads the given files, see ParseFilesURL.
+func FilesURL(paths []string, writeBack bool) string {
+	escaped := make([]string, len(paths))
+
+	for i, path := range paths {
+		escaped[i] = strings.NewReplacer("%", "%25", ",", "%2C", "?", "%3F", "#", "%23").Replace(path)
+	}
+
+	urlstr := fmt.Sprintf("%s:%s", DriverFiles, strings.Join(escaped, ","))
+	if writeBack {
+		urlstr += "?writeback=true"
+	}
+
+	return urlstr
+}
+
+// ParseFilesURL returns the files and the write back option of a URL like
+// "files:data/*.csv,extra.json?writeback=true". Paths are separated by commas
+// and may be glob patterns.
+func ParseFilesURL(urlstr string) ([]string, bool, error) {
+	u, err := url.Parse(urlstr)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if u.Scheme != DriverFiles && u.Scheme != "fs" {
+		return nil, false, fmt.Errorf("unsupported scheme %q", u.Scheme)
+	}
+
+	list := u.Opaque
+	if list == "" {
+		list = u.EscapedPath()
+	}
+
+	var paths []string
+
+	for _, pattern := range strings.Split(list, ",") {
+		if pattern == "" {
+			continue
+		}
+
+		pattern, err := url.PathUnescape(pattern)
+		if err != nil {
+			return nil, false, err
+		}
+
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, false, err
+		}
+
+		switch {
+		case len(matches) > 0:
+			paths = append(paths, matches...)
+		case strings.ContainsAny(pattern, "*?["):
+			return nil, false, fmt.Errorf("no files match %s", pattern)
+		default:
+			// Let the loader report the missing file.
+			paths = append(paths, pattern)
+		}
+	}
+
+	if len(paths) == 0 {
+		return nil, false, errors.New("at least one file is required")
+	}
+
+	writeBack := u.Query().Get("writeback") == "true"
+
+	return paths, writeBack, nil
+}
+
+// IsDataFile reports whether the path has the extension of a file the Files
+// driver can load.
+func IsDataFile(path string) bool {
+	_, err := fileFormat(path)
+	return err == nil
+}
+
+func (db *Files) TestConnection(urlstr string) (err error) {
+	return db.Connect(urlstr)
+}
+
+func (db *Files) Connect(urlstr string) (err error) {
+	db.SetProvider(DriverSqlite)
+
+	paths, writeBack, err := ParseFilesURL(urlstr)
+	if err != nil {
+		return err
+	}
+
+	db.WriteBack = writeBack
+
+	db.Connection, err = sql.Open("sqlite", ":memory:")
+	if err != nil {
+		return err
+	}
+
+	// Every connection to :memory: opens a new empty database.
+	db.Connection.SetMaxOpenConns(1)
+
+	db.tables = map[string]*fileTable{}
+
+	for _, path := range paths {
+		table, err := loadFileTable(db.Connection, path, db.tableName(path))
+		if err != nil {
+			db.Connection.Close()
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		db.tables[table.Name] = table
+	}
+
+	return nil
+}
+
+func (db *Files) GetDatabases() ([]string, error) {
+	return append([]string{filesDatabaseName}, db.attached...), nil
+}
+
+func (db *Files) UpdateRecord(database, table, column, value, primaryKeyColumnName, primaryKeyValue string) error {
+	if err := db.SQLite.UpdateRecord(database, table, column, value, primaryKeyColumnName, primaryKeyValue); err != nil {
+		return err
+	}
+
+	return db.writeBack(table)
+}
+
+func (db *Files) DeleteRecord(database, table, primaryKeyColumnName, primaryKeyValue string) error {
+	if err := db.SQLite.DeleteRecord(database, table, primaryKeyColumnName, primaryKeyValue); err != nil {
+		return err
+	}
+
+	return db.writeBack(table)
+}
+
+func (db *Files) ExecuteDMLStatement(query string) (result string, err error) {
+	result, err = db.SQLite.ExecuteDMLStatement(query)
+	if err != nil {
+		return "", err
+	}
+
+	// Any table may have been changed by the statement.
+	tables := make([]string, 0, len(db.tables))
+	for table := range db.tables {
+		tables = append(tables, table)
+	}
+
+	return result, db.writeBack(tables...)
+}
+
+func (db *Files) ExecutePendingChanges(changes []models.DBDMLChange) error {
+	if err := db.SQLite.ExecutePendingChanges(changes); err != nil {
+		return err
+	}
+
+	var tables []string
+	for _, change := range changes {
+		if !slices.Contains(tables, change.Table) {
+			tables = append(tables, change.Table)
+		}
+	}
+
+	return db.writeBack(tables...)
+}
+
+// writeBack writes the given tables to the files they were loaded from, the
+// tables that were not loaded from a file are skipped.
+func (db *Files) writeBack(tables ...string) error {
+	if !db.WriteBack {
+		return nil
+	}
+
+	slices.Sort(tables)
+
+	for _, name := range tables {
+		table, ok := db.tables[name]
+		if !ok {
+			continue
+		}
+
+		if err := table.write(db.Connection); err != nil {
+			return fmt.Errorf("could not write %s: %w", table.Path, err)
+		}
+	}
+
+	return nil
+}
+
+// tableName returns a unique table name made of the file name without its
+// extension.
+func (db *Files) tableName(path string) string {
+	base := filepath.Base(path)
+	base = strings.TrimSuffix(base, filepath.Ext(base))
+
+	name := strings.Map(func(r rune) rune {
+		if r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' {
+			return r
+		}
+		return '_'
+	}, base)
+
+	if name == "" {
+		name = "file"
+	}
+
+	unique := name
+	for i := 2; db.tables[unique] != nil; i++ {
+		unique = fmt.Sprintf("%s_%d", name, i)
+	}
+
+	return unique
+}
@@ -0,0 +1,811 @@
+package drivers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/compress"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+)
+
+// File formats.
+const (
+	fileFormatCSV     = "csv"
+	fileFormatTSV     = "tsv"
+	fileFormatJSON    = "json"
+	fileFormatNDJSON  = "ndjson"
+	fileFormatParquet = "parquet"
+)
+
+// Column types of the loaded tables. Dates are kept as TEXT, the SQLite
+// driver would return DATE columns as Go times.
+const (
+	fileColumnInteger = "INTEGER"
+	fileColumnReal    = "REAL"
+	fileColumnBoolean = "BOOLEAN"
+	fileColumnText    = "TEXT"
+	fileColumnJSON    = "JSON"
+)
+
+var fileNumberRegexp = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][-+]?[0-9]+)?$`)
+
+// fileTable is a file loaded into a table.
+type fileTable struct {
+	Name    string
+	Path    string
+	Format  string
+	Columns []fileColumn
+
+	// parquetSchema keeps the Parquet types to write the file back.
+	parquetSchema *arrow.Schema
+}
+
+type fileColumn struct {
+	Name string
+	Type string
+}
+
+// fileFormat returns the format of a file from its extension.
+func fileFormat(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return fileFormatCSV, nil
+	case ".tsv":
+		return fileFormatTSV, nil
+	case ".json":
+		return fileFormatJSON, nil
+	case ".jsonl", ".ndjson":
+		return fileFormatNDJSON, nil
+	case ".parquet":
+		return fileFormatParquet, nil
+	}
+
+	return "", fmt.Errorf("unsupported file type %q", filepath.Ext(path))
+}
+
+// loadFileTable reads a file and creates the table holding its rows. The
+// values read are nil, strings, json.Number, bools or json.RawMessage for
+// nested JSON values, the column types are inferred from them.
+func loadFileTable(connection *sql.DB, path, name string) (*fileTable, error) {
+	format, err := fileFormat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	table := &fileTable{Name: name, Path: path, Format: format}
+
+	var rows [][]any
+
+	switch format {
+	case fileFormatCSV:
+		table.Columns, rows, err = readDelimitedFile(path, ',')
+	case fileFormatTSV:
+		table.Columns, rows, err = readDelimitedFile(path, '\t')
+	case fileFormatJSON, fileFormatNDJSON:
+		table.Columns, rows, table.Format, err = readJSONFile(path)
+	case fileFormatParquet:
+		table.Columns, rows, table.parquetSchema, err = readParquetFile(path)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(table.Columns) == 0 {
+		return nil, errors.New("the file has no columns")
+	}
+
+	for i, column := range table.Columns {
+		if column.Type != "" {
+			continue
+		}
+
+		values := make([]any, len(rows))
+		for j, row := range rows {
+			values[j] = row[i]
+		}
+
+		table.Columns[i].Type = inferColumnType(values)
+	}
+
+	return table, table.create(connection, rows)
+}
+
+func (table *fileTable) create(connection *sql.DB, rows [][]any) error {
+	sqlite := &SQLite{}
+
+	definitions := make([]string, len(table.Columns))
+	placeholders := make([]string, len(table.Columns))
+
+	for i, column := range table.Columns {
+		definitions[i] = fmt.Sprintf("%s %s", sqlite.FormatReference(column.Name), column.Type)
+		placeholders[i] = "?"
+	}
+
+	tx, err := connection.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(fmt.Sprintf("CREATE TABLE %s (%s)", sqlite.formatTableName(table.Name), strings.Join(definitions, ", "))); err != nil {
+		return err
+	}
+
+	statement, err := tx.Prepare(fmt.Sprintf("INSERT INTO %s VALUES (%s)", sqlite.formatTableName(table.Name), strings.Join(placeholders, ", ")))
+	if err != nil {
+		return err
+	}
+
+	defer statement.Close()
+
+	args := make([]any, len(table.Columns))
+
+	for _, row := range rows {
+		for i, column := range table.Columns {
+			args[i] = storedFileValue(row[i], column.Type)
+		}
+
+		if _, err := statement.Exec(args...); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// write replaces the file with the rows of the table.
+func (table *fileTable) write(connection *sql.DB) error {
+	rows, err := connection.Query("SELECT * FROM " + (&SQLite{}).formatTableName(table.Name))
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	names, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	// Columns added with the SQL editor are written as text.
+	columns := make([]fileColumn, len(names))
+	for i, name := range names {
+		columns[i] = fileColumn{Name: name, Type: fileColumnText}
+
+		for _, column := range table.Columns {
+			if column.Name == name {
+				columns[i].Type = column.Type
+			}
+		}
+	}
+
+	var records [][]any
+
+	for rows.Next() {
+		record := make([]any, len(columns))
+		pointers := make([]any, len(columns))
+		for i := range record {
+			pointers[i] = &record[i]
+		}
+
+		if err := rows.Scan(pointers...); err != nil {
+			return err
+		}
+
+		records = append(records, record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	return replaceFile(table.Path, func(w io.Writer) error {
+		switch table.Format {
+		case fileFormatCSV:
+			return writeDelimitedFile(w, ',', columns, records)
+		case fileFormatTSV:
+			return writeDelimitedFile(w, '\t', columns, records)
+		case fileFormatJSON, fileFormatNDJSON:
+			return writeJSONFile(w, table.Format == fileFormatJSON, columns, records)
+		case fileFormatParquet:
+			return writeParquetFile(w, table.parquetSchema, columns, records)
+		}
+
+		return fmt.Errorf("unsupported file format %q", table.Format)
+	})
+}
+
+// replaceFile writes a temporary file next to path and renames it, a failed
+// write leaves the original file untouched.
+func replaceFile(path string, write func(w io.Writer) error) error {
+	temp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+
+	buffer := bufio.NewWriter(temp)
+
+	err = write(buffer)
+	if err == nil {
+		err = buffer.Flush()
+	}
+
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		if info, statErr := os.Stat(path); statErr == nil {
+			err = os.Chmod(temp.Name(), info.Mode())
+		}
+	}
+
+	if err == nil {
+		err = os.Rename(temp.Name(), path)
+	}
+
+	if err != nil {
+		os.Remove(temp.Name())
+	}
+
+	return err
+}
+
+// inferColumnType returns the narrowest type holding every value, integers
+// and reals mix into REAL and any other mix is TEXT.
+func inferColumnType(values []any) string {
+	columnType := ""
+
+	for _, value := range values {
+		valueType := ""
+
+		switch v := value.(type) {
+		case nil:
+			continue
+		case json.Number:
+			valueType = fileColumnReal
+			if _, err := strconv.ParseInt(v.String(), 10, 64); err == nil {
+				valueType = fileColumnInteger
+			}
+		case bool:
+			valueType = fileColumnBoolean
+		case json.RawMessage:
+			valueType = fileColumnJSON
+		default:
+			return fileColumnText
+		}
+
+		switch {
+		case columnType == "" || columnType == valueType:
+			columnType = valueType
+		case (columnType == fileColumnInteger || columnType == fileColumnReal) && (valueType == fileColumnInteger || valueType == fileColumnReal):
+			columnType = fileColumnReal
+		default:
+			return fileColumnText
+		}
+	}
+
+	if columnType == "" {
+		return fileColumnText
+	}
+
+	return columnType
+}
+
+// storedFileValue converts a value read from a file to the value stored in a
+// column of the given type, booleans are stored as 1 and 0.
+func storedFileValue(value any, columnType string) any {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case json.Number:
+		switch columnType {
+		case fileColumnInteger:
+			if n, err := v.Int64(); err == nil {
+				return n
+			}
+		case fileColumnReal:
+			if n, err := v.Float64(); err == nil {
+				return n
+			}
+		}
+		return v.String()
+	case bool:
+		if columnType == fileColumnBoolean {
+			if v {
+				return int64(1)
+			}
+			return int64(0)
+		}
+		return strconv.FormatBool(v)
+	case json.RawMessage:
+		return string(v)
+	default:
+		return v
+	}
+}
+
+// fileValueText returns the text written to a file for a value of a column.
+func fileValueText(value any, columnType string) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case int64:
+		if columnType == fileColumnBoolean {
+			return strconv.FormatBool(v != 0)
+		}
+		return strconv.FormatInt(v, 10)
+	case float64:
+		// encoding/json avoids exponents for the usual magnitudes.
+		if b, err := json.Marshal(v); err == nil {
+			return string(b)
+		}
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case []byte:
+		return string(v)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// textFileValue parses a CSV cell, empty cells are NULL.
+func textFileValue(text string) any {
+	switch {
+	case text == "":
+		return nil
+	case text == "true":
+		return true
+	case text == "false":
+		return false
+	case fileNumberRegexp.MatchString(text):
+		return json.Number(text)
+	}
+
+	return text
+}
+
+func readDelimitedFile(path string, delimiter rune) ([]fileColumn, [][]any, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+
+	columns := fileColumns(header)
+
+	var rows [][]any
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		row := make([]any, len(columns))
+		for i := range min(len(record), len(columns)) {
+			row[i] = textFileValue(record[i])
+		}
+
+		rows = append(rows, row)
+	}
+
+	return columns, rows, nil
+}
+
+// fileColumns names the columns of a header, empty and repeated names are
+// made unique.
+func fileColumns(names []string) []fileColumn {
+	columns := make([]fileColumn, len(names))
+	seen := map[string]bool{}
+
+	for i, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			name = fmt.Sprintf("column%d", i+1)
+		}
+
+		unique := name
+		for n := 2; seen[unique]; n++ {
+			unique = fmt.Sprintf("%s_%d", name, n)
+		}
+
+		seen[unique] = true
+		columns[i] = fileColumn{Name: unique}
+	}
+
+	return columns
+}
+
+func writeDelimitedFile(w io.Writer, delimiter rune, columns []fileColumn, records [][]any) error {
+	writer := csv.NewWriter(w)
+	writer.Comma = delimiter
+
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = column.Name
+	}
+
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		row := make([]string, len(columns))
+		for i, column := range columns {
+			row[i] = fileValueText(record[i], column.Type)
+		}
+
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
+
+// readJSONFile reads an array of objects or one object per line, the format
+// found is returned so the file is written back the same way.
+func readJSONFile(path string) ([]fileColumn, [][]any, string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	content = bytes.TrimPrefix(content, []byte("\ufeff"))
+
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+
+	format := fileFormatNDJSON
+
+	if trimmed := bytes.TrimSpace(content); len(trimmed) > 0 && trimmed[0] == '[' {
+		format = fileFormatJSON
+
+		if _, err := decoder.Token(); err != nil {
+			return nil, nil, "", err
+		}
+	}
+
+	var names []string
+	indexes := map[string]int{}
+
+	var objects []map[string]any
+
+	for decoder.More() {
+		object, keys, err := readJSONObject(decoder)
+		if err != nil {
+			return nil, nil, "", err
+		}
+
+		for _, key := range keys {
+			if _, ok := indexes[key]; !ok {
+				indexes[key] = len(names)
+				names = append(names, key)
+			}
+		}
+
+		objects = append(objects, object)
+	}
+
+	columns := make([]fileColumn, len(names))
+	for i, name := range names {
+		columns[i] = fileColumn{Name: name}
+	}
+
+	rows := make([][]any, len(objects))
+
+	for i, object := range objects {
+		rows[i] = make([]any, len(columns))
+		for key, value := range object {
+			rows[i][indexes[key]] = value
+		}
+	}
+
+	return columns, rows, format, nil
+}
+
+// readJSONObject reads the next object and returns its values with the keys
+// in the order of the file.
+func readJSONObject(decoder *json.Decoder) (map[string]any, []string, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return nil, nil, fmt.Errorf("expected an object at offset %d", decoder.InputOffset())
+	}
+
+	object := map[string]any{}
+
+	var keys []string
+
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		key := token.(string)
+
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return nil, nil, err
+		}
+
+		var value any
+
+		switch raw[0] {
+		case '{', '[':
+			var compact bytes.Buffer
+			if err := json.Compact(&compact, raw); err != nil {
+				return nil, nil, err
+			}
+			value = json.RawMessage(compact.Bytes())
+		case 'n':
+			value = nil
+		case 't', 'f':
+			value = raw[0] == 't'
+		case '"':
+			var text string
+			if err := json.Unmarshal(raw, &text); err != nil {
+				return nil, nil, err
+			}
+			value = text
+		default:
+			value = json.Number(raw)
+		}
+
+		if _, ok := object[key]; !ok {
+			keys = append(keys, key)
+		}
+
+		object[key] = value
+	}
+
+	// Closing brace.
+	if _, err := decoder.Token(); err != nil {
+		return nil, nil, err
+	}
+
+	return object, keys, nil
+}
+
+func writeJSONFile(w io.Writer, array bool, columns []fileColumn, records [][]any) error {
+	if array {
+		if len(records) == 0 {
+			_, err := io.WriteString(w, "[]\n")
+			return err
+		}
+
+		if _, err := io.WriteString(w, "[\n"); err != nil {
+			return err
+		}
+	}
+
+	for i, record := range records {
+		var object bytes.Buffer
+
+		object.WriteString("{")
+
+		for j, column := range columns {
+			if j > 0 {
+				object.WriteString(",")
+			}
+
+			key, _ := json.Marshal(column.Name)
+			object.Write(key)
+			object.WriteString(":")
+
+			value, err := jsonFileValue(record[j], column.Type)
+			if err != nil {
+				return err
+			}
+			object.Write(value)
+		}
+
+		object.WriteString("}")
+
+		line := object.String()
+
+		switch {
+		case !array:
+			line += "\n"
+		case i < len(records)-1:
+			line = "  " + line + ",\n"
+		default:
+			line = "  " + line + "\n]\n"
+		}
+
+		if _, err := io.WriteString(w, line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func jsonFileValue(value any, columnType string) ([]byte, error) {
+	switch v := value.(type) {
+	case int64:
+		if columnType == fileColumnBoolean {
+			return json.Marshal(v != 0)
+		}
+	case []byte:
+		value = string(v)
+	}
+
+	if text, ok := value.(string); ok && columnType == fileColumnJSON && json.Valid([]byte(text)) {
+		return []byte(text), nil
+	}
+
+	return json.Marshal(value)
+}
+
+func readParquetFile(path string) ([]fileColumn, [][]any, *arrow.Schema, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	defer file.Close()
+
+	table, err := pqarrow.ReadTable(context.Background(), file, parquet.NewReaderProperties(memory.DefaultAllocator), pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	defer table.Release()
+
+	schema := table.Schema()
+
+	columns := make([]fileColumn, len(schema.Fields()))
+	rows := make([][]any, table.NumRows())
+
+	for i := range rows {
+		rows[i] = make([]any, len(columns))
+	}
+
+	for i, field := range schema.Fields() {
+		columns[i] = fileColumn{Name: field.Name, Type: parquetColumnType(field.Type)}
+
+		row := 0
+
+		for _, chunk := range table.Column(i).Data().Chunks() {
+			for j := 0; j < chunk.Len(); j++ {
+				rows[row][i] = parquetValue(chunk, j, columns[i].Type)
+				row++
+			}
+		}
+	}
+
+	return columns, rows, schema, nil
+}
+
+func parquetColumnType(dataType arrow.DataType) string {
+	switch dataType.ID() {
+	case arrow.INT8, arrow.INT16, arrow.INT32, arrow.INT64, arrow.UINT8, arrow.UINT16, arrow.UINT32, arrow.UINT64:
+		return fileColumnInteger
+	case arrow.FLOAT16, arrow.FLOAT32, arrow.FLOAT64:
+		return fileColumnReal
+	case arrow.BOOL:
+		return fileColumnBoolean
+	case arrow.LIST, arrow.LARGE_LIST, arrow.FIXED_SIZE_LIST, arrow.STRUCT, arrow.MAP:
+		return fileColumnJSON
+	}
+
+	return fileColumnText
+}
+
+func parquetValue(values arrow.Array, i int, columnType string) any {
+	if values.IsNull(i) {
+		return nil
+	}
+
+	switch columnType {
+	case fileColumnInteger, fileColumnReal:
+		return json.Number(values.ValueStr(i))
+	case fileColumnBoolean:
+		return values.ValueStr(i) == "true"
+	case fileColumnJSON:
+		if raw, err := json.Marshal(values.GetOneForMarshal(i)); err == nil {
+			return json.RawMessage(raw)
+		}
+	}
+
+	return values.ValueStr(i)
+}
+
+// writeParquetFile writes the records with the types the file was read with,
+// columns added with the SQL editor are written as strings.
+func writeParquetFile(w io.Writer, schema *arrow.Schema, columns []fileColumn, records [][]any) error {
+	fields := make([]arrow.Field, len(columns))
+
+	for i, column := range columns {
+		fields[i] = arrow.Field{Name: column.Name, Type: arrow.BinaryTypes.String, Nullable: true}
+
+		if schema != nil {
+			if index := schema.FieldIndices(column.Name); len(index) > 0 {
+				fields[i] = schema.Field(index[0])
+			}
+		}
+	}
+
+	writeSchema := arrow.NewSchema(fields, nil)
+
+	builder := array.NewRecordBuilder(memory.DefaultAllocator, writeSchema)
+	defer builder.Release()
+
+	for _, record := range records {
+		for i, column := range columns {
+			if record[i] == nil {
+				builder.Field(i).AppendNull()
+				continue
+			}
+
+			if err := builder.Field(i).AppendValueFromString(fileValueText(record[i], column.Type)); err != nil {
+				return fmt.Errorf("column %s: %w", column.Name, err)
+			}
+		}
+	}
+
+	record := builder.NewRecord()
+	defer record.Release()
+
+	table := array.NewTableFromRecords(writeSchema, []arrow.Record{record})
+	defer table.Release()
+
+	// The writer closes its output, the buffer keeps w open.
+	var buffer bytes.Buffer
+
+	properties := parquet.NewWriterProperties(parquet.WithCompression(compress.Codecs.Snappy))
+
+	if err := pqarrow.WriteTable(table, &buffer, max(record.NumRows(), 1), properties, pqarrow.DefaultWriterProps()); err != nil {
+		return err
+	}
+
+	_, err := buffer.WriteTo(w)
+
+	return err
+}
@@ -0,0 +1,234 @@
+package drivers
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+func TestParseFilesURL(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, name := range []string{"a.csv", "b.csv", "c,d.json"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	paths, writeBack, err := ParseFilesURL(FilesURL([]string{filepath.Join(dir, "*.csv"), filepath.Join(dir, "c,d.json")}, true))
+	if err != nil {
+		t.Fatalf("ParseFilesURL failed: %v", err)
+	}
+
+	expected := []string{filepath.Join(dir, "a.csv"), filepath.Join(dir, "b.csv"), filepath.Join(dir, "c,d.json")}
+	if !reflect.DeepEqual(paths, expected) || !writeBack {
+		t.Fatalf("expected %v with write back, but got %v and %v", expected, paths, writeBack)
+	}
+
+	if _, _, err := ParseFilesURL("files:" + filepath.Join(dir, "*.parquet")); err == nil {
+		t.Fatal("expected an error when no file matches")
+	}
+}
+
+func TestInferColumnType(t *testing.T) {
+	testCases := []struct {
+		name     string
+		values   []any
+		expected string
+	}{
+		{name: "Integers", values: []any{json.Number("1"), nil, json.Number("-20")}, expected: fileColumnInteger},
+		{name: "Integers and reals", values: []any{json.Number("1"), json.Number("2.5")}, expected: fileColumnReal},
+		{name: "Booleans", values: []any{true, false}, expected: fileColumnBoolean},
+		{name: "Nested JSON", values: []any{json.RawMessage(`{"a":1}`)}, expected: fileColumnJSON},
+		{name: "Mixed", values: []any{json.Number("1"), true}, expected: fileColumnText},
+		{name: "Text", values: []any{json.Number("1"), "007"}, expected: fileColumnText},
+		{name: "Only NULL", values: []any{nil}, expected: fileColumnText},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if result := inferColumnType(tc.values); result != tc.expected {
+				t.Fatalf("expected %s, but got %s", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestFiles_CSV(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "users 2024.csv")
+
+	content := "id,zip,score,active,name\n1,007,1.5,true,Alice\n2,10001,2,false,\"Bob, Jr.\"\n3,,,,\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	db := &Files{}
+	if err := db.Connect(FilesURL([]string{path}, true)); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer db.Close()
+
+	if db.GetProvider() != DriverSqlite {
+		t.Fatalf("expected the files to be browsed as SQLite, but got %s", db.GetProvider())
+	}
+
+	tables, err := db.GetTables(filesDatabaseName)
+	if err != nil {
+		t.Fatalf("GetTables failed: %v", err)
+	}
+
+	if expected := map[string][]string{filesDatabaseName: {"users_2024"}}; !reflect.DeepEqual(tables, expected) {
+		t.Fatalf("expected %v, but got %v", expected, tables)
+	}
+
+	columns, err := db.GetTableColumns(filesDatabaseName, "users_2024")
+	if err != nil {
+		t.Fatalf("GetTableColumns failed: %v", err)
+	}
+
+	var types []string
+	for _, column := range columns[1:] {
+		types = append(types, column[1])
+	}
+
+	if expected := []string{"INTEGER", "TEXT", "REAL", "BOOLEAN", "TEXT"}; !reflect.DeepEqual(types, expected) {
+		t.Fatalf("expected the types %v, but got %v", expected, types)
+	}
+
+	records, _, err := db.ExecuteQuery("SELECT id, zip, score FROM users_2024 WHERE active = true")
+	if err != nil {
+		t.Fatalf("ExecuteQuery failed: %v", err)
+	}
+
+	if expected := [][]string{{"id", "zip", "score"}, {"1", "007", "1.5"}}; !reflect.DeepEqual(records, expected) {
+		t.Fatalf("expected %v, but got %v", expected, records)
+	}
+
+	if _, err := db.ExecuteDMLStatement("UPDATE users_2024 SET score = score * 2, active = NOT active WHERE id = 2"); err != nil {
+		t.Fatalf("ExecuteDMLStatement failed: %v", err)
+	}
+
+	written, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "id,zip,score,active,name\n1,007,1.5,true,Alice\n2,10001,4,true,\"Bob, Jr.\"\n3,,,,\n"
+	if string(written) != expected {
+		t.Fatalf("expected %q, but got %q", expected, string(written))
+	}
+}
+
+func TestFiles_JSON(t *testing.T) {
+	dir := t.TempDir()
+
+	arrayPath := filepath.Join(dir, "orders.json")
+	linesPath := filepath.Join(dir, "events.jsonl")
+
+	if err := os.WriteFile(arrayPath, []byte(`[{"id": 1, "tags": ["a", "b"], "total": 9.5}, {"id": 2, "note": "late", "total": null}]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(linesPath, []byte("{\"type\":\"click\",\"ok\":true}\n{\"type\":\"view\",\"ok\":false}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	db := &Files{}
+	if err := db.Connect(FilesURL([]string{arrayPath, linesPath}, true)); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer db.Close()
+
+	records, _, err := db.GetRecords(filesDatabaseName, "orders", "", "", 0, DefaultRowLimit)
+	if err != nil {
+		t.Fatalf("GetRecords failed: %v", err)
+	}
+
+	expected := [][]string{
+		{"id", "tags", "total", "note", "rowid"},
+		{"1", `["a","b"]`, "9.5", "NULL&", "1"},
+		{"2", "NULL&", "NULL&", "late", "2"},
+	}
+
+	if !reflect.DeepEqual(records, expected) {
+		t.Fatalf("expected %v, but got %v", expected, records)
+	}
+
+	if _, err := db.ExecuteDMLStatement("DELETE FROM events WHERE NOT ok"); err != nil {
+		t.Fatalf("ExecuteDMLStatement failed: %v", err)
+	}
+
+	for path, expected := range map[string]string{
+		arrayPath: "[\n  {\"id\":1,\"tags\":[\"a\",\"b\"],\"total\":9.5,\"note\":null},\n  {\"id\":2,\"tags\":null,\"total\":null,\"note\":\"late\"}\n]\n",
+		linesPath: "{\"type\":\"click\",\"ok\":true}\n",
+	} {
+		written, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(written) != expected {
+			t.Fatalf("%s: expected %q, but got %q", path, expected, string(written))
+		}
+	}
+}
+
+func TestFiles_Parquet(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.parquet")
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "day", Type: arrow.FixedWidthTypes.Date32, Nullable: true},
+		{Name: "visits", Type: arrow.PrimitiveTypes.Int32, Nullable: true},
+		{Name: "ratio", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+	}, nil)
+
+	columns := []fileColumn{{Name: "day", Type: fileColumnText}, {Name: "visits", Type: fileColumnInteger}, {Name: "ratio", Type: fileColumnReal}}
+
+	var buffer bytes.Buffer
+	if err := writeParquetFile(&buffer, schema, columns, [][]any{{"2024-01-01", int64(10), 0.5}, {"2024-01-02", nil, 0.25}}); err != nil {
+		t.Fatalf("writeParquetFile failed: %v", err)
+	}
+
+	if err := os.WriteFile(path, buffer.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	db := &Files{}
+	if err := db.Connect(FilesURL([]string{path}, true)); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.ExecuteDMLStatement("UPDATE metrics SET visits = 20 WHERE day = '2024-01-02'"); err != nil {
+		t.Fatalf("ExecuteDMLStatement failed: %v", err)
+	}
+
+	readColumns, rows, readSchema, err := readParquetFile(path)
+	if err != nil {
+		t.Fatalf("readParquetFile failed: %v", err)
+	}
+
+	for i, field := range readSchema.Fields() {
+		if !arrow.TypeEqual(field.Type, schema.Field(i).Type) {
+			t.Fatalf("expected %s to keep the type %s, but got %s", field.Name, schema.Field(i).Type, field.Type)
+		}
+	}
+
+	if !reflect.DeepEqual(readColumns, columns) {
+		t.Fatalf("expected %v, but got %v", columns, readColumns)
+	}
+
+	expected := [][]any{
+		{"2024-01-01", json.Number("10"), json.Number("0.5")},
+		{"2024-01-02", json.Number("20"), json.Number("0.25")},
+	}
+
+	if !reflect.DeepEqual(rows, expected) {
+		t.Fatalf("expected %v, but got %v", expected, rows)
+	}
+}
@@ -85,7 +85,15 @@ func (db *MSSQL) GetTables(database string) (map[string][]string, error) {
 
 	tables := make(map[string][]string)
 
-	query := `SELECT name FROM sys.tables`
+	query := fmt.Sprintf(`
+		SELECT
+			s.name AS schema_name,
+			t.name AS table_name
+		FROM %[1]s.sys.tables t
+		INNER JOIN %[1]s.sys.schemas s
+			ON t.schema_id = s.schema_id
+		ORDER BY s.name, t.name
+	`, db.FormatReference(database))
 	rows, err := db.Connection.Query(query)
 	if err != nil {
 		return nil, err
@@ -94,12 +102,12 @@ func (db *MSSQL) GetTables(database string) (map[string][]string, error) {
 	defer rows.Close()
 
 	for rows.Next() {
-		var table string
-		if err := rows.Scan(&table); err != nil {
+		var schema, table string
+		if err := rows.Scan(&schema, &table); err != nil {
 			return nil, err
 		}
 
-		tables[database] = append(tables[database], table)
+		tables[schema] = append(tables[schema], table)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -113,46 +121,43 @@ func (db *MSSQL) GetTableColumns(database, table string) ([][]string, error) {
 	query := `
         SELECT
             c.name AS column_name,
-            t.name AS data_type,
+            ty.name AS data_type,
             c.is_nullable,
             def.definition AS column_default
-        FROM sys.columns c
-        INNER JOIN sys.types t ON c.system_type_id = t.system_type_id
-        LEFT JOIN sys.default_constraints def ON c.default_object_id = def.parent_column_id
-        WHERE c.object_id = OBJECT_ID(@p2)
-        AND t.name <> 'sysname'
+        FROM %[1]s.sys.columns c
+        INNER JOIN %[1]s.sys.tables t ON c.object_id = t.object_id
+        INNER JOIN %[1]s.sys.schemas s ON t.schema_id = s.schema_id
+        INNER JOIN %[1]s.sys.types ty ON c.user_type_id = ty.user_type_id
+        LEFT JOIN %[1]s.sys.default_constraints def ON c.default_object_id = def.object_id
+        WHERE t.name = @p2
+          AND s.name = @p3
         ORDER BY c.column_id;
     `
-	return db.getTableInformations(query, database, table, "")
+	return db.getTableInformations(query, database, table)
 }
 
-func (db *MSSQL) GetConstraints(_, table string) ([][]string, error) {
-	currentSchema, err := db.getCurrentSchema()
-	if err != nil {
-		return nil, err
-	}
-
+func (db *MSSQL) GetConstraints(database, table string) ([][]string, error) {
 	query := `
         SELECT 
             kc.name AS constraint_name,
             c.name AS column_name,
             kc.type_desc AS constraint_type
-        FROM sys.key_constraints kc
-        INNER JOIN sys.tables t 
+        FROM %[1]s.sys.key_constraints kc
+        INNER JOIN %[1]s.sys.tables t 
             ON kc.parent_object_id = t.object_id
-        INNER JOIN sys.schemas s 
+        INNER JOIN %[1]s.sys.schemas s 
             ON t.schema_id = s.schema_id
-        INNER JOIN sys.index_columns ic 
+        INNER JOIN %[1]s.sys.index_columns ic 
             ON kc.unique_index_id = ic.index_id 
             AND kc.parent_object_id = ic.object_id
-        INNER JOIN sys.columns c 
+        INNER JOIN %[1]s.sys.columns c 
             ON ic.column_id = c.column_id 
             AND ic.object_id = c.object_id
-        WHERE s.name = @p1
-          AND t.name = @p2
+        WHERE t.name = @p2
+          AND s.name = @p3
           AND kc.type IN ('PK', 'UQ')  -- Primary keys and unique constraints
     `
-	return db.getTableInformations(query, currentSchema, table, "")
+	return db.getTableInformations(query, database, table)
 }
 
 func (db *MSSQL) GetForeignKeys(database, table string) ([][]string, error) {
@@ -160,37 +165,32 @@ func (db *MSSQL) GetForeignKeys(database, table string) ([][]string, error) {
         SELECT 
             fk.name AS constraint_name,
             c.name AS column_name,
-            DB_NAME(DB_ID(@p1)) AS current_database,
+            @p1 AS current_database,
             OBJECT_SCHEMA_NAME(fk.referenced_object_id, DB_ID(@p1)) + '.' + 
             OBJECT_NAME(fk.referenced_object_id, DB_ID(@p1)) AS referenced_table,
             rc.name AS referenced_column,
             fk.delete_referential_action_desc AS delete_rule,
             fk.update_referential_action_desc AS update_rule
-        FROM sys.foreign_keys fk
-        INNER JOIN sys.foreign_key_columns fkc 
+        FROM %[1]s.sys.foreign_keys fk
+        INNER JOIN %[1]s.sys.foreign_key_columns fkc 
             ON fk.object_id = fkc.constraint_object_id
-        INNER JOIN sys.columns c 
+        INNER JOIN %[1]s.sys.columns c 
             ON fkc.parent_column_id = c.column_id 
             AND fkc.parent_object_id = c.object_id
-        INNER JOIN sys.columns rc 
+        INNER JOIN %[1]s.sys.columns rc 
             ON fkc.referenced_column_id = rc.column_id 
             AND fkc.referenced_object_id = rc.object_id
-        INNER JOIN sys.tables t 
+        INNER JOIN %[1]s.sys.tables t 
             ON fk.parent_object_id = t.object_id
-        INNER JOIN sys.schemas s 
+        INNER JOIN %[1]s.sys.schemas s 
             ON t.schema_id = s.schema_id
         WHERE t.name = @p2
-          AND DB_NAME(DB_ID(@p1)) = @p1
+          AND s.name = @p3
     `
-	return db.getTableInformations(query, database, table, "")
+	return db.getTableInformations(query, database, table)
 }
 
 func (db *MSSQL) GetIndexes(database, table string) ([][]string, error) {
-	currentSchema, err := db.getCurrentSchema()
-	if err != nil {
-		return nil, err
-	}
-
 	query := `
         SELECT
             t.name AS table_name,
@@ -203,25 +203,22 @@ func (db *MSSQL) GetIndexes(database, table string) ([][]string, error) {
             CAST(ic.is_included_column AS BIT) AS is_included,
             CAST(i.has_filter AS BIT) AS has_filter,
             i.filter_definition
-        FROM sys.tables t
-        INNER JOIN sys.schemas s 
+        FROM %[1]s.sys.tables t
+        INNER JOIN %[1]s.sys.schemas s 
             ON t.schema_id = s.schema_id
-        INNER JOIN sys.databases d 
-            ON d.name = @p1
-        INNER JOIN sys.indexes i 
+        INNER JOIN %[1]s.sys.indexes i 
             ON t.object_id = i.object_id
-        INNER JOIN sys.index_columns ic 
+        INNER JOIN %[1]s.sys.index_columns ic 
             ON i.object_id = ic.object_id 
             AND i.index_id = ic.index_id
-        INNER JOIN sys.columns c 
+        INNER JOIN %[1]s.sys.columns c 
             ON ic.column_id = c.column_id 
             AND t.object_id = c.object_id
         WHERE t.name = @p2
           AND s.name = @p3
-          AND DB_ID(@p1) = d.database_id
         ORDER BY i.type_desc
     `
-	return db.getTableInformations(query, database, table, currentSchema)
+	return db.getTableInformations(query, database, table)
 }
 
 func (db *MSSQL) GetRecords(database, table, where, sort string, offset, limit int) ([][]string, int, error) {
@@ -240,7 +237,7 @@ func (db *MSSQL) GetRecords(database, table, where, sort string, offset, limit i
 	results := make([][]string, 0)
 
 	query := "SELECT * FROM "
-	query += db.FormatReference(table)
+	query += db.formatTableName(database, table)
 
 	if where != "" {
 		query += fmt.Sprintf(" %s", where)
@@ -337,7 +334,7 @@ func (db *MSSQL) GetRecords(database, table, where, sort string, offset, limit i
 	}
 
 	totalRecords := 0
-	row := db.Connection.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM %s", db.FormatReference(table)))
+	row := db.Connection.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM %s", db.formatTableName(database, table)))
 	if err := row.Scan(&totalRecords); err != nil {
 		return nil, 0, err
 	}
@@ -367,11 +364,11 @@ func (db *MSSQL) UpdateRecord(database, table, column, value, primaryKeyColumnNa
 	}
 
 	query := "UPDATE "
-	query += table
+	query += db.formatTableName(database, table)
 	query += " SET "
-	query += column
+	query += db.FormatReference(column)
 	query += " = @p1 WHERE "
-	query += primaryKeyColumnName
+	query += db.FormatReference(primaryKeyColumnName)
 	query += " = @p2"
 	_, err := db.Connection.Exec(query, value, primaryKeyValue)
 
@@ -396,9 +393,9 @@ func (db *MSSQL) DeleteRecord(database, table, primaryKeyColumnName, primaryKeyV
 	}
 
 	query := "DELETE FROM "
-	query += table
+	query += db.formatTableName(database, table)
 	query += " WHERE "
-	query += primaryKeyColumnName
+	query += db.FormatReference(primaryKeyColumnName)
 	query += " = @p1"
 	_, err := db.Connection.Exec(query, primaryKeyValue)
 
@@ -475,7 +472,7 @@ func (db *MSSQL) ExecutePendingChanges(changes []models.DBDMLChange) error {
 
 	for _, change := range changes {
 
-		formattedTableName := db.FormatReference(change.Table)
+		formattedTableName := db.formatTableName(change.Database, change.Table)
 
 		switch change.Type {
 
@@ -502,36 +499,36 @@ func (db *MSSQL) GetPrimaryKeyColumnNames(database, table string) ([]string, err
 		return nil, errors.New("table name is required")
 	}
 
-	currentSchema, err := db.getCurrentSchema()
+	schema, table, err := db.splitTableName(table)
 	if err != nil {
 		return nil, err
 	}
 
 	pkColumnName := make([]string, 0)
-	query := `SELECT
+	query := fmt.Sprintf(`SELECT
 			c.name AS column_name
 		FROM
-			sys.tables t
+			%[1]s.sys.tables t
 		INNER JOIN
-			sys.schemas s
+			%[1]s.sys.schemas s
 				ON t.schema_id = s.schema_id
 		INNER JOIN
-			sys.key_constraints kc
+			%[1]s.sys.key_constraints kc
 				ON t.object_id = kc.parent_object_id
 				AND kc.type = @p1
 		INNER JOIN
-			sys.index_columns ic
+			%[1]s.sys.index_columns ic
 				ON kc.unique_index_id = ic.index_id
 				AND t.object_id = ic.object_id
 		INNER JOIN
-			sys.columns c
+			%[1]s.sys.columns c
 				ON ic.column_id = c.column_id
 				AND t.object_id = c.object_id
 		WHERE 
 			s.name = @p2
 			AND t.name = @p3
-		ORDER BY ic.key_ordinal`
-	rows, err := db.Connection.Query(query, "PK", currentSchema, table)
+		ORDER BY ic.key_ordinal`, db.FormatReference(database))
+	rows, err := db.Connection.Query(query, "PK", schema, table)
 	if err != nil {
 		return nil, err
 	}
@@ -567,6 +564,23 @@ func (db *MSSQL) GetProvider() string {
 	return db.Provider
 }
 
+// Ping checks that the connection to the server is alive.
+func (db *MSSQL) Ping() error {
+	if db.Connection == nil {
+		return errors.New("not connected")
+	}
+
+	return db.Connection.Ping()
+}
+
+func (db *MSSQL) Close() error {
+	if db.Connection == nil {
+		return nil
+	}
+
+	return db.Connection.Close()
+}
+
 // getTableInformations is used for following func:
 //
 //   - [GetTableColumns]
@@ -576,9 +590,10 @@ func (db *MSSQL) GetProvider() string {
 //
 // getTableInformations requires following parameter:
 //
-//   - database name, used for filtering table_catalog
-//   - table name, used for filtering table_name
-func (db *MSSQL) getTableInformations(query, database, table, schema string) ([][]string, error) {
+//   - query, where %[1]s is replaced by the database the sys views are read from
+//   - database name, passed as @p1
+//   - table name, optionally schema qualified, passed as @p2 with its schema as @p3
+func (db *MSSQL) getTableInformations(query, database, table string) ([][]string, error) {
 	if database == "" {
 		return nil, errors.New("database name is required")
 	}
@@ -591,15 +606,14 @@ func (db *MSSQL) getTableInformations(query, database, table, schema string) ([]
 		return nil, errors.New("query can not be empty")
 	}
 
-	results := make([][]string, 0)
-
-	args := []any{database, table}
-
-	if schema != "" {
-		args = append(args, schema)
+	schema, table, err := db.splitTableName(table)
+	if err != nil {
+		return nil, err
 	}
 
-	rows, err := db.Connection.Query(query, args...)
+	results := make([][]string, 0)
+
+	rows, err := db.Connection.Query(fmt.Sprintf(query, db.FormatReference(database)), database, table, schema)
 	if err != nil {
 		return nil, err
 	}
@@ -663,7 +677,7 @@ func (db *MSSQL) FormatArg(arg any) string {
 }
 
 func (db *MSSQL) FormatReference(reference string) string {
-	return fmt.Sprintf("[%s]", reference)
+	return fmt.Sprintf("[%s]", strings.ReplaceAll(reference, "]", "]]"))
 }
 
 func (db *MSSQL) FormatPlaceholder(index int) string {
@@ -673,7 +687,7 @@ func (db *MSSQL) FormatPlaceholder(index int) string {
 func (db *MSSQL) DMLChangeToQueryString(change models.DBDMLChange) (string, error) {
 	var queryStr string
 
-	formattedTableName := db.FormatReference(change.Table)
+	formattedTableName := db.formatTableName(change.Database, change.Table)
 
 	columnNames, values := getColNamesAndArgsAsString(change.Values)
 
@@ -690,6 +704,42 @@ func (db *MSSQL) DMLChangeToQueryString(change models.DBDMLChange) (string, erro
 	return queryStr, nil
 }
 
+// formatTableName returns the [database].[schema].[table] name of a table, the
+// database and schema are left out when they are not known, SQL Server then
+// uses the current database and the default schema of the user.
+func (db *MSSQL) formatTableName(database, table string) string {
+	schema, name, _ := strings.Cut(table, ".")
+	if name == "" {
+		schema, name = "", table
+	}
+
+	switch {
+	case database != "" && schema != "":
+		return fmt.Sprintf("%s.%s.%s", db.FormatReference(database), db.FormatReference(schema), db.FormatReference(name))
+	case database != "":
+		return fmt.Sprintf("%s..%s", db.FormatReference(database), db.FormatReference(name))
+	case schema != "":
+		return fmt.Sprintf("%s.%s", db.FormatReference(schema), db.FormatReference(name))
+	}
+
+	return db.FormatReference(name)
+}
+
+// splitTableName returns the schema and name of a schema qualified table, the
+// schema of unqualified tables is the default schema of the user.
+func (db *MSSQL) splitTableName(table string) (string, string, error) {
+	if schema, name, ok := strings.Cut(table, "."); ok {
+		return schema, name, nil
+	}
+
+	schema, err := db.getCurrentSchema()
+	if err != nil {
+		return "", "", err
+	}
+
+	return schema, table, nil
+}
+
 func (db *MSSQL) getCurrentSchema() (string, error) {
 	query := "SELECT SCHEMA_NAME() AS CurrentSchema"
 	row := db.Connection.QueryRow(query)
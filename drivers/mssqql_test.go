@@ -80,20 +80,20 @@ func TestMSSQL_GetPrimaryKeyColumnNames(t *testing.T) {
 	mock.ExpectQuery(`SELECT
 			c.name AS column_name
 		FROM
-			sys.tables t
+			[test_db].sys.tables t
 		INNER JOIN
-			sys.schemas s
+			[test_db].sys.schemas s
 				ON t.schema_id = s.schema_id
 		INNER JOIN
-			sys.key_constraints kc
+			[test_db].sys.key_constraints kc
 				ON t.object_id = kc.parent_object_id
 				AND kc.type = @p1
 		INNER JOIN
-			sys.index_columns ic
+			[test_db].sys.index_columns ic
 				ON kc.unique_index_id = ic.index_id
 				AND t.object_id = ic.object_id
 		INNER JOIN
-			sys.columns c
+			[test_db].sys.columns c
 				ON ic.column_id = c.column_id
 				AND t.object_id = c.object_id
 		WHERE 
@@ -147,32 +147,36 @@ func TestMSSQL_GetForeignKeys(t *testing.T) {
 		"CASCADE",
 	)
 
+	schemaRow := sqlmock.NewRows([]string{"CurrentSchema"}).AddRow("dbo")
+
+	mock.ExpectQuery("SELECT SCHEMA_NAME() AS CurrentSchema").WillReturnRows(schemaRow)
+
 	mock.ExpectQuery(`
         SELECT 
             fk.name AS constraint_name,
             c.name AS column_name,
-            DB_NAME(DB_ID(@p1)) AS current_database,
+            @p1 AS current_database,
             OBJECT_SCHEMA_NAME(fk.referenced_object_id, DB_ID(@p1)) + '.' + 
             OBJECT_NAME(fk.referenced_object_id, DB_ID(@p1)) AS referenced_table,
             rc.name AS referenced_column,
             fk.delete_referential_action_desc AS delete_rule,
             fk.update_referential_action_desc AS update_rule
-        FROM sys.foreign_keys fk
-        INNER JOIN sys.foreign_key_columns fkc 
+        FROM [test_db].sys.foreign_keys fk
+        INNER JOIN [test_db].sys.foreign_key_columns fkc 
             ON fk.object_id = fkc.constraint_object_id
-        INNER JOIN sys.columns c 
+        INNER JOIN [test_db].sys.columns c 
             ON fkc.parent_column_id = c.column_id 
             AND fkc.parent_object_id = c.object_id
-        INNER JOIN sys.columns rc 
+        INNER JOIN [test_db].sys.columns rc 
             ON fkc.referenced_column_id = rc.column_id 
             AND fkc.referenced_object_id = rc.object_id
-        INNER JOIN sys.tables t 
+        INNER JOIN [test_db].sys.tables t 
             ON fk.parent_object_id = t.object_id
-        INNER JOIN sys.schemas s 
+        INNER JOIN [test_db].sys.schemas s 
             ON t.schema_id = s.schema_id
         WHERE t.name = @p2
-          AND DB_NAME(DB_ID(@p1)) = @p1
-    `).WithArgs(DBNameMSSQL, tableNameMSSQL).WillReturnRows(rows)
+          AND s.name = @p3
+    `).WithArgs(DBNameMSSQL, tableNameMSSQL, schemaMSSQL).WillReturnRows(rows)
 
 	constraints, err := pg.GetForeignKeys(DBNameMSSQL, tableNameMSSQL)
 	if err != nil {
@@ -306,22 +310,19 @@ func TestMSSQL_GetIndexes(t *testing.T) {
             CAST(ic.is_included_column AS BIT) AS is_included,
             CAST(i.has_filter AS BIT) AS has_filter,
             i.filter_definition
-        FROM sys.tables t
-        INNER JOIN sys.schemas s 
+        FROM [test_db].sys.tables t
+        INNER JOIN [test_db].sys.schemas s 
             ON t.schema_id = s.schema_id
-        INNER JOIN sys.databases d 
-            ON d.name = @p1
-        INNER JOIN sys.indexes i 
+        INNER JOIN [test_db].sys.indexes i 
             ON t.object_id = i.object_id
-        INNER JOIN sys.index_columns ic 
+        INNER JOIN [test_db].sys.index_columns ic 
             ON i.object_id = ic.object_id 
             AND i.index_id = ic.index_id
-        INNER JOIN sys.columns c 
+        INNER JOIN [test_db].sys.columns c 
             ON ic.column_id = c.column_id 
             AND t.object_id = c.object_id
         WHERE t.name = @p2
           AND s.name = @p3
-          AND DB_ID(@p1) = d.database_id
         ORDER BY i.type_desc
     `).
 		WithArgs(DBNameMSSQL, tableNameMSSQL, schemaMSSQL).
@@ -364,8 +365,9 @@ func TestMSSQL_ExecutePendingChanges(t *testing.T) {
 
 	changes := []models.DBDMLChange{
 		{
-			Table: tableNameMSSQL,
-			Type:  models.DMLUpdateType,
+			Database: DBNameMSSQL,
+			Table:    schemaMSSQL + "." + tableNameMSSQL,
+			Type:     models.DMLUpdateType,
 			Values: []models.CellValue{
 				{Column: "name", Value: "New'; DROP TABLE Users;--", Type: models.String},
 			},
@@ -377,8 +379,8 @@ func TestMSSQL_ExecutePendingChanges(t *testing.T) {
 	mock.ExpectBegin()
 	// Verify exact escaped query string
 	mock.ExpectExec(fmt.Sprintf(
-		"UPDATE \\[%s\\] SET \\[name\\] = \\@p1 WHERE \\[id\\] = \\@p2",
-		tableNameMSSQL,
+		"UPDATE \\[%s\\]\\.\\[%s\\]\\.\\[%s\\] SET \\[name\\] = \\@p1 WHERE \\[id\\] = \\@p2",
+		DBNameMSSQL, schemaMSSQL, tableNameMSSQL,
 	)).WithArgs("New'; DROP TABLE Users;--", 1).WillReturnResult(sqlmock.NewResult(0, 1))
 	mock.ExpectCommit()
 
@@ -405,14 +407,14 @@ func TestMSSQL_GetRecords(t *testing.T) {
 		AddRow(1, "Alice").
 		AddRow(2, "Bob")
 
-	mock.ExpectQuery(fmt.Sprintf("SELECT \\* FROM \\[%s\\] ORDER BY \\(SELECT NULL\\) OFFSET \\@p1 ROWS FETCH NEXT \\@p2 ROWS ONLY", tableNameMSSQL)).
+	mock.ExpectQuery(fmt.Sprintf("SELECT \\* FROM \\[%s\\]\\.\\[%s\\]\\.\\[%s\\] ORDER BY \\(SELECT NULL\\) OFFSET \\@p1 ROWS FETCH NEXT \\@p2 ROWS ONLY", DBNameMSSQL, schemaMSSQL, tableNameMSSQL)).
 		WithArgs(0, DefaultRowLimit).
 		WillReturnRows(rows)
 
-	mock.ExpectQuery(fmt.Sprintf("SELECT COUNT\\(\\*\\) FROM \\[%s\\]", tableNameMSSQL)).
+	mock.ExpectQuery(fmt.Sprintf("SELECT COUNT\\(\\*\\) FROM \\[%s\\]\\.\\[%s\\]\\.\\[%s\\]", DBNameMSSQL, schemaMSSQL, tableNameMSSQL)).
 		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
 
-	records, total, err := pg.GetRecords(DBNameMSSQL, tableNameMSSQL, "", "", 0, DefaultRowLimit)
+	records, total, err := pg.GetRecords(DBNameMSSQL, schemaMSSQL+"."+tableNameMSSQL, "", "", 0, DefaultRowLimit)
 	if err != nil {
 		t.Fatalf("GetRecords failed: %v", err)
 	}
@@ -435,3 +437,59 @@ func TestMSSQL_GetRecords(t *testing.T) {
 		t.Errorf("Unfulfilled expectations: %s", err)
 	}
 }
+
+func TestMSSQL_GetTables(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock: %v", err)
+	}
+	defer db.Close()
+
+	pg := &MSSQL{Connection: db}
+
+	mock.ExpectQuery(`FROM \[test_db\]\.sys\.tables t`).
+		WillReturnRows(sqlmock.NewRows([]string{"schema_name", "table_name"}).
+			AddRow("dbo", "users").
+			AddRow("dbo", "orders").
+			AddRow("sales", "invoices"))
+
+	tables, err := pg.GetTables(DBNameMSSQL)
+	if err != nil {
+		t.Fatalf("GetTables failed: %v", err)
+	}
+
+	expected := map[string][]string{
+		"dbo":   {"users", "orders"},
+		"sales": {"invoices"},
+	}
+
+	if !reflect.DeepEqual(tables, expected) {
+		t.Fatalf("Expected %v, got %v", expected, tables)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestMSSQL_formatTableName(t *testing.T) {
+	db := &MSSQL{}
+
+	testCases := []struct {
+		database string
+		table    string
+		expected string
+	}{
+		{database: "shop", table: "sales.orders", expected: "[shop].[sales].[orders]"},
+		{database: "shop", table: "orders", expected: "[shop]..[orders]"},
+		{database: "", table: "sales.orders", expected: "[sales].[orders]"},
+		{database: "", table: "orders", expected: "[orders]"},
+		{database: "my]db", table: "dbo.orders", expected: "[my]]db].[dbo].[orders]"},
+	}
+
+	for _, tc := range testCases {
+		if got := db.formatTableName(tc.database, tc.table); got != tc.expected {
+			t.Errorf("formatTableName(%q, %q) = %q, expected %q", tc.database, tc.table, got, tc.expected)
+		}
+	}
+}
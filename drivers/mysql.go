@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/xo/dburl"
@@ -14,8 +15,14 @@ import (
 type MySQL struct {
 	Connection *sql.DB
 	Provider   string
+	// Version is the version string of the server, read on Connect.
+	Version string
 }
 
+// mySQLSystemDatabases are left out of the databases, TiDB lists the ones of
+// information_schema in uppercase.
+var mySQLSystemDatabases = []string{"information_schema", "mysql", "performance_schema", "sys", "metrics_schema"}
+
 func (db *MySQL) TestConnection(urlstr string) (err error) {
 	return db.Connect(urlstr)
 }
@@ -33,9 +40,51 @@ func (db *MySQL) Connect(urlstr string) (err error) {
 		return err
 	}
 
+	return db.detectProvider()
+}
+
+// detectProvider reads the version of the server to tell MariaDB and TiDB
+// apart from MySQL.
+func (db *MySQL) detectProvider() error {
+	if err := db.Connection.QueryRow("SELECT VERSION()").Scan(&db.Version); err != nil {
+		return err
+	}
+
+	db.SetProvider(mySQLFlavour(db.Version))
+
 	return nil
 }
 
+// mySQLFlavour returns the provider of a server from its version string, like
+// "10.11.6-MariaDB-1:10.11.6+maria~ubu2204" or "8.0.11-TiDB-v7.5.0".
+func mySQLFlavour(version string) string {
+	switch {
+	case strings.Contains(version, "MariaDB"):
+		return DriverMariaDB
+	case strings.Contains(version, "TiDB"):
+		return DriverTiDB
+	}
+
+	return DriverMySQL
+}
+
+// tiDBEnforcesForeignKeys reports whether a TiDB server checks its foreign
+// keys, which older versions only parse.
+func tiDBEnforcesForeignKeys(version string) bool {
+	var major, minor int
+
+	index := strings.Index(version, "TiDB-v")
+	if index == -1 {
+		return false
+	}
+
+	if _, err := fmt.Sscanf(version[index:], "TiDB-v%d.%d", &major, &minor); err != nil {
+		return false
+	}
+
+	return major > 6 || major == 6 && minor >= 6
+}
+
 func (db *MySQL) GetDatabases() ([]string, error) {
 	var databases []string
 
@@ -51,7 +100,7 @@ func (db *MySQL) GetDatabases() ([]string, error) {
 		if err != nil {
 			return nil, err
 		}
-		if database != "information_schema" && database != "mysql" && database != "performance_schema" && database != "sys" {
+		if !slices.Contains(mySQLSystemDatabases, strings.ToLower(database)) {
 			databases = append(databases, database)
 		}
 	}
@@ -152,6 +201,18 @@ func (db *MySQL) GetConstraints(database, table string) (results [][]string, err
 
 	query := "SELECT CONSTRAINT_NAME, COLUMN_NAME, REFERENCED_TABLE_NAME, REFERENCED_COLUMN_NAME FROM information_schema.KEY_COLUMN_USAGE WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?"
 
+	if db.GetProvider() == DriverMariaDB {
+		// The check constraints MariaDB enforces have no key columns.
+		query = `
+			SELECT tc.CONSTRAINT_NAME, kcu.COLUMN_NAME, kcu.REFERENCED_TABLE_NAME, kcu.REFERENCED_COLUMN_NAME, tc.CONSTRAINT_TYPE
+			FROM information_schema.TABLE_CONSTRAINTS tc
+			LEFT JOIN information_schema.KEY_COLUMN_USAGE kcu
+				ON kcu.CONSTRAINT_SCHEMA = tc.CONSTRAINT_SCHEMA AND kcu.TABLE_NAME = tc.TABLE_NAME AND kcu.CONSTRAINT_NAME = tc.CONSTRAINT_NAME
+			WHERE tc.TABLE_SCHEMA = ? AND tc.TABLE_NAME = ?
+			ORDER BY tc.CONSTRAINT_NAME, kcu.ORDINAL_POSITION
+		`
+	}
+
 	rows, err := db.Connection.Query(query, database, table)
 	if err != nil {
 		return nil, err
@@ -201,6 +262,11 @@ func (db *MySQL) GetForeignKeys(database, table string) (results [][]string, err
 
 	query := "SELECT TABLE_NAME, COLUMN_NAME, CONSTRAINT_NAME, REFERENCED_COLUMN_NAME, REFERENCED_TABLE_NAME FROM information_schema.KEY_COLUMN_USAGE WHERE REFERENCED_TABLE_SCHEMA = ? AND REFERENCED_TABLE_NAME = ?"
 
+	if db.GetProvider() == DriverTiDB && !tiDBEnforcesForeignKeys(db.Version) {
+		// TiDB before 6.6 parses the foreign keys but does not enforce them.
+		return [][]string{{"TABLE_NAME", "COLUMN_NAME", "CONSTRAINT_NAME", "REFERENCED_COLUMN_NAME", "REFERENCED_TABLE_NAME"}}, nil
+	}
+
 	rows, err := db.Connection.Query(query, database, table)
 	if err != nil {
 		return nil, err
@@ -515,6 +581,23 @@ func (db *MySQL) GetProvider() string {
 	return db.Provider
 }
 
+// Ping checks that the connection to the server is alive.
+func (db *MySQL) Ping() error {
+	if db.Connection == nil {
+		return errors.New("not connected")
+	}
+
+	return db.Connection.Ping()
+}
+
+func (db *MySQL) Close() error {
+	if db.Connection == nil {
+		return nil
+	}
+
+	return db.Connection.Close()
+}
+
 func (db *MySQL) formatTableName(database, table string) string {
 	return fmt.Sprintf("`%s`.`%s`", database, table)
 }
@@ -1645,3 +1645,139 @@ func TestMySQL_formatTableName(t *testing.T) {
 		t.Fatalf("formatTableName failed: got %q, expected %q", tableName, expectedTableName)
 	}
 }
+
+func TestMySQL_detectProvider(t *testing.T) {
+	testCases := []struct {
+		version  string
+		expected string
+	}{
+		{version: "8.0.36", expected: DriverMySQL},
+		{version: "10.11.6-MariaDB-1:10.11.6+maria~ubu2204", expected: DriverMariaDB},
+		{version: "8.0.11-TiDB-v7.5.0", expected: DriverTiDB},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.version, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected", err)
+			}
+			defer db.Close()
+
+			mysql := &MySQL{Connection: db}
+
+			mock.ExpectQuery(`SELECT VERSION\(\)`).WillReturnRows(sqlmock.NewRows([]string{"VERSION()"}).AddRow(tc.version))
+
+			if err := mysql.detectProvider(); err != nil {
+				t.Fatalf("detectProvider failed: %v", err)
+			}
+
+			if mysql.GetProvider() != tc.expected || Dialect(mysql.GetProvider()) != DriverMySQL {
+				t.Fatalf("expected %s, but got %s", tc.expected, mysql.GetProvider())
+			}
+		})
+	}
+}
+
+func TestMySQL_GetDatabases_TiDB(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected", err)
+	}
+	defer db.Close()
+
+	mysql := &MySQL{Connection: db, Provider: DriverTiDB}
+
+	rows := sqlmock.NewRows([]string{"Database"}).
+		AddRow("INFORMATION_SCHEMA").
+		AddRow("METRICS_SCHEMA").
+		AddRow("PERFORMANCE_SCHEMA").
+		AddRow("mysql").
+		AddRow(testDBNameMySQL)
+
+	mock.ExpectQuery("SHOW DATABASES").WillReturnRows(rows)
+
+	databases, err := mysql.GetDatabases()
+	if err != nil {
+		t.Fatalf("GetDatabases failed: %v", err)
+	}
+
+	if expected := []string{testDBNameMySQL}; !reflect.DeepEqual(databases, expected) {
+		t.Fatalf("Expected %v, got %v", expected, databases)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %s", err)
+	}
+}
+
+func TestMySQL_GetConstraints_MariaDB(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected", err)
+	}
+	defer db.Close()
+
+	mysql := &MySQL{Connection: db, Provider: DriverMariaDB}
+
+	columns := []string{"CONSTRAINT_NAME", "COLUMN_NAME", "REFERENCED_TABLE_NAME", "REFERENCED_COLUMN_NAME", "CONSTRAINT_TYPE"}
+
+	rows := sqlmock.NewRows(columns).
+		AddRow("PRIMARY", "id", nil, nil, "PRIMARY KEY").
+		AddRow("positive_age", nil, nil, nil, "CHECK")
+
+	mock.ExpectQuery("FROM information_schema.TABLE_CONSTRAINTS tc").
+		WithArgs(testDBNameMySQL, testDBTableNameMySQL).
+		WillReturnRows(rows)
+
+	constraints, err := mysql.GetConstraints(testDBNameMySQL, testDBTableNameMySQL)
+	if err != nil {
+		t.Fatalf("GetConstraints failed: %v", err)
+	}
+
+	expected := []models.ConstraintSchema{
+		{Name: "PRIMARY", Type: "PRIMARY KEY", Columns: []string{"id"}},
+		{Name: "positive_age", Type: "CHECK"},
+	}
+
+	if result := normalizeConstraints(constraints); !reflect.DeepEqual(result, expected) {
+		t.Fatalf("Expected %v, got %v", expected, result)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %s", err)
+	}
+}
+
+func TestMySQL_GetForeignKeys_TiDB(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected", err)
+	}
+	defer db.Close()
+
+	mysql := &MySQL{Connection: db, Provider: DriverTiDB, Version: "8.0.11-TiDB-v6.5.3"}
+
+	foreignKeys, err := mysql.GetForeignKeys(testDBNameMySQL, testDBTableNameMySQL)
+	if err != nil {
+		t.Fatalf("GetForeignKeys failed: %v", err)
+	}
+
+	if len(foreignKeys) != 1 {
+		t.Fatalf("expected no foreign keys before TiDB 6.6, but got %v", foreignKeys[1:])
+	}
+
+	mysql.Version = "8.0.11-TiDB-v6.6.0"
+
+	mock.ExpectQuery("SELECT TABLE_NAME, COLUMN_NAME, CONSTRAINT_NAME, REFERENCED_COLUMN_NAME, REFERENCED_TABLE_NAME FROM information_schema.KEY_COLUMN_USAGE").
+		WithArgs(testDBNameMySQL, testDBTableNameMySQL).
+		WillReturnRows(sqlmock.NewRows([]string{"TABLE_NAME", "COLUMN_NAME", "CONSTRAINT_NAME", "REFERENCED_COLUMN_NAME", "REFERENCED_TABLE_NAME"}))
+
+	if _, err := mysql.GetForeignKeys(testDBNameMySQL, testDBTableNameMySQL); err != nil {
+		t.Fatalf("GetForeignKeys failed: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,683 @@
+package drivers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	// Oracle driver
+	_ "github.com/sijms/go-ora/v2"
+	"github.com/xo/dburl"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// Oracle maps its schemas to the databases of the Tree, tables are listed by
+// name under the schema that owns them.
+type Oracle struct {
+	Connection *sql.DB
+	Provider   string
+}
+
+// oracleRowID is the pseudocolumn used to identify the rows of the tables
+// without a primary key.
+const oracleRowID = "ROWID"
+
+func (db *Oracle) TestConnection(urlstr string) error {
+	return db.Connect(urlstr)
+}
+
+func (db *Oracle) Connect(urlstr string) error {
+	if urlstr == "" {
+		return errors.New("url string can not be empty")
+	}
+
+	db.SetProvider(DriverOracle)
+
+	var err error
+
+	db.Connection, err = dburl.Open(urlstr)
+	if err != nil {
+		return err
+	}
+
+	if err := db.Connection.Ping(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetDatabases returns the schemas owning tables, leaving out the ones
+// maintained by Oracle.
+func (db *Oracle) GetDatabases() ([]string, error) {
+	var databases []string
+
+	query := `
+		SELECT DISTINCT t.owner
+		FROM all_tables t
+		JOIN all_users u ON u.username = t.owner
+		WHERE u.oracle_maintained = 'N'
+		ORDER BY t.owner
+	`
+
+	rows, err := db.Connection.Query(query)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var database string
+		if err := rows.Scan(&database); err != nil {
+			return nil, err
+		}
+
+		databases = append(databases, database)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return databases, nil
+}
+
+func (db *Oracle) GetTables(database string) (map[string][]string, error) {
+	if database == "" {
+		return nil, errors.New("database name is required")
+	}
+
+	rows, err := db.Connection.Query("SELECT table_name FROM all_tables WHERE owner = :1 ORDER BY table_name", database)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	tables := make(map[string][]string)
+
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			return nil, err
+		}
+
+		tables[database] = append(tables[database], table)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tables, nil
+}
+
+func (db *Oracle) GetTableColumns(database, table string) ([][]string, error) {
+	query := `
+		SELECT
+			column_name,
+			data_type,
+			nullable AS is_nullable,
+			data_default AS column_default
+		FROM all_tab_columns
+		WHERE owner = :1 AND table_name = :2
+		ORDER BY column_id
+	`
+
+	return db.getTableInformations(query, database, table)
+}
+
+// GetConstraints returns the primary key, unique and check constraints. The
+// NOT NULL checks Oracle generates for the columns are left out.
+func (db *Oracle) GetConstraints(database, table string) ([][]string, error) {
+	query := `
+		SELECT
+			c.constraint_name,
+			cc.column_name,
+			CASE c.constraint_type
+				WHEN 'P' THEN 'PRIMARY KEY'
+				WHEN 'U' THEN 'UNIQUE'
+				ELSE 'CHECK'
+			END AS constraint_type
+		FROM all_constraints c
+		LEFT JOIN all_cons_columns cc
+			ON cc.owner = c.owner AND cc.constraint_name = c.constraint_name
+		WHERE c.owner = :1
+			AND c.table_name = :2
+			AND (c.constraint_type IN ('P', 'U') OR (c.constraint_type = 'C' AND c.generated = 'USER NAME'))
+		ORDER BY c.constraint_name, cc.position
+	`
+
+	return db.getTableInformations(query, database, table)
+}
+
+func (db *Oracle) GetForeignKeys(database, table string) ([][]string, error) {
+	query := `
+		SELECT
+			c.constraint_name,
+			cc.column_name,
+			CASE WHEN r.owner = c.owner THEN r.table_name ELSE r.owner || '.' || r.table_name END AS referenced_table_name,
+			rc.column_name AS referenced_column_name,
+			c.delete_rule
+		FROM all_constraints c
+		JOIN all_cons_columns cc
+			ON cc.owner = c.owner AND cc.constraint_name = c.constraint_name
+		JOIN all_constraints r
+			ON r.owner = c.r_owner AND r.constraint_name = c.r_constraint_name
+		JOIN all_cons_columns rc
+			ON rc.owner = r.owner AND rc.constraint_name = r.constraint_name AND rc.position = cc.position
+		WHERE c.owner = :1
+			AND c.table_name = :2
+			AND c.constraint_type = 'R'
+		ORDER BY c.constraint_name, cc.position
+	`
+
+	return db.getTableInformations(query, database, table)
+}
+
+func (db *Oracle) GetIndexes(database, table string) ([][]string, error) {
+	query := `
+		SELECT
+			i.index_name,
+			ic.column_name,
+			CASE i.uniqueness WHEN 'UNIQUE' THEN 1 ELSE 0 END AS is_unique,
+			i.index_type
+		FROM all_indexes i
+		JOIN all_ind_columns ic
+			ON ic.index_owner = i.owner AND ic.index_name = i.index_name
+		WHERE i.table_owner = :1
+			AND i.table_name = :2
+		ORDER BY i.index_name, ic.column_position
+	`
+
+	return db.getTableInformations(query, database, table)
+}
+
+// GetRecords pages with OFFSET ... FETCH NEXT, available since Oracle 12c.
+// The ROWID of the rows is selected last when the table has no primary key,
+// so the rows can still be edited.
+func (db *Oracle) GetRecords(database, table, where, sort string, offset, limit int) ([][]string, int, error) {
+	if database == "" {
+		return nil, 0, errors.New("database name is required")
+	}
+
+	if table == "" {
+		return nil, 0, errors.New("table name is required")
+	}
+
+	if limit == 0 {
+		limit = DefaultRowLimit
+	}
+
+	rowID, err := db.RowIDColumn(database, table)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	formattedTableName := db.formatTableName(database, table)
+
+	query := "SELECT t.*"
+	if rowID != "" {
+		query += ", t." + rowID
+	}
+	query += " FROM " + formattedTableName + " t"
+
+	if where != "" {
+		query += fmt.Sprintf(" %s", where)
+	}
+
+	if sort != "" {
+		query += fmt.Sprintf(" ORDER BY %s", sort)
+	}
+
+	query += " OFFSET :1 ROWS FETCH NEXT :2 ROWS ONLY"
+
+	rows, err := db.Connection.Query(query, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	results := [][]string{columns}
+
+	for rows.Next() {
+		values, err := scanOracleRow(rows, len(columns))
+		if err != nil {
+			return nil, 0, err
+		}
+
+		var row []string
+		for _, value := range values {
+			switch {
+			case value == nil:
+				row = append(row, "NULL&")
+			case *value == "":
+				row = append(row, "EMPTY&")
+			default:
+				row = append(row, *value)
+			}
+		}
+
+		results = append(results, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	// close to release the connection
+	if err := rows.Close(); err != nil {
+		return nil, 0, err
+	}
+
+	countQuery := "SELECT COUNT(*) FROM " + formattedTableName + " t"
+
+	if where != "" {
+		countQuery += fmt.Sprintf(" %s", where)
+	}
+
+	totalRecords := 0
+	if err := db.Connection.QueryRow(countQuery).Scan(&totalRecords); err != nil {
+		return nil, 0, err
+	}
+
+	return results, totalRecords, nil
+}
+
+func (db *Oracle) UpdateRecord(database, table, column, value, primaryKeyColumnName, primaryKeyValue string) error {
+	if database == "" {
+		return errors.New("database name is required")
+	}
+
+	if table == "" {
+		return errors.New("table name is required")
+	}
+
+	if column == "" {
+		return errors.New("table column is required")
+	}
+
+	if primaryKeyColumnName == "" {
+		return errors.New("primary key column is required")
+	}
+
+	if primaryKeyValue == "" {
+		return errors.New("primary key value is required")
+	}
+
+	query := "UPDATE "
+	query += db.formatTableName(database, table)
+	query += fmt.Sprintf(" SET %s = :1 WHERE %s = :2", db.FormatReference(column), db.FormatReference(primaryKeyColumnName))
+
+	_, err := db.Connection.Exec(query, value, primaryKeyValue)
+
+	return err
+}
+
+func (db *Oracle) DeleteRecord(database, table, primaryKeyColumnName, primaryKeyValue string) error {
+	if database == "" {
+		return errors.New("database name is required")
+	}
+
+	if table == "" {
+		return errors.New("table name is required")
+	}
+
+	if primaryKeyColumnName == "" {
+		return errors.New("primary key column is required")
+	}
+
+	if primaryKeyValue == "" {
+		return errors.New("primary key value is required")
+	}
+
+	query := "DELETE FROM "
+	query += db.formatTableName(database, table)
+	query += fmt.Sprintf(" WHERE %s = :1", db.FormatReference(primaryKeyColumnName))
+
+	_, err := db.Connection.Exec(query, primaryKeyValue)
+
+	return err
+}
+
+func (db *Oracle) ExecuteDMLStatement(query string) (string, error) {
+	if query == "" {
+		return "", errors.New("query is required")
+	}
+
+	res, err := db.Connection.Exec(oracleStatement(query))
+	if err != nil {
+		return "", err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%d rows affected", rowsAffected), nil
+}
+
+func (db *Oracle) ExecuteQuery(query string) ([][]string, int, error) {
+	if query == "" {
+		return nil, 0, errors.New("query can not be empty")
+	}
+
+	rows, err := db.Connection.Query(oracleStatement(query))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	records := make([][]string, 0)
+
+	for rows.Next() {
+		values, err := scanOracleRow(rows, len(columns))
+		if err != nil {
+			return nil, 0, err
+		}
+
+		var row []string
+		for _, value := range values {
+			if value == nil {
+				row = append(row, "NULL")
+			} else {
+				row = append(row, *value)
+			}
+		}
+
+		records = append(records, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	// Prepend the columns to the records.
+	results := append([][]string{columns}, records...)
+
+	return results, len(records), nil
+}
+
+func (db *Oracle) ExecutePendingChanges(changes []models.DBDMLChange) error {
+	var queries []models.Query
+
+	for _, change := range changes {
+		formattedTableName := db.formatTableName(change.Database, change.Table)
+
+		switch change.Type {
+		case models.DMLInsertType:
+			queries = append(queries, buildInsertQuery(formattedTableName, change.Values, db))
+		case models.DMLUpdateType:
+			queries = append(queries, buildUpdateQuery(formattedTableName, change.Values, change.PrimaryKeyInfo, db))
+		case models.DMLDeleteType:
+			queries = append(queries, buildDeleteQuery(formattedTableName, change.PrimaryKeyInfo, db))
+		}
+	}
+
+	return queriesInTransaction(db.Connection, queries)
+}
+
+// GetPrimaryKeyColumnNames returns the declared primary key of the table.
+func (db *Oracle) GetPrimaryKeyColumnNames(database, table string) ([]string, error) {
+	if database == "" {
+		return nil, errors.New("database name is required")
+	}
+
+	if table == "" {
+		return nil, errors.New("table name is required")
+	}
+
+	query := `
+		SELECT cc.column_name
+		FROM all_constraints c
+		JOIN all_cons_columns cc
+			ON cc.owner = c.owner AND cc.constraint_name = c.constraint_name
+		WHERE c.owner = :1
+			AND c.table_name = :2
+			AND c.constraint_type = 'P'
+		ORDER BY cc.position
+	`
+
+	rows, err := db.Connection.Query(query, database, table)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var primaryKey []string
+
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return nil, err
+		}
+
+		primaryKey = append(primaryKey, column)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return primaryKey, nil
+}
+
+// RowIDColumn returns ROWID for the tables without primary key.
+func (db *Oracle) RowIDColumn(database, table string) (string, error) {
+	primaryKey, err := db.GetPrimaryKeyColumnNames(database, table)
+	if err != nil || len(primaryKey) > 0 {
+		return "", err
+	}
+
+	return oracleRowID, nil
+}
+
+func (db *Oracle) SetProvider(provider string) {
+	db.Provider = provider
+}
+
+func (db *Oracle) GetProvider() string {
+	return db.Provider
+}
+
+// Ping checks that the connection to the server is alive.
+func (db *Oracle) Ping() error {
+	if db.Connection == nil {
+		return errors.New("not connected")
+	}
+
+	return db.Connection.Ping()
+}
+
+func (db *Oracle) Close() error {
+	if db.Connection == nil {
+		return nil
+	}
+
+	return db.Connection.Close()
+}
+
+func (db *Oracle) formatTableName(database, table string) string {
+	if database == "" {
+		return db.FormatReference(table)
+	}
+
+	return fmt.Sprintf("%s.%s", db.FormatReference(database), db.FormatReference(table))
+}
+
+// getTableInformations runs a catalog query filtered by the owner and the
+// name of the table, the rows are returned with the column names first.
+func (db *Oracle) getTableInformations(query, database, table string) ([][]string, error) {
+	if database == "" {
+		return nil, errors.New("database name is required")
+	}
+
+	if table == "" {
+		return nil, errors.New("table name is required")
+	}
+
+	rows, err := db.Connection.Query(query, database, table)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	results := [][]string{columns}
+
+	for rows.Next() {
+		values, err := scanOracleRow(rows, len(columns))
+		if err != nil {
+			return nil, err
+		}
+
+		var row []string
+		for _, value := range values {
+			if value == nil {
+				row = append(row, "NULL")
+			} else {
+				row = append(row, strings.TrimSpace(*value))
+			}
+		}
+
+		results = append(results, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// scanOracleRow scans the current row as strings, NULL values are nil. The
+// driver returns typed values, dates are formatted without the time zone
+// Oracle DATE and TIMESTAMP columns do not have.
+func scanOracleRow(rows *sql.Rows, count int) ([]*string, error) {
+	rowValues := make([]any, count)
+	for i := range rowValues {
+		rowValues[i] = new(any)
+	}
+
+	if err := rows.Scan(rowValues...); err != nil {
+		return nil, err
+	}
+
+	values := make([]*string, count)
+
+	for i, col := range rowValues {
+		var formatted string
+
+		switch v := (*col.(*any)).(type) {
+		case nil:
+			continue
+		case string:
+			formatted = v
+		case []byte:
+			formatted = fmt.Sprintf("%X", v)
+		case time.Time:
+			formatted = v.Format("2006-01-02 15:04:05.999999999")
+		default:
+			formatted = fmt.Sprintf("%v", v)
+		}
+
+		values[i] = &formatted
+	}
+
+	return values, nil
+}
+
+// oracleStatement removes the trailing semicolon of a statement typed in the
+// SQL editor, Oracle rejects it outside of PL/SQL blocks.
+func oracleStatement(query string) string {
+	query = strings.TrimSpace(query)
+
+	if strings.HasSuffix(strings.ToUpper(query), "END;") {
+		return query
+	}
+
+	return strings.TrimSuffix(query, ";")
+}
+
+func (db *Oracle) FormatArg(arg any) string {
+	if arg == "NULL" || arg == "DEFAULT" {
+		return fmt.Sprintf("%v", arg)
+	}
+
+	switch v := arg.(type) {
+	case int, int64:
+		return fmt.Sprintf("%v", v)
+	case float64, float32:
+		return fmt.Sprintf("%v", v)
+	case string:
+		escaped := strings.ReplaceAll(v, "'", "''")
+		return fmt.Sprintf("'%s'", escaped)
+	case []byte:
+		return fmt.Sprintf("HEXTORAW('%X')", v)
+	case bool:
+		if v {
+			return "1"
+		}
+		return "0"
+	case nil:
+		return "NULL"
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// FormatReference quotes an identifier, except ROWID that is a pseudocolumn
+// and not found when quoted.
+func (db *Oracle) FormatReference(reference string) string {
+	if reference == oracleRowID {
+		return reference
+	}
+
+	return fmt.Sprintf("\"%s\"", strings.ReplaceAll(reference, "\"", "\"\""))
+}
+
+func (db *Oracle) FormatPlaceholder(index int) string {
+	return fmt.Sprintf(":%d", index)
+}
+
+func (db *Oracle) DMLChangeToQueryString(change models.DBDMLChange) (string, error) {
+	var queryStr string
+
+	formattedTableName := db.formatTableName(change.Database, change.Table)
+
+	columnNames, values := getColNamesAndArgsAsString(change.Values)
+
+	switch change.Type {
+	case models.DMLInsertType:
+		queryStr = buildInsertQueryString(formattedTableName, columnNames, values, db)
+	case models.DMLUpdateType:
+		queryStr = buildUpdateQueryString(formattedTableName, columnNames, values, change.PrimaryKeyInfo, db)
+	case models.DMLDeleteType:
+		queryStr = buildDeleteQueryString(formattedTableName, change.PrimaryKeyInfo, db)
+	}
+
+	return queryStr, nil
+}
@@ -0,0 +1,378 @@
+package drivers
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+const (
+	testDBNameOracle      = "APP"
+	testDBTableNameOracle = "USERS"
+)
+
+func TestOracle_FormatArg(t *testing.T) {
+	db := &Oracle{}
+
+	testCases := []struct {
+		name     string
+		arg      any
+		expected string
+	}{
+		{
+			name:     "Integer argument",
+			arg:      123,
+			expected: "123",
+		},
+		{
+			name:     "String with single quote",
+			arg:      "O'Reilly",
+			expected: "'O''Reilly'",
+		},
+		{
+			name:     "Boolean argument",
+			arg:      true,
+			expected: "1",
+		},
+		{
+			name:     "Byte slice argument",
+			arg:      []byte{0xca, 0xfe},
+			expected: "HEXTORAW('CAFE')",
+		},
+		{
+			name:     "Nil argument",
+			arg:      nil,
+			expected: "NULL",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			formattedArg := db.FormatArg(tc.arg)
+			if formattedArg != tc.expected {
+				t.Fatalf("expected %q, but got %q", tc.expected, formattedArg)
+			}
+		})
+	}
+}
+
+func TestOracle_DMLChangeToQueryString(t *testing.T) {
+	db := &Oracle{}
+
+	testCases := []struct {
+		name     string
+		change   models.DBDMLChange
+		expected string
+	}{
+		{
+			name: "Insert with int value",
+			change: models.DBDMLChange{
+				Database: testDBNameOracle, Table: testDBTableNameOracle,
+				Type: models.DMLInsertType,
+				Values: []models.CellValue{
+					{Column: "NAME", Value: "test_name", Type: models.String},
+					{Column: "AGE", Value: 30, Type: models.String},
+				},
+			},
+			expected: `INSERT INTO "APP"."USERS" (NAME, AGE) VALUES ('test_name', 30)`,
+		},
+		{
+			name: "Update by ROWID",
+			change: models.DBDMLChange{
+				Database: testDBNameOracle, Table: testDBTableNameOracle,
+				Type: models.DMLUpdateType,
+				Values: []models.CellValue{
+					{Column: "NAME", Value: "test_name", Type: models.String},
+				},
+				PrimaryKeyInfo: []models.PrimaryKeyInfo{
+					{Name: "ROWID", Value: "AAAR3sAAEAAAACXAAA"},
+				},
+			},
+			expected: `UPDATE "APP"."USERS" SET "NAME" = 'test_name' WHERE ROWID = 'AAAR3sAAEAAAACXAAA'`,
+		},
+		{
+			name: "Delete with int value",
+			change: models.DBDMLChange{
+				Database: testDBNameOracle, Table: testDBTableNameOracle,
+				Type: models.DMLDeleteType,
+				PrimaryKeyInfo: []models.PrimaryKeyInfo{
+					{Name: "ID", Value: 1},
+				},
+			},
+			expected: `DELETE FROM "APP"."USERS" WHERE "ID" = 1`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			queryString, err := db.DMLChangeToQueryString(tc.change)
+			if err != nil {
+				t.Fatalf("DMLChangeToQueryString failed: %v", err)
+			}
+			if queryString != tc.expected {
+				t.Fatalf("Expected: %q\nGot: %q", tc.expected, queryString)
+			}
+		})
+	}
+}
+
+func TestOracle_GetTables(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock: %v", err)
+	}
+	defer db.Close()
+
+	oracle := &Oracle{Connection: db}
+
+	rows := sqlmock.NewRows([]string{"table_name"}).
+		AddRow("ORDERS").
+		AddRow("USERS")
+
+	mock.ExpectQuery("SELECT table_name FROM all_tables WHERE owner = :1").
+		WithArgs(testDBNameOracle).
+		WillReturnRows(rows)
+
+	tables, err := oracle.GetTables(testDBNameOracle)
+	if err != nil {
+		t.Fatalf("GetTables failed: %v", err)
+	}
+
+	expected := map[string][]string{testDBNameOracle: {"ORDERS", "USERS"}}
+	if !reflect.DeepEqual(tables, expected) {
+		t.Fatalf("Expected %v, got %v", expected, tables)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestOracle_GetRecords(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock: %v", err)
+	}
+	defer db.Close()
+
+	oracle := &Oracle{Connection: db}
+
+	mock.ExpectQuery("FROM all_constraints c").
+		WithArgs(testDBNameOracle, testDBTableNameOracle).
+		WillReturnRows(sqlmock.NewRows([]string{"column_name"}))
+
+	rows := sqlmock.NewRows([]string{"ID", "NAME", "ROWID"}).
+		AddRow(1, "Alice", "AAAR3sAAEAAAACXAAA").
+		AddRow(2, nil, "AAAR3sAAEAAAACXAAB")
+
+	mock.ExpectQuery(`SELECT t.\*, t.ROWID FROM "APP"."USERS" t WHERE ID > 0 ORDER BY "ID" ASC OFFSET :1 ROWS FETCH NEXT :2 ROWS ONLY`).
+		WithArgs(0, DefaultRowLimit).
+		WillReturnRows(rows)
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM "APP"."USERS" t WHERE ID > 0`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+	records, total, err := oracle.GetRecords(testDBNameOracle, testDBTableNameOracle, "WHERE ID > 0", `"ID" ASC`, 0, DefaultRowLimit)
+	if err != nil {
+		t.Fatalf("GetRecords failed: %v", err)
+	}
+
+	expected := [][]string{
+		{"ID", "NAME", "ROWID"},
+		{"1", "Alice", "AAAR3sAAEAAAACXAAA"},
+		{"2", "NULL&", "AAAR3sAAEAAAACXAAB"},
+	}
+
+	if !reflect.DeepEqual(records, expected) {
+		t.Fatalf("Expected %v, got %v", expected, records)
+	}
+
+	if total != 2 {
+		t.Fatalf("Expected total 2, got %d", total)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestOracle_GetForeignKeys(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock: %v", err)
+	}
+	defer db.Close()
+
+	oracle := &Oracle{Connection: db}
+
+	columns := []string{"CONSTRAINT_NAME", "COLUMN_NAME", "REFERENCED_TABLE_NAME", "REFERENCED_COLUMN_NAME", "DELETE_RULE"}
+
+	mock.ExpectQuery("c.constraint_type = 'R'").
+		WithArgs(testDBNameOracle, "ORDERS").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("ORDERS_USER_FK", "USER_ID", "USERS", "ID", "CASCADE"))
+
+	results, err := oracle.GetForeignKeys(testDBNameOracle, "ORDERS")
+	if err != nil {
+		t.Fatalf("GetForeignKeys failed: %v", err)
+	}
+
+	expected := [][]string{columns, {"ORDERS_USER_FK", "USER_ID", "USERS", "ID", "CASCADE"}}
+
+	if !reflect.DeepEqual(results, expected) {
+		t.Fatalf("Expected %v, got %v", expected, results)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestOracle_ExecutePendingChanges(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock: %v", err)
+	}
+	defer db.Close()
+
+	oracle := &Oracle{Connection: db}
+
+	changes := []models.DBDMLChange{
+		{
+			Database: testDBNameOracle,
+			Table:    testDBTableNameOracle,
+			Type:     models.DMLUpdateType,
+			Values: []models.CellValue{
+				{Column: "NAME", Value: "New Name", Type: models.String},
+			},
+			PrimaryKeyInfo: []models.PrimaryKeyInfo{
+				{Name: "ROWID", Value: "AAAR3sAAEAAAACXAAA"},
+			},
+		},
+		{
+			Database: testDBNameOracle,
+			Table:    testDBTableNameOracle,
+			Type:     models.DMLDeleteType,
+			PrimaryKeyInfo: []models.PrimaryKeyInfo{
+				{Name: "ID", Value: 2},
+			},
+		},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "APP"."USERS" SET "NAME" = :1 WHERE ROWID = :2`).
+		WithArgs("New Name", "AAAR3sAAEAAAACXAAA").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM "APP"."USERS" WHERE "ID" = :1`).
+		WithArgs(2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := oracle.ExecutePendingChanges(changes); err != nil {
+		t.Fatalf("ExecutePendingChanges failed: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestOracle_GetPrimaryKeyColumnNames(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock: %v", err)
+	}
+	defer db.Close()
+
+	oracle := &Oracle{Connection: db}
+
+	mock.ExpectQuery("c.constraint_type = 'P'").
+		WithArgs(testDBNameOracle, testDBTableNameOracle).
+		WillReturnRows(sqlmock.NewRows([]string{"column_name"}).AddRow("ID"))
+
+	mock.ExpectQuery("c.constraint_type = 'P'").
+		WithArgs(testDBNameOracle, "LOGS").
+		WillReturnRows(sqlmock.NewRows([]string{"column_name"}))
+
+	mock.ExpectQuery("c.constraint_type = 'P'").
+		WithArgs(testDBNameOracle, "LOGS").
+		WillReturnRows(sqlmock.NewRows([]string{"column_name"}))
+
+	keys, err := oracle.GetPrimaryKeyColumnNames(testDBNameOracle, testDBTableNameOracle)
+	if err != nil {
+		t.Fatalf("GetPrimaryKeyColumnNames failed: %v", err)
+	}
+
+	if expected := []string{"ID"}; !reflect.DeepEqual(keys, expected) {
+		t.Fatalf("Expected %v, got %v", expected, keys)
+	}
+
+	keys, err = oracle.GetPrimaryKeyColumnNames(testDBNameOracle, "LOGS")
+	if err != nil {
+		t.Fatalf("GetPrimaryKeyColumnNames failed: %v", err)
+	}
+
+	if len(keys) != 0 {
+		t.Fatalf("Expected no primary key, got %v", keys)
+	}
+
+	rowID, err := oracle.RowIDColumn(testDBNameOracle, "LOGS")
+	if err != nil {
+		t.Fatalf("RowIDColumn failed: %v", err)
+	}
+
+	if rowID != "ROWID" {
+		t.Fatalf("Expected the ROWID fallback, got %q", rowID)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestOracle_SetGetProvider(t *testing.T) {
+	db := &Oracle{}
+	db.SetProvider(DriverOracle)
+
+	if db.GetProvider() != DriverOracle {
+		t.Fatalf("Provider mismatch: got %s, expected %s", db.GetProvider(), DriverOracle)
+	}
+}
+
+func TestOracle_formatTableName(t *testing.T) {
+	db := &Oracle{}
+
+	testCases := []struct {
+		database string
+		table    string
+		expected string
+	}{
+		{database: testDBNameOracle, table: testDBTableNameOracle, expected: `"APP"."USERS"`},
+		{database: "", table: `we"ird`, expected: `"we""ird"`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s.%s", tc.database, tc.table), func(t *testing.T) {
+			if tableName := db.formatTableName(tc.database, tc.table); tableName != tc.expected {
+				t.Fatalf("formatTableName failed: got %s, expected %s", tableName, tc.expected)
+			}
+		})
+	}
+}
+
+func TestOracleStatement(t *testing.T) {
+	testCases := map[string]string{
+		"SELECT * FROM dual;": "SELECT * FROM dual",
+		"BEGIN NULL; END;":    "BEGIN NULL; END;",
+		" DELETE FROM t ":     "DELETE FROM t",
+	}
+
+	for query, expected := range testCases {
+		if result := oracleStatement(query); result != expected {
+			t.Fatalf("expected %q, but got %q", expected, result)
+		}
+	}
+}
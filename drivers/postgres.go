@@ -4,7 +4,10 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"net/url"
 	"strings"
+	"sync"
+	"time"
 
 	// import postgresql driver
 	_ "github.com/lib/pq"
@@ -20,11 +23,18 @@ type Postgres struct {
 	CurrentDatabase  string
 	PreviousDatabase string
 	Urlstr           string
+	// Version is the version string of the server, read on Connect.
+	Version string
+
+	// connections holds the connection to each database switched to, by
+	// database name. mu guards it and the switch of the current connection.
+	connections map[string]*sql.DB
+	mu          sync.Mutex
 }
 
-const (
-	defaultPort = "5432"
-)
+// postgresIdleTimeout is how long the idle connections to the databases that
+// are not the current one are kept open.
+const postgresIdleTimeout = 5 * time.Minute
 
 func (db *Postgres) TestConnection(urlstr string) error {
 	return db.Connect(urlstr)
@@ -38,9 +48,11 @@ func (db *Postgres) Connect(urlstr string) error {
 		return err
 	}
 
+	db.mu.Lock()
 	db.Connection = connection
+	db.mu.Unlock()
 
-	err = db.Connection.Ping()
+	err = connection.Ping()
 	if err != nil {
 		return err
 	}
@@ -48,7 +60,7 @@ func (db *Postgres) Connect(urlstr string) error {
 	db.Urlstr = urlstr
 
 	// Get the current database.
-	rows := db.Connection.QueryRow("SELECT current_database();")
+	rows := connection.QueryRow("SELECT current_database();")
 
 	database := ""
 	err = rows.Scan(&database)
@@ -56,14 +68,46 @@ func (db *Postgres) Connect(urlstr string) error {
 		return err
 	}
 
+	db.mu.Lock()
 	db.CurrentDatabase = database
 	db.PreviousDatabase = database
+	db.connections = map[string]*sql.DB{database: connection}
+	db.mu.Unlock()
+
+	return db.detectProvider()
+}
+
+// detectProvider reads the version of the server to tell CockroachDB apart
+// from Postgres.
+func (db *Postgres) detectProvider() error {
+	if err := db.conn().QueryRow("SELECT version()").Scan(&db.Version); err != nil {
+		return err
+	}
+
+	db.SetProvider(postgresFlavour(db.Version))
 
 	return nil
 }
 
+// postgresFlavour returns the provider of a server from its version string,
+// like "CockroachDB CCL v23.1.11 (x86_64-pc-linux-gnu, ...)".
+func postgresFlavour(version string) string {
+	if strings.HasPrefix(version, "CockroachDB") {
+		return DriverCockroachDB
+	}
+
+	return DriverPostgres
+}
+
 func (db *Postgres) GetDatabases() ([]string, error) {
-	rows, err := db.Connection.Query("SELECT datname FROM pg_database;")
+	query := "SELECT datname FROM pg_database;"
+
+	if db.GetProvider() == DriverCockroachDB {
+		// The system database of CockroachDB is internal to the cluster.
+		query = "SELECT database_name FROM [SHOW DATABASES] WHERE database_name <> 'system' ORDER BY database_name"
+	}
+
+	rows, err := db.conn().Query(query)
 	if err != nil {
 		return nil, err
 	}
@@ -104,7 +148,12 @@ func (db *Postgres) GetTables(database string) (map[string][]string, error) {
 	}
 
 	query := "SELECT table_name, table_schema FROM information_schema.tables WHERE table_catalog = $1"
-	rows, err := db.Connection.Query(query, database)
+
+	if db.GetProvider() == DriverCockroachDB {
+		// The virtual schemas of CockroachDB hold hundreds of internal tables.
+		query += " AND table_schema NOT IN ('crdb_internal', 'pg_extension')"
+	}
+	rows, err := db.conn().Query(query, database)
 	if err != nil {
 		return nil, err
 	}
@@ -161,7 +210,7 @@ func (db *Postgres) GetTableColumns(database, table string) ([][]string, error)
 
 	query := "SELECT column_name, data_type, is_nullable, column_default FROM information_schema.columns WHERE table_catalog = $1 AND table_schema = $2 AND table_name = $3 ORDER by ordinal_position"
 
-	rows, err := db.Connection.Query(query, database, tableSchema, tableName)
+	rows, err := db.conn().Query(query, database, tableSchema, tableName)
 	if err != nil {
 		return nil, err
 	}
@@ -228,7 +277,7 @@ func (db *Postgres) GetConstraints(database, table string) ([][]string, error) {
 	tableSchema := splitTableString[0]
 	tableName := splitTableString[1]
 
-	rows, err := db.Connection.Query(fmt.Sprintf(`
+	rows, err := db.conn().Query(fmt.Sprintf(`
         SELECT
             tc.constraint_name,
             kcu.column_name,
@@ -308,7 +357,7 @@ func (db *Postgres) GetForeignKeys(database, table string) ([][]string, error) {
 	tableSchema := splitTableString[0]
 	tableName := splitTableString[1]
 
-	rows, err := db.Connection.Query(fmt.Sprintf(`
+	rows, err := db.conn().Query(fmt.Sprintf(`
         SELECT
             tc.constraint_name,
             kcu.column_name,
@@ -389,7 +438,7 @@ func (db *Postgres) GetIndexes(database, table string) ([][]string, error) {
 	tableSchema := splitTableString[0]
 	tableName := splitTableString[1]
 
-	rows, err := db.Connection.Query(fmt.Sprintf(`
+	query := fmt.Sprintf(`
         SELECT
             i.relname AS index_name,
             a.attname AS column_name,
@@ -414,7 +463,23 @@ func (db *Postgres) GetIndexes(database, table string) ([][]string, error) {
         ORDER BY
             t.relname,
             i.relname
-  `, tableSchema, tableName))
+  `, tableSchema, tableName)
+
+	var args []any
+
+	if db.GetProvider() == DriverCockroachDB {
+		// pg_index is only emulated by CockroachDB, its statistics view
+		// describes the indexes without their stored columns.
+		query = `
+			SELECT index_name, column_name, non_unique
+			FROM information_schema.statistics
+			WHERE table_schema = $1 AND table_name = $2 AND storing = 'NO'
+			ORDER BY index_name, seq_in_index
+		`
+		args = []any{tableSchema, tableName}
+	}
+
+	rows, err := db.conn().Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -493,7 +558,7 @@ func (db *Postgres) GetRecords(database, table, where, sort string, offset, limi
 		limit = DefaultRowLimit
 	}
 
-	paginatedRows, err := db.Connection.Query(query, limit, offset)
+	paginatedRows, err := db.conn().Query(query, limit, offset)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -548,7 +613,7 @@ func (db *Postgres) GetRecords(database, table, where, sort string, offset, limi
 		countQuery += fmt.Sprintf(" %s", where)
 	}
 
-	row := db.Connection.QueryRow(countQuery)
+	row := db.conn().QueryRow(countQuery)
 
 	var totalRecords int
 	if err := row.Scan(&totalRecords); err != nil {
@@ -597,7 +662,7 @@ func (db *Postgres) UpdateRecord(database, table, column, value, primaryKeyColum
 	query += formattedTableName
 	query += fmt.Sprintf(" SET \"%s\" = $1 WHERE \"%s\" = $2", column, primaryKeyColumnName)
 
-	_, err := db.Connection.Exec(query, value, primaryKeyValue)
+	_, err := db.conn().Exec(query, value, primaryKeyValue)
 	if err != nil && switchDatabaseOnError {
 		err = db.SwitchDatabase(db.PreviousDatabase)
 	}
@@ -637,7 +702,7 @@ func (db *Postgres) DeleteRecord(database, table, primaryKeyColumnName, primaryK
 	query += formattedTableName
 	query += fmt.Sprintf(" WHERE \"%s\" = $1", primaryKeyColumnName)
 
-	_, err := db.Connection.Exec(query, primaryKeyValue)
+	_, err := db.conn().Exec(query, primaryKeyValue)
 	if err != nil && switchDatabaseOnError {
 		err = db.SwitchDatabase(db.PreviousDatabase)
 	}
@@ -646,7 +711,7 @@ func (db *Postgres) DeleteRecord(database, table, primaryKeyColumnName, primaryK
 }
 
 func (db *Postgres) ExecuteDMLStatement(query string) (result string, err error) {
-	res, err := db.Connection.Exec(query)
+	res, err := db.conn().Exec(query)
 	if err != nil {
 		return result, err
 	}
@@ -658,7 +723,7 @@ func (db *Postgres) ExecuteDMLStatement(query string) (result string, err error)
 }
 
 func (db *Postgres) ExecuteQuery(query string) ([][]string, int, error) {
-	rows, err := db.Connection.Query(query)
+	rows, err := db.conn().Query(query)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -719,7 +784,7 @@ func (db *Postgres) ExecutePendingChanges(changes []models.DBDMLChange) error {
 		}
 	}
 
-	return queriesInTransaction(db.Connection, queries)
+	return queriesInTransaction(db.conn(), queries)
 }
 
 func (db *Postgres) GetPrimaryKeyColumnNames(database, table string) ([]string, error) {
@@ -751,7 +816,7 @@ func (db *Postgres) GetPrimaryKeyColumnNames(database, table string) ([]string,
 		}()
 	}
 
-	row, err := db.Connection.Query(`
+	query := `
 		SELECT
 			a.attname AS column_name
 		FROM
@@ -762,7 +827,23 @@ func (db *Postgres) GetPrimaryKeyColumnNames(database, table string) ([]string,
 			JOIN pg_namespace n ON n.oid = c.relnamespace
 		WHERE
 			relname = $2 AND nspname = $1 AND indisprimary
-	`, schemaName, tableName)
+	`
+
+	if db.GetProvider() == DriverCockroachDB {
+		// Older CockroachDB versions can not compare with the int2vector of
+		// pg_index, the key is read from information_schema instead.
+		query = `
+			SELECT kcu.column_name
+			FROM information_schema.table_constraints tc
+			JOIN information_schema.key_column_usage kcu
+				ON kcu.constraint_schema = tc.constraint_schema AND kcu.constraint_name = tc.constraint_name
+				AND kcu.table_name = tc.table_name
+			WHERE tc.table_schema = $1 AND tc.table_name = $2 AND tc.constraint_type = 'PRIMARY KEY'
+			ORDER BY kcu.ordinal_position
+		`
+	}
+
+	row, err := db.conn().Query(query, schemaName, tableName)
 	if err != nil {
 		logger.Error("GetPrimaryKeyColumnNames", map[string]any{"error": err.Error()})
 		return nil, err
@@ -800,34 +881,79 @@ func (db *Postgres) GetProvider() string {
 	return db.Provider
 }
 
-func (db *Postgres) SwitchDatabase(database string) error {
-	parsedConn, err := dburl.Parse(db.Urlstr)
-	if err != nil {
-		return err
+// Ping checks that the connection to the server is alive.
+func (db *Postgres) Ping() error {
+	connection := db.conn()
+	if connection == nil {
+		return errors.New("not connected")
 	}
 
-	user := parsedConn.User.Username()
-	password, _ := parsedConn.User.Password()
-	host := parsedConn.Host
-	port := parsedConn.Port()
-	dbname := parsedConn.Path
+	return connection.Ping()
+}
 
-	if port == "" {
-		port = defaultPort
-	}
+// conn returns the connection to the current database, which SwitchDatabase
+// replaces while the other methods run.
+func (db *Postgres) conn() *sql.DB {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	return db.Connection
+}
+
+// Close closes the connections to all the databases switched to.
+func (db *Postgres) Close() error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	var err error
+
+	for database, connection := range db.connections {
+		if connection != db.Connection {
+			err = errors.Join(err, connection.Close())
+		}
 
-	if dbname == "" {
-		dbname = database
+		delete(db.connections, database)
 	}
 
-	connection, err := sql.Open("postgres", fmt.Sprintf("host=%s port=%s user=%s password=%s dbname='%s' sslmode=disable", host, port, user, password, dbname))
-	if err != nil {
+	if db.Connection == nil {
 		return err
 	}
 
-	err = db.Connection.Close()
-	if err != nil {
-		return err
+	return errors.Join(err, db.Connection.Close())
+}
+
+// SwitchDatabase makes the connection to another database the current one.
+// The connections are kept, switching back to a database reuses its pool.
+func (db *Postgres) SwitchDatabase(database string) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if db.connections == nil {
+		db.connections = make(map[string]*sql.DB)
+	}
+
+	if db.Connection != nil && db.CurrentDatabase != "" {
+		db.connections[db.CurrentDatabase] = db.Connection
+	}
+
+	connection, ok := db.connections[database]
+	if !ok {
+		urlstr, err := postgresDatabaseURL(db.Urlstr, database)
+		if err != nil {
+			return err
+		}
+
+		connection, err = dburl.Open(urlstr)
+		if err != nil {
+			return err
+		}
+
+		if err := connection.Ping(); err != nil {
+			return errors.Join(err, connection.Close())
+		}
+
+		connection.SetConnMaxIdleTime(postgresIdleTimeout)
+		db.connections[database] = connection
 	}
 
 	db.Connection = connection
@@ -837,6 +963,30 @@ func (db *Postgres) SwitchDatabase(database string) error {
 	return nil
 }
 
+// postgresDatabaseURL returns the connection URL with its database replaced,
+// the credentials and the parameters like sslmode are kept as they are.
+func postgresDatabaseURL(urlstr, database string) (string, error) {
+	u, err := url.Parse(urlstr)
+	if err != nil {
+		return "", err
+	}
+
+	if u.Opaque != "" {
+		return "", fmt.Errorf("can not switch the database of %s URLs", u.Scheme)
+	}
+
+	u.Path = "/" + database
+	u.RawPath = ""
+
+	// A dbname parameter takes precedence over the path.
+	if query := u.Query(); query.Has("dbname") {
+		query.Set("dbname", database)
+		u.RawQuery = query.Encode()
+	}
+
+	return u.String(), nil
+}
+
 func (db *Postgres) formatTableName(table string) (string, error) {
 	splitTableString := strings.Split(table, ".")
 
@@ -0,0 +1,258 @@
+package drivers
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync/atomic"
+	"syscall"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// connectionErrorMessages are parts of the messages of the errors the drivers
+// return when the connection to the server was lost, for the drivers that do
+// not wrap the network errors.
+var connectionErrorMessages = []string{
+	"bad connection",
+	"broken pipe",
+	"connection is already closed",
+	"connection refused",
+	"connection reset",
+	"driver: bad connection",
+	"i/o timeout",
+	"invalid connection",
+	"no connection",
+	"server closed",
+	"unexpected eof",
+	"use of closed network connection",
+}
+
+// IsConnectionError reports whether the error means that the connection to the
+// server was lost, rather than that the statement failed.
+func IsConnectionError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) || errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EPIPE) || errors.Is(err, net.ErrClosed) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	message := strings.ToLower(err.Error())
+
+	for _, part := range connectionErrorMessages {
+		if strings.Contains(message, part) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Resilient retries the calls of its driver that fail because the connection
+// was lost, once Reconnect brought it back. Only the calls that do not change
+// anything are retried, a statement that failed on its way back could have
+// been applied: the calls that change something wait for a connection being
+// reconnected to come back before they run, and wait for it when they failed
+// because of the connection, but are not run again.
+//
+// The database/sql pools open new connections with the URL the driver
+// connected with, so the database a Postgres driver switched to is kept. The
+// state of the sessions, like the databases attached to SQLite, is restored by
+// the drivers implementing SessionRestorer.
+type Resilient struct {
+	Driver
+
+	// Reconnect is called when a call failed because of the connection, the
+	// call is retried when it returns nil.
+	Reconnect func() error
+	// Wait is called before the calls that change something, it returns once
+	// the connection is not being reconnected.
+	Wait func() error
+
+	// running is the number of calls running on the driver.
+	running atomic.Int32
+}
+
+// Unwrap returns the driver wrapped by a Resilient or Tunnelled driver, the
+// optional interfaces like DatabaseAttacher are implemented by it.
+func Unwrap(driver Driver) Driver {
+	for {
+		switch wrapper := driver.(type) {
+		case *Resilient:
+			driver = wrapper.Driver
+		case *Tunnelled:
+			driver = wrapper.Driver
+		default:
+			return driver
+		}
+	}
+}
+
+// Busy reports whether a call is running on the driver, a ping would wait for
+// it on the drivers with a single connection.
+func (r *Resilient) Busy() bool {
+	return r.running.Load() > 0
+}
+
+// run runs a call on the driver, it is counted as running meanwhile.
+func (r *Resilient) run(call func() error) error {
+	r.running.Add(1)
+	defer r.running.Add(-1)
+
+	return call()
+}
+
+// read runs a call that does not change anything, it is retried once the
+// connection is back when it failed because of the connection.
+func (r *Resilient) read(call func() error) error {
+	err := r.run(call)
+	if r.Reconnect != nil && IsConnectionError(err) && r.Reconnect() == nil {
+		err = r.run(call)
+	}
+
+	return err
+}
+
+// write runs a call that changes something once the connection is not being
+// reconnected. When it failed because of the connection it waits for the
+// connection to come back but is not run again.
+func (r *Resilient) write(call func() error) error {
+	if r.Wait != nil {
+		if err := r.Wait(); err != nil {
+			return err
+		}
+	}
+
+	err := r.run(call)
+	if r.Reconnect != nil && IsConnectionError(err) {
+		if reconnectErr := r.Reconnect(); reconnectErr != nil {
+			return errors.Join(err, reconnectErr)
+		}
+
+		return fmt.Errorf("the connection was lost, check whether the statement was applied before running it again: %w", err)
+	}
+
+	return err
+}
+
+func (r *Resilient) GetDatabases() (databases []string, err error) {
+	err = r.read(func() error {
+		databases, err = r.Driver.GetDatabases()
+		return err
+	})
+
+	return databases, err
+}
+
+func (r *Resilient) GetTables(database string) (tables map[string][]string, err error) {
+	err = r.read(func() error {
+		tables, err = r.Driver.GetTables(database)
+		return err
+	})
+
+	return tables, err
+}
+
+func (r *Resilient) GetTableColumns(database, table string) (columns [][]string, err error) {
+	err = r.read(func() error {
+		columns, err = r.Driver.GetTableColumns(database, table)
+		return err
+	})
+
+	return columns, err
+}
+
+func (r *Resilient) GetConstraints(database, table string) (constraints [][]string, err error) {
+	err = r.read(func() error {
+		constraints, err = r.Driver.GetConstraints(database, table)
+		return err
+	})
+
+	return constraints, err
+}
+
+func (r *Resilient) GetForeignKeys(database, table string) (foreignKeys [][]string, err error) {
+	err = r.read(func() error {
+		foreignKeys, err = r.Driver.GetForeignKeys(database, table)
+		return err
+	})
+
+	return foreignKeys, err
+}
+
+func (r *Resilient) GetIndexes(database, table string) (indexes [][]string, err error) {
+	err = r.read(func() error {
+		indexes, err = r.Driver.GetIndexes(database, table)
+		return err
+	})
+
+	return indexes, err
+}
+
+func (r *Resilient) GetRecords(database, table, where, sort string, offset, limit int) (records [][]string, count int, err error) {
+	err = r.read(func() error {
+		records, count, err = r.Driver.GetRecords(database, table, where, sort, offset, limit)
+		return err
+	})
+
+	return records, count, err
+}
+
+func (r *Resilient) GetPrimaryKeyColumnNames(database, table string) (columns []string, err error) {
+	err = r.read(func() error {
+		columns, err = r.Driver.GetPrimaryKeyColumnNames(database, table)
+		return err
+	})
+
+	return columns, err
+}
+
+func (r *Resilient) UpdateRecord(database, table, column, value, primaryKeyColumnName, primaryKeyValue string) error {
+	return r.write(func() error {
+		return r.Driver.UpdateRecord(database, table, column, value, primaryKeyColumnName, primaryKeyValue)
+	})
+}
+
+func (r *Resilient) DeleteRecord(database, table, primaryKeyColumnName, primaryKeyValue string) error {
+	return r.write(func() error {
+		return r.Driver.DeleteRecord(database, table, primaryKeyColumnName, primaryKeyValue)
+	})
+}
+
+func (r *Resilient) ExecuteDMLStatement(query string) (result string, err error) {
+	err = r.write(func() error {
+		result, err = r.Driver.ExecuteDMLStatement(query)
+		return err
+	})
+
+	return result, err
+}
+
+// ExecuteQuery runs the queries of the editor, which can change something.
+func (r *Resilient) ExecuteQuery(query string) (records [][]string, count int, err error) {
+	err = r.write(func() error {
+		records, count, err = r.Driver.ExecuteQuery(query)
+		return err
+	})
+
+	return records, count, err
+}
+
+func (r *Resilient) ExecutePendingChanges(changes []models.DBDMLChange) error {
+	return r.write(func() error {
+		return r.Driver.ExecutePendingChanges(changes)
+	})
+}
@@ -0,0 +1,198 @@
+package drivers
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+	"testing"
+)
+
+func TestResilient_IsConnectionError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "nil", err: nil, expected: false},
+		{name: "bad connection", err: fmt.Errorf("query: %w", driver.ErrBadConn), expected: true},
+		{name: "refused", err: &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}, expected: true},
+		{name: "reset", err: fmt.Errorf("read: %w", syscall.ECONNRESET), expected: true},
+		{name: "mysql", err: errors.New("invalid connection"), expected: true},
+		{name: "message", err: errors.New("write tcp 127.0.0.1:5432: broken pipe"), expected: true},
+		{name: "syntax", err: errors.New(`syntax error at or near "SELEC"`), expected: false},
+		{name: "constraint", err: errors.New("duplicate key value violates unique constraint"), expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := IsConnectionError(tt.err); actual != tt.expected {
+				t.Errorf("IsConnectionError(%v) = %v, expected %v", tt.err, actual, tt.expected)
+			}
+		})
+	}
+}
+
+// flakyDriver fails the first calls with the error.
+type flakyDriver struct {
+	Driver
+	failures int
+	err      error
+	calls    int
+}
+
+func (d *flakyDriver) GetDatabases() ([]string, error) {
+	d.calls++
+
+	if d.calls <= d.failures {
+		return nil, d.err
+	}
+
+	return []string{"app"}, nil
+}
+
+func TestResilient_Retry(t *testing.T) {
+	tests := []struct {
+		name          string
+		failures      int
+		err           error
+		reconnectErr  error
+		expectedCalls int
+		wantErr       bool
+	}{
+		{name: "no error", failures: 0, expectedCalls: 1},
+		{name: "connection lost", failures: 1, err: driver.ErrBadConn, expectedCalls: 2},
+		{name: "still lost", failures: 2, err: driver.ErrBadConn, expectedCalls: 2, wantErr: true},
+		{name: "not reconnected", failures: 1, err: driver.ErrBadConn, reconnectErr: errors.New("down"), expectedCalls: 1, wantErr: true},
+		{name: "other error", failures: 1, err: errors.New("permission denied"), expectedCalls: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flaky := &flakyDriver{failures: tt.failures, err: tt.err}
+			reconnects := 0
+
+			resilient := &Resilient{Driver: flaky, Reconnect: func() error {
+				reconnects++
+				return tt.reconnectErr
+			}}
+
+			_, err := resilient.GetDatabases()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetDatabases() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if flaky.calls != tt.expectedCalls {
+				t.Errorf("GetDatabases() called the driver %d times, expected %d", flaky.calls, tt.expectedCalls)
+			}
+
+			if Unwrap(resilient) != flaky {
+				t.Errorf("Unwrap() did not return the wrapped driver")
+			}
+		})
+	}
+}
+
+func (d *flakyDriver) ExecuteDMLStatement(query string) (string, error) {
+	d.calls++
+
+	if d.calls <= d.failures {
+		return "", d.err
+	}
+
+	return "1 row affected", nil
+}
+
+func TestResilient_Write(t *testing.T) {
+	tests := []struct {
+		name          string
+		failures      int
+		err           error
+		waitErr       error
+		expectedCalls int
+		reconnects    int
+		wantErr       bool
+	}{
+		{name: "no error", expectedCalls: 1},
+		{name: "connection lost", failures: 1, err: driver.ErrBadConn, expectedCalls: 1, reconnects: 1, wantErr: true},
+		{name: "other error", failures: 1, err: errors.New("permission denied"), expectedCalls: 1, wantErr: true},
+		{name: "not reconnected", waitErr: errors.New("down"), expectedCalls: 0, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flaky := &flakyDriver{failures: tt.failures, err: tt.err}
+			reconnects, waits := 0, 0
+
+			resilient := &Resilient{
+				Driver: flaky,
+				Reconnect: func() error {
+					reconnects++
+					return nil
+				},
+				Wait: func() error {
+					waits++
+					return tt.waitErr
+				},
+			}
+
+			_, err := resilient.ExecuteDMLStatement("DELETE FROM users")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExecuteDMLStatement() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			// A statement is never run again, it could have been applied.
+			if flaky.calls != tt.expectedCalls || reconnects != tt.reconnects || waits != 1 {
+				t.Errorf("ExecuteDMLStatement() called the driver %d times and reconnected %d times, expected %d and %d", flaky.calls, reconnects, tt.expectedCalls, tt.reconnects)
+			}
+
+			if IsConnectionError(tt.err) && !errors.Is(err, tt.err) {
+				t.Errorf("expected the connection error to be wrapped, but got %v", err)
+			}
+		})
+	}
+}
+
+// blockingDriver blocks GetDatabases until release is closed.
+type blockingDriver struct {
+	Driver
+	started chan struct{}
+	release chan struct{}
+}
+
+func (d *blockingDriver) GetDatabases() ([]string, error) {
+	close(d.started)
+	<-d.release
+
+	return nil, nil
+}
+
+func TestResilient_Busy(t *testing.T) {
+	blocking := &blockingDriver{started: make(chan struct{}), release: make(chan struct{})}
+	resilient := &Resilient{Driver: blocking}
+
+	if resilient.Busy() {
+		t.Fatal("expected the driver not to be busy")
+	}
+
+	done := make(chan struct{})
+
+	go func() {
+		_, _ = resilient.GetDatabases()
+		close(done)
+	}()
+
+	<-blocking.started
+
+	if !resilient.Busy() {
+		t.Fatal("expected the driver to be busy while a call runs")
+	}
+
+	close(blocking.release)
+	<-done
+
+	if resilient.Busy() {
+		t.Fatal("expected the driver not to be busy once the call returned")
+	}
+}
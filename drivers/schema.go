@@ -0,0 +1,429 @@
+package drivers
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// Each driver returns its metadata with the column names of the underlying
+// catalog query, so these lists map the names used by every provider to the
+// fields of the normalized schema structs.
+var (
+	schemaColumnNameHeaders       = []string{"column_name", "field", "name"}
+	schemaColumnTypeHeaders       = []string{"data_type", "type"}
+	schemaColumnNullHeaders       = []string{"is_nullable", "null"}
+	schemaColumnNotNullHeaders    = []string{"notnull"}
+	schemaColumnDefaultHeaders    = []string{"column_default", "default", "dflt_value", "default_expression"}
+	schemaConstraintNameHeaders   = []string{"constraint_name"}
+	schemaConstraintTypeHeaders   = []string{"constraint_type"}
+	schemaReferencedTableHeaders  = []string{"referenced_table_name", "foreign_table_name", "referenced_table", "table"}
+	schemaReferencedColumnHeaders = []string{"referenced_column_name", "foreign_column_name", "referenced_column", "to"}
+	schemaForeignKeyColumnHeaders = []string{"column_name", "from"}
+	schemaForeignKeyNameHeaders   = []string{"constraint_name", "id"}
+	schemaIndexNameHeaders        = []string{"index_name", "key_name", "name"}
+	schemaIndexColumnHeaders      = []string{"column_name"}
+	schemaIndexUniqueHeaders      = []string{"is_unique", "unique"}
+	schemaIndexNonUniqueHeaders   = []string{"non_unique"}
+)
+
+// ListTables returns the tables of a database in the format expected by the
+// other driver methods. For Postgres and MSSQL that is "schema.table", the
+// Postgres system schemas are left out.
+func ListTables(db Driver, database string) ([]string, error) {
+	// SQLite only uses the database name to group the tables.
+	if database == "" && db.GetProvider() == DriverSqlite {
+		database = "main"
+	}
+
+	tables, err := db.GetTables(database)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+
+	for key, values := range tables {
+		if Dialect(db.GetProvider()) == DriverPostgres && (key == "pg_catalog" || key == "information_schema") {
+			continue
+		}
+
+		for _, table := range values {
+			if HasSchemas(db.GetProvider()) {
+				names = append(names, fmt.Sprintf("%s.%s", key, table))
+			} else {
+				names = append(names, table)
+			}
+		}
+	}
+
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// GetTableSchema reads the columns, constraints, foreign keys and indexes of a
+// table through the driver and normalizes them into a models.TableSchema.
+func GetTableSchema(db Driver, database, table string) (models.TableSchema, error) {
+	schema := models.TableSchema{Name: table}
+
+	columns, err := db.GetTableColumns(database, table)
+	if err != nil {
+		return schema, err
+	}
+
+	constraints, err := db.GetConstraints(database, table)
+	if err != nil {
+		return schema, err
+	}
+
+	foreignKeys, err := db.GetForeignKeys(database, table)
+	if err != nil {
+		return schema, err
+	}
+
+	indexes, err := db.GetIndexes(database, table)
+	if err != nil {
+		return schema, err
+	}
+
+	schema.Columns = normalizeColumns(columns)
+	schema.Constraints = normalizeConstraints(constraints)
+
+	// SQLite and ClickHouse do not list the primary key with the constraints.
+	if !slices.ContainsFunc(schema.Constraints, isPrimaryKeyConstraint) {
+		primaryKey, err := db.GetPrimaryKeyColumnNames(database, table)
+		if err != nil {
+			return schema, err
+		}
+
+		if len(primaryKey) > 0 {
+			schema.Constraints = append([]models.ConstraintSchema{{
+				Name:    tableNameWithoutSchema(table) + "_pkey",
+				Type:    "PRIMARY KEY",
+				Columns: primaryKey,
+			}}, schema.Constraints...)
+		}
+	}
+	schema.ForeignKeys = normalizeForeignKeys(table, foreignKeys, constraints)
+	schema.Indexes = normalizeIndexes(indexes)
+
+	if db.GetProvider() == DriverSqlite {
+		if schema.Indexes, err = sqliteIndexColumns(db, schema.Indexes); err != nil {
+			return schema, err
+		}
+	}
+
+	return schema, nil
+}
+
+// sqliteIndexColumns fills the columns of SQLite indexes, PRAGMA index_list
+// only returns their names. The indexes SQLite creates for primary keys and
+// unique constraints are left out, they are part of the table definition.
+func sqliteIndexColumns(db Driver, indexes []models.IndexSchema) ([]models.IndexSchema, error) {
+	var result []models.IndexSchema
+
+	for _, index := range indexes {
+		if strings.HasPrefix(index.Name, "sqlite_autoindex_") {
+			continue
+		}
+
+		rows, _, err := db.ExecuteQuery(fmt.Sprintf("PRAGMA index_info(%s)", db.FormatReference(index.Name)))
+		if err != nil {
+			return nil, err
+		}
+
+		if len(rows) > 0 {
+			nameIndex := headerIndex(rows[0], "name")
+
+			for _, row := range rows[1:] {
+				index.Columns = append(index.Columns, cellAt(row, nameIndex))
+			}
+		}
+
+		result = append(result, index)
+	}
+
+	return result, nil
+}
+
+func isPrimaryKeyConstraint(constraint models.ConstraintSchema) bool {
+	return constraint.Type == "PRIMARY KEY"
+}
+
+// GetDatabaseSchema returns the schema of every table in the database, keyed
+// by table name.
+func GetDatabaseSchema(db Driver, database string) (map[string]models.TableSchema, error) {
+	tables, err := ListTables(db, database)
+	if err != nil {
+		return nil, err
+	}
+
+	schemas := make(map[string]models.TableSchema, len(tables))
+
+	for _, table := range tables {
+		schema, err := GetTableSchema(db, database, table)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", table, err)
+		}
+		schemas[table] = schema
+	}
+
+	return schemas, nil
+}
+
+func normalizeColumns(rows [][]string) []models.ColumnSchema {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	header := rows[0]
+	nameIndex := headerIndex(header, schemaColumnNameHeaders...)
+	typeIndex := headerIndex(header, schemaColumnTypeHeaders...)
+	nullIndex := headerIndex(header, schemaColumnNullHeaders...)
+	notNullIndex := headerIndex(header, schemaColumnNotNullHeaders...)
+	defaultIndex := headerIndex(header, schemaColumnDefaultHeaders...)
+
+	if nameIndex == -1 {
+		return nil
+	}
+
+	columns := make([]models.ColumnSchema, 0, len(rows)-1)
+
+	for _, row := range rows[1:] {
+		column := models.ColumnSchema{
+			Name:    cellAt(row, nameIndex),
+			Type:    cellAt(row, typeIndex),
+			Default: cellAt(row, defaultIndex),
+		}
+
+		switch {
+		case nullIndex != -1:
+			column.Nullable = isTruthy(cellAt(row, nullIndex))
+		case notNullIndex != -1:
+			column.Nullable = !isTruthy(cellAt(row, notNullIndex))
+		default:
+			// ClickHouse encodes nullability in the type itself.
+			column.Nullable = strings.HasPrefix(column.Type, "Nullable(")
+		}
+
+		if column.Default == "NULL" {
+			column.Default = ""
+		}
+
+		columns = append(columns, column)
+	}
+
+	return columns
+}
+
+func normalizeConstraints(rows [][]string) []models.ConstraintSchema {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	header := rows[0]
+	nameIndex := headerIndex(header, schemaConstraintNameHeaders...)
+	columnIndex := headerIndex(header, schemaColumnNameHeaders...)
+	typeIndex := headerIndex(header, schemaConstraintTypeHeaders...)
+	referencedTableIndex := headerIndex(header, schemaReferencedTableHeaders...)
+
+	if nameIndex == -1 {
+		return nil
+	}
+
+	var constraints []models.ConstraintSchema
+	positions := map[string]int{}
+
+	for _, row := range rows[1:] {
+		name := cellAt(row, nameIndex)
+		constraintType := normalizeConstraintType(cellAt(row, typeIndex), name)
+
+		if referencedTable := cellAt(row, referencedTableIndex); referencedTable != "" && referencedTable != "NULL" {
+			// MySQL returns the foreign keys together with the other constraints.
+			continue
+		}
+
+		position, ok := positions[name]
+		if !ok {
+			position = len(constraints)
+			positions[name] = position
+			constraints = append(constraints, models.ConstraintSchema{Name: name, Type: constraintType})
+		}
+
+		if column := cellAt(row, columnIndex); column != "" && !containsString(constraints[position].Columns, column) {
+			constraints[position].Columns = append(constraints[position].Columns, column)
+		}
+	}
+
+	return constraints
+}
+
+func normalizeConstraintType(constraintType, name string) string {
+	upper := strings.ToUpper(strings.ReplaceAll(constraintType, "_", " "))
+
+	switch {
+	case strings.HasPrefix(upper, "PRIMARY KEY"), constraintType == "" && name == "PRIMARY":
+		return "PRIMARY KEY"
+	case strings.HasPrefix(upper, "UNIQUE"), constraintType == "":
+		return "UNIQUE"
+	case strings.HasPrefix(upper, "FOREIGN KEY"):
+		return "FOREIGN KEY"
+	}
+
+	return upper
+}
+
+func normalizeForeignKeys(table string, rows [][]string, constraintRows [][]string) []models.ForeignKeySchema {
+	var foreignKeys []models.ForeignKeySchema
+	positions := map[string]int{}
+
+	add := func(name, column, referencedTable, referencedColumn string) {
+		position, ok := positions[name]
+		if !ok {
+			position = len(foreignKeys)
+			positions[name] = position
+			foreignKeys = append(foreignKeys, models.ForeignKeySchema{Name: name, ReferencedTable: referencedTable})
+		}
+
+		if column != "" && !containsString(foreignKeys[position].Columns, column) {
+			foreignKeys[position].Columns = append(foreignKeys[position].Columns, column)
+			foreignKeys[position].ReferencedColumns = append(foreignKeys[position].ReferencedColumns, referencedColumn)
+		}
+	}
+
+	if len(rows) > 0 {
+		header := rows[0]
+		nameIndex := headerIndex(header, schemaForeignKeyNameHeaders...)
+		columnIndex := headerIndex(header, schemaForeignKeyColumnHeaders...)
+		referencedTableIndex := headerIndex(header, schemaReferencedTableHeaders...)
+		referencedColumnIndex := headerIndex(header, schemaReferencedColumnHeaders...)
+		tableIndex := headerIndex(header, "table_name")
+
+		if nameIndex != -1 && referencedTableIndex != -1 {
+			for _, row := range rows[1:] {
+				// MySQL lists the foreign keys referencing the table instead of
+				// the ones defined on it, those are read from the constraints.
+				if tableIndex != -1 && cellAt(row, tableIndex) != tableNameWithoutSchema(table) {
+					continue
+				}
+
+				add(cellAt(row, nameIndex), cellAt(row, columnIndex), cellAt(row, referencedTableIndex), cellAt(row, referencedColumnIndex))
+			}
+		}
+	}
+
+	if len(constraintRows) > 0 {
+		header := constraintRows[0]
+		nameIndex := headerIndex(header, schemaConstraintNameHeaders...)
+		columnIndex := headerIndex(header, schemaColumnNameHeaders...)
+		referencedTableIndex := headerIndex(header, "referenced_table_name")
+		referencedColumnIndex := headerIndex(header, "referenced_column_name")
+
+		if nameIndex != -1 && referencedTableIndex != -1 {
+			for _, row := range constraintRows[1:] {
+				referencedTable := cellAt(row, referencedTableIndex)
+				if referencedTable == "" || referencedTable == "NULL" {
+					continue
+				}
+
+				add(cellAt(row, nameIndex), cellAt(row, columnIndex), referencedTable, cellAt(row, referencedColumnIndex))
+			}
+		}
+	}
+
+	return foreignKeys
+}
+
+func normalizeIndexes(rows [][]string) []models.IndexSchema {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	header := rows[0]
+	nameIndex := headerIndex(header, schemaIndexNameHeaders...)
+	columnIndex := headerIndex(header, schemaIndexColumnHeaders...)
+	uniqueIndex := headerIndex(header, schemaIndexUniqueHeaders...)
+	nonUniqueIndex := headerIndex(header, schemaIndexNonUniqueHeaders...)
+
+	if nameIndex == -1 {
+		return nil
+	}
+
+	var indexes []models.IndexSchema
+	positions := map[string]int{}
+
+	for _, row := range rows[1:] {
+		name := cellAt(row, nameIndex)
+
+		position, ok := positions[name]
+		if !ok {
+			position = len(indexes)
+			positions[name] = position
+
+			index := models.IndexSchema{Name: name}
+			if uniqueIndex != -1 {
+				index.Unique = isTruthy(cellAt(row, uniqueIndex))
+			} else if nonUniqueIndex != -1 {
+				index.Unique = !isTruthy(cellAt(row, nonUniqueIndex))
+			}
+
+			indexes = append(indexes, index)
+		}
+
+		if column := cellAt(row, columnIndex); column != "" && !containsString(indexes[position].Columns, column) {
+			indexes[position].Columns = append(indexes[position].Columns, column)
+		}
+	}
+
+	return indexes
+}
+
+// headerIndex returns the index of the first header matching one of the
+// given names (case insensitive), or -1 if there is none.
+func headerIndex(header []string, names ...string) int {
+	for _, name := range names {
+		for i, column := range header {
+			if strings.EqualFold(column, name) {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+func cellAt(row []string, index int) string {
+	if index < 0 || index >= len(row) {
+		return ""
+	}
+
+	return row[index]
+}
+
+func isTruthy(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "1", "yes", "true", "t", "y":
+		return true
+	}
+
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func tableNameWithoutSchema(table string) string {
+	split := strings.Split(table, ".")
+	return split[len(split)-1]
+}
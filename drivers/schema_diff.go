@@ -0,0 +1,581 @@
+package drivers
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// Schema object kinds used in models.SchemaObjectDiff.
+const (
+	SchemaObjectColumn     = "column"
+	SchemaObjectConstraint = "constraint"
+	SchemaObjectForeignKey = "foreign key"
+	SchemaObjectIndex      = "index"
+)
+
+// DiffSchemas compares two sets of table schemas. The source is the desired
+// state and the target is the one being migrated, so an object that only
+// exists in the source is reported as added.
+func DiffSchemas(source, target map[string]models.TableSchema) models.SchemaDiff {
+	names := map[string]struct{}{}
+	for name := range source {
+		names[name] = struct{}{}
+	}
+	for name := range target {
+		names[name] = struct{}{}
+	}
+
+	diff := models.SchemaDiff{}
+
+	for _, name := range slices.Sorted(maps.Keys(names)) {
+		sourceTable, inSource := source[name]
+		targetTable, inTarget := target[name]
+
+		tableDiff := models.TableSchemaDiff{Name: name}
+
+		switch {
+		case inSource && !inTarget:
+			tableDiff.Status = models.SchemaDiffAdded
+			tableDiff.Source = &sourceTable
+			tableDiff.Objects = diffTableObjects(&sourceTable, nil)
+		case !inSource && inTarget:
+			tableDiff.Status = models.SchemaDiffRemoved
+			tableDiff.Target = &targetTable
+			tableDiff.Objects = diffTableObjects(nil, &targetTable)
+		default:
+			tableDiff.Source = &sourceTable
+			tableDiff.Target = &targetTable
+			tableDiff.Objects = diffTableObjects(&sourceTable, &targetTable)
+			tableDiff.Status = models.SchemaDiffUnchanged
+
+			for _, object := range tableDiff.Objects {
+				if object.Status != models.SchemaDiffUnchanged {
+					tableDiff.Status = models.SchemaDiffChanged
+					break
+				}
+			}
+		}
+
+		diff.Tables = append(diff.Tables, tableDiff)
+	}
+
+	return diff
+}
+
+// ConvertSchemas translates the table schemas of the source provider to the
+// table names and the column types of the target provider, so that they can be
+// compared with the schemas of the target. A table is named like the target
+// table with the same name without its schema, or is put in the default schema
+// of the target.
+func ConvertSchemas(source map[string]models.TableSchema, sourceProvider string, target map[string]models.TableSchema, targetProvider string) map[string]models.TableSchema {
+	sourceProvider = Dialect(sourceProvider)
+	targetProvider = Dialect(targetProvider)
+
+	if sourceProvider == targetProvider {
+		return source
+	}
+
+	targetNames := map[string]string{}
+	for _, name := range slices.Sorted(maps.Keys(target)) {
+		if _, ok := targetNames[tableNameWithoutSchema(name)]; !ok {
+			targetNames[tableNameWithoutSchema(name)] = name
+		}
+	}
+
+	rename := func(table string) string {
+		name := tableNameWithoutSchema(table)
+
+		if targetName, ok := targetNames[name]; ok {
+			return targetName
+		}
+
+		if schema := defaultSchema(targetProvider); schema != "" {
+			return schema + "." + name
+		}
+
+		return name
+	}
+
+	converted := make(map[string]models.TableSchema, len(source))
+
+	for _, table := range source {
+		table.Name = rename(table.Name)
+
+		table.Columns = slices.Clone(table.Columns)
+		for i, column := range table.Columns {
+			table.Columns[i].Type = MapColumnType(sourceProvider, targetProvider, column.Type)
+
+			// ClickHouse encodes nullability in the type.
+			if targetProvider == DriverClickhouse && column.Nullable {
+				table.Columns[i].Type = fmt.Sprintf("Nullable(%s)", table.Columns[i].Type)
+			}
+		}
+
+		table.ForeignKeys = slices.Clone(table.ForeignKeys)
+		for i, foreignKey := range table.ForeignKeys {
+			table.ForeignKeys[i].ReferencedTable = rename(foreignKey.ReferencedTable)
+		}
+
+		converted[table.Name] = table
+	}
+
+	return converted
+}
+
+// defaultSchema returns the schema the tables of a provider are created in
+// when none is given, or an empty string for providers without schemas.
+func defaultSchema(provider string) string {
+	switch Dialect(provider) {
+	case DriverPostgres:
+		return "public"
+	case DriverMSSQL:
+		return "dbo"
+	}
+
+	return ""
+}
+
+// HasChanges reports whether the diff contains anything to migrate.
+func HasChanges(diff models.SchemaDiff) bool {
+	for _, table := range diff.Tables {
+		if table.Status != models.SchemaDiffUnchanged {
+			return true
+		}
+	}
+
+	return false
+}
+
+type schemaObject struct {
+	name       string
+	definition string
+}
+
+func diffTableObjects(source, target *models.TableSchema) []models.SchemaObjectDiff {
+	var objects []models.SchemaObjectDiff
+
+	collect := func(table *models.TableSchema) map[string][]schemaObject {
+		result := map[string][]schemaObject{}
+		if table == nil {
+			return result
+		}
+
+		for _, column := range table.Columns {
+			result[SchemaObjectColumn] = append(result[SchemaObjectColumn], schemaObject{column.Name, describeColumn(column)})
+		}
+		for _, constraint := range table.Constraints {
+			result[SchemaObjectConstraint] = append(result[SchemaObjectConstraint], schemaObject{constraint.Name, describeConstraint(constraint)})
+		}
+		for _, foreignKey := range table.ForeignKeys {
+			result[SchemaObjectForeignKey] = append(result[SchemaObjectForeignKey], schemaObject{foreignKey.Name, describeForeignKey(foreignKey)})
+		}
+		for _, index := range table.Indexes {
+			result[SchemaObjectIndex] = append(result[SchemaObjectIndex], schemaObject{index.Name, describeIndex(index)})
+		}
+
+		return result
+	}
+
+	sourceObjects := collect(source)
+	targetObjects := collect(target)
+
+	for _, kind := range []string{SchemaObjectColumn, SchemaObjectConstraint, SchemaObjectForeignKey, SchemaObjectIndex} {
+		targetByName := map[string]schemaObject{}
+		for _, object := range targetObjects[kind] {
+			targetByName[object.name] = object
+		}
+
+		seen := map[string]bool{}
+
+		for _, object := range sourceObjects[kind] {
+			seen[object.name] = true
+
+			objectDiff := models.SchemaObjectDiff{Kind: kind, Name: object.name, Source: object.definition}
+
+			targetObject, ok := targetByName[object.name]
+			switch {
+			case !ok:
+				objectDiff.Status = models.SchemaDiffAdded
+			case !strings.EqualFold(targetObject.definition, object.definition):
+				objectDiff.Status = models.SchemaDiffChanged
+				objectDiff.Target = targetObject.definition
+			default:
+				objectDiff.Status = models.SchemaDiffUnchanged
+				objectDiff.Target = targetObject.definition
+			}
+
+			objects = append(objects, objectDiff)
+		}
+
+		for _, object := range targetObjects[kind] {
+			if !seen[object.name] {
+				objects = append(objects, models.SchemaObjectDiff{Kind: kind, Name: object.name, Target: object.definition, Status: models.SchemaDiffRemoved})
+			}
+		}
+	}
+
+	return objects
+}
+
+func describeColumn(column models.ColumnSchema) string {
+	definition := column.Type
+
+	if !column.Nullable {
+		definition += " NOT NULL"
+	}
+
+	if column.Default != "" {
+		definition += " DEFAULT " + column.Default
+	}
+
+	return definition
+}
+
+func describeConstraint(constraint models.ConstraintSchema) string {
+	return fmt.Sprintf("%s (%s)", constraint.Type, strings.Join(constraint.Columns, ", "))
+}
+
+func describeForeignKey(foreignKey models.ForeignKeySchema) string {
+	return fmt.Sprintf("(%s) REFERENCES %s (%s)", strings.Join(foreignKey.Columns, ", "), foreignKey.ReferencedTable, strings.Join(foreignKey.ReferencedColumns, ", "))
+}
+
+func describeIndex(index models.IndexSchema) string {
+	definition := fmt.Sprintf("(%s)", strings.Join(index.Columns, ", "))
+
+	if index.Unique {
+		definition = "UNIQUE " + definition
+	}
+
+	return definition
+}
+
+// GenerateMigration builds the statements that bring the target database in
+// line with the source, using the dialect of the target driver.
+func GenerateMigration(diff models.SchemaDiff, target Driver) []string {
+	var statements []string
+
+	for _, table := range diff.Tables {
+		switch table.Status {
+		case models.SchemaDiffAdded:
+			statements = append(statements, buildCreateTableStatement(*table.Source, target))
+			for _, index := range table.Source.Indexes {
+				if !isConstraintIndex(*table.Source, index.Name) {
+					statements = append(statements, buildCreateIndexStatement(table.Name, index, target))
+				}
+			}
+		case models.SchemaDiffRemoved:
+			statements = append(statements, fmt.Sprintf("DROP TABLE %s", formatQualifiedReference(table.Name, target)))
+		case models.SchemaDiffChanged:
+			statements = append(statements, buildAlterTableStatements(table, target)...)
+		}
+	}
+
+	for i := range statements {
+		if !strings.HasPrefix(statements[i], "--") {
+			statements[i] += ";"
+		}
+	}
+
+	return statements
+}
+
+func buildAlterTableStatements(table models.TableSchemaDiff, target Driver) []string {
+	var statements []string
+
+	tableName := formatQualifiedReference(table.Name, target)
+	provider := Dialect(target.GetProvider())
+
+	// Drops go first so that renamed objects do not collide with their
+	// previous definitions.
+	for _, object := range table.Objects {
+		if object.Status != models.SchemaDiffRemoved && object.Status != models.SchemaDiffChanged {
+			continue
+		}
+
+		if object.Kind == SchemaObjectColumn && object.Status == models.SchemaDiffChanged {
+			continue
+		}
+
+		switch object.Kind {
+		case SchemaObjectColumn:
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", tableName, target.FormatReference(object.Name)))
+		case SchemaObjectIndex:
+			if isConstraintIndex(*table.Target, object.Name) {
+				continue
+			}
+			statements = append(statements, buildDropIndexStatement(table.Name, object.Name, target))
+		case SchemaObjectConstraint, SchemaObjectForeignKey:
+			statements = append(statements, buildDropConstraintStatement(tableName, object, target))
+		}
+	}
+
+	for _, object := range table.Objects {
+		if object.Status != models.SchemaDiffAdded && object.Status != models.SchemaDiffChanged {
+			continue
+		}
+
+		switch object.Kind {
+		case SchemaObjectColumn:
+			column := findColumn(table.Source.Columns, object.Name)
+
+			if object.Status == models.SchemaDiffAdded {
+				statements = append(statements, fmt.Sprintf("ALTER TABLE %s ADD %s", tableName, buildColumnDefinition(column, target)))
+				continue
+			}
+
+			statements = append(statements, buildAlterColumnStatements(tableName, column, target)...)
+		case SchemaObjectIndex:
+			if isConstraintIndex(*table.Source, object.Name) {
+				continue
+			}
+			for _, index := range table.Source.Indexes {
+				if index.Name == object.Name {
+					statements = append(statements, buildCreateIndexStatement(table.Name, index, target))
+				}
+			}
+		case SchemaObjectConstraint:
+			for _, constraint := range table.Source.Constraints {
+				if constraint.Name == object.Name {
+					statements = append(statements, buildAddConstraintStatement(tableName, constraint, target))
+				}
+			}
+		case SchemaObjectForeignKey:
+			for _, foreignKey := range table.Source.ForeignKeys {
+				if foreignKey.Name == object.Name {
+					if provider == DriverSqlite || provider == DriverClickhouse || provider == DriverDuckDB {
+						statements = append(statements, fmt.Sprintf("-- %s does not support adding foreign key %s to an existing table", provider, foreignKey.Name))
+						continue
+					}
+					statements = append(statements, fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s FOREIGN KEY %s", tableName, target.FormatReference(foreignKey.Name), buildForeignKeyReference(foreignKey, target)))
+				}
+			}
+		}
+	}
+
+	return statements
+}
+
+func buildCreateTableStatement(table models.TableSchema, target Driver) string {
+	var definitions []string
+
+	for _, column := range table.Columns {
+		definitions = append(definitions, "  "+buildColumnDefinition(column, target))
+	}
+
+	for _, constraint := range table.Constraints {
+		definitions = append(definitions, "  "+buildConstraintDefinition(constraint, target))
+	}
+
+	if target.GetProvider() != DriverClickhouse {
+		for _, foreignKey := range table.ForeignKeys {
+			definitions = append(definitions, fmt.Sprintf("  CONSTRAINT %s FOREIGN KEY %s", target.FormatReference(foreignKey.Name), buildForeignKeyReference(foreignKey, target)))
+		}
+	}
+
+	statement := fmt.Sprintf("CREATE TABLE %s (\n%s\n)", formatQualifiedReference(table.Name, target), strings.Join(definitions, ",\n"))
+
+	if target.GetProvider() == DriverClickhouse {
+		statement += " ENGINE = MergeTree ORDER BY " + clickhouseOrderBy(table, target)
+	}
+
+	return statement
+}
+
+func clickhouseOrderBy(table models.TableSchema, target Driver) string {
+	for _, constraint := range table.Constraints {
+		if constraint.Type == "PRIMARY KEY" {
+			return fmt.Sprintf("(%s)", formatReferences(constraint.Columns, target))
+		}
+	}
+
+	return "tuple()"
+}
+
+func buildColumnDefinition(column models.ColumnSchema, target Driver) string {
+	definition := fmt.Sprintf("%s %s", target.FormatReference(column.Name), column.Type)
+
+	// ClickHouse encodes nullability in the type.
+	if target.GetProvider() != DriverClickhouse && !column.Nullable {
+		definition += " NOT NULL"
+	}
+
+	if column.Default != "" {
+		definition += " DEFAULT " + column.Default
+	}
+
+	return definition
+}
+
+func buildAlterColumnStatements(tableName string, column models.ColumnSchema, target Driver) []string {
+	columnName := target.FormatReference(column.Name)
+
+	switch Dialect(target.GetProvider()) {
+	case DriverPostgres, DriverDuckDB:
+		statements := []string{fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE %s", tableName, columnName, column.Type)}
+
+		if column.Nullable {
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP NOT NULL", tableName, columnName))
+		} else {
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET NOT NULL", tableName, columnName))
+		}
+
+		if column.Default != "" {
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET DEFAULT %s", tableName, columnName, column.Default))
+		} else {
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP DEFAULT", tableName, columnName))
+		}
+
+		return statements
+	case DriverMSSQL:
+		nullability := "NULL"
+		if !column.Nullable {
+			nullability = "NOT NULL"
+		}
+
+		return []string{fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s %s %s", tableName, columnName, column.Type, nullability)}
+	case DriverSqlite:
+		return []string{fmt.Sprintf("-- SQLite can not alter column %s of %s, the table has to be rebuilt", column.Name, tableName)}
+	case DriverOracle:
+		nullability := "NULL"
+		if !column.Nullable {
+			nullability = "NOT NULL"
+		}
+
+		definition := fmt.Sprintf("%s %s", columnName, column.Type)
+		if column.Default != "" {
+			definition += " DEFAULT " + column.Default
+		}
+
+		return []string{fmt.Sprintf("ALTER TABLE %s MODIFY (%s %s)", tableName, definition, nullability)}
+	case DriverMySQL, DriverClickhouse:
+		return []string{fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s", tableName, buildColumnDefinition(column, target))}
+	default:
+		return []string{fmt.Sprintf("-- %s does not support altering column %s of %s", target.GetProvider(), column.Name, tableName)}
+	}
+}
+
+func buildConstraintDefinition(constraint models.ConstraintSchema, target Driver) string {
+	if constraint.Type == "PRIMARY KEY" && target.GetProvider() != DriverMSSQL && Dialect(target.GetProvider()) != DriverPostgres {
+		return fmt.Sprintf("PRIMARY KEY (%s)", formatReferences(constraint.Columns, target))
+	}
+
+	return fmt.Sprintf("CONSTRAINT %s %s (%s)", target.FormatReference(constraint.Name), constraint.Type, formatReferences(constraint.Columns, target))
+}
+
+func buildAddConstraintStatement(tableName string, constraint models.ConstraintSchema, target Driver) string {
+	switch Dialect(target.GetProvider()) {
+	case DriverSqlite, DriverClickhouse, DriverDuckDB:
+		return fmt.Sprintf("-- %s does not support adding constraint %s to an existing table", target.GetProvider(), constraint.Name)
+	}
+
+	return fmt.Sprintf("ALTER TABLE %s ADD %s", tableName, buildConstraintDefinition(constraint, target))
+}
+
+func buildDropConstraintStatement(tableName string, object models.SchemaObjectDiff, target Driver) string {
+	name := target.FormatReference(object.Name)
+
+	switch Dialect(target.GetProvider()) {
+	case DriverSqlite, DriverClickhouse, DriverDuckDB:
+		return fmt.Sprintf("-- %s does not support dropping %s %s from an existing table", target.GetProvider(), object.Kind, object.Name)
+	case DriverMySQL:
+		if object.Kind == SchemaObjectForeignKey {
+			return fmt.Sprintf("ALTER TABLE %s DROP FOREIGN KEY %s", tableName, name)
+		}
+		if object.Name == "PRIMARY" {
+			return fmt.Sprintf("ALTER TABLE %s DROP PRIMARY KEY", tableName)
+		}
+		return fmt.Sprintf("ALTER TABLE %s DROP INDEX %s", tableName, name)
+	}
+
+	return fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s", tableName, name)
+}
+
+func buildForeignKeyReference(foreignKey models.ForeignKeySchema, target Driver) string {
+	return fmt.Sprintf("(%s) REFERENCES %s (%s)", formatReferences(foreignKey.Columns, target), formatQualifiedReference(foreignKey.ReferencedTable, target), formatReferences(foreignKey.ReferencedColumns, target))
+}
+
+func buildCreateIndexStatement(table string, index models.IndexSchema, target Driver) string {
+	tableName := formatQualifiedReference(table, target)
+
+	if target.GetProvider() == DriverClickhouse {
+		return fmt.Sprintf("ALTER TABLE %s ADD INDEX %s (%s) TYPE minmax", tableName, target.FormatReference(index.Name), formatReferences(index.Columns, target))
+	}
+
+	statement := "CREATE INDEX"
+	if index.Unique {
+		statement = "CREATE UNIQUE INDEX"
+	}
+
+	return fmt.Sprintf("%s %s ON %s (%s)", statement, target.FormatReference(index.Name), tableName, formatReferences(index.Columns, target))
+}
+
+func buildDropIndexStatement(table, index string, target Driver) string {
+	tableName := formatQualifiedReference(table, target)
+	indexName := target.FormatReference(index)
+
+	switch Dialect(target.GetProvider()) {
+	case DriverMySQL, DriverMSSQL:
+		return fmt.Sprintf("DROP INDEX %s ON %s", indexName, tableName)
+	case DriverClickhouse:
+		return fmt.Sprintf("ALTER TABLE %s DROP INDEX %s", tableName, indexName)
+	case DriverPostgres:
+		split := strings.Split(table, ".")
+		if len(split) > 1 {
+			return fmt.Sprintf("DROP INDEX %s.%s", target.FormatReference(split[0]), indexName)
+		}
+	}
+
+	return fmt.Sprintf("DROP INDEX %s", indexName)
+}
+
+// isConstraintIndex reports whether an index backs a primary key or unique
+// constraint, those are created and dropped together with the constraint.
+func isConstraintIndex(table models.TableSchema, index string) bool {
+	if index == "PRIMARY" {
+		return true
+	}
+
+	for _, constraint := range table.Constraints {
+		if constraint.Name == index {
+			return true
+		}
+	}
+
+	return false
+}
+
+func findColumn(columns []models.ColumnSchema, name string) models.ColumnSchema {
+	for _, column := range columns {
+		if column.Name == name {
+			return column
+		}
+	}
+
+	return models.ColumnSchema{Name: name}
+}
+
+func formatReferences(references []string, target Driver) string {
+	formatted := make([]string, len(references))
+
+	for i, reference := range references {
+		formatted[i] = target.FormatReference(reference)
+	}
+
+	return strings.Join(formatted, ", ")
+}
+
+// formatQualifiedReference formats every part of a dotted reference like
+// "schema.table" on its own.
+func formatQualifiedReference(reference string, target Driver) string {
+	split := strings.Split(reference, ".")
+
+	for i, part := range split {
+		split[i] = target.FormatReference(part)
+	}
+
+	return strings.Join(split, ".")
+}
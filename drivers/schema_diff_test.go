@@ -0,0 +1,364 @@
+package drivers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+func TestNormalizeColumns(t *testing.T) {
+	testCases := []struct {
+		name     string
+		rows     [][]string
+		expected []models.ColumnSchema
+	}{
+		{
+			name: "MySQL describe",
+			rows: [][]string{
+				{"Field", "Type", "Null", "Key", "Default", "Extra"},
+				{"id", "int", "NO", "PRI", "NULL", "auto_increment"},
+				{"name", "varchar(255)", "YES", "", "'unknown'", ""},
+			},
+			expected: []models.ColumnSchema{
+				{Name: "id", Type: "int", Nullable: false},
+				{Name: "name", Type: "varchar(255)", Nullable: true, Default: "'unknown'"},
+			},
+		},
+		{
+			name: "SQLite table_info",
+			rows: [][]string{
+				{"name", "type", "notnull", "dflt_value", "pk"},
+				{"id", "INTEGER", "1", "", "1"},
+				{"name", "TEXT", "0", "", "0"},
+			},
+			expected: []models.ColumnSchema{
+				{Name: "id", Type: "INTEGER", Nullable: false},
+				{Name: "name", Type: "TEXT", Nullable: true},
+			},
+		},
+		{
+			name: "ClickHouse describe",
+			rows: [][]string{
+				{"name", "type", "default_type", "default_expression"},
+				{"id", "UInt64", "", ""},
+				{"name", "Nullable(String)", "", ""},
+			},
+			expected: []models.ColumnSchema{
+				{Name: "id", Type: "UInt64", Nullable: false},
+				{Name: "name", Type: "Nullable(String)", Nullable: true},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			columns := normalizeColumns(tc.rows)
+			if !reflect.DeepEqual(columns, tc.expected) {
+				t.Fatalf("expected %v, but got %v", tc.expected, columns)
+			}
+		})
+	}
+}
+
+func TestNormalizeForeignKeys_MySQL(t *testing.T) {
+	constraints := [][]string{
+		{"CONSTRAINT_NAME", "COLUMN_NAME", "REFERENCED_TABLE_NAME", "REFERENCED_COLUMN_NAME"},
+		{"PRIMARY", "id", "NULL", "NULL"},
+		{"orders_user_fk", "user_id", "users", "id"},
+	}
+
+	// MySQL returns the foreign keys pointing to the table, those belong to
+	// the referencing table.
+	foreignKeys := [][]string{
+		{"TABLE_NAME", "COLUMN_NAME", "CONSTRAINT_NAME", "REFERENCED_COLUMN_NAME", "REFERENCED_TABLE_NAME"},
+		{"items", "order_id", "items_order_fk", "id", "orders"},
+	}
+
+	expected := []models.ForeignKeySchema{
+		{Name: "orders_user_fk", Columns: []string{"user_id"}, ReferencedTable: "users", ReferencedColumns: []string{"id"}},
+	}
+
+	result := normalizeForeignKeys("orders", foreignKeys, constraints)
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("expected %v, but got %v", expected, result)
+	}
+
+	expectedConstraints := []models.ConstraintSchema{
+		{Name: "PRIMARY", Type: "PRIMARY KEY", Columns: []string{"id"}},
+	}
+
+	resultConstraints := normalizeConstraints(constraints)
+	if !reflect.DeepEqual(resultConstraints, expectedConstraints) {
+		t.Fatalf("expected %v, but got %v", expectedConstraints, resultConstraints)
+	}
+}
+
+func testSchemas() (map[string]models.TableSchema, map[string]models.TableSchema) {
+	source := map[string]models.TableSchema{
+		"public.users": {
+			Name: "public.users",
+			Columns: []models.ColumnSchema{
+				{Name: "id", Type: "integer"},
+				{Name: "email", Type: "text"},
+				{Name: "name", Type: "text", Nullable: true},
+			},
+			Constraints: []models.ConstraintSchema{
+				{Name: "users_pkey", Type: "PRIMARY KEY", Columns: []string{"id"}},
+			},
+			Indexes: []models.IndexSchema{
+				{Name: "users_pkey", Columns: []string{"id"}, Unique: true},
+				{Name: "users_email_idx", Columns: []string{"email"}, Unique: true},
+			},
+		},
+		"public.posts": {
+			Name: "public.posts",
+			Columns: []models.ColumnSchema{
+				{Name: "id", Type: "integer"},
+				{Name: "user_id", Type: "integer"},
+			},
+			ForeignKeys: []models.ForeignKeySchema{
+				{Name: "posts_user_fk", Columns: []string{"user_id"}, ReferencedTable: "public.users", ReferencedColumns: []string{"id"}},
+			},
+		},
+	}
+
+	target := map[string]models.TableSchema{
+		"public.users": {
+			Name: "public.users",
+			Columns: []models.ColumnSchema{
+				{Name: "id", Type: "integer"},
+				{Name: "name", Type: "varchar(50)", Nullable: true},
+				{Name: "age", Type: "integer", Nullable: true},
+			},
+			Constraints: []models.ConstraintSchema{
+				{Name: "users_pkey", Type: "PRIMARY KEY", Columns: []string{"id"}},
+			},
+			Indexes: []models.IndexSchema{
+				{Name: "users_pkey", Columns: []string{"id"}, Unique: true},
+			},
+		},
+		"public.legacy": {
+			Name:    "public.legacy",
+			Columns: []models.ColumnSchema{{Name: "id", Type: "integer"}},
+		},
+	}
+
+	return source, target
+}
+
+func TestDiffSchemas(t *testing.T) {
+	source, target := testSchemas()
+
+	diff := DiffSchemas(source, target)
+
+	expectedTables := map[string]models.SchemaDiffStatus{
+		"public.legacy": models.SchemaDiffRemoved,
+		"public.posts":  models.SchemaDiffAdded,
+		"public.users":  models.SchemaDiffChanged,
+	}
+
+	if len(diff.Tables) != len(expectedTables) {
+		t.Fatalf("expected %d tables, but got %d", len(expectedTables), len(diff.Tables))
+	}
+
+	for _, table := range diff.Tables {
+		if table.Status != expectedTables[table.Name] {
+			t.Fatalf("expected %s to have status %d, but got %d", table.Name, expectedTables[table.Name], table.Status)
+		}
+	}
+
+	users := diff.Tables[2]
+
+	expectedObjects := map[string]models.SchemaDiffStatus{
+		"column id":             models.SchemaDiffUnchanged,
+		"column email":          models.SchemaDiffAdded,
+		"column name":           models.SchemaDiffChanged,
+		"column age":            models.SchemaDiffRemoved,
+		"constraint users_pkey": models.SchemaDiffUnchanged,
+		"index users_pkey":      models.SchemaDiffUnchanged,
+		"index users_email_idx": models.SchemaDiffAdded,
+	}
+
+	if len(users.Objects) != len(expectedObjects) {
+		t.Fatalf("expected %d objects, but got %d", len(expectedObjects), len(users.Objects))
+	}
+
+	for _, object := range users.Objects {
+		key := object.Kind + " " + object.Name
+		if object.Status != expectedObjects[key] {
+			t.Fatalf("expected %s to have status %d, but got %d", key, expectedObjects[key], object.Status)
+		}
+	}
+
+	if HasChanges(DiffSchemas(source, source)) {
+		t.Fatal("expected no changes when comparing a schema with itself")
+	}
+}
+
+func TestGenerateMigration_Postgres(t *testing.T) {
+	source, target := testSchemas()
+
+	migration := GenerateMigration(DiffSchemas(source, target), &Postgres{Provider: DriverPostgres})
+
+	expected := []string{
+		`DROP TABLE "public"."legacy";`,
+		"CREATE TABLE \"public\".\"posts\" (\n  \"id\" integer NOT NULL,\n  \"user_id\" integer NOT NULL,\n  CONSTRAINT \"posts_user_fk\" FOREIGN KEY (\"user_id\") REFERENCES \"public\".\"users\" (\"id\")\n);",
+		`ALTER TABLE "public"."users" DROP COLUMN "age";`,
+		`ALTER TABLE "public"."users" ADD "email" text NOT NULL;`,
+		`ALTER TABLE "public"."users" ALTER COLUMN "name" TYPE text;`,
+		`ALTER TABLE "public"."users" ALTER COLUMN "name" DROP NOT NULL;`,
+		`ALTER TABLE "public"."users" ALTER COLUMN "name" DROP DEFAULT;`,
+		`CREATE UNIQUE INDEX "users_email_idx" ON "public"."users" ("email");`,
+	}
+
+	if !reflect.DeepEqual(migration, expected) {
+		t.Fatalf("expected %q, but got %q", expected, migration)
+	}
+}
+
+func TestGenerateMigration_MySQL(t *testing.T) {
+	source := map[string]models.TableSchema{
+		"users": {
+			Name: "users",
+			Columns: []models.ColumnSchema{
+				{Name: "id", Type: "int"},
+				{Name: "name", Type: "varchar(100)", Nullable: true},
+			},
+		},
+	}
+
+	target := map[string]models.TableSchema{
+		"users": {
+			Name: "users",
+			Columns: []models.ColumnSchema{
+				{Name: "id", Type: "int"},
+				{Name: "name", Type: "varchar(50)", Nullable: true},
+			},
+			Indexes: []models.IndexSchema{
+				{Name: "users_name_idx", Columns: []string{"name"}},
+			},
+		},
+	}
+
+	migration := GenerateMigration(DiffSchemas(source, target), &MySQL{Provider: DriverMySQL})
+
+	expected := []string{
+		"DROP INDEX `users_name_idx` ON `users`;",
+		"ALTER TABLE `users` MODIFY COLUMN `name` varchar(100);",
+	}
+
+	if !reflect.DeepEqual(migration, expected) {
+		t.Fatalf("expected %q, but got %q", expected, migration)
+	}
+}
+
+func TestConvertSchemas_MySQLToPostgres(t *testing.T) {
+	source := map[string]models.TableSchema{
+		"users": {
+			Name: "users",
+			Columns: []models.ColumnSchema{
+				{Name: "id", Type: "int(11)"},
+				{Name: "active", Type: "tinyint(1)"},
+			},
+		},
+		"posts": {
+			Name: "posts",
+			Columns: []models.ColumnSchema{
+				{Name: "id", Type: "int(11)"},
+				{Name: "user_id", Type: "int(11)"},
+			},
+			ForeignKeys: []models.ForeignKeySchema{
+				{Name: "posts_user_fk", Columns: []string{"user_id"}, ReferencedTable: "users", ReferencedColumns: []string{"id"}},
+			},
+		},
+	}
+
+	target := map[string]models.TableSchema{
+		"app.users": {
+			Name: "app.users",
+			Columns: []models.ColumnSchema{
+				{Name: "id", Type: "integer"},
+				{Name: "active", Type: "boolean"},
+			},
+		},
+	}
+
+	converted := ConvertSchemas(source, DriverMySQL, target, DriverPostgres)
+
+	expected := map[string]models.TableSchema{
+		"app.users": {
+			Name: "app.users",
+			Columns: []models.ColumnSchema{
+				{Name: "id", Type: "integer"},
+				{Name: "active", Type: "boolean"},
+			},
+		},
+		"public.posts": {
+			Name: "public.posts",
+			Columns: []models.ColumnSchema{
+				{Name: "id", Type: "integer"},
+				{Name: "user_id", Type: "integer"},
+			},
+			ForeignKeys: []models.ForeignKeySchema{
+				{Name: "posts_user_fk", Columns: []string{"user_id"}, ReferencedTable: "app.users", ReferencedColumns: []string{"id"}},
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(converted, expected) {
+		t.Fatalf("expected %v, but got %v", expected, converted)
+	}
+
+	diff := DiffSchemas(converted, target)
+	if diff.Tables[0].Name != "app.users" || diff.Tables[0].Status != models.SchemaDiffUnchanged {
+		t.Fatalf("expected app.users to be unchanged, but got %v", diff.Tables[0])
+	}
+}
+
+func TestGenerateMigration_AlterColumn(t *testing.T) {
+	source := map[string]models.TableSchema{
+		"users": {Name: "users", Columns: []models.ColumnSchema{{Name: "name", Type: "varchar(100)"}}},
+	}
+
+	target := map[string]models.TableSchema{
+		"users": {Name: "users", Columns: []models.ColumnSchema{{Name: "name", Type: "varchar(50)", Nullable: true}}},
+	}
+
+	testCases := []struct {
+		name     string
+		target   Driver
+		expected []string
+	}{
+		{
+			name:   "MySQL",
+			target: &MySQL{Provider: DriverMySQL},
+			expected: []string{
+				"ALTER TABLE `users` MODIFY COLUMN `name` varchar(100) NOT NULL;",
+			},
+		},
+		{
+			name:   "Oracle",
+			target: &Oracle{Provider: DriverOracle},
+			expected: []string{
+				`ALTER TABLE "users" MODIFY ("name" varchar(100) NOT NULL);`,
+			},
+		},
+		{
+			name:   "MSSQL",
+			target: &MSSQL{Provider: DriverMSSQL},
+			expected: []string{
+				"ALTER TABLE [users] ALTER COLUMN [name] varchar(100) NOT NULL;",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			migration := GenerateMigration(DiffSchemas(source, target), tc.target)
+			if !reflect.DeepEqual(migration, tc.expected) {
+				t.Fatalf("expected %q, but got %q", tc.expected, migration)
+			}
+		})
+	}
+}
@@ -0,0 +1,331 @@
+package drivers
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/xo/dburl"
+
+	"github.com/jorgerojas26/lazysql/helpers"
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+const passwordCommandTimeout = 30 * time.Second
+
+// envReference matches ${env:VAR}, also when it was escaped in a URL.
+var envReference = regexp.MustCompile(`(?i)(?:\$|%24)(?:\{|%7B)env(?::|%3A)([a-z_][a-z0-9_]*)(?:\}|%7D)`)
+
+// ResolveSecrets returns a copy of the connection with the environment
+// variables expanded and the password read from its PasswordCommand, .pgpass
+// or .my.cnf. The connection itself is left untouched so the secrets are never
+// saved.
+func ResolveSecrets(connection models.Connection) (models.Connection, error) {
+	var err error
+
+	if connection.URL, err = expandEnv(connection.URL, escapeURLValue); err != nil {
+		return connection, err
+	}
+
+	if connection.PasswordCommand, err = expandEnv(connection.PasswordCommand, nil); err != nil {
+		return connection, err
+	}
+
+	if connection.SSH != nil {
+		ssh := *connection.SSH
+		ssh.JumpHosts = append([]string{}, ssh.JumpHosts...)
+
+		for _, field := range append([]*string{&ssh.Host, &ssh.User, &ssh.KeyFile, &ssh.KnownHostsFile}, jumpHostRefs(ssh.JumpHosts)...) {
+			if *field, err = expandEnv(*field, nil); err != nil {
+				return connection, err
+			}
+		}
+
+		connection.SSH = &ssh
+	}
+
+	if connection.TLS != nil {
+		tls := *connection.TLS
+
+		for _, field := range []*string{&tls.CAFile, &tls.CertFile, &tls.KeyFile, &tls.ServerName} {
+			if *field, err = expandEnv(*field, nil); err != nil {
+				return connection, err
+			}
+		}
+
+		connection.TLS = &tls
+	}
+
+	connection.URL, err = resolvePassword(connection)
+
+	return connection, err
+}
+
+func jumpHostRefs(hosts []string) []*string {
+	refs := make([]*string, len(hosts))
+	for i := range hosts {
+		refs[i] = &hosts[i]
+	}
+
+	return refs
+}
+
+// expandEnv replaces the ${env:VAR} references of the text with the values of
+// the variables, escaped by escape when it is set.
+func expandEnv(text string, escape func(string) string) (string, error) {
+	var err error
+
+	expanded := envReference.ReplaceAllStringFunc(text, func(reference string) string {
+		name := envReference.FindStringSubmatch(reference)[1]
+
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			err = fmt.Errorf("environment variable %s is not set", name)
+		}
+
+		if escape != nil {
+			return escape(value)
+		}
+
+		return value
+	})
+
+	return expanded, err
+}
+
+// EscapeEnvReferences escapes the ${env:VAR} references of the URL, which is
+// then parsed with the references kept in it. The references already escaped
+// are left as they are.
+func EscapeEnvReferences(urlstr string) string {
+	return envReference.ReplaceAllStringFunc(urlstr, func(reference string) string {
+		if unescaped, err := url.PathUnescape(reference); err == nil {
+			reference = unescaped
+		}
+
+		return escapeURLValue(reference)
+	})
+}
+
+// escapeURLValue escapes every character that is not unreserved, the value is
+// then valid in any part of a URL.
+func escapeURLValue(value string) string {
+	var escaped strings.Builder
+
+	for _, b := range []byte(value) {
+		switch {
+		case 'a' <= b && b <= 'z', 'A' <= b && b <= 'Z', '0' <= b && b <= '9', b == '-', b == '.', b == '_', b == '~':
+			escaped.WriteByte(b)
+		default:
+			fmt.Fprintf(&escaped, "%%%02X", b)
+		}
+	}
+
+	return escaped.String()
+}
+
+// resolvePassword returns the URL of the connection with the password of its
+// PasswordCommand, or the one found in .pgpass or .my.cnf when the URL has
+// none.
+func resolvePassword(connection models.Connection) (string, error) {
+	if connection.PasswordCommand == "" && connection.TLS == nil && !strings.Contains(connection.URL, "://") {
+		return connection.URL, nil
+	}
+
+	parsed, err := dburl.Parse(connection.URL)
+	if err != nil || IsFileProvider(parsed.Driver) {
+		// Let the driver report the URL it can not open.
+		return connection.URL, nil
+	}
+
+	u, err := url.Parse(connection.URL)
+	if err != nil {
+		return connection.URL, nil
+	}
+
+	username := u.User.Username()
+	_, hasPassword := u.User.Password()
+
+	var password string
+
+	switch {
+	case connection.PasswordCommand != "":
+		ctx, cancel := context.WithTimeout(context.Background(), passwordCommandTimeout)
+		defer cancel()
+
+		password, err = helpers.CommandOutput(ctx, connection.PasswordCommand)
+		if err != nil {
+			return "", fmt.Errorf("password command failed: %w", err)
+		}
+
+	case hasPassword:
+		return connection.URL, nil
+
+	case parsed.Driver == DriverPostgres:
+		password = pgpassPassword(parsed, username)
+
+	case parsed.Driver == DriverMySQL:
+		username, password = myCnfCredentials(username)
+	}
+
+	if password == "" {
+		return connection.URL, nil
+	}
+
+	u.User = url.UserPassword(username, password)
+
+	return u.String(), nil
+}
+
+// pgpassPassword looks up the password of the URL in the file of PGPASSFILE or
+// in ~/.pgpass, like libpq does.
+func pgpassPassword(parsed *dburl.URL, username string) string {
+	file := os.Getenv("PGPASSFILE")
+	if file == "" {
+		if runtime.GOOS == "windows" {
+			file = filepath.Join(os.Getenv("APPDATA"), "postgresql", "pgpass.conf")
+		} else {
+			file = expandHome("~/.pgpass")
+		}
+	}
+
+	info, err := os.Stat(file)
+	if err != nil || (runtime.GOOS != "windows" && info.Mode().Perm()&0o077 != 0) {
+		// libpq ignores the file when others can read it.
+		return ""
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	host := parsed.Hostname()
+	if host == "" {
+		host = "localhost"
+	}
+
+	port := parsed.Port()
+	if port == "" {
+		port = defaultPorts[DriverPostgres]
+	}
+
+	database := strings.TrimPrefix(parsed.Path, "/")
+	if database == "" {
+		database = username
+	}
+
+	scanner := bufio.NewScanner(f)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := splitPgpassLine(line)
+		if len(fields) != 5 {
+			continue
+		}
+
+		matches := true
+
+		for i, value := range []string{host, port, database, username} {
+			if fields[i] != "*" && fields[i] != value {
+				matches = false
+				break
+			}
+		}
+
+		if matches {
+			return fields[4]
+		}
+	}
+
+	return ""
+}
+
+// splitPgpassLine splits a hostname:port:database:username:password line,
+// where \: and \\ are a colon and a backslash.
+func splitPgpassLine(line string) []string {
+	var fields []string
+	var field strings.Builder
+
+	escaped := false
+
+	for _, r := range line {
+		switch {
+		case escaped:
+			field.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == ':':
+			fields = append(fields, field.String())
+			field.Reset()
+		default:
+			field.WriteRune(r)
+		}
+	}
+
+	return append(fields, field.String())
+}
+
+// myCnfCredentials reads the user and the password of the [client] section of
+// ~/.my.cnf, the user of the URL is kept when it has one.
+func myCnfCredentials(username string) (string, string) {
+	f, err := os.Open(expandHome("~/.my.cnf"))
+	if err != nil {
+		return username, ""
+	}
+	defer f.Close()
+
+	var user, password string
+
+	section := ""
+	scanner := bufio.NewScanner(f)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "", strings.HasPrefix(line, "#"), strings.HasPrefix(line, ";"):
+			continue
+		case strings.HasPrefix(line, "["):
+			section = strings.Trim(line, "[] ")
+			continue
+		case section != "client":
+			continue
+		}
+
+		key, value, _ := strings.Cut(line, "=")
+		value = strings.TrimSpace(value)
+
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+
+		switch strings.TrimSpace(key) {
+		case "user":
+			user = value
+		case "password":
+			password = value
+		}
+	}
+
+	if username == "" {
+		username = user
+	} else if user != "" && user != username {
+		// The password belongs to another user.
+		return username, ""
+	}
+
+	return username, password
+}
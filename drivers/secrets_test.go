@@ -0,0 +1,216 @@
+package drivers
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+func TestSecrets_ResolveSecrets(t *testing.T) {
+	t.Setenv("LAZYSQL_TEST_USER", "me")
+	t.Setenv("LAZYSQL_TEST_PASSWORD", "p@ss:word")
+	t.Setenv("LAZYSQL_TEST_HOST", "bastion")
+	t.Setenv("PGPASSFILE", filepath.Join(t.TempDir(), "missing"))
+
+	tests := []struct {
+		name       string
+		connection models.Connection
+		expected   string
+		wantErr    bool
+	}{
+		{
+			name:       "no references",
+			connection: models.Connection{URL: "postgres://user:pass@db:5432/app"},
+			expected:   "postgres://user:pass@db:5432/app",
+		},
+		{
+			name:       "references",
+			connection: models.Connection{URL: "postgres://${env:LAZYSQL_TEST_USER}:${env:LAZYSQL_TEST_PASSWORD}@db:5432/app"},
+			expected:   "postgres://me:p%40ss%3Aword@db:5432/app",
+		},
+		{
+			name:       "escaped references",
+			connection: models.Connection{URL: "postgres://%24%7Benv%3ALAZYSQL_TEST_USER%7D:$%7Benv%3ALAZYSQL_TEST_PASSWORD%7D@db:5432/app"},
+			expected:   "postgres://me:p%40ss%3Aword@db:5432/app",
+		},
+		{
+			name:       "missing variable",
+			connection: models.Connection{URL: "postgres://user:${env:LAZYSQL_TEST_MISSING}@db:5432/app"},
+			wantErr:    true,
+		},
+		{
+			name:       "password command",
+			connection: models.Connection{URL: "mysql://user@db:3306/app", PasswordCommand: "echo secret"},
+			expected:   "mysql://user:secret@db:3306/app",
+		},
+		{
+			name:       "password command replaces the password",
+			connection: models.Connection{URL: "mysql://user:old@db:3306/app", PasswordCommand: "echo ${env:LAZYSQL_TEST_USER}"},
+			expected:   "mysql://user:me@db:3306/app",
+		},
+		{
+			name:       "failing password command",
+			connection: models.Connection{URL: "mysql://user@db:3306/app", PasswordCommand: "false"},
+			wantErr:    true,
+		},
+		{
+			name:       "sqlite",
+			connection: models.Connection{URL: "sqlite://${env:LAZYSQL_TEST_USER}.db"},
+			expected:   "sqlite://me.db",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.connection.PasswordCommand != "" && runtime.GOOS == "windows" {
+				t.Skip("the commands are not available on windows")
+			}
+
+			connection, err := ResolveSecrets(tt.connection)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveSecrets() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if connection.URL != tt.expected {
+				t.Errorf("ResolveSecrets() = %q, expected %q", connection.URL, tt.expected)
+			}
+		})
+	}
+
+	t.Run("keeps the connection", func(t *testing.T) {
+		original := models.Connection{
+			URL: "postgres://user:pass@db:5432/app",
+			SSH: &models.SSHTunnel{Host: "${env:LAZYSQL_TEST_HOST}", JumpHosts: []string{"${env:LAZYSQL_TEST_HOST}"}},
+		}
+
+		connection, err := ResolveSecrets(original)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if connection.SSH.Host != "bastion" || connection.SSH.JumpHosts[0] != "bastion" {
+			t.Errorf("ResolveSecrets() SSH = %+v", connection.SSH)
+		}
+
+		if original.SSH.Host != "${env:LAZYSQL_TEST_HOST}" || original.SSH.JumpHosts[0] != "${env:LAZYSQL_TEST_HOST}" {
+			t.Errorf("ResolveSecrets() changed the connection: %+v", original.SSH)
+		}
+	})
+}
+
+func TestSecrets_EscapeEnvReferences(t *testing.T) {
+	tests := []struct {
+		url      string
+		expected string
+	}{
+		{"postgres://user:${env:PASSWORD}@db/app", "postgres://user:%24%7Benv%3APASSWORD%7D@db/app"},
+		{"postgres://user:%24%7Benv%3APASSWORD%7D@db/app", "postgres://user:%24%7Benv%3APASSWORD%7D@db/app"},
+		{"postgres://user:$%7Benv%3APASSWORD%7D@db/app", "postgres://user:%24%7Benv%3APASSWORD%7D@db/app"},
+		{"postgres://user:pa$$@db/app", "postgres://user:pa$$@db/app"},
+	}
+
+	for _, tt := range tests {
+		if actual := EscapeEnvReferences(tt.url); actual != tt.expected {
+			t.Errorf("EscapeEnvReferences(%q) = %q, expected %q", tt.url, actual, tt.expected)
+		}
+	}
+}
+
+func TestSecrets_Pgpass(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the permissions of the file are not checked on windows")
+	}
+
+	file := filepath.Join(t.TempDir(), "pgpass")
+	content := "# comment\n" +
+		"other:5432:*:user:wrong\n" +
+		"db:5432:app:admin:admin\\:pass\n" +
+		"db:*:*:user:secret\n"
+
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("PGPASSFILE", file)
+
+	tests := []struct {
+		url      string
+		expected string
+	}{
+		{"postgres://user@db/app", "postgres://user:secret@db/app"},
+		{"postgres://admin@db/app", "postgres://admin:admin%3Apass@db/app"},
+		{"postgres://admin@db/other", "postgres://admin@db/other"},
+		{"postgres://user:mine@db/app", "postgres://user:mine@db/app"},
+	}
+
+	for _, tt := range tests {
+		connection, err := ResolveSecrets(models.Connection{URL: tt.url})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if connection.URL != tt.expected {
+			t.Errorf("ResolveSecrets(%q) = %q, expected %q", tt.url, connection.URL, tt.expected)
+		}
+	}
+
+	if err := os.Chmod(file, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	connection, err := ResolveSecrets(models.Connection{URL: "postgres://user@db/app"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if connection.URL != "postgres://user@db/app" {
+		t.Errorf("ResolveSecrets() read a file others can read: %q", connection.URL)
+	}
+}
+
+func TestSecrets_MyCnf(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	content := "[mysqld]\npassword = server\n\n[client]\nuser = me\npassword = \"my secret\"\n"
+	if err := os.WriteFile(filepath.Join(home, ".my.cnf"), []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		url      string
+		expected string
+	}{
+		{"mysql://db:3306/app", "mysql://me:my%20secret@db:3306/app"},
+		{"mysql://me@db:3306/app", "mysql://me:my%20secret@db:3306/app"},
+		{"mysql://other@db:3306/app", "mysql://other@db:3306/app"},
+	}
+
+	for _, tt := range tests {
+		connection, err := ResolveSecrets(models.Connection{URL: tt.url})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if connection.URL != tt.expected {
+			t.Errorf("ResolveSecrets(%q) = %q, expected %q", tt.url, connection.URL, tt.expected)
+		}
+	}
+}
+
+func TestSecrets_SplitPgpassLine(t *testing.T) {
+	expected := []string{"host", "5432", "db:name", "user", `pass\word`}
+
+	if actual := splitPgpassLine(`host:5432:db\:name:user:pass\\word`); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("splitPgpassLine() = %q, expected %q", actual, expected)
+	}
+}
@@ -4,6 +4,9 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/jorgerojas26/lazysql/models"
@@ -12,8 +15,17 @@ import (
 type SQLite struct {
 	Connection *sql.DB
 	Provider   string
+	// attached are the names of the databases attached to the connection,
+	// as listed by GetDatabases.
+	attached []string
+	// attachedFiles are the files attached by AttachDatabase, by name, they
+	// are attached again to a new connection.
+	attachedFiles map[string]string
 }
 
+// sqliteMainSchema is the schema of the database file of the connection.
+const sqliteMainSchema = "main"
+
 func (db *SQLite) TestConnection(urlstr string) (err error) {
 	return db.Connect(urlstr)
 }
@@ -26,6 +38,9 @@ func (db *SQLite) Connect(urlstr string) (err error) {
 		return err
 	}
 
+	// Attached databases only exist on the connection that attached them.
+	db.Connection.SetMaxOpenConns(1)
+
 	err = db.Connection.Ping()
 	if err != nil {
 		return err
@@ -34,23 +49,33 @@ func (db *SQLite) Connect(urlstr string) (err error) {
 	return nil
 }
 
+// GetDatabases returns the file name of the main database followed by the
+// names of the attached databases.
 func (db *SQLite) GetDatabases() ([]string, error) {
 	var databases []string
 
-	rows, err := db.Connection.Query("SELECT file FROM pragma_database_list WHERE name='main'")
+	rows, err := db.Connection.Query("SELECT name, file FROM pragma_database_list WHERE name <> 'temp' ORDER BY seq")
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
+	var attached []string
+
 	for rows.Next() {
-		var database string
-		err := rows.Scan(&database)
+		var name, file string
+		err := rows.Scan(&name, &file)
 		if err != nil {
 			return nil, err
 		}
 
-		split := strings.Split(database, "/")
+		if name != sqliteMainSchema {
+			attached = append(attached, name)
+			databases = append(databases, name)
+			continue
+		}
+
+		split := strings.Split(file, "/")
 		dbName := split[len(split)-1]
 
 		databases = append(databases, dbName)
@@ -59,15 +84,73 @@ func (db *SQLite) GetDatabases() ([]string, error) {
 		return nil, err
 	}
 
+	db.attached = attached
+
 	return databases, nil
 }
 
+// AttachDatabase attaches a database file to the connection under the given
+// name, the file is created if it does not exist.
+func (db *SQLite) AttachDatabase(path, name string) error {
+	if path == "" {
+		return errors.New("database file is required")
+	}
+
+	if name == "" {
+		return errors.New("database name is required")
+	}
+
+	if strings.Contains(name, ".") {
+		return errors.New("database name can not contain dots")
+	}
+
+	if _, err := db.Connection.Exec(fmt.Sprintf("ATTACH DATABASE ? AS %s", db.FormatReference(name)), path); err != nil {
+		return err
+	}
+
+	db.attached = append(db.attached, name)
+
+	if db.attachedFiles == nil {
+		db.attachedFiles = map[string]string{}
+	}
+
+	db.attachedFiles[name] = path
+
+	return nil
+}
+
+// RestoreSession attaches the files attached by AttachDatabase again when the
+// connection was opened again without them.
+func (db *SQLite) RestoreSession() error {
+	if len(db.attachedFiles) == 0 {
+		return nil
+	}
+
+	if _, err := db.GetDatabases(); err != nil {
+		return err
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(db.attachedFiles)) {
+		if slices.Contains(db.attached, name) {
+			continue
+		}
+
+		if _, err := db.Connection.Exec(fmt.Sprintf("ATTACH DATABASE ? AS %s", db.FormatReference(name)), db.attachedFiles[name]); err != nil {
+			return err
+		}
+
+		db.attached = append(db.attached, name)
+	}
+
+	return nil
+}
+
 func (db *SQLite) GetTables(database string) (map[string][]string, error) {
 	if database == "" {
 		return nil, errors.New("database name is required")
 	}
 
-	rows, err := db.Connection.Query("SELECT name FROM sqlite_master WHERE type='table'")
+	rows, err := db.Connection.Query(fmt.Sprintf("SELECT name FROM %s WHERE type='table'", db.schemaTable(database)))
 	if err != nil {
 		return nil, err
 	}
@@ -91,12 +174,14 @@ func (db *SQLite) GetTables(database string) (map[string][]string, error) {
 	return tables, nil
 }
 
-func (db *SQLite) GetTableColumns(_, table string) (results [][]string, err error) {
+// GetTableColumns uses table_xinfo, that also lists the generated columns and
+// the hidden columns of virtual tables in its hidden column.
+func (db *SQLite) GetTableColumns(database, table string) (results [][]string, err error) {
 	if table == "" {
 		return nil, errors.New("table name is required")
 	}
 
-	rows, err := db.Connection.Query(fmt.Sprintf("PRAGMA table_info(%s)", db.formatTableName(table)))
+	rows, err := db.Connection.Query(db.tablePragma("table_xinfo", database, table))
 	if err != nil {
 		return nil, err
 	}
@@ -139,12 +224,12 @@ func (db *SQLite) GetTableColumns(_, table string) (results [][]string, err erro
 	return results, nil
 }
 
-func (db *SQLite) GetConstraints(_, table string) (results [][]string, err error) {
+func (db *SQLite) GetConstraints(database, table string) (results [][]string, err error) {
 	if table == "" {
 		return nil, errors.New("table name is required")
 	}
 
-	query := "SELECT sql FROM sqlite_master "
+	query := fmt.Sprintf("SELECT sql FROM %s ", db.schemaTable(database))
 	query += "WHERE type='table' AND name = ?"
 
 	rows, err := db.Connection.Query(query, table)
@@ -189,14 +274,12 @@ func (db *SQLite) GetConstraints(_, table string) (results [][]string, err error
 	return results, nil
 }
 
-func (db *SQLite) GetForeignKeys(_, table string) (results [][]string, err error) {
+func (db *SQLite) GetForeignKeys(database, table string) (results [][]string, err error) {
 	if table == "" {
 		return nil, errors.New("table name is required")
 	}
 
-	formattedTableName := db.formatTableName(table)
-
-	rows, err := db.Connection.Query("PRAGMA foreign_key_list(" + formattedTableName + ")")
+	rows, err := db.Connection.Query(db.tablePragma("foreign_key_list", database, table))
 	if err != nil {
 		return nil, err
 	}
@@ -238,13 +321,12 @@ func (db *SQLite) GetForeignKeys(_, table string) (results [][]string, err error
 	return results, nil
 }
 
-func (db *SQLite) GetIndexes(_, table string) (results [][]string, err error) {
+func (db *SQLite) GetIndexes(database, table string) (results [][]string, err error) {
 	if table == "" {
 		return nil, errors.New("table name is required")
 	}
 
-	formattedTableName := db.formatTableName(table)
-	rows, err := db.Connection.Query("PRAGMA index_list(" + formattedTableName + ")")
+	rows, err := db.Connection.Query(db.tablePragma("index_list", database, table))
 	if err != nil {
 		return nil, err
 	}
@@ -286,7 +368,10 @@ func (db *SQLite) GetIndexes(_, table string) (results [][]string, err error) {
 	return results, nil
 }
 
-func (db *SQLite) GetRecords(_, table, where, sort string, offset, limit int) (paginatedResults [][]string, totalRecords int, err error) {
+// GetRecords selects the columns listed by GetTableColumns, so the hidden
+// columns are shown too, and the rowid of the tables without a primary key
+// so their rows can be edited.
+func (db *SQLite) GetRecords(database, table, where, sort string, offset, limit int) (paginatedResults [][]string, totalRecords int, err error) {
 	if table == "" {
 		return nil, 0, errors.New("table name is required")
 	}
@@ -295,8 +380,22 @@ func (db *SQLite) GetRecords(_, table, where, sort string, offset, limit int) (p
 		limit = DefaultRowLimit
 	}
 
-	query := "SELECT * FROM "
-	query += db.formatTableName(table)
+	tableColumns, err := db.GetTableColumns(database, table)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var selectedColumns []string
+	for _, column := range tableColumns[1:] {
+		selectedColumns = append(selectedColumns, db.FormatReference(column[0]))
+	}
+
+	if rowID := sqliteRowIDColumn(tableColumns); rowID != "" {
+		selectedColumns = append(selectedColumns, rowID)
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM ", strings.Join(selectedColumns, ", "))
+	query += db.qualifiedTableName(database, table)
 
 	if where != "" {
 		query += fmt.Sprintf(" %s", where)
@@ -359,7 +458,7 @@ func (db *SQLite) GetRecords(_, table, where, sort string, offset, limit int) (p
 	}
 
 	countQuery := "SELECT COUNT(*) FROM "
-	countQuery += db.formatTableName(table)
+	countQuery += db.qualifiedTableName(database, table)
 	row := db.Connection.QueryRow(countQuery)
 	if err := row.Scan(&totalRecords); err != nil {
 		return nil, 0, err
@@ -413,7 +512,7 @@ func (db *SQLite) ExecuteQuery(query string) ([][]string, int, error) {
 	return results, len(records), nil
 }
 
-func (db *SQLite) UpdateRecord(_, table, column, value, primaryKeyColumnName, primaryKeyValue string) error {
+func (db *SQLite) UpdateRecord(database, table, column, value, primaryKeyColumnName, primaryKeyValue string) error {
 	if table == "" {
 		return errors.New("table name is required")
 	}
@@ -435,7 +534,7 @@ func (db *SQLite) UpdateRecord(_, table, column, value, primaryKeyColumnName, pr
 	}
 
 	query := "UPDATE "
-	query += db.formatTableName(table)
+	query += db.qualifiedTableName(database, table)
 	query += fmt.Sprintf(" SET %s = ? WHERE %s = ?", column, primaryKeyColumnName)
 
 	_, err := db.Connection.Exec(query, value, primaryKeyValue)
@@ -443,7 +542,7 @@ func (db *SQLite) UpdateRecord(_, table, column, value, primaryKeyColumnName, pr
 	return err
 }
 
-func (db *SQLite) DeleteRecord(_, table, primaryKeyColumnName, primaryKeyValue string) error {
+func (db *SQLite) DeleteRecord(database, table, primaryKeyColumnName, primaryKeyValue string) error {
 	if table == "" {
 		return errors.New("table name is required")
 	}
@@ -457,7 +556,7 @@ func (db *SQLite) DeleteRecord(_, table, primaryKeyColumnName, primaryKeyValue s
 	}
 
 	query := "DELETE FROM "
-	query += db.formatTableName(table)
+	query += db.qualifiedTableName(database, table)
 	query += fmt.Sprintf(" WHERE %s = ?", primaryKeyColumnName)
 
 	_, err := db.Connection.Exec(query, primaryKeyValue)
@@ -484,7 +583,7 @@ func (db *SQLite) ExecutePendingChanges(changes []models.DBDMLChange) error {
 
 	for _, change := range changes {
 
-		formattedTableName := db.formatTableName(change.Table)
+		formattedTableName := db.qualifiedTableName(change.Database, change.Table)
 
 		switch change.Type {
 
@@ -500,27 +599,62 @@ func (db *SQLite) ExecutePendingChanges(changes []models.DBDMLChange) error {
 	return queriesInTransaction(db.Connection, queries)
 }
 
+// GetPrimaryKeyColumnNames returns the declared primary key of the table.
 func (db *SQLite) GetPrimaryKeyColumnNames(database, table string) (primaryKeyColumnName []string, err error) {
 	columns, err := db.GetTableColumns(database, table)
 	if err != nil {
 		return nil, err
 	}
 
-	indexOfPkColumn := -1
+	return sqlitePrimaryKey(columns), nil
+}
+
+// RowIDColumn returns the alias the rowid of a table without primary key is
+// selected with.
+func (db *SQLite) RowIDColumn(database, table string) (string, error) {
+	columns, err := db.GetTableColumns(database, table)
+	if err != nil {
+		return "", err
+	}
+
+	return sqliteRowIDColumn(columns), nil
+}
 
-	for i, col := range columns[0] {
-		if col == "pk" {
-			indexOfPkColumn = i
+// sqlitePrimaryKey returns the primary key columns of the rows returned by
+// GetTableColumns, in the order of the key.
+func sqlitePrimaryKey(columns [][]string) []string {
+	indexOfPkColumn := slices.Index(columns[0], "pk")
+	if indexOfPkColumn == -1 {
+		return nil
+	}
+
+	var primaryKey []string
+	for position := 1; position < len(columns); position++ {
+		for _, col := range columns[1:] {
+			if col[indexOfPkColumn] == strconv.Itoa(position) {
+				primaryKey = append(primaryKey, col[0])
+			}
 		}
 	}
 
-	for i, col := range columns {
-		if i > 0 && col[indexOfPkColumn] != "0" {
-			primaryKeyColumnName = append(primaryKeyColumnName, col[0])
+	return primaryKey
+}
+
+// sqliteRowIDColumn returns the name the rowid of a table without primary key
+// can be selected with, that is the first of its aliases not used by a
+// column. It returns an empty string for the tables with a primary key.
+func sqliteRowIDColumn(columns [][]string) string {
+	if len(sqlitePrimaryKey(columns)) > 0 {
+		return ""
+	}
+
+	for _, alias := range []string{"rowid", "_rowid_", "oid"} {
+		if !slices.ContainsFunc(columns[1:], func(column []string) bool { return strings.EqualFold(column[0], alias) }) {
+			return alias
 		}
 	}
 
-	return primaryKeyColumnName, nil
+	return ""
 }
 
 func (db *SQLite) SetProvider(provider string) {
@@ -531,10 +665,67 @@ func (db *SQLite) GetProvider() string {
 	return db.Provider
 }
 
+// Ping checks that the connection to the server is alive.
+func (db *SQLite) Ping() error {
+	if db.Connection == nil {
+		return errors.New("not connected")
+	}
+
+	return db.Connection.Ping()
+}
+
+func (db *SQLite) Close() error {
+	if db.Connection == nil {
+		return nil
+	}
+
+	return db.Connection.Close()
+}
+
 func (db *SQLite) formatTableName(table string) string {
 	return fmt.Sprintf("`%s`", table)
 }
 
+// schemaName returns the schema a database is queried with. Databases that
+// are not attached, like the file name the main database is listed as, refer
+// to main.
+func (db *SQLite) schemaName(database string) string {
+	if slices.Contains(db.attached, database) {
+		return database
+	}
+
+	return sqliteMainSchema
+}
+
+// qualifiedTableName prefixes the table with the schema of the database when
+// it is an attached one.
+func (db *SQLite) qualifiedTableName(database, table string) string {
+	if schema := db.schemaName(database); schema != sqliteMainSchema {
+		return fmt.Sprintf("%s.%s", db.FormatReference(schema), db.formatTableName(table))
+	}
+
+	return db.formatTableName(table)
+}
+
+// schemaTable returns the sqlite_master table of the schema of the database.
+func (db *SQLite) schemaTable(database string) string {
+	if schema := db.schemaName(database); schema != sqliteMainSchema {
+		return fmt.Sprintf("%s.sqlite_master", db.FormatReference(schema))
+	}
+
+	return "sqlite_master"
+}
+
+// tablePragma returns the query of a pragma taking a table as argument, run
+// on the schema of the database.
+func (db *SQLite) tablePragma(pragma, database, table string) string {
+	if schema := db.schemaName(database); schema != sqliteMainSchema {
+		pragma = fmt.Sprintf("%s.%s", db.FormatReference(schema), pragma)
+	}
+
+	return fmt.Sprintf("PRAGMA %s(%s)", pragma, db.formatTableName(table))
+}
+
 func (db *SQLite) FormatArg(arg any) string {
 	if arg == "NULL" || arg == "DEFAULT" {
 		return fmt.Sprintf("%v", arg)
@@ -580,7 +771,7 @@ func (db *SQLite) FormatPlaceholder(_ int) string {
 func (db *SQLite) DMLChangeToQueryString(change models.DBDMLChange) (string, error) {
 	var queryStr string
 
-	formattedTableName := db.formatTableName(change.Table)
+	formattedTableName := db.qualifiedTableName(change.Database, change.Table)
 
 	columnNames, values := getColNamesAndArgsAsString(change.Values)
 
@@ -3,7 +3,9 @@ package drivers
 import (
 	"errors"
 	"fmt"
+	"path/filepath"
 	"reflect"
+	"slices"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -238,7 +240,7 @@ func TestSQLite_GetTableColumns_Error(t *testing.T) {
 	defer db.Close()
 
 	sqlite := &SQLite{Connection: db}
-	mock.ExpectQuery(fmt.Sprintf("PRAGMA table_info\\(%s\\)", sqlite.formatTableName(testDBTableNameSQLite))).
+	mock.ExpectQuery(fmt.Sprintf("PRAGMA table_xinfo\\(%s\\)", sqlite.formatTableName(testDBTableNameSQLite))).
 		WillReturnError(errors.New("query error"))
 
 	_, err = sqlite.GetTableColumns(testDBNameSQLite, testDBTableNameSQLite)
@@ -261,12 +263,17 @@ func TestSQLite_GetRecords(t *testing.T) {
 
 	sqlite := &SQLite{Connection: db}
 
+	mock.ExpectQuery(fmt.Sprintf("PRAGMA table_xinfo\\(%s\\)", sqlite.formatTableName(testDBTableNameSQLite))).
+		WillReturnRows(sqlmock.NewRows([]string{"cid", "name", "type", "notnull", "dflt_value", "pk", "hidden"}).
+			AddRow(0, "id", "INTEGER", 1, nil, 1, 0).
+			AddRow(1, "name", "TEXT", 0, nil, 0, 0))
+
 	columns := []string{"id", "name"}
 	rows := sqlmock.NewRows(columns).
 		AddRow(1, "Alice").
 		AddRow(2, "Bob")
 
-	mock.ExpectQuery(fmt.Sprintf("SELECT \\* FROM %s LIMIT \\?, \\?", sqlite.formatTableName(testDBTableNameSQLite))).
+	mock.ExpectQuery(fmt.Sprintf("SELECT `id`, `name` FROM %s LIMIT \\?, \\?", sqlite.formatTableName(testDBTableNameSQLite))).
 		WithArgs(0, DefaultRowLimit).
 		WillReturnRows(rows)
 
@@ -452,11 +459,11 @@ func TestSQLite_GetPrimaryKeyColumnNames(t *testing.T) {
 
 	sqlite := &SQLite{Connection: db}
 
-	rows := sqlmock.NewRows([]string{"cid", "name", "type", "notnull", "dflt_value", "pk"}).
-		AddRow(0, "id", "INTEGER", 1, nil, 1).
-		AddRow(1, "name", "TEXT", 0, nil, 0)
+	rows := sqlmock.NewRows([]string{"cid", "name", "type", "notnull", "dflt_value", "pk", "hidden"}).
+		AddRow(0, "id", "INTEGER", 1, nil, 1, 0).
+		AddRow(1, "name", "TEXT", 0, nil, 0, 0)
 
-	mock.ExpectQuery(fmt.Sprintf("PRAGMA table_info\\(%s\\)", sqlite.formatTableName(testDBTableNameSQLite))).
+	mock.ExpectQuery(fmt.Sprintf("PRAGMA table_xinfo\\(%s\\)", sqlite.formatTableName(testDBTableNameSQLite))).
 		WillReturnRows(rows)
 
 	keys, err := sqlite.GetPrimaryKeyColumnNames(testDBNameSQLite, testDBTableNameSQLite)
@@ -502,3 +509,131 @@ func TestSQLite_formatTableName(t *testing.T) {
 		t.Fatalf("formatTableName failed: got %q, expected %q", tableName, expectedTableName)
 	}
 }
+
+func TestSQLite_AttachDatabase(t *testing.T) {
+	dir := t.TempDir()
+
+	db := &SQLite{}
+	if err := db.Connect(filepath.Join(dir, "main.db")); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Connection.Exec("CREATE TABLE items (name TEXT, upper_name TEXT GENERATED ALWAYS AS (upper(name)))"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.AttachDatabase(filepath.Join(dir, "other.db"), "other"); err != nil {
+		t.Fatalf("AttachDatabase failed: %v", err)
+	}
+
+	if _, err := db.Connection.Exec("CREATE TABLE other.items (name TEXT); INSERT INTO other.items VALUES ('a'), ('b')"); err != nil {
+		t.Fatal(err)
+	}
+
+	databases, err := db.GetDatabases()
+	if err != nil {
+		t.Fatalf("GetDatabases failed: %v", err)
+	}
+
+	if expected := []string{"main.db", "other"}; !reflect.DeepEqual(databases, expected) {
+		t.Fatalf("expected %v, but got %v", expected, databases)
+	}
+
+	columns, err := db.GetTableColumns("main.db", "items")
+	if err != nil {
+		t.Fatalf("GetTableColumns failed: %v", err)
+	}
+
+	if len(columns) != 3 || columns[2][0] != "upper_name" {
+		t.Fatalf("expected the generated column, but got %v", columns)
+	}
+
+	records, total, err := db.GetRecords("other", "items", "", "", 0, DefaultRowLimit)
+	if err != nil {
+		t.Fatalf("GetRecords failed: %v", err)
+	}
+
+	expected := [][]string{
+		{"name", "rowid"},
+		{"a", "1"},
+		{"b", "2"},
+	}
+
+	if !reflect.DeepEqual(records, expected) || total != 2 {
+		t.Fatalf("expected %v, but got %v (%d)", expected, records, total)
+	}
+
+	keys, err := db.GetPrimaryKeyColumnNames("other", "items")
+	if err != nil {
+		t.Fatalf("GetPrimaryKeyColumnNames failed: %v", err)
+	}
+
+	if len(keys) != 0 {
+		t.Fatalf("expected no primary key, but got %v", keys)
+	}
+
+	rowID, err := db.RowIDColumn("other", "items")
+	if err != nil {
+		t.Fatalf("RowIDColumn failed: %v", err)
+	}
+
+	if err := db.UpdateRecord("other", "items", "name", "c", rowID, "2"); err != nil {
+		t.Fatalf("UpdateRecord failed: %v", err)
+	}
+
+	var name string
+	if err := db.Connection.QueryRow("SELECT name FROM other.items WHERE rowid = 2").Scan(&name); err != nil || name != "c" {
+		t.Fatalf("expected the attached row to be updated, but got %q (%v)", name, err)
+	}
+
+	if err := db.AttachDatabase(filepath.Join(dir, "bad.db"), "bad.name"); err == nil {
+		t.Fatal("expected an error for a name with dots")
+	}
+}
+
+func TestSQLite_RestoreSession(t *testing.T) {
+	dir := t.TempDir()
+
+	db := &SQLite{}
+	if err := db.Connect(filepath.Join(dir, "main.db")); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.RestoreSession(); err != nil {
+		t.Fatalf("RestoreSession failed without attached databases: %v", err)
+	}
+
+	if err := db.AttachDatabase(filepath.Join(dir, "other.db"), "other"); err != nil {
+		t.Fatalf("AttachDatabase failed: %v", err)
+	}
+
+	if _, err := db.Connection.Exec("CREATE TABLE other.items (name TEXT)"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Dropping the idle connection makes the pool open a new one, without
+	// the attached database.
+	db.Connection.SetMaxIdleConns(0)
+	db.Connection.SetMaxIdleConns(1)
+
+	databases, err := db.GetDatabases()
+	if err != nil {
+		t.Fatalf("GetDatabases failed: %v", err)
+	}
+
+	if slices.Contains(databases, "other") {
+		t.Fatalf("expected a new connection, but got %v", databases)
+	}
+
+	for range 2 {
+		if err := db.RestoreSession(); err != nil {
+			t.Fatalf("RestoreSession failed: %v", err)
+		}
+	}
+
+	if _, _, err := db.GetRecords("other", "items", "", "", 0, DefaultRowLimit); err != nil {
+		t.Fatalf("expected the database to be attached again, but got %v", err)
+	}
+}
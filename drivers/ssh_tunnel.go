@@ -0,0 +1,491 @@
+package drivers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xo/dburl"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"github.com/jorgerojas26/lazysql/helpers/logger"
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+const (
+	sshDefaultPort = "22"
+	sshDialTimeout = 10 * time.Second
+)
+
+// defaultPorts are the ports the servers of the providers listen on when the
+// URL does not have one.
+var defaultPorts = map[string]string{
+	DriverMySQL:      "3306",
+	DriverPostgres:   "5432",
+	DriverMSSQL:      "1433",
+	DriverClickhouse: "9000",
+	DriverOracle:     "1521",
+}
+
+// SSHTunnel forwards the connections made to a local port to a remote address
+// through an SSH server. The SSH session is opened again when it drops.
+type SSHTunnel struct {
+	config        models.SSHTunnel
+	remoteAddress string
+	listener      net.Listener
+
+	// key is the key of the tunnel in sshTunnels and refs the number of
+	// connections using it, both guarded by sshTunnelsMu.
+	key  string
+	refs int
+
+	auth            []ssh.AuthMethod
+	hostKeyCallback ssh.HostKeyCallback
+	agent           net.Conn
+
+	mu      sync.Mutex
+	clients []*ssh.Client
+	closed  bool
+}
+
+var (
+	sshTunnelsMu sync.Mutex
+	sshTunnels   = map[string]*SSHTunnel{}
+)
+
+// OpenSSHTunnel returns a tunnel to the remote address through the SSH server
+// of the config. The connections to the same address through the same server
+// share their tunnel, each of them releases it with Release once closed.
+func OpenSSHTunnel(config models.SSHTunnel, remoteAddress string) (*SSHTunnel, error) {
+	key := sshTunnelKey(config, remoteAddress)
+
+	sshTunnelsMu.Lock()
+	defer sshTunnelsMu.Unlock()
+
+	if tunnel, ok := sshTunnels[key]; ok && !tunnel.isClosed() {
+		tunnel.refs++
+		return tunnel, nil
+	}
+
+	tunnel, err := NewSSHTunnel(config, remoteAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	tunnel.key = key
+	tunnel.refs = 1
+	sshTunnels[key] = tunnel
+
+	return tunnel, nil
+}
+
+// sshTunnelKey returns the key the connections sharing a tunnel have in
+// common, the connections authenticating with other keys or checking the hosts
+// against other known hosts get their own tunnel.
+func sshTunnelKey(config models.SSHTunnel, remoteAddress string) string {
+	return fmt.Sprintf("%s@%s>%s>%s key=%s agent=%t known_hosts=%s",
+		config.User, config.Host, strings.Join(config.JumpHosts, ","), remoteAddress,
+		config.KeyFile, config.UseAgent, config.KnownHostsFile)
+}
+
+// Release gives back a tunnel returned by OpenSSHTunnel, it is closed once the
+// last connection using it released it. It does nothing on a nil tunnel.
+func (tunnel *SSHTunnel) Release() error {
+	if tunnel == nil {
+		return nil
+	}
+
+	sshTunnelsMu.Lock()
+	defer sshTunnelsMu.Unlock()
+
+	if tunnel.refs--; tunnel.refs > 0 {
+		return nil
+	}
+
+	if sshTunnels[tunnel.key] == tunnel {
+		delete(sshTunnels, tunnel.key)
+	}
+
+	return tunnel.Close()
+}
+
+// NewSSHTunnel connects to the SSH server of the config and starts listening
+// on a free local port.
+func NewSSHTunnel(config models.SSHTunnel, remoteAddress string) (*SSHTunnel, error) {
+	if config.Host == "" {
+		return nil, errors.New("SSH host is required")
+	}
+
+	tunnel := &SSHTunnel{
+		config:        config,
+		remoteAddress: remoteAddress,
+	}
+
+	if err := tunnel.setupAuth(); err != nil {
+		return nil, errors.Join(err, tunnel.Close())
+	}
+
+	// Connecting right away reports wrong settings before the driver
+	// tries to use the tunnel.
+	if _, err := tunnel.client(); err != nil {
+		return nil, errors.Join(err, tunnel.Close())
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, errors.Join(err, tunnel.Close())
+	}
+
+	tunnel.listener = listener
+
+	go tunnel.serve()
+
+	return tunnel, nil
+}
+
+// LocalAddress returns the address the tunnel listens on.
+func (tunnel *SSHTunnel) LocalAddress() string {
+	return tunnel.listener.Addr().String()
+}
+
+// Close stops listening and closes the SSH sessions.
+func (tunnel *SSHTunnel) Close() error {
+	tunnel.mu.Lock()
+	defer tunnel.mu.Unlock()
+
+	tunnel.closed = true
+
+	var err error
+
+	if tunnel.listener != nil {
+		err = errors.Join(err, tunnel.listener.Close())
+	}
+
+	err = errors.Join(err, tunnel.closeClients())
+
+	if tunnel.agent != nil {
+		err = errors.Join(err, tunnel.agent.Close())
+	}
+
+	return err
+}
+
+func (tunnel *SSHTunnel) isClosed() bool {
+	tunnel.mu.Lock()
+	defer tunnel.mu.Unlock()
+
+	return tunnel.closed
+}
+
+func (tunnel *SSHTunnel) serve() {
+	for {
+		conn, err := tunnel.listener.Accept()
+		if err != nil {
+			if !tunnel.isClosed() {
+				logger.Error("SSH tunnel stopped accepting connections", map[string]any{"error": err.Error()})
+			}
+
+			return
+		}
+
+		go tunnel.forward(conn)
+	}
+}
+
+func (tunnel *SSHTunnel) forward(local net.Conn) {
+	defer local.Close()
+
+	remote, err := tunnel.dial()
+	if err != nil {
+		logger.Error("SSH tunnel could not reach the remote address", map[string]any{"address": tunnel.remoteAddress, "error": err.Error()})
+		return
+	}
+	defer remote.Close()
+
+	done := make(chan struct{}, 2)
+
+	go func() {
+		_, _ = io.Copy(remote, local)
+		done <- struct{}{}
+	}()
+
+	go func() {
+		_, _ = io.Copy(local, remote)
+		done <- struct{}{}
+	}()
+
+	<-done
+}
+
+// dial opens a connection to the remote address. When the SSH session is
+// gone it is opened again once.
+func (tunnel *SSHTunnel) dial() (net.Conn, error) {
+	client, err := tunnel.client()
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := client.Dial("tcp", tunnel.remoteAddress)
+	if err == nil {
+		return conn, nil
+	}
+
+	tunnel.dropClient(client)
+
+	if client, err = tunnel.client(); err != nil {
+		return nil, err
+	}
+
+	return client.Dial("tcp", tunnel.remoteAddress)
+}
+
+// client returns the SSH session to the server, connecting through the jump
+// hosts if there is no session yet.
+func (tunnel *SSHTunnel) client() (*ssh.Client, error) {
+	tunnel.mu.Lock()
+	defer tunnel.mu.Unlock()
+
+	if tunnel.closed {
+		return nil, errors.New("SSH tunnel is closed")
+	}
+
+	if len(tunnel.clients) > 0 {
+		return tunnel.clients[len(tunnel.clients)-1], nil
+	}
+
+	hops := append(append([]string{}, tunnel.config.JumpHosts...), tunnel.config.Host)
+
+	for _, hop := range hops {
+		user, address := sshUserAndAddress(hop, tunnel.config.User)
+
+		config := &ssh.ClientConfig{
+			User:            user,
+			Auth:            tunnel.auth,
+			HostKeyCallback: tunnel.hostKeyCallback,
+			Timeout:         sshDialTimeout,
+		}
+
+		var conn net.Conn
+		var err error
+
+		if len(tunnel.clients) == 0 {
+			conn, err = net.DialTimeout("tcp", address, sshDialTimeout)
+		} else {
+			conn, err = tunnel.clients[len(tunnel.clients)-1].Dial("tcp", address)
+		}
+
+		if err != nil {
+			return nil, errors.Join(fmt.Errorf("could not reach SSH server %s: %w", address, err), tunnel.closeClients())
+		}
+
+		clientConn, channels, requests, err := ssh.NewClientConn(conn, address, config)
+		if err != nil {
+			return nil, errors.Join(fmt.Errorf("could not connect to SSH server %s: %w", address, err), conn.Close(), tunnel.closeClients())
+		}
+
+		tunnel.clients = append(tunnel.clients, ssh.NewClient(clientConn, channels, requests))
+	}
+
+	client := tunnel.clients[len(tunnel.clients)-1]
+
+	// Forget the session when it drops so the next connection opens a new
+	// one.
+	go func() {
+		_ = client.Wait()
+		tunnel.dropClient(client)
+	}()
+
+	return client, nil
+}
+
+// dropClient closes the SSH sessions if client is still the current one.
+func (tunnel *SSHTunnel) dropClient(client *ssh.Client) {
+	tunnel.mu.Lock()
+	defer tunnel.mu.Unlock()
+
+	if len(tunnel.clients) > 0 && tunnel.clients[len(tunnel.clients)-1] == client {
+		_ = tunnel.closeClients()
+	}
+}
+
+// closeClients closes the SSH sessions, the last hop first. The caller holds
+// the lock.
+func (tunnel *SSHTunnel) closeClients() error {
+	var err error
+
+	for i := len(tunnel.clients) - 1; i >= 0; i-- {
+		if closeErr := tunnel.clients[i].Close(); closeErr != nil && !errors.Is(closeErr, net.ErrClosed) {
+			err = errors.Join(err, closeErr)
+		}
+	}
+
+	tunnel.clients = nil
+
+	return err
+}
+
+func (tunnel *SSHTunnel) setupAuth() error {
+	if tunnel.config.KeyFile != "" {
+		key, err := os.ReadFile(expandHome(tunnel.config.KeyFile))
+		if err != nil {
+			return err
+		}
+
+		signer, err := ssh.ParsePrivateKey(key)
+
+		var passphraseErr *ssh.PassphraseMissingError
+		if errors.As(err, &passphraseErr) {
+			return fmt.Errorf("SSH key %s is protected by a passphrase, which is not supported: add it to the SSH agent and set UseAgent instead", tunnel.config.KeyFile)
+		}
+
+		if err != nil {
+			return fmt.Errorf("could not read SSH key %s: %w", tunnel.config.KeyFile, err)
+		}
+
+		tunnel.auth = append(tunnel.auth, ssh.PublicKeys(signer))
+	}
+
+	if tunnel.config.UseAgent {
+		socket := os.Getenv("SSH_AUTH_SOCK")
+		if socket == "" {
+			return errors.New("SSH agent is not running, SSH_AUTH_SOCK is not set")
+		}
+
+		conn, err := net.Dial("unix", socket)
+		if err != nil {
+			return fmt.Errorf("could not connect to SSH agent: %w", err)
+		}
+
+		tunnel.agent = conn
+		tunnel.auth = append(tunnel.auth, ssh.PublicKeysCallback(agent.NewClient(conn).Signers))
+	}
+
+	if len(tunnel.auth) == 0 {
+		return errors.New("SSH key file or agent is required")
+	}
+
+	knownHostsFile := tunnel.config.KnownHostsFile
+	if knownHostsFile == "" {
+		knownHostsFile = "~/.ssh/known_hosts"
+	}
+
+	callback, err := knownhosts.New(expandHome(knownHostsFile))
+	if err != nil {
+		return fmt.Errorf("could not read known hosts: %w", err)
+	}
+
+	tunnel.hostKeyCallback = callback
+
+	return nil
+}
+
+// TunnelURL returns the URL of the connection, going through its SSH tunnel
+// when it has one. The tunnel is returned to be released once the connection
+// is closed, it is nil without SSH.
+func TunnelURL(connection models.Connection) (string, *SSHTunnel, error) {
+	if connection.SSH == nil || connection.SSH.Host == "" {
+		return connection.URL, nil, nil
+	}
+
+	parsed, err := dburl.Parse(connection.URL)
+	if err != nil {
+		return "", nil, err
+	}
+
+	port := parsed.Port()
+	if port == "" {
+		port = defaultPorts[parsed.Driver]
+	}
+
+	if parsed.Driver == DriverClickhouse && parsed.Transport == "http" && parsed.Port() == "" {
+		port = "8123"
+	}
+
+	if parsed.Hostname() == "" || port == "" {
+		return "", nil, fmt.Errorf("%s connections can not be tunnelled", parsed.Driver)
+	}
+
+	u, err := url.Parse(connection.URL)
+	if err != nil {
+		return "", nil, err
+	}
+
+	tunnel, err := OpenSSHTunnel(*connection.SSH, net.JoinHostPort(parsed.Hostname(), port))
+	if err != nil {
+		return "", nil, err
+	}
+
+	u.Host = tunnel.LocalAddress()
+
+	return u.String(), tunnel, nil
+}
+
+// Tunnelled is a driver connected through an SSH tunnel, closing it releases
+// the tunnel.
+type Tunnelled struct {
+	Driver
+
+	Tunnel *SSHTunnel
+}
+
+func (db *Tunnelled) Close() error {
+	return errors.Join(db.Driver.Close(), db.Tunnel.Release())
+}
+
+// Connect connects the driver to the connection and returns the driver to use
+// from then on, it releases the SSH tunnel of the connection when closed.
+func Connect(db Driver, connection models.Connection) (Driver, error) {
+	urlstr, tunnel, err := ConnectionURL(connection)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Connect(urlstr); err != nil {
+		return nil, errors.Join(err, tunnel.Release())
+	}
+
+	if tunnel == nil {
+		return db, nil
+	}
+
+	return &Tunnelled{Driver: db, Tunnel: tunnel}, nil
+}
+
+// sshUserAndAddress splits a [user@]host[:port] host, using the default user
+// and port for the parts that are missing.
+func sshUserAndAddress(host, defaultUser string) (string, string) {
+	user := defaultUser
+
+	if at := strings.LastIndex(host, "@"); at != -1 {
+		user = host[:at]
+		host = host[at+1:]
+	}
+
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		host = net.JoinHostPort(strings.Trim(host, "[]"), sshDefaultPort)
+	}
+
+	return user, host
+}
+
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
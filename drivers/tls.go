@@ -0,0 +1,277 @@
+package drivers
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"sync"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/xo/dburl"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+var (
+	tlsConfigsMu sync.Mutex
+	tlsConfigs   = map[string]*tls.Config{}
+)
+
+// ConnectionURL returns the URL the driver of the connection opens, with its
+// secrets resolved, its TLS settings and through its SSH tunnel. The tunnel,
+// if any, is released once the connection is closed.
+func ConnectionURL(connection models.Connection) (string, *SSHTunnel, error) {
+	connection, err := ResolveSecrets(connection)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if err := ValidateTLS(connection); err != nil {
+		return "", nil, err
+	}
+
+	urlstr, err := TLSURL(connection)
+	if err != nil {
+		return "", nil, err
+	}
+
+	connection.URL = urlstr
+
+	return TunnelURL(connection)
+}
+
+// ValidateTLS reports the TLS settings that can not work with the SSH tunnel of
+// the connection, so they are rejected when the connection is saved rather
+// than when connecting. lib/pq verifies the certificate of Postgres servers
+// against the host it dials, which is the local end of the tunnel.
+func ValidateTLS(connection models.Connection) error {
+	if connection.SSH == nil || connection.SSH.Host == "" {
+		return nil
+	}
+
+	// A URL that does not parse is reported when connecting.
+	parsed, err := dburl.Parse(connection.URL)
+	if err != nil || parsed.Driver != DriverPostgres {
+		return nil
+	}
+
+	mode := parsed.Query().Get("sslmode")
+
+	if config := connection.TLS; config != nil && *config != (models.TLSConfig{}) {
+		mode = config.Mode
+		if mode == "" {
+			mode = models.TLSModeVerifyFull
+		}
+	}
+
+	if mode == models.TLSModeVerifyFull {
+		return errors.New("TLS mode verify-full can not check the host of postgres servers through an SSH tunnel, use verify-ca")
+	}
+
+	return nil
+}
+
+// TLSURL returns the URL of the connection with its TLS settings translated to
+// the parameters of its driver.
+func TLSURL(connection models.Connection) (string, error) {
+	config := connection.TLS
+	if config == nil || *config == (models.TLSConfig{}) {
+		return connection.URL, nil
+	}
+
+	mode := config.Mode
+	if mode == "" {
+		mode = models.TLSModeVerifyFull
+	}
+
+	switch mode {
+	case models.TLSModeDisable, models.TLSModeRequire, models.TLSModeVerifyCA, models.TLSModeVerifyFull:
+	default:
+		return "", fmt.Errorf("unknown TLS mode %q", mode)
+	}
+
+	parsed, err := dburl.Parse(connection.URL)
+	if err != nil {
+		return "", err
+	}
+
+	u, err := url.Parse(connection.URL)
+	if err != nil {
+		return "", err
+	}
+
+	serverName := config.ServerName
+	if serverName == "" {
+		serverName = parsed.Hostname()
+	}
+
+	query := u.Query()
+
+	switch parsed.Driver {
+	case DriverPostgres:
+		// lib/pq verifies the certificate against the host it dials.
+		if config.ServerName != "" {
+			return "", errors.New("TLS server name is not supported by postgres")
+		}
+
+		query.Set("sslmode", mode)
+		setParam(query, "sslrootcert", expandHome(config.CAFile))
+		setParam(query, "sslcert", expandHome(config.CertFile))
+		setParam(query, "sslkey", expandHome(config.KeyFile))
+
+	case DriverMySQL, DriverClickhouse:
+		if mode == models.TLSModeDisable {
+			query.Set("tls", "false")
+			break
+		}
+
+		name, err := registerTLSConfig(mode, config, serverName)
+		if err != nil {
+			return "", err
+		}
+
+		query.Set("tls", name)
+
+	case DriverMSSQL:
+		if config.CertFile != "" || config.KeyFile != "" {
+			return "", errors.New("TLS client certificates are not supported by sqlserver")
+		}
+
+		switch mode {
+		case models.TLSModeDisable:
+			query.Set("encrypt", "disable")
+		case models.TLSModeRequire:
+			query.Set("encrypt", "true")
+			query.Set("TrustServerCertificate", "true")
+		case models.TLSModeVerifyCA:
+			return "", errors.New("TLS mode verify-ca is not supported by sqlserver, use verify-full")
+		case models.TLSModeVerifyFull:
+			query.Set("encrypt", "true")
+			query.Set("TrustServerCertificate", "false")
+			query.Set("hostNameInCertificate", serverName)
+			setParam(query, "certificate", expandHome(config.CAFile))
+		}
+
+	default:
+		return "", fmt.Errorf("TLS is not supported by %s", parsed.Driver)
+	}
+
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
+
+// registerTLSConfig builds the tls.Config of the mode and registers it with the
+// MySQL driver and for ClickHouse under a name derived from the settings.
+func registerTLSConfig(mode string, config *models.TLSConfig, serverName string) (string, error) {
+	sum := sha256.Sum256(fmt.Appendf(nil, "%s\x00%s\x00%s\x00%s\x00%s", mode, config.CAFile, config.CertFile, config.KeyFile, serverName))
+	name := "lazysql-" + hex.EncodeToString(sum[:8])
+
+	tlsConfigsMu.Lock()
+	defer tlsConfigsMu.Unlock()
+
+	if _, ok := tlsConfigs[name]; ok {
+		return name, nil
+	}
+
+	tlsConfig, err := newTLSConfig(mode, config, serverName)
+	if err != nil {
+		return "", err
+	}
+
+	if err := mysql.RegisterTLSConfig(name, tlsConfig); err != nil {
+		return "", err
+	}
+
+	tlsConfigs[name] = tlsConfig
+
+	return name, nil
+}
+
+func newTLSConfig(mode string, config *models.TLSConfig, serverName string) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		ServerName: serverName,
+	}
+
+	if config.CAFile != "" {
+		ca, err := os.ReadFile(expandHome(config.CAFile))
+		if err != nil {
+			return nil, err
+		}
+
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("no certificates found in %s", config.CAFile)
+		}
+	}
+
+	if config.CertFile != "" || config.KeyFile != "" {
+		certificate, err := tls.LoadX509KeyPair(expandHome(config.CertFile), expandHome(config.KeyFile))
+		if err != nil {
+			return nil, err
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	switch mode {
+	case models.TLSModeRequire:
+		tlsConfig.InsecureSkipVerify = true
+	case models.TLSModeVerifyCA:
+		// The chain is verified by hand to skip the check of the host name.
+		tlsConfig.InsecureSkipVerify = true
+		tlsConfig.VerifyConnection = func(state tls.ConnectionState) error {
+			if len(state.PeerCertificates) == 0 {
+				return errors.New("server did not send a certificate")
+			}
+
+			intermediates := x509.NewCertPool()
+			for _, certificate := range state.PeerCertificates[1:] {
+				intermediates.AddCert(certificate)
+			}
+
+			_, err := state.PeerCertificates[0].Verify(x509.VerifyOptions{
+				Roots:         tlsConfig.RootCAs,
+				Intermediates: intermediates,
+			})
+
+			return err
+		}
+	}
+
+	return tlsConfig, nil
+}
+
+// tlsConfigFromParam returns the tls.Config of a tls URL parameter, it takes
+// the same values as the one of the MySQL driver.
+func tlsConfigFromParam(value string) (*tls.Config, error) {
+	switch value {
+	case "", "false":
+		return nil, nil
+	case "true":
+		return &tls.Config{}, nil
+	case "skip-verify":
+		return &tls.Config{InsecureSkipVerify: true}, nil
+	}
+
+	tlsConfigsMu.Lock()
+	defer tlsConfigsMu.Unlock()
+
+	config, ok := tlsConfigs[value]
+	if !ok {
+		return nil, fmt.Errorf("unknown TLS config %q", value)
+	}
+
+	return config, nil
+}
+
+func setParam(query url.Values, key, value string) {
+	if value != "" {
+		query.Set(key, value)
+	}
+}
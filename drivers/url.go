@@ -0,0 +1,197 @@
+package drivers
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/xo/dburl"
+
+	"github.com/jorgerojas26/lazysql/models"
+)
+
+// ClickHouse protocols, the native one is used when none is set.
+const (
+	ClickhouseProtocolNative = "native"
+	ClickhouseProtocolHTTP   = "http"
+)
+
+// IsFileProvider reports whether the databases of a provider are local files
+// instead of servers.
+func IsFileProvider(provider string) bool {
+	switch provider {
+	case DriverSqlite, DriverDuckDB, DriverFiles:
+		return true
+	}
+
+	return false
+}
+
+// BuildURL generates the URL of a connection from its Provider, Username,
+// Password, Hostname, Port, DBName, File, Protocol and URLParams, escaping
+// every part of it.
+func BuildURL(connection models.Connection) (string, error) {
+	params := strings.TrimLeft(connection.URLParams, "?&")
+
+	switch connection.Provider {
+	case DriverSqlite:
+		if connection.File == "" {
+			return "", errors.New("file is required")
+		}
+
+		// The file URIs are decoded twice on the way to SQLite, these can not
+		// be escaped.
+		if strings.ContainsAny(connection.File, "%?#") {
+			return "", errors.New("file paths with %, ? or # are not supported")
+		}
+
+		return withParams("file:"+connection.File, params), nil
+
+	case DriverDuckDB:
+		if connection.File == "" {
+			return "", errors.New("file is required")
+		}
+
+		return withParams(DriverDuckDB+":"+connection.File, params), nil
+
+	case DriverFiles:
+		var paths []string
+
+		for _, path := range strings.Split(connection.File, ",") {
+			if path = strings.TrimSpace(path); path != "" {
+				paths = append(paths, path)
+			}
+		}
+
+		if len(paths) == 0 {
+			return "", errors.New("at least one file is required")
+		}
+
+		return withParams(FilesURL(paths, false), params), nil
+
+	case DriverPostgres, DriverMySQL, DriverMSSQL, DriverClickhouse, DriverOracle:
+	default:
+		return "", fmt.Errorf("unsupported provider %q", connection.Provider)
+	}
+
+	u := url.URL{
+		Scheme: connection.Provider,
+		Host:   connection.Hostname,
+	}
+
+	if connection.Provider == DriverClickhouse && connection.Protocol == ClickhouseProtocolHTTP {
+		u.Scheme += "+" + ClickhouseProtocolHTTP
+	}
+
+	if connection.Port != "" {
+		u.Host = net.JoinHostPort(connection.Hostname, connection.Port)
+	} else if strings.Contains(connection.Hostname, ":") {
+		u.Host = "[" + connection.Hostname + "]"
+	}
+
+	switch {
+	case connection.Password != "":
+		u.User = url.UserPassword(connection.Username, connection.Password)
+	case connection.Username != "":
+		u.User = url.User(connection.Username)
+	}
+
+	if connection.Provider == DriverMSSQL {
+		// The database of SQL Server is a parameter.
+		if connection.DBName != "" {
+			params = strings.Trim("database="+url.QueryEscape(connection.DBName)+"&"+params, "&")
+		}
+	} else if connection.DBName != "" {
+		u.Path = "/" + connection.DBName
+	}
+
+	u.RawQuery = params
+
+	return u.String(), nil
+}
+
+// ParseURL splits a URL into the fields used by BuildURL.
+func ParseURL(urlstr string) (models.Connection, error) {
+	parsed, err := dburl.Parse(EscapeEnvReferences(urlstr))
+	if err != nil {
+		return models.Connection{}, err
+	}
+
+	connection := models.Connection{
+		Provider:  parsed.Driver,
+		URLParams: parsed.RawQuery,
+	}
+
+	switch parsed.Driver {
+	case DriverSqlite:
+		connection.File, err = url.PathUnescape(parsed.Opaque)
+		if err != nil {
+			return models.Connection{}, err
+		}
+
+		return connection, nil
+
+	case DriverDuckDB:
+		connection.File = parsed.Opaque
+
+		return connection, nil
+
+	case DriverFiles:
+		paths := strings.Split(parsed.Opaque, ",")
+
+		for i, path := range paths {
+			if paths[i], err = url.PathUnescape(path); err != nil {
+				return models.Connection{}, err
+			}
+		}
+
+		connection.File = strings.Join(paths, ",")
+
+		return connection, nil
+
+	case DriverPostgres, DriverMySQL, DriverMSSQL, DriverClickhouse, DriverOracle:
+	default:
+		return models.Connection{}, fmt.Errorf("unsupported provider %q", parsed.Driver)
+	}
+
+	connection.Hostname = parsed.Hostname()
+	connection.Port = parsed.Port()
+
+	if parsed.Driver != DriverMSSQL {
+		connection.DBName = strings.TrimPrefix(parsed.Path, "/")
+	}
+
+	if parsed.User != nil {
+		connection.Username = parsed.User.Username()
+		connection.Password, _ = parsed.User.Password()
+	}
+
+	if parsed.Driver == DriverClickhouse && parsed.Transport == ClickhouseProtocolHTTP {
+		connection.Protocol = ClickhouseProtocolHTTP
+	}
+
+	if parsed.Driver == DriverMSSQL {
+		query := parsed.Query()
+
+		for key, values := range query {
+			if strings.EqualFold(key, "database") {
+				connection.DBName = values[0]
+				query.Del(key)
+			}
+		}
+
+		connection.URLParams = query.Encode()
+	}
+
+	return connection, nil
+}
+
+func withParams(urlstr, params string) string {
+	if params == "" {
+		return urlstr
+	}
+
+	return urlstr + "?" + params
+}
@@ -54,15 +54,24 @@ func buildInsertQuery(formattedTableName string, values []models.CellValue, driv
 	index := 1
 
 	for _, value := range values {
-		if value.Type != models.Default {
-			cols = append(cols, driver.FormatReference(value.Column))
-		}
+		switch value.Type {
+		case models.Default:
+			continue
+		case models.Null:
+			placeholders = append(placeholders, "NULL")
+		case models.Empty:
+			placeholders = append(placeholders, "''")
+		default:
+			if value.Value == nil {
+				continue
+			}
 
-		if value.Value != nil && value.Type != models.Default {
 			placeholders = append(placeholders, driver.FormatPlaceholder(index))
 			args = append(args, value.Value)
 			index++
 		}
+
+		cols = append(cols, driver.FormatReference(value.Column))
 	}
 
 	queryStr := "INSERT INTO " + formattedTableName
@@ -124,10 +133,12 @@ func buildUpdateQuery(sanitizedTableName string, values []models.CellValue, prim
 		sanitizedCols[i] = driver.FormatReference(value.Column)
 	}
 
-	args := make([]any, len(values))
-	for i, value := range values {
-		if value.Value != nil {
-			args[i] = value.Value
+	// Only the values that got a placeholder are passed as arguments, NULL,
+	// empty and DEFAULT values are written inline.
+	args := []any{}
+	for _, value := range values {
+		if value.Type != models.Null && value.Type != models.Empty && value.Type != models.Default {
+			args = append(args, value.Value)
 		}
 	}
 
@@ -152,7 +163,7 @@ func buildUpdateQuery(sanitizedTableName string, values []models.CellValue, prim
 	}
 
 	for i, sanitizedPki := range sanitizedPrimaryKeyInfo {
-		placeholder := driver.FormatPlaceholder(len(placeholders) + i + 1)
+		placeholder := driver.FormatPlaceholder(len(args) + 1)
 		reference := sanitizedPki.Name
 
 		if i == 0 {
@@ -261,7 +272,7 @@ func buildPlaceholders(values []models.CellValue, driver Driver) []string {
 	for _, cell := range values {
 		switch cell.Type {
 		case models.Empty:
-			placeholders = append(placeholders, "")
+			placeholders = append(placeholders, "''")
 		case models.Null:
 			placeholders = append(placeholders, "NULL")
 		case models.Default:
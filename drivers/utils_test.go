@@ -2,6 +2,7 @@ package drivers
 
 import (
 	"errors"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -119,3 +120,102 @@ func Test_queriesInTransaction(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildInsertQuery(t *testing.T) {
+	values := []models.CellValue{
+		{Column: "id", Type: models.String, Value: "1"},
+		{Column: "name", Type: models.Empty},
+		{Column: "email", Type: models.Null},
+		{Column: "created_at", Type: models.Default},
+		{Column: "note", Type: models.String, Value: "hi"},
+	}
+
+	testCases := []struct {
+		name     string
+		driver   Driver
+		expected models.Query
+	}{
+		{
+			name:   "numbered placeholders",
+			driver: &Postgres{},
+			expected: models.Query{
+				Query: `INSERT INTO "users" ("id", "name", "email", "note") VALUES ($1, '', NULL, $2)`,
+				Args:  []any{"1", "hi"},
+			},
+		},
+		{
+			name:   "question marks",
+			driver: &MySQL{},
+			expected: models.Query{
+				Query: "INSERT INTO `users` (`id`, `name`, `email`, `note`) VALUES (?, '', NULL, ?)",
+				Args:  []any{"1", "hi"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			table := tc.driver.FormatReference("users")
+
+			if result := buildInsertQuery(table, values, tc.driver); !reflect.DeepEqual(result, tc.expected) {
+				t.Fatalf("expected %v, but got %v", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestBuildUpdateQuery(t *testing.T) {
+	primaryKeys := []models.PrimaryKeyInfo{{Name: "id", Value: "1"}, {Name: "tenant", Value: "a"}}
+
+	testCases := []struct {
+		name     string
+		values   []models.CellValue
+		expected models.Query
+	}{
+		{
+			name:   "value",
+			values: []models.CellValue{{Column: "name", Type: models.String, Value: "Bob"}},
+			expected: models.Query{
+				Query: `UPDATE "users" SET "name" = $1 WHERE "id" = $2 AND "tenant" = $3`,
+				Args:  []any{"Bob", "1", "a"},
+			},
+		},
+		{
+			name: "inline values",
+			values: []models.CellValue{
+				{Column: "name", Type: models.Null},
+				{Column: "email", Type: models.Empty},
+				{Column: "created_at", Type: models.Default},
+				{Column: "note", Type: models.String, Value: "hi"},
+			},
+			expected: models.Query{
+				Query: `UPDATE "users" SET "name" = NULL, "email" = '', "created_at" = DEFAULT, "note" = $1 WHERE "id" = $2 AND "tenant" = $3`,
+				Args:  []any{"hi", "1", "a"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if result := buildUpdateQuery(`"users"`, tc.values, primaryKeys, &Postgres{}); !reflect.DeepEqual(result, tc.expected) {
+				t.Fatalf("expected %v, but got %v", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestBuildPlaceholders(t *testing.T) {
+	values := []models.CellValue{
+		{Type: models.String, Value: "a"},
+		{Type: models.Empty},
+		{Type: models.Null},
+		{Type: models.Default},
+		{Type: models.String, Value: "b"},
+	}
+
+	expected := []string{"$1", "''", "NULL", "DEFAULT", "$2"}
+
+	if result := buildPlaceholders(values, &Postgres{}); !reflect.DeepEqual(result, expected) {
+		t.Fatalf("expected %q, but got %q", expected, result)
+	}
+}
@@ -1,77 +1,344 @@
 package helpers
 
 import (
+	"bufio"
 	"context"
 	"errors"
+	"fmt"
 	"io"
+	"maps"
+	"net/http"
+	"os"
 	"os/exec"
+	"regexp"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
-	"github.com/mitchellh/go-linereader"
-
 	"github.com/jorgerojas26/lazysql/helpers/logger"
 )
 
-func RunCommand(ctx context.Context, command string, doneFn func()) error {
-	var cmd *exec.Cmd
+// maxCommandOutputLines is how many of the last lines of the output of a
+// command are kept.
+const maxCommandOutputLines = 1000
+
+// Process is a command started in the background, the last lines of its
+// output are kept.
+type Process struct {
+	Command string
 
-	parts := strings.Fields(command)
-	if len(parts) == 1 {
-		cmd = exec.CommandContext(ctx, parts[0]) // #nosec G204
-	} else {
-		cmd = exec.CommandContext(ctx, parts[0], parts[1:]...) // #nosec G204
+	cmd  *exec.Cmd
+	done chan struct{}
+	err  error
+
+	mu     sync.Mutex
+	output []string
+	// lines is the number of lines written since the command started, the
+	// oldest ones are dropped from output.
+	lines int
+	// lineAdded is closed and replaced when a line is written.
+	lineAdded chan struct{}
+}
+
+// StartCommand starts the command with the extra environment variables, in the
+// directory when it is set. The command is killed when the context is done.
+func StartCommand(ctx context.Context, command string, env map[string]string, dir string) (*Process, error) {
+	args, err := SplitCommand(command)
+	if err != nil {
+		return nil, err
 	}
 
-	// Create a pipe to read the output from.
-	pr, pw := io.Pipe()
-	startedCh := make(chan struct{})
-	finishedCh := make(chan struct{})
-	go logOutput(pr, startedCh, finishedCh)
+	if len(args) == 0 {
+		return nil, errors.New("command is empty")
+	}
 
-	// Connect the pipe to stdout and stderr.
-	cmd.Stderr = pw
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...) // #nosec G204
+	cmd.Dir = dir
+	// The children of the command could keep the output open after it was
+	// killed.
+	cmd.WaitDelay = time.Second
+
+	if len(env) > 0 {
+		cmd.Env = os.Environ()
+
+		for _, name := range slices.Sorted(maps.Keys(env)) {
+			cmd.Env = append(cmd.Env, name+"="+env[name])
+		}
+	}
+
+	pr, pw := io.Pipe()
 	cmd.Stdout = pw
+	cmd.Stderr = pw
+
+	process := &Process{
+		Command:   command,
+		cmd:       cmd,
+		done:      make(chan struct{}),
+		lineAdded: make(chan struct{}),
+	}
 
 	if err := cmd.Start(); err != nil {
-		return err
+		return nil, err
 	}
 
+	logger.Info("Command started", map[string]any{"command": command})
+
+	captured := make(chan struct{})
+	go process.capture(pr, captured)
+
 	go func() {
-		if err := cmd.Wait(); err != nil {
-			logger.Error("Command stopped", map[string]any{"error": err.Error()})
+		err := cmd.Wait()
+		_ = pw.Close()
+		<-captured
+
+		if err != nil && ctx.Err() == nil {
+			logger.Error("Command stopped", map[string]any{"command": command, "error": err.Error()})
 		}
 
-		_ = pw.Close()
-		<-finishedCh
-		doneFn()
+		process.mu.Lock()
+		process.err = err
+		process.mu.Unlock()
+
+		close(process.done)
 	}()
 
-	// Wait for the command to start
+	return process, nil
+}
+
+func (p *Process) capture(r io.Reader, captured chan struct{}) {
+	defer close(captured)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		logger.Debug("Command output", map[string]any{"line": line})
+
+		p.mu.Lock()
+		p.output = append(p.output, line)
+		if len(p.output) > maxCommandOutputLines {
+			p.output = p.output[len(p.output)-maxCommandOutputLines:]
+		}
+
+		p.lines++
+		close(p.lineAdded)
+		p.lineAdded = make(chan struct{})
+		p.mu.Unlock()
+	}
+
+	// Keep reading so the command is not blocked by a line too long.
+	_, _ = io.Copy(io.Discard, r)
+}
+
+// Done is closed when the command exited.
+func (p *Process) Done() <-chan struct{} {
+	return p.done
+}
+
+// Running reports whether the command did not exit yet.
+func (p *Process) Running() bool {
 	select {
-	case <-ctx.Done():
-		logger.Error("Command canceled", map[string]any{"error": ctx.Err()})
-	case <-startedCh:
-		logger.Info("Command started", map[string]any{"command": command})
-	case <-time.After(5 * time.Second):
-		_ = cmd.Process.Kill()
-		return errors.New("command timeout")
+	case <-p.done:
+		return false
+	default:
+		return true
 	}
+}
+
+// Err returns the error the command exited with.
+func (p *Process) Err() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 
-	return nil
+	return p.err
 }
 
-func logOutput(r io.Reader, startedCh, finishedCh chan struct{}) {
-	defer close(finishedCh)
-	lr := linereader.New(r)
+// Output returns the last lines written by the command.
+func (p *Process) Output() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return slices.Clone(p.output)
+}
 
-	// Wait for the command to start
-	line := <-lr.Ch
-	startedCh <- struct{}{}
-	logger.Debug("Command output", map[string]any{"line": line})
+// LastLine returns the last line written by the command.
+func (p *Process) LastLine() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 
-	// Log the rest of the output
-	for line := range lr.Ch {
-		logger.Debug("Command output", map[string]any{"line": line})
+	if len(p.output) == 0 {
+		return ""
+	}
+
+	return p.output[len(p.output)-1]
+}
+
+// WaitForLog waits for a line of the output, since the command started,
+// matching the expression.
+func (p *Process) WaitForLog(ctx context.Context, expression *regexp.Regexp) error {
+	checked := 0
+	exited := false
+
+	for {
+		p.mu.Lock()
+
+		// The lines dropped from the output were checked already.
+		first := max(checked-(p.lines-len(p.output)), 0)
+		for _, line := range p.output[first:] {
+			if expression.MatchString(line) {
+				p.mu.Unlock()
+				return nil
+			}
+		}
+
+		checked = p.lines
+		lineAdded := p.lineAdded
+
+		p.mu.Unlock()
+
+		if exited {
+			return errors.New("command exited before writing a line matching " + expression.String())
+		}
+
+		select {
+		case <-lineAdded:
+		case <-p.done:
+			// The output is complete, check it one last time.
+			exited = true
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// CommandOutput runs the command and returns what it printed, without the
+// trailing new lines.
+func CommandOutput(ctx context.Context, command string) (string, error) {
+	parts, err := SplitCommand(command)
+	if err != nil {
+		return "", err
+	}
+
+	if len(parts) == 0 {
+		return "", errors.New("command is empty")
+	}
+
+	cmd := exec.CommandContext(ctx, parts[0], parts[1:]...) // #nosec G204
+
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return "", fmt.Errorf("%s: %w: %s", parts[0], err, message)
+		}
+
+		return "", fmt.Errorf("%s: %w", parts[0], err)
+	}
+
+	return strings.TrimRight(string(output), "\r\n"), nil
+}
+
+// SplitCommand splits the command into its arguments like a POSIX shell
+// does: single quotes keep their content as it is, double quotes keep it
+// except for the backslashes escaping ", \, $ and `, and a backslash outside
+// quotes escapes the next character.
+func SplitCommand(command string) ([]string, error) {
+	var args []string
+	var arg strings.Builder
+
+	// inArg is set once a character or quotes started the argument, so ''
+	// is an empty argument.
+	inArg := false
+	runes := []rune(command)
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		switch {
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+
+		case r == '\'':
+			end := slices.Index(runes[i+1:], '\'')
+			if end < 0 {
+				return nil, errors.New("unterminated single quote")
+			}
+
+			arg.WriteString(string(runes[i+1 : i+1+end]))
+			i += end + 1
+			inArg = true
+
+		case r == '"':
+			i++
+
+			for ; i < len(runes) && runes[i] != '"'; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) && strings.ContainsRune("\"\\$`", runes[i+1]) {
+					i++
+				}
+
+				arg.WriteRune(runes[i])
+			}
+
+			if i == len(runes) {
+				return nil, errors.New("unterminated double quote")
+			}
+
+			inArg = true
+
+		case r == '\\':
+			if i+1 == len(runes) {
+				return nil, errors.New("trailing backslash")
+			}
+
+			i++
+			arg.WriteRune(runes[i])
+			inArg = true
+
+		default:
+			arg.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if inArg {
+		args = append(args, arg.String())
+	}
+
+	return args, nil
+}
+
+// WaitForHTTP waits for the URL to answer, with any status but a server
+// error.
+func WaitForHTTP(ctx context.Context, url string) error {
+	client := &http.Client{Timeout: 2 * time.Second}
+
+	for {
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+
+		response, err := client.Do(request)
+		if err == nil {
+			_ = response.Body.Close()
+
+			if response.StatusCode < http.StatusInternalServerError {
+				return nil
+			}
+		}
+
+		select {
+		case <-time.After(500 * time.Millisecond):
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for %s: %w", url, ctx.Err())
+		}
 	}
 }
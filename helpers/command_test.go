@@ -0,0 +1,164 @@
+package helpers
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestSplitCommand(t *testing.T) {
+	testCases := []struct {
+		name     string
+		command  string
+		expected []string
+		wantErr  bool
+	}{
+		{name: "words", command: "ssh -N  -L 5432:db:5432\thost", expected: []string{"ssh", "-N", "-L", "5432:db:5432", "host"}},
+		{name: "empty", command: "  ", expected: nil},
+		{name: "single quotes", command: `echo 'a  "b" \n $c'`, expected: []string{"echo", `a  "b" \n $c`}},
+		{name: "double quotes", command: `echo "a 'b' \"c\" \\ \$d \x"`, expected: []string{"echo", `a 'b' "c" \ $d \x`}},
+		{name: "empty arguments", command: `cmd '' "" x`, expected: []string{"cmd", "", "", "x"}},
+		{name: "adjacent quotes", command: `a'b c'"d e"f`, expected: []string{"ab cd ef"}},
+		{name: "escaped space", command: `ls my\ file \'x`, expected: []string{"ls", "my file", "'x"}},
+		{name: "unterminated single quote", command: "echo 'a", wantErr: true},
+		{name: "unterminated double quote", command: `echo "a\"`, wantErr: true},
+		{name: "trailing backslash", command: `echo a\`, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			args, err := SplitCommand(tc.command)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected an error to be %v, but got %v", tc.wantErr, err)
+			}
+
+			if !reflect.DeepEqual(args, tc.expected) {
+				t.Fatalf("expected %q, but got %q", tc.expected, args)
+			}
+		})
+	}
+}
+
+func startTestCommand(t *testing.T, command string, env map[string]string, dir string) *Process {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("the commands are run with sh")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	process, err := StartCommand(ctx, command, env, dir)
+	if err != nil {
+		t.Fatalf("StartCommand failed: %v", err)
+	}
+
+	return process
+}
+
+func TestProcess_WaitForLog(t *testing.T) {
+	testCases := []struct {
+		name       string
+		script     string
+		expression string
+		wantErr    bool
+	}{
+		{
+			name:       "line written after more lines than are kept",
+			script:     "seq 1 2500; echo ready; sleep 5",
+			expression: "^ready$",
+		},
+		{
+			name:       "line among lines dropped while waiting",
+			script:     "seq 1 2500; sleep 5",
+			expression: "^2400$",
+		},
+		{
+			name:       "line written before exiting",
+			script:     "echo starting; echo listening on 5432",
+			expression: `listening on \d+`,
+		},
+		{
+			name:       "exits without the line",
+			script:     "seq 1 2500",
+			expression: "^ready$",
+			wantErr:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			process := startTestCommand(t, "sh -c '"+tc.script+"'", nil, "")
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			err := process.WaitForLog(ctx, regexp.MustCompile(tc.expression))
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected an error to be %v, but got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestProcess_Output(t *testing.T) {
+	process := startTestCommand(t, "sh -c 'seq 1 1500'", nil, "")
+	<-process.Done()
+
+	output := process.Output()
+	if len(output) != maxCommandOutputLines || output[0] != "501" || process.LastLine() != "1500" {
+		t.Fatalf("expected the last %d lines, but got %d lines from %q to %q", maxCommandOutputLines, len(output), output[0], process.LastLine())
+	}
+
+	if process.Running() || process.Err() != nil {
+		t.Fatalf("expected the command to have exited, but got %v", process.Err())
+	}
+}
+
+func TestStartCommand_EnvAndDir(t *testing.T) {
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	process := startTestCommand(t, `sh -c 'echo "$LAZYSQL_TEST_PORT"; pwd -P'`, map[string]string{"LAZYSQL_TEST_PORT": "15432"}, dir)
+	<-process.Done()
+
+	if expected := []string{"15432", dir}; !reflect.DeepEqual(process.Output(), expected) {
+		t.Fatalf("expected %q, but got %q", expected, process.Output())
+	}
+}
+
+func TestStartCommand_Errors(t *testing.T) {
+	if _, err := StartCommand(context.Background(), " ", nil, ""); err == nil {
+		t.Fatal("expected an error for an empty command")
+	}
+
+	if _, err := StartCommand(context.Background(), "echo 'a", nil, ""); err == nil {
+		t.Fatal("expected an error for an unterminated quote")
+	}
+
+	if _, err := StartCommand(context.Background(), "lazysql-command-that-does-not-exist", nil, ""); err == nil {
+		t.Fatal("expected an error for a missing command")
+	}
+}
+
+func TestCommandOutput(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the commands are run with sh")
+	}
+
+	output, err := CommandOutput(context.Background(), `sh -c 'printf "s3cret\n\n"'`)
+	if err != nil || output != "s3cret" {
+		t.Fatalf("expected %q, but got %q, %v", "s3cret", output, err)
+	}
+
+	if _, err := CommandOutput(context.Background(), `sh -c 'echo denied >&2; exit 1'`); err == nil || !regexp.MustCompile(`denied$`).MatchString(err.Error()) {
+		t.Fatalf("expected the error output in the error, but got %v", err)
+	}
+}
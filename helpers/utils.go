@@ -2,7 +2,7 @@ package helpers
 
 import (
 	"context"
-	"errors"
+	"fmt"
 	"net"
 	"strconv"
 	"time"
@@ -41,13 +41,13 @@ func GetFreePort() (string, error) {
 	return strconv.Itoa(l.Addr().(*net.TCPAddr).Port), nil
 }
 
-// WaitForPort waits for a port to be open.
+// WaitForPort waits for a port to be open, until the context is done.
 func WaitForPort(ctx context.Context, port string) error {
 	dialer := &net.Dialer{
 		Timeout: 500 * time.Millisecond,
 	}
 
-	for i := 0; i < 10; i++ {
+	for {
 		conn, err := dialer.DialContext(ctx, "tcp", "localhost:"+port)
 		if err == nil {
 			_ = conn.Close()
@@ -58,9 +58,7 @@ func WaitForPort(ctx context.Context, port string) error {
 		case <-time.After(500 * time.Millisecond):
 			continue
 		case <-ctx.Done():
-			return ctx.Err()
+			return fmt.Errorf("waiting for port %s: %w", port, ctx.Err())
 		}
 	}
-
-	return errors.New("Timeout waiting for port " + port)
 }
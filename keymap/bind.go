@@ -2,17 +2,48 @@ package keymap
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/jorgerojas26/lazysql/commands"
 )
 
 // Struct that holds a key and a command
 type Bind struct {
-	Key         Key
+	Key Key
+	// Then are the keys pressed after Key for sequences like gg.
+	Then        []Key
 	Cmd         commands.Command
 	Description string
 }
 
+// Keys returns the keys pressed, in order, to run the command.
+func (b Bind) Keys() Keys {
+	return append(Keys{b.Key}, b.Then...)
+}
+
 func (b Bind) String() string {
-	return fmt.Sprintf("%s = %s", b.Key.String(), b.Cmd.String())
+	return fmt.Sprintf("%s = %s", b.Keys().String(), b.Cmd.String())
+}
+
+// Keys is a sequence of keys.
+type Keys []Key
+
+func (k Keys) String() string {
+	text := ""
+
+	for _, key := range k {
+		text += key.String()
+	}
+
+	return text
+}
+
+// Equal reports whether the sequences have the same keys.
+func (k Keys) Equal(other Keys) bool {
+	return slices.Equal(k, other)
+}
+
+// HasPrefix reports whether the sequence starts with the other one.
+func (k Keys) HasPrefix(prefix Keys) bool {
+	return len(prefix) <= len(k) && slices.Equal(k[:len(prefix)], prefix)
 }
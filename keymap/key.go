@@ -1,21 +1,262 @@
 package keymap
 
-import "github.com/gdamore/tcell/v2"
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/gdamore/tcell/v2"
+)
 
 // Key is a structure that represents a key that can be bound
 // to an command
 type Key struct {
 	Code tcell.Key // Special character codes.
 	Char rune      // used when the key represents a single ascii char like "a" or "2".
+	// Mod are the modifiers pressed with the key, besides the ones implied by
+	// the key like Ctrl for tcell.KeyCtrlE.
+	Mod tcell.ModMask
+}
+
+// modifierMask are the modifiers compared when matching keys.
+const modifierMask = tcell.ModCtrl | tcell.ModAlt | tcell.ModShift
+
+// modifierNames are the names of the modifiers in the key notation, in lower
+// case.
+var modifierNames = map[string]tcell.ModMask{
+	"c":     tcell.ModCtrl,
+	"ctrl":  tcell.ModCtrl,
+	"a":     tcell.ModAlt,
+	"alt":   tcell.ModAlt,
+	"m":     tcell.ModAlt,
+	"meta":  tcell.ModAlt,
+	"s":     tcell.ModShift,
+	"shift": tcell.ModShift,
+}
+
+// keyAliases are the names of the keys in the key notation that tcell does not
+// name, or names differently, in lower case.
+var keyAliases = map[string]tcell.Key{
+	"backspace": tcell.KeyBackspace2,
+	"bs":        tcell.KeyBackspace2,
+	"cr":        tcell.KeyEnter,
+	"return":    tcell.KeyEnter,
+	"escape":    tcell.KeyEscape,
+	"del":       tcell.KeyDelete,
+	"pagedown":  tcell.KeyPgDn,
+	"pageup":    tcell.KeyPgUp,
 }
 
+// keyCodes are the keys named in the key notation, in lower case.
+var keyCodes = func() map[string]tcell.Key {
+	codes := make(map[string]tcell.Key, len(tcell.KeyNames)+len(keyAliases))
+
+	for code, name := range tcell.KeyNames {
+		codes[strings.ToLower(name)] = code
+	}
+
+	for name, code := range keyAliases {
+		codes[name] = code
+	}
+
+	return codes
+}()
+
 func (k Key) String() string {
+	if k.Char == ' ' {
+		return "<" + modifiersString(k.Mod) + "Space>"
+	}
+
 	if k.Char != 0 {
+		if k.Mod != 0 {
+			return "<" + modifiersString(k.Mod) + string(k.Char) + ">"
+		}
+
 		return string(k.Char)
 	}
 
+	// tcell names Ctrl+H Backspace, most terminals send Backspace2 for the
+	// Backspace key.
+	switch k.Code {
+	case tcell.KeyBackspace2:
+		return "<" + modifiersString(k.Mod) + "Backspace>"
+	case tcell.KeyBackspace:
+		return "<" + modifiersString(k.Mod) + "Ctrl-H>"
+	}
+
 	if desc, ok := tcell.KeyNames[k.Code]; ok {
-		return "<" + desc + ">"
+		return "<" + modifiersString(k.Mod) + desc + ">"
 	}
 	return ""
 }
+
+func modifiersString(mod tcell.ModMask) string {
+	text := ""
+
+	if mod&tcell.ModCtrl != 0 {
+		text += "Ctrl-"
+	}
+
+	if mod&tcell.ModAlt != 0 {
+		text += "Alt-"
+	}
+
+	if mod&tcell.ModShift != 0 {
+		text += "Shift-"
+	}
+
+	return text
+}
+
+// KeyOf returns the key pressed in the event, with its modifiers. The
+// modifiers implied by the key are dropped: Shift for the characters, which
+// are upper case or symbols, Ctrl for the control codes like tcell.KeyCtrlE,
+// and Shift for tcell.KeyBacktab.
+func KeyOf(event *tcell.EventKey) Key {
+	mod := event.Modifiers() & modifierMask
+
+	switch {
+	case event.Key() == tcell.KeyRune:
+		return Key{Char: event.Rune(), Mod: mod &^ tcell.ModShift}
+	case event.Key() <= tcell.KeyCtrlUnderscore, event.Key() == tcell.KeyDEL:
+		return Key{Code: event.Key(), Mod: mod &^ tcell.ModCtrl}
+	case event.Key() == tcell.KeyBacktab:
+		return Key{Code: event.Key(), Mod: mod &^ tcell.ModShift}
+	}
+
+	return Key{Code: event.Key(), Mod: mod}
+}
+
+// Event returns an event of the key being pressed, it is resolved to the
+// commands bound to the key.
+func (k Key) Event() *tcell.EventKey {
+	if k.Char != 0 {
+		return tcell.NewEventKey(tcell.KeyRune, k.Char, k.Mod)
+	}
+
+	return tcell.NewEventKey(k.Code, 0, k.Mod)
+}
+
+// Matches reports whether the key pressed, as returned by KeyOf, is the key,
+// with the same modifiers.
+func (k Key) Matches(pressed Key) bool {
+	if pressed.Char != 0 {
+		if k.Char != pressed.Char {
+			return false
+		}
+	} else if k.Char != 0 || k.Code != pressed.Code {
+		return false
+	}
+
+	return k.Mod == pressed.Mod
+}
+
+// ParseKeys parses a sequence of keys written like in vim: characters stand
+// for themselves and special keys are written between angle brackets with
+// their modifiers, like <Enter>, <C-e>, <A-x>, <S-Tab> or <Ctrl-Up>. For
+// example gg is g pressed twice and <C-w>j is Ctrl+W followed by j.
+func ParseKeys(text string) (Keys, error) {
+	var keys Keys
+
+	runes := []rune(text)
+
+	for i := 0; i < len(runes); i++ {
+		if runes[i] == '<' {
+			end := strings.IndexRune(string(runes[i+1:]), '>')
+			// A lone < is the character, as in <>.
+			if end > 0 {
+				name := string(runes[i+1:])[:end]
+
+				key, err := parseKeyName(name)
+				if err != nil {
+					return nil, err
+				}
+
+				keys = append(keys, key)
+				i += len([]rune(name)) + 1
+
+				continue
+			}
+		}
+
+		keys = append(keys, Key{Char: runes[i]})
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("no key")
+	}
+
+	return keys, nil
+}
+
+// parseKeyName parses the name of a key written between angle brackets.
+func parseKeyName(name string) (Key, error) {
+	var mod tcell.ModMask
+
+	base := name
+
+	for {
+		i := strings.IndexByte(base, '-')
+		if i <= 0 || i == len(base)-1 {
+			break
+		}
+
+		modifier, ok := modifierNames[strings.ToLower(base[:i])]
+		if !ok {
+			break
+		}
+
+		mod |= modifier
+		base = base[i+1:]
+	}
+
+	if runes := []rune(base); len(runes) == 1 {
+		return charKey(runes[0], mod, name)
+	}
+
+	if strings.EqualFold(base, "space") {
+		return charKey(' ', mod, name)
+	}
+
+	code, ok := keyCodes[strings.ToLower(base)]
+	if !ok {
+		return Key{}, fmt.Errorf("unknown key <%s>", name)
+	}
+
+	if code == tcell.KeyTab && mod&tcell.ModShift != 0 {
+		code = tcell.KeyBacktab
+		mod &^= tcell.ModShift
+	}
+
+	return Key{Code: code, Mod: mod}, nil
+}
+
+// charKey returns the key of the character with the modifiers, tcell reports
+// Ctrl with a letter as a key code and Shift with a letter as the upper case
+// letter.
+func charKey(char rune, mod tcell.ModMask, name string) (Key, error) {
+	if mod&tcell.ModShift != 0 {
+		if !unicode.IsLetter(char) {
+			return Key{}, fmt.Errorf("unsupported key <%s>, write the shifted character instead", name)
+		}
+
+		char = unicode.ToUpper(char)
+		mod &^= tcell.ModShift
+	}
+
+	if mod&tcell.ModCtrl != 0 {
+		mod &^= tcell.ModCtrl
+
+		switch lower := unicode.ToLower(char); {
+		case lower >= 'a' && lower <= 'z':
+			return Key{Code: tcell.KeyCtrlA + tcell.Key(lower-'a'), Mod: mod}, nil
+		case char == ' ':
+			return Key{Code: tcell.KeyCtrlSpace, Mod: mod}, nil
+		default:
+			return Key{}, fmt.Errorf("unsupported key <%s>", name)
+		}
+	}
+
+	return Key{Char: char, Mod: mod}, nil
+}
@@ -0,0 +1,110 @@
+package keymap
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestParseKeys(t *testing.T) {
+	testCases := []struct {
+		name     string
+		text     string
+		expected Keys
+		wantErr  bool
+	}{
+		{name: "character", text: "j", expected: Keys{{Char: 'j'}}},
+		{name: "sequence", text: "gg", expected: Keys{{Char: 'g'}, {Char: 'g'}}},
+		{name: "ctrl sequence", text: "<C-w>j", expected: Keys{{Code: tcell.KeyCtrlW}, {Char: 'j'}}},
+		{name: "long ctrl", text: "<Ctrl-E>", expected: Keys{{Code: tcell.KeyCtrlE}}},
+		{name: "shift tab", text: "<S-Tab>", expected: Keys{{Code: tcell.KeyBacktab}}},
+		{name: "space", text: "<Space>", expected: Keys{{Char: ' '}}},
+		{name: "ctrl space", text: "<C-Space>", expected: Keys{{Code: tcell.KeyCtrlSpace}}},
+		{name: "alt", text: "<A-x>", expected: Keys{{Char: 'x', Mod: tcell.ModAlt}}},
+		{name: "shift letter", text: "<S-a>", expected: Keys{{Char: 'A'}}},
+		{name: "ctrl arrow", text: "<C-Up>", expected: Keys{{Code: tcell.KeyUp, Mod: tcell.ModCtrl}}},
+		{name: "alias", text: "<CR>", expected: Keys{{Code: tcell.KeyEnter}}},
+		{name: "lone angle brackets", text: "<>", expected: Keys{{Char: '<'}, {Char: '>'}}},
+		{name: "minus", text: "<->", expected: Keys{{Char: '-'}}},
+		{name: "unknown key", text: "<Hyper>", wantErr: true},
+		{name: "shift symbol", text: "<S-1>", wantErr: true},
+		{name: "ctrl symbol", text: "<C-1>", wantErr: true},
+		{name: "empty", text: "", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			keys, err := ParseKeys(tc.text)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected an error to be %v, but got %v", tc.wantErr, err)
+			}
+
+			if !keys.Equal(tc.expected) {
+				t.Fatalf("expected %v, but got %v", tc.expected, keys)
+			}
+		})
+	}
+}
+
+func TestKeyOf(t *testing.T) {
+	testCases := []struct {
+		name     string
+		event    *tcell.EventKey
+		expected Key
+	}{
+		{name: "character", event: tcell.NewEventKey(tcell.KeyRune, 'j', tcell.ModNone), expected: Key{Char: 'j'}},
+		{name: "upper case", event: tcell.NewEventKey(tcell.KeyRune, 'J', tcell.ModShift), expected: Key{Char: 'J'}},
+		{name: "alt character", event: tcell.NewEventKey(tcell.KeyRune, 'j', tcell.ModAlt), expected: Key{Char: 'j', Mod: tcell.ModAlt}},
+		{name: "control code", event: tcell.NewEventKey(tcell.KeyCtrlE, 0, tcell.ModCtrl), expected: Key{Code: tcell.KeyCtrlE}},
+		{name: "shift tab", event: tcell.NewEventKey(tcell.KeyBacktab, 0, tcell.ModShift), expected: Key{Code: tcell.KeyBacktab}},
+		{name: "ctrl arrow", event: tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModCtrl), expected: Key{Code: tcell.KeyUp, Mod: tcell.ModCtrl}},
+		{name: "meta is ignored", event: tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModMeta), expected: Key{Code: tcell.KeyDown}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if key := KeyOf(tc.event); key != tc.expected {
+				t.Fatalf("expected %v, but got %v", tc.expected, key)
+			}
+		})
+	}
+}
+
+func TestKey_Matches(t *testing.T) {
+	testCases := []struct {
+		name     string
+		key      Key
+		pressed  Key
+		expected bool
+	}{
+		{name: "same character", key: Key{Char: 'j'}, pressed: Key{Char: 'j'}, expected: true},
+		{name: "other character", key: Key{Char: 'j'}, pressed: Key{Char: 'k'}, expected: false},
+		{name: "extra modifier", key: Key{Char: 'j'}, pressed: Key{Char: 'j', Mod: tcell.ModAlt}, expected: false},
+		{name: "missing modifier", key: Key{Char: 'j', Mod: tcell.ModAlt}, pressed: Key{Char: 'j'}, expected: false},
+		{name: "same modifier", key: Key{Char: 'j', Mod: tcell.ModAlt}, pressed: Key{Char: 'j', Mod: tcell.ModAlt}, expected: true},
+		{name: "code", key: Key{Code: tcell.KeyEnter}, pressed: Key{Code: tcell.KeyEnter}, expected: true},
+		{name: "character for code", key: Key{Char: 'j'}, pressed: Key{Code: tcell.KeyEnter}, expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if result := tc.key.Matches(tc.pressed); result != tc.expected {
+				t.Fatalf("expected %v, but got %v", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestKey_String(t *testing.T) {
+	// The keys parsed are written back in the key notation.
+	for _, text := range []string{"j", "<Space>", "<Ctrl-W>", "<Alt-x>", "<Backtab>", "<Ctrl-Up>"} {
+		keys, err := ParseKeys(text)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if result := keys.String(); result != text {
+			t.Fatalf("expected %q, but got %q", text, result)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package keymap
 
 import (
+	"time"
+
 	"github.com/gdamore/tcell/v2"
 
 	"github.com/jorgerojas26/lazysql/commands"
@@ -9,20 +11,133 @@ import (
 // Map is a collection of keybinds
 type Map []Bind
 
+// DefaultTimeout is how long the next key of a sequence like gg, or of a count
+// like 5j, is waited for.
+const DefaultTimeout = time.Second
+
+// sequenceState is what was typed so far of a sequence and of the count in
+// front of it.
+type sequenceState struct {
+	keys  Keys
+	count int
+	at    time.Time
+}
+
+// State is what was typed so far of a sequence and of its count, the maps
+// resolve the events through it.
+type State struct {
+	// Timeout is how long the next key of a sequence or a count is waited
+	// for.
+	Timeout time.Duration
+
+	// The same event is resolved by the maps of the focused primitive and of
+	// its parents, each starts from the state before the event.
+	lastEvent *tcell.EventKey
+	before    sequenceState
+	after     sequenceState
+}
+
+// NewState returns a state waiting DefaultTimeout for the next key of a
+// sequence.
+func NewState() *State {
+	return &State{Timeout: DefaultTimeout}
+}
+
 // Resolve translates a tcell.EventKey to a
 // command based on the bindings in the map.
 //
 // If no binding could be found. commands.Noop is returned.
-func (m Map) Resolve(event *tcell.EventKey) commands.Command {
-	for _, bind := range m {
-		if event.Key() == tcell.KeyRune {
-			if bind.Key.Char == event.Rune() {
-				return bind.Cmd
+func (s *State) Resolve(m Map, event *tcell.EventKey) commands.Command {
+	command, _ := s.resolve(m, event, false)
+	return command
+}
+
+// ResolveCount resolves the event like Resolve, and returns the count typed
+// before the command, like 5 in 5j, or 1. The digits bound to commands of the
+// map do not start a count.
+func (s *State) ResolveCount(m Map, event *tcell.EventKey) (commands.Command, int) {
+	return s.resolve(m, event, true)
+}
+
+// Pending reports whether the keys typed so far are the start of a sequence
+// or a count, the event completing them has not come yet.
+func (s *State) Pending() bool {
+	return len(s.after.keys) > 0 || s.after.count > 0
+}
+
+func (s *State) resolve(m Map, event *tcell.EventKey, counted bool) (commands.Command, int) {
+	if event != s.lastEvent {
+		s.lastEvent = event
+		s.before = s.after
+		s.after = sequenceState{}
+
+		if time.Since(s.before.at) > s.Timeout {
+			s.before = sequenceState{}
+		}
+	}
+
+	before := s.before
+	pressed := KeyOf(event)
+
+	count := max(before.count, 1)
+
+	if len(before.keys) > 0 {
+		sequence := append(append(Keys{}, before.keys...), pressed)
+
+		for _, bind := range m {
+			keys := bind.Keys()
+
+			if len(keys) == len(sequence) && keys.matches(sequence) {
+				return bind.Cmd, count
+			}
+
+			if len(keys) > len(sequence) && keys[:len(sequence)].matches(sequence) {
+				s.after = sequenceState{keys: sequence, count: before.count, at: time.Now()}
+				return commands.Noop, count
 			}
-		} else if event.Key() == bind.Key.Code {
-			return bind.Cmd
+		}
+
+		// The key does not continue the sequence, it is resolved alone.
+	}
+
+	// A digit continues the count, even when it is bound like 0.
+	if counted && before.count > 0 && isDigit(pressed) {
+		s.after = sequenceState{count: before.count*10 + int(pressed.Char-'0'), at: time.Now()}
+		return commands.Noop, count
+	}
+
+	for _, bind := range m {
+		if !bind.Key.Matches(pressed) {
+			continue
+		}
+
+		if len(bind.Then) > 0 {
+			s.after = sequenceState{keys: Keys{pressed}, count: before.count, at: time.Now()}
+			return commands.Noop, count
+		}
+
+		return bind.Cmd, count
+	}
+
+	if counted && isDigit(pressed) && pressed.Char != '0' {
+		s.after = sequenceState{count: int(pressed.Char - '0'), at: time.Now()}
+	}
+
+	return commands.Noop, count
+}
+
+func isDigit(key Key) bool {
+	return key.Mod == 0 && key.Char >= '0' && key.Char <= '9'
+}
+
+// matches reports whether the keys pressed, as returned by KeyOf, are the
+// keys of the sequence.
+func (k Keys) matches(pressed Keys) bool {
+	for i, key := range k {
+		if !key.Matches(pressed[i]) {
+			return false
 		}
 	}
 
-	return commands.Noop
+	return true
 }
@@ -0,0 +1,148 @@
+package keymap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/jorgerojas26/lazysql/commands"
+)
+
+var testMap = Map{
+	Bind{Key: Key{Char: 'j'}, Cmd: commands.MoveDown},
+	Bind{Key: Key{Char: 'g'}, Then: []Key{{Char: 'g'}}, Cmd: commands.GotoTop},
+	Bind{Key: Key{Char: 'G'}, Cmd: commands.GotoBottom},
+	Bind{Key: Key{Code: tcell.KeyCtrlW}, Then: []Key{{Char: 'j'}}, Cmd: commands.TabNext},
+	Bind{Key: Key{Char: '0'}, Cmd: commands.GotoStart},
+	Bind{Key: Key{Char: '2'}, Cmd: commands.ColumnsMenu},
+	Bind{Key: Key{Char: '1', Mod: tcell.ModAlt}, Cmd: commands.RecordsMenu},
+}
+
+// resolved is the command and the count an event was resolved to.
+type resolved struct {
+	command commands.Command
+	count   int
+}
+
+func TestState_ResolveCount(t *testing.T) {
+	testCases := []struct {
+		name     string
+		keys     string
+		expected []resolved
+		pending  bool
+	}{
+		{name: "key", keys: "j", expected: []resolved{{commands.MoveDown, 1}}},
+		{name: "sequence", keys: "gg", expected: []resolved{{commands.Noop, 1}, {commands.GotoTop, 1}}},
+		{name: "started sequence", keys: "g", expected: []resolved{{commands.Noop, 1}}, pending: true},
+		{name: "ctrl sequence", keys: "<C-w>j", expected: []resolved{{commands.Noop, 1}, {commands.TabNext, 1}}},
+		{name: "broken sequence", keys: "gG", expected: []resolved{{commands.Noop, 1}, {commands.GotoBottom, 1}}},
+		{name: "count", keys: "5j", expected: []resolved{{commands.Noop, 1}, {commands.MoveDown, 5}}},
+		{name: "count with a bound zero", keys: "10j", expected: []resolved{{commands.Noop, 1}, {commands.Noop, 1}, {commands.MoveDown, 10}}},
+		{name: "count of a sequence", keys: "3gg", expected: []resolved{{commands.Noop, 1}, {commands.Noop, 3}, {commands.GotoTop, 3}}},
+		{name: "started count", keys: "4", expected: []resolved{{commands.Noop, 1}}, pending: true},
+		{name: "bound zero", keys: "0", expected: []resolved{{commands.GotoStart, 1}}},
+		{name: "bound digit", keys: "2", expected: []resolved{{commands.ColumnsMenu, 1}}},
+		{name: "count continued by a bound digit", keys: "12j", expected: []resolved{{commands.Noop, 1}, {commands.Noop, 1}, {commands.MoveDown, 12}}},
+		{name: "alt digit", keys: "<A-1>", expected: []resolved{{commands.RecordsMenu, 1}}},
+		{name: "count before an alt digit", keys: "5<A-1>", expected: []resolved{{commands.Noop, 1}, {commands.RecordsMenu, 5}}},
+		{name: "unbound key", keys: "x", expected: []resolved{{commands.Noop, 1}}},
+		{name: "modifier", keys: "<A-j>", expected: []resolved{{commands.Noop, 1}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			state := NewState()
+
+			keys, err := ParseKeys(tc.keys)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var result []resolved
+
+			for _, key := range keys {
+				command, count := state.ResolveCount(testMap, key.Event())
+				result = append(result, resolved{command, count})
+			}
+
+			if len(result) != len(tc.expected) {
+				t.Fatalf("expected %v, but got %v", tc.expected, result)
+			}
+
+			for i := range result {
+				if result[i] != tc.expected[i] {
+					t.Fatalf("expected %v, but got %v", tc.expected, result)
+				}
+			}
+
+			if state.Pending() != tc.pending {
+				t.Fatalf("expected pending to be %v, but got %v", tc.pending, state.Pending())
+			}
+		})
+	}
+}
+
+func TestState_Resolve(t *testing.T) {
+	state := NewState()
+
+	// Without counts, the digits are only their bindings.
+	if command := state.Resolve(testMap, Key{Char: '5'}.Event()); command != commands.Noop || state.Pending() {
+		t.Fatalf("expected 5 not to start a count, but got %v", command)
+	}
+
+	if command := state.Resolve(testMap, Key{Char: '2'}.Event()); command != commands.ColumnsMenu {
+		t.Fatalf("expected %v, but got %v", commands.ColumnsMenu, command)
+	}
+}
+
+func TestState_SameEvent(t *testing.T) {
+	state := NewState()
+	other := Map{Bind{Key: Key{Char: 'g'}, Cmd: commands.Search}}
+
+	// The maps resolving the same event start from the same state.
+	g := Key{Char: 'g'}.Event()
+
+	if command := state.Resolve(testMap, g); command != commands.Noop {
+		t.Fatalf("expected the sequence to start, but got %v", command)
+	}
+
+	if command := state.Resolve(other, g); command != commands.Search {
+		t.Fatalf("expected %v, but got %v", commands.Search, command)
+	}
+
+	if command := state.Resolve(testMap, Key{Char: 'g'}.Event()); command != commands.GotoTop {
+		t.Fatalf("expected %v, but got %v", commands.GotoTop, command)
+	}
+}
+
+func TestState_Timeout(t *testing.T) {
+	testCases := []struct {
+		name     string
+		keys     string
+		expected resolved
+	}{
+		{name: "sequence", keys: "gg", expected: resolved{commands.Noop, 1}},
+		{name: "count", keys: "5j", expected: resolved{commands.MoveDown, 1}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			state := &State{Timeout: time.Millisecond}
+
+			keys, err := ParseKeys(tc.keys)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			state.ResolveCount(testMap, keys[0].Event())
+
+			time.Sleep(5 * time.Millisecond)
+
+			command, count := state.ResolveCount(testMap, keys[1].Event())
+			if (resolved{command, count}) != tc.expected {
+				t.Fatalf("expected %v, but got %v", tc.expected, resolved{command, count})
+			}
+		})
+	}
+}
@@ -5,12 +5,14 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"net/url"
 	"os"
 
 	"github.com/go-sql-driver/mysql"
 
 	"github.com/jorgerojas26/lazysql/app"
 	"github.com/jorgerojas26/lazysql/components"
+	"github.com/jorgerojas26/lazysql/drivers"
 	"github.com/jorgerojas26/lazysql/helpers/logger"
 )
 
@@ -21,9 +23,11 @@ func main() {
 		f := flag.CommandLine.Output()
 		fmt.Fprintln(f, "lazysql")
 		fmt.Fprintln(f, "")
-		fmt.Fprintf(f, "Usage:  %s [options] [connection_url]\n\n", os.Args[0])
+		fmt.Fprintf(f, "Usage:  %s [options] [connection_url | files...]\n\n", os.Args[0])
 		fmt.Fprintln(f, "  connection_url")
 		fmt.Fprintln(f, "        database URL to connect to. Omit to start in picker mode")
+		fmt.Fprintln(f, "  files")
+		fmt.Fprintln(f, "        CSV, TSV, JSON, NDJSON or Parquet files to browse as tables")
 		fmt.Fprintln(f, "")
 		fmt.Fprintln(f, "Options:")
 		flag.PrintDefaults()
@@ -31,6 +35,7 @@ func main() {
 	printVersion := flag.Bool("version", false, "Show version")
 	logLevel := flag.String("loglevel", "info", "Log level")
 	logFile := flag.String("logfile", "", "Log file")
+	writeBack := flag.Bool("writeback", false, "Write the changes made to files back to them")
 	flag.Parse()
 
 	if *printVersion {
@@ -67,10 +72,16 @@ func main() {
 	// Parse the command line arguments.
 	args := flag.Args()
 
-	switch len(args) {
-	case 0:
+	switch {
+	case len(args) == 0:
 		// Launch into the connection picker.
-	case 1:
+	case areDataFiles(args):
+		// Browse the files as the tables of a database.
+		err := components.InitFromArg(drivers.FilesURL(args, *writeBack))
+		if err != nil {
+			log.Fatal(err)
+		}
+	case len(args) == 1:
 		// Set a connection from the command line.
 		err := components.InitFromArg(args[0])
 		if err != nil {
@@ -84,3 +95,19 @@ func main() {
 		log.Fatalf("Error running app: %v", err)
 	}
 }
+
+// areDataFiles reports whether every argument is a file the files driver can
+// load, e.g. the expansion of ./data/*.csv, and not a URL.
+func areDataFiles(args []string) bool {
+	for _, arg := range args {
+		if parsed, err := url.Parse(arg); err == nil && len(parsed.Scheme) > 1 {
+			return false
+		}
+
+		if !drivers.IsDataFile(arg) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -13,6 +13,12 @@ type AppConfig struct {
 type Connection struct {
 	Name string
 
+	// Group is the group the connection is listed in and Tags are matched by
+	// the filter of the connection list. A group or a tag naming an
+	// environment, like prod, colours the connection.
+	Group string   `toml:",omitempty"`
+	Tags  []string `toml:",omitempty"`
+
 	// either use this directly
 	URL string
 
@@ -25,12 +31,97 @@ type Connection struct {
 	DBName    string
 	URLParams string
 
+	// File is the database file of SQLite and DuckDB, or the comma separated
+	// files of the files provider.
+	File string `toml:",omitempty"`
+
+	// Protocol is the protocol of ClickHouse connections, native or http.
+	Protocol string `toml:",omitempty"`
+
+	// PasswordCommand is run when connecting, what it prints is the
+	// password of the connection.
+	PasswordCommand string `toml:",omitempty"`
+
 	Commands []*Command
+
+	// SSH tunnels the connection through an SSH server when set.
+	SSH *SSHTunnel `toml:",omitempty"`
+
+	// TLS configures the encryption of the connection when set.
+	TLS *TLSConfig `toml:",omitempty"`
+}
+
+// TLS modes, they follow the sslmode of PostgreSQL.
+const (
+	TLSModeDisable    = "disable"
+	TLSModeRequire    = "require"
+	TLSModeVerifyCA   = "verify-ca"
+	TLSModeVerifyFull = "verify-full"
+)
+
+// TLSConfig describes how a connection is encrypted, the drivers translate it
+// to their own URL parameters.
+type TLSConfig struct {
+	// Mode is one of the TLSMode constants, it defaults to verify-full.
+	Mode string
+
+	// CAFile verifies the certificate of the server instead of the system
+	// certificates.
+	CAFile string
+
+	// CertFile and KeyFile are the client certificate and its key.
+	CertFile string
+	KeyFile  string
+
+	// ServerName is the name the certificate of the server is verified
+	// against, it defaults to the host of the URL.
+	ServerName string
+}
+
+// SSHTunnel describes the SSH server a connection is tunnelled through.
+type SSHTunnel struct {
+	// Host is the SSH server, as host or host:port.
+	Host string
+	User string
+
+	// KeyFile is a private key used to authenticate, next to the keys of
+	// the SSH agent when UseAgent is set.
+	KeyFile  string
+	UseAgent bool
+
+	// KnownHostsFile verifies the host keys of the servers, it defaults to
+	// ~/.ssh/known_hosts.
+	KnownHostsFile string
+
+	// JumpHosts are the servers connected through, in order, to reach Host.
+	// They are written as [user@]host[:port].
+	JumpHosts []string
 }
 
 type Command struct {
 	Command     string
 	WaitForPort string
+
+	// Env are environment variables added to the ones of lazysql and Dir is
+	// the directory the command runs in.
+	Env map[string]string `toml:",omitempty"`
+	Dir string            `toml:",omitempty"`
+
+	// WaitForLog is a regular expression matched by a line of the output once
+	// the command is ready, and WaitForHTTP a URL answering once it is ready.
+	WaitForLog  string `toml:",omitempty"`
+	WaitForHTTP string `toml:",omitempty"`
+
+	// Timeout is how long the command may take to be ready, like 30s.
+	Timeout string `toml:",omitempty"`
+}
+
+// SavedQuery is a query of the configuration, listed in the command palette.
+// A query naming a connection is only listed while connected to it.
+type SavedQuery struct {
+	Name       string
+	Query      string
+	Connection string `toml:",omitempty"`
 }
 
 type StateChange struct {
@@ -108,3 +199,127 @@ type SidebarEditingCommitParams struct {
 	NewValue   string
 	Type       CellValueType
 }
+
+// TableSchema is a provider independent representation of a table's
+// structure. It is built from the driver metadata getters so that tables
+// from different connections can be compared with each other.
+type TableSchema struct {
+	Name        string
+	Columns     []ColumnSchema
+	Constraints []ConstraintSchema
+	ForeignKeys []ForeignKeySchema
+	Indexes     []IndexSchema
+}
+
+type ColumnSchema struct {
+	Name     string
+	Type     string
+	Default  string
+	Nullable bool
+}
+
+type ConstraintSchema struct {
+	Name    string
+	Type    string
+	Columns []string
+}
+
+type ForeignKeySchema struct {
+	Name              string
+	Columns           []string
+	ReferencedTable   string
+	ReferencedColumns []string
+}
+
+type IndexSchema struct {
+	Name    string
+	Columns []string
+	Unique  bool
+}
+
+type SchemaDiffStatus int8
+
+const (
+	SchemaDiffUnchanged SchemaDiffStatus = iota
+	SchemaDiffAdded
+	SchemaDiffRemoved
+	SchemaDiffChanged
+)
+
+// SchemaObjectDiff describes the difference of a single schema object
+// (column, index, constraint or foreign key) between two tables.
+// Source and Target hold a human readable definition of the object on
+// each side, and are empty when the object does not exist on that side.
+type SchemaObjectDiff struct {
+	Kind   string
+	Name   string
+	Source string
+	Target string
+	Status SchemaDiffStatus
+}
+
+type TableSchemaDiff struct {
+	Name    string
+	Source  *TableSchema
+	Target  *TableSchema
+	Objects []SchemaObjectDiff
+	Status  SchemaDiffStatus
+}
+
+type SchemaDiff struct {
+	Tables []TableSchemaDiff
+}
+
+// DataRowDiff is a row that differs between the source and the target table
+// of a data diff. It uses the same statuses as the schema diff, added rows
+// only exist in the source and removed rows only exist in the target.
+// Source and Target follow the order of DataDiff.Columns.
+type DataRowDiff struct {
+	PrimaryKeyInfo []PrimaryKeyInfo
+	Source         []string
+	Target         []string
+	ChangedColumns []int
+	Status         SchemaDiffStatus
+}
+
+type DataDiff struct {
+	Columns           []string
+	PrimaryKeyColumns []string
+	Rows              []DataRowDiff
+	SourceRowCount    int
+	TargetRowCount    int
+}
+
+// ERDiagram is the entity-relationship diagram of a set of tables. Tables
+// are positioned on a grid of character cells by the layout.
+type ERDiagram struct {
+	Tables    []ERTable
+	Relations []ERRelation
+	Width     int
+	Height    int
+}
+
+// ERTable is a box of the diagram, only the key columns are shown.
+type ERTable struct {
+	Name    string
+	Columns []ERColumn
+	X       int
+	Y       int
+	Width   int
+	Height  int
+}
+
+type ERColumn struct {
+	Name       string
+	Type       string
+	PrimaryKey bool
+	ForeignKey bool
+}
+
+type ERRelation struct {
+	Name              string
+	Table             string
+	Columns           []string
+	ReferencedTable   string
+	ReferencedColumns []string
+}